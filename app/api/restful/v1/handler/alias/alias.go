@@ -0,0 +1,200 @@
+package alias
+
+import (
+	"net/http"
+	"strconv"
+
+	v1 "banking/app/api/restful/v1"
+	"banking/domain"
+	"banking/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AliasHandler struct {
+	aliasService domain.IAliasService
+}
+
+func NewAliasHandler(AliasService domain.IAliasService) domain.IAliasHandler {
+	return &AliasHandler{
+		aliasService: AliasService,
+	}
+}
+
+// @Tags Alias
+// @Router /api/v1/user/alias [post]
+// @Summary Create Alias
+// @Description Add an email, phone number or handle the user is paid by. A handle is verified at once, an email or phone number is sent a code to verify
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAliasReq true "alias"
+// @Success 201 {object} CreateAliasResp "success created alias"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 409 {object} v1.Problem "alias is taken"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *AliasHandler) CreateAlias() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "AliasHandler.CreateAlias", "handler")
+		defer span.End()
+
+		var input CreateAliasReq
+		if err := c.ShouldBindJSON(&input); err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		alias, err := h.aliasService.CreateAlias(ctx, c.GetUint("authedUserId"), input.Type, input.Value)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, &CreateAliasResp{
+			Data: toAlias(alias),
+		})
+	}
+}
+
+// @Tags Alias
+// @Router /api/v1/user/alias/{aliasId}/verify [post]
+// @Summary Verify Alias
+// @Description Verify an email or phone alias with the code sent to it
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param aliasId path uint true "alias id"
+// @Param request body VerifyAliasReq true "verification code"
+// @Success 200 {object} VerifyAliasResp "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 404 {object} v1.Problem "not found"
+// @Failure 409 {object} v1.Problem "alias is taken"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *AliasHandler) VerifyAlias() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "AliasHandler.VerifyAlias", "handler")
+		defer span.End()
+
+		aliasID, err := strconv.ParseUint(c.Param("aliasId"), 10, 64)
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid alias id")
+			return
+		}
+
+		var input VerifyAliasReq
+		if err := c.ShouldBindJSON(&input); err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		alias, err := h.aliasService.VerifyAlias(ctx, c.GetUint("authedUserId"), uint(aliasID), input.Code)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &VerifyAliasResp{
+			Data: toAlias(alias),
+		})
+	}
+}
+
+// @Tags Alias
+// @Router /api/v1/user/alias [get]
+// @Summary Get Aliases
+// @Description Get the aliases of the user
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} GetAliasesResp "success"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *AliasHandler) GetAliases() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "AliasHandler.GetAliases", "handler")
+		defer span.End()
+
+		aliases, err := h.aliasService.GetAliases(ctx, c.GetUint("authedUserId"))
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		data := make([]*Alias, 0, len(aliases))
+		for _, alias := range aliases {
+			data = append(data, toAlias(alias))
+		}
+
+		c.JSON(http.StatusOK, &GetAliasesResp{
+			Data: data,
+		})
+	}
+}
+
+// @Tags Alias
+// @Router /api/v1/user/alias/{aliasId} [delete]
+// @Summary Delete Alias
+// @Description Delete an alias of the user, it no longer resolves
+// @Produce json
+// @Security BearerAuth
+// @Param aliasId path uint true "alias id"
+// @Success 200 {object} v1.MsgResponse "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 404 {object} v1.Problem "not found"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *AliasHandler) DeleteAlias() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "AliasHandler.DeleteAlias", "handler")
+		defer span.End()
+
+		aliasID, err := strconv.ParseUint(c.Param("aliasId"), 10, 64)
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid alias id")
+			return
+		}
+
+		if err := h.aliasService.DeleteAlias(ctx, c.GetUint("authedUserId"), uint(aliasID)); err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &v1.MsgResponse{
+			Msg: "alias deleted",
+		})
+	}
+}
+
+// @Tags Alias
+// @Router /api/v1/transaction/payee [get]
+// @Summary Resolve Alias
+// @Description Find the recipient of an email, phone number or handle, with a masked name to confirm before a transfer
+// @Produce json
+// @Param alias query string true "email, phone number or handle"
+// @Success 200 {object} ResolveAliasResp "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 404 {object} v1.Problem "not found"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *AliasHandler) ResolveAlias() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "AliasHandler.ResolveAlias", "handler")
+		defer span.End()
+
+		alias := c.Query("alias")
+		if alias == "" {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "alias is required")
+			return
+		}
+
+		payee, err := h.aliasService.Resolve(ctx, alias)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &ResolveAliasResp{
+			Data: &Payee{
+				Alias:      alias,
+				AliasType:  payee.AliasType,
+				MaskedName: payee.MaskedName,
+			},
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package alias
+
+import (
+	"time"
+
+	"banking/model/mysql"
+)
+
+type Alias struct {
+	ID         uint            `json:"id"`
+	Type       mysql.AliasType `json:"type"`
+	Value      string          `json:"value"`
+	Verified   bool            `json:"verified"`
+	VerifiedAt *time.Time      `json:"verifiedAt,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+type CreateAliasReq struct {
+	Type  mysql.AliasType `json:"type" binding:"required,oneof=email phone handle"`
+	Value string          `json:"value" binding:"required,max=255"`
+}
+
+type CreateAliasResp struct {
+	Data *Alias `json:"data"`
+}
+
+type VerifyAliasReq struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type VerifyAliasResp struct {
+	Data *Alias `json:"data"`
+}
+
+type GetAliasesResp struct {
+	Data []*Alias `json:"data"`
+}
+
+// Payee is shown to the payer to confirm the recipient of an alias before a transfer
+type Payee struct {
+	Alias      string          `json:"alias"`
+	AliasType  mysql.AliasType `json:"aliasType"`
+	MaskedName string          `json:"maskedName"`
+}
+
+type ResolveAliasResp struct {
+	Data *Payee `json:"data"`
+}
+
+func toAlias(alias *mysql.PayeeAlias) *Alias {
+	return &Alias{
+		ID:         alias.ID,
+		Type:       alias.Type,
+		Value:      alias.Value,
+		Verified:   alias.Verified(),
+		VerifiedAt: alias.VerifiedAt,
+		CreatedAt:  alias.CreatedAt,
+	}
+}
@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	v1 "banking/app/api/restful/v1"
+	"banking/domain"
+	"banking/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+type AuditHandler struct {
+	auditService domain.IAuditService
+}
+
+func NewAuditHandler(AuditService domain.IAuditService) domain.IAuditHandler {
+	return &AuditHandler{
+		auditService: AuditService,
+	}
+}
+
+// @Tags Audit
+// @Router /api/v1/audit/logs [get]
+// @Summary Get Audit Logs
+// @Description Query the audit log by actor, action and time range, pages are requested with afterId
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param actorId query uint false "actor user id"
+// @Param action query string false "action, e.g. transaction.transfer"
+// @Param from query string false "start time, RFC3339"
+// @Param to query string false "end time, RFC3339"
+// @Param afterId query uint false "return entries after this id"
+// @Param limit query int false "page size, at most 500"
+// @Success 200 {object} GetAuditLogsResp "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 403 {object} v1.Problem "forbidden"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *AuditHandler) GetAuditLogs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "AuditHandler.GetAuditLogs", "handler")
+		defer span.End()
+
+		var actorID, afterID uint64
+		var err error
+		if value := c.Query("actorId"); value != "" {
+			if actorID, err = strconv.ParseUint(value, 10, 64); err != nil {
+				v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid actor id")
+				return
+			}
+		}
+		if value := c.Query("afterId"); value != "" {
+			if afterID, err = strconv.ParseUint(value, 10, 64); err != nil {
+				v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid after id")
+				return
+			}
+		}
+
+		var from, to time.Time
+		if value := c.Query("from"); value != "" {
+			if from, err = time.Parse(time.RFC3339, value); err != nil {
+				v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid from time")
+				return
+			}
+		}
+		if value := c.Query("to"); value != "" {
+			if to, err = time.Parse(time.RFC3339, value); err != nil {
+				v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid to time")
+				return
+			}
+		}
+
+		limit := defaultPageSize
+		if value := c.Query("limit"); value != "" {
+			if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+				v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid limit")
+				return
+			}
+		}
+		if limit > maxPageSize {
+			limit = maxPageSize
+		}
+
+		logs, err := h.auditService.GetAuditLogs(ctx, uint(actorID), c.Query("action"), from, to, uint(afterID), limit)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		resp := &GetAuditLogsResp{
+			Data: make([]*AuditLog, 0, len(logs)),
+		}
+		for _, log := range logs {
+			resp.Data = append(resp.Data, &AuditLog{
+				ID:         log.ID,
+				CreatedAt:  log.CreatedAt,
+				ActorID:    log.ActorID,
+				ActorEmail: log.ActorEmail,
+				AuthMethod: log.AuthMethod,
+				IP:         log.IP,
+				Action:     log.Action,
+				Target:     log.Target,
+				Before:     log.Before,
+				After:      log.After,
+				RequestID:  log.RequestID,
+				Outcome:    log.Outcome,
+				PrevHash:   log.PrevHash,
+				Hash:       log.Hash,
+			})
+		}
+		if len(logs) == limit {
+			resp.NextAfterID = logs[len(logs)-1].ID
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Tags Audit
+// @Router /api/v1/audit/verify [get]
+// @Summary Verify Audit Chain
+// @Description Recompute the hash chain of the audit log and report the first tampered entry
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} VerifyAuditChainResp "success"
+// @Failure 403 {object} v1.Problem "forbidden"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *AuditHandler) VerifyAuditChain() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "AuditHandler.VerifyAuditChain", "handler")
+		defer span.End()
+
+		checked, brokenID, err := h.auditService.VerifyAuditChain(ctx)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &VerifyAuditChainResp{
+			Valid:    brokenID == 0,
+			Checked:  checked,
+			BrokenID: brokenID,
+		})
+	}
+}
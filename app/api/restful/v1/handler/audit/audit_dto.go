@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"time"
+
+	"banking/model/mysql"
+)
+
+type AuditLog struct {
+	ID         uint               `json:"id"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	ActorID    *uint              `json:"actorId"`
+	ActorEmail string             `json:"actorEmail"`
+	AuthMethod string             `json:"authMethod"`
+	IP         string             `json:"ip"`
+	Action     string             `json:"action"`
+	Target     string             `json:"target"`
+	Before     string             `json:"before,omitempty"`
+	After      string             `json:"after,omitempty"`
+	RequestID  string             `json:"requestId"`
+	Outcome    mysql.AuditOutcome `json:"outcome"`
+	PrevHash   string             `json:"prevHash"`
+	Hash       string             `json:"hash"`
+}
+
+type GetAuditLogsResp struct {
+	Data []*AuditLog `json:"data"`
+	// NextAfterID is passed as afterId to fetch the next page, 0 when there are no more entries
+	NextAfterID uint `json:"nextAfterId"`
+}
+
+type VerifyAuditChainResp struct {
+	Valid    bool `json:"valid"`
+	Checked  int  `json:"checked"`
+	BrokenID uint `json:"brokenId,omitempty"`
+}
@@ -0,0 +1,149 @@
+package balancehistory
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	v1 "banking/app/api/restful/v1"
+	"banking/domain"
+	"banking/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BalanceHistoryHandler struct {
+	balanceHistoryService domain.IBalanceHistoryService
+}
+
+func NewBalanceHistoryHandler(BalanceHistoryService domain.IBalanceHistoryService) domain.IBalanceHistoryHandler {
+	return &BalanceHistoryHandler{
+		balanceHistoryService: BalanceHistoryService,
+	}
+}
+
+// @Tags Balance History
+// @Router /api/v1/transaction/{userId}/balance [get]
+// @Summary Get Balance At
+// @Description Balance of the user after every transaction before a point in time, the current balance without at
+// @Produce json
+// @Param X-API-Key header string true "api key"
+// @Param X-Secret-Key header string true "api secret"
+// @Param X-User-Id header uint true "user id of the api key"
+// @Param userId path uint true "user id"
+// @Param at query string false "point in time, RFC3339"
+// @Success 200 {object} GetBalanceAtResp "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 403 {object} v1.Problem "forbidden"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *BalanceHistoryHandler) GetBalanceAt() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "BalanceHistoryHandler.GetBalanceAt", "handler")
+		defer span.End()
+
+		userID, ok := authorizedUserID(c)
+		if !ok {
+			return
+		}
+
+		at := time.Now()
+		if value := c.Query("at"); value != "" {
+			var err error
+			if at, err = time.Parse(time.RFC3339, value); err != nil {
+				v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid at time")
+				return
+			}
+		}
+
+		balance, err := h.balanceHistoryService.GetBalanceAt(ctx, userID, at)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &GetBalanceAtResp{
+			Data: &BalanceAt{
+				UserID:  userID,
+				At:      at,
+				Balance: balance,
+			},
+		})
+	}
+}
+
+// @Tags Balance History
+// @Router /api/v1/transaction/{userId}/balance/daily [get]
+// @Summary Get Daily Balances
+// @Description End of day balance of every day from from to to, both included, with the days of the time zone tz
+// @Produce json
+// @Param X-API-Key header string true "api key"
+// @Param X-Secret-Key header string true "api secret"
+// @Param X-User-Id header uint true "user id of the api key"
+// @Param userId path uint true "user id"
+// @Param from query string true "first day, YYYY-MM-DD"
+// @Param to query string true "last day, YYYY-MM-DD"
+// @Param tz query string false "IANA time zone of the days, UTC by default"
+// @Success 200 {object} GetDailyBalancesResp "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 403 {object} v1.Problem "forbidden"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *BalanceHistoryHandler) GetDailyBalances() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "BalanceHistoryHandler.GetDailyBalances", "handler")
+		defer span.End()
+
+		userID, ok := authorizedUserID(c)
+		if !ok {
+			return
+		}
+
+		var input GetDailyBalancesReq
+		if err := c.ShouldBindQuery(&input); err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		location, err := time.LoadLocation(input.TZ)
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid time zone")
+			return
+		}
+
+		from, err := time.ParseInLocation(time.DateOnly, input.From, location)
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid from day")
+			return
+		}
+		to, err := time.ParseInLocation(time.DateOnly, input.To, location)
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid to day")
+			return
+		}
+
+		balances, err := h.balanceHistoryService.GetDailyBalances(ctx, userID, from, to)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &GetDailyBalancesResp{
+			Data: balances,
+		})
+	}
+}
+
+// authorizedUserID is the userId of the path when it is the authenticated user, otherwise it aborts
+func authorizedUserID(c *gin.Context) (uint, bool) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid user id")
+		return 0, false
+	}
+
+	if uint(userID) != c.GetUint("authedUserId") {
+		v1.AbortWithProblem(c, domain.CodeForbidden, "unauthorized")
+		return 0, false
+	}
+
+	return uint(userID), true
+}
@@ -0,0 +1,29 @@
+package balancehistory
+
+import (
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+type BalanceAt struct {
+	UserID  uint            `json:"userId"`
+	At      time.Time       `json:"at"`
+	Balance decimal.Decimal `json:"balance"`
+}
+
+type GetBalanceAtResp struct {
+	Data *BalanceAt `json:"data"`
+}
+
+type GetDailyBalancesReq struct {
+	From string `form:"from" binding:"required"`
+	To   string `form:"to" binding:"required"`
+	TZ   string `form:"tz"` // empty is UTC
+}
+
+type GetDailyBalancesResp struct {
+	Data []*mysqlModel.DailyBalance `json:"data"`
+}
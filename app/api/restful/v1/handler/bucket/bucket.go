@@ -0,0 +1,68 @@
+package bucket
+
+import (
+	"net/http"
+	"strconv"
+
+	v1 "banking/app/api/restful/v1"
+	"banking/domain"
+	"banking/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BucketHandler struct {
+	bucketService domain.IBucketService
+}
+
+func NewBucketHandler(BucketService domain.IBucketService) domain.IBucketHandler {
+	return &BucketHandler{
+		bucketService: BucketService,
+	}
+}
+
+// @Tags Bucket
+// @Router /api/v1/admin/user/{userId}/buckets [put]
+// @Summary Set Balance Buckets
+// @Description Make a user a hot account whose credits are spread over balance buckets, 0 folds the buckets back into the user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path uint true "user id"
+// @Param request body SetBalanceBucketsReq true "number of buckets"
+// @Success 200 {object} SetBalanceBucketsResp "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 404 {object} v1.Problem "not found"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *BucketHandler) SetBalanceBuckets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "BucketHandler.SetBalanceBuckets", "handler")
+		defer span.End()
+
+		userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid user id")
+			return
+		}
+
+		var input SetBalanceBucketsReq
+		if err := c.ShouldBindJSON(&input); err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		user, err := h.bucketService.SetBalanceBuckets(ctx, uint(userID), *input.Buckets)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &SetBalanceBucketsResp{
+			Data: &HotAccount{
+				UserID:         user.ID,
+				Balance:        user.Balance,
+				BalanceBuckets: user.BalanceBuckets,
+			},
+		})
+	}
+}
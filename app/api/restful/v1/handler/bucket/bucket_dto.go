@@ -0,0 +1,19 @@
+package bucket
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+type SetBalanceBucketsReq struct {
+	Buckets *int `json:"buckets" binding:"required,min=0"`
+}
+
+type SetBalanceBucketsResp struct {
+	Data *HotAccount `json:"data"`
+}
+
+type HotAccount struct {
+	UserID         uint            `json:"userId"`
+	Balance        decimal.Decimal `json:"balance"`
+	BalanceBuckets int             `json:"balanceBuckets"`
+}
@@ -0,0 +1,154 @@
+package fraud
+
+import (
+	"net/http"
+	"strconv"
+
+	v1 "banking/app/api/restful/v1"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FraudHandler struct {
+	fraudService       domain.IFraudService
+	transactionService domain.ITransactionService
+}
+
+func NewFraudHandler(FraudService domain.IFraudService, TransactionService domain.ITransactionService) domain.IFraudHandler {
+	return &FraudHandler{
+		fraudService:       FraudService,
+		transactionService: TransactionService,
+	}
+}
+
+// @Tags Fraud
+// @Router /api/v1/admin/fraud/reviews [get]
+// @Summary Get Fraud Reviews
+// @Description Get transfers held or blocked by fraud screening
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "review status" Enums(pending, approved, rejected, blocked)
+// @Success 200 {object} GetReviewsResp "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *FraudHandler) GetReviews() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "FraudHandler.GetReviews", "handler")
+		defer span.End()
+
+		status := mysqlModel.FraudReviewStatus(c.Query("status"))
+		switch status {
+		case "", mysqlModel.ReviewPending, mysqlModel.ReviewApproved, mysqlModel.ReviewRejected, mysqlModel.ReviewBlocked:
+		default:
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid status")
+			return
+		}
+
+		reviews, err := h.fraudService.GetReviews(ctx, status)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		data := make([]*Review, 0, len(reviews))
+		for _, review := range reviews {
+			data = append(data, &Review{
+				ID:            review.ID,
+				FromUserID:    review.FromUserID,
+				ToUserID:      review.ToUserID,
+				Amount:        review.Amount,
+				Score:         review.Score,
+				Decision:      review.Decision,
+				Reasons:       review.Reasons,
+				Status:        review.Status,
+				ReviewedBy:    review.ReviewedBy,
+				TransactionID: review.TransactionID,
+				CreatedAt:     review.CreatedAt,
+			})
+		}
+
+		c.JSON(http.StatusOK, &GetReviewsResp{
+			Data: data,
+		})
+	}
+}
+
+// @Tags Fraud
+// @Router /api/v1/admin/fraud/reviews/{reviewId}/approve [post]
+// @Summary Approve Fraud Review
+// @Description Approve a held transfer and execute it
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param reviewId path uint true "review id"
+// @Success 200 {object} ApproveReviewResp "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 404 {object} v1.Problem "not found"
+// @Failure 409 {object} v1.Problem "conflict"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *FraudHandler) ApproveReview() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "FraudHandler.ApproveReview", "handler")
+		defer span.End()
+
+		reviewID, err := strconv.ParseUint(c.Param("reviewId"), 10, 64)
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid review id")
+			return
+		}
+
+		transaction, err := h.transactionService.ApproveReview(ctx, uint(reviewID), c.GetUint("authedUserId"))
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &ApproveReviewResp{
+			Data: &Transaction{
+				ID:              transaction.ID,
+				FromUserID:      transaction.FromUserID,
+				FromUserBalance: transaction.FromUserBalance,
+				ToUserID:        transaction.ToUserID,
+				Amount:          transaction.Amount,
+			},
+		})
+	}
+}
+
+// @Tags Fraud
+// @Router /api/v1/admin/fraud/reviews/{reviewId}/reject [post]
+// @Summary Reject Fraud Review
+// @Description Reject a held transfer, no money is moved
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param reviewId path uint true "review id"
+// @Success 200 {object} v1.MsgResponse "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 409 {object} v1.Problem "conflict"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *FraudHandler) RejectReview() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "FraudHandler.RejectReview", "handler")
+		defer span.End()
+
+		reviewID, err := strconv.ParseUint(c.Param("reviewId"), 10, 64)
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid review id")
+			return
+		}
+
+		if err := h.fraudService.RejectReview(ctx, uint(reviewID), c.GetUint("authedUserId")); err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &v1.MsgResponse{
+			Msg: "transfer rejected",
+		})
+	}
+}
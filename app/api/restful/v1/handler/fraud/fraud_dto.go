@@ -0,0 +1,39 @@
+package fraud
+
+import (
+	"time"
+
+	"banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+type Review struct {
+	ID            uint                    `json:"id"`
+	FromUserID    uint                    `json:"fromUserId"`
+	ToUserID      uint                    `json:"toUserId"`
+	Amount        decimal.Decimal         `json:"amount"`
+	Score         int                     `json:"score"`
+	Decision      mysql.FraudDecision     `json:"decision"`
+	Reasons       string                  `json:"reasons"`
+	Status        mysql.FraudReviewStatus `json:"status"`
+	ReviewedBy    *uint                   `json:"reviewedBy"`
+	TransactionID *uint                   `json:"transactionId"`
+	CreatedAt     time.Time               `json:"createdAt"`
+}
+
+type GetReviewsResp struct {
+	Data []*Review `json:"data"`
+}
+
+type ApproveReviewResp struct {
+	Data *Transaction `json:"data"`
+}
+
+type Transaction struct {
+	ID              uint            `json:"id"`
+	FromUserID      uint            `json:"fromUserId"`
+	FromUserBalance decimal.Decimal `json:"fromUserBalance"`
+	ToUserID        uint            `json:"toUserId"`
+	Amount          decimal.Decimal `json:"amount"`
+}
@@ -0,0 +1,54 @@
+package health
+
+import (
+	"net/http"
+
+	"banking/domain"
+	"banking/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	healthService domain.IHealthService
+}
+
+func NewHealthHandler(HealthService domain.IHealthService) domain.IHealthHandler {
+	return &HealthHandler{
+		healthService: HealthService,
+	}
+}
+
+// @Tags Health
+// @Router /healthz [get]
+// @Summary Liveness
+// @Description The process serves requests, dependencies are not checked so an outage of MySQL or redis does not restart every replica
+// @Produce json
+// @Success 200 {object} domain.HealthReport "alive"
+func (h *HealthHandler) Liveness() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, domain.HealthReport{Status: domain.HealthStatusOK})
+	}
+}
+
+// @Tags Health
+// @Router /readyz [get]
+// @Summary Readiness
+// @Description Checks the MySQL master, the MySQL slave and its replica lag and redis, results are cached for health.cacheTTL seconds. Fails while the server shuts down
+// @Produce json
+// @Success 200 {object} domain.HealthReport "ready"
+// @Failure 503 {object} domain.HealthReport "not ready"
+func (h *HealthHandler) Readiness() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "HealthHandler.Readiness", "handler")
+		defer span.End()
+
+		report := h.healthService.Readiness(ctx)
+		if report.Status != domain.HealthStatusOK {
+			c.JSON(http.StatusServiceUnavailable, report)
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
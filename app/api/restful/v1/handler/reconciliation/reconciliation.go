@@ -0,0 +1,208 @@
+package reconciliation
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	v1 "banking/app/api/restful/v1"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxStatementSize limits uploaded statement files to 10 MB
+const maxStatementSize = 10 << 20
+
+type ReconciliationHandler struct {
+	reconciliationService domain.IReconciliationService
+}
+
+func NewReconciliationHandler(ReconciliationService domain.IReconciliationService) domain.IReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationService: ReconciliationService,
+	}
+}
+
+// @Tags Reconciliation
+// @Router /api/v1/admin/reconciliation/import [post]
+// @Summary Import Bank Statement
+// @Description Import an MT940 or camt.053 statement file and match its lines to deposit and withdraw transactions
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param format formData string true "statement format" Enums(mt940, camt053)
+// @Param file formData file true "statement file"
+// @Success 201 {object} ImportStatementResp "success imported statement"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *ReconciliationHandler) ImportStatement() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "ReconciliationHandler.ImportStatement", "handler")
+		defer span.End()
+
+		format := mysqlModel.StatementFormat(c.PostForm("format"))
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "statement file is required")
+			return
+		}
+
+		if fileHeader.Size > maxStatementSize {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "statement file is too large")
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		statement, err := h.reconciliationService.ImportStatement(ctx, format, fileHeader.Filename, content, c.GetUint("authedUserId"))
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		data := &Statement{
+			ID:           statement.ID,
+			Format:       statement.Format,
+			StatementRef: statement.StatementRef,
+			Account:      statement.Account,
+			Lines:        make([]*StatementLine, 0, len(statement.Lines)),
+		}
+		for i := range statement.Lines {
+			line := &statement.Lines[i]
+			switch line.Status {
+			case mysqlModel.Matched:
+				data.Matched++
+			case mysqlModel.Unmatched:
+				data.Unmatched++
+			case mysqlModel.Ambiguous:
+				data.Ambiguous++
+			}
+			data.Lines = append(data.Lines, toStatementLine(line))
+		}
+
+		c.JSON(http.StatusCreated, &ImportStatementResp{
+			Data: data,
+		})
+	}
+}
+
+// @Tags Reconciliation
+// @Router /api/v1/admin/reconciliation/{statementId}/lines [get]
+// @Summary Get Statement Lines
+// @Description Get the lines of an imported statement, optionally filtered by reconciliation status
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param statementId path uint true "statement id"
+// @Param status query string false "reconciliation status" Enums(matched, unmatched, ambiguous, resolved, ignored)
+// @Success 200 {object} GetStatementLinesResp "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *ReconciliationHandler) GetStatementLines() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "ReconciliationHandler.GetStatementLines", "handler")
+		defer span.End()
+
+		statementID, err := strconv.ParseUint(c.Param("statementId"), 10, 64)
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid statement id")
+			return
+		}
+
+		status := mysqlModel.ReconciliationStatus(c.Query("status"))
+		switch status {
+		case "", mysqlModel.Matched, mysqlModel.Unmatched, mysqlModel.Ambiguous, mysqlModel.Resolved, mysqlModel.Ignored:
+		default:
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid status")
+			return
+		}
+
+		lines, err := h.reconciliationService.GetStatementLines(ctx, uint(statementID), status)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		data := make([]*StatementLine, 0, len(lines))
+		for _, line := range lines {
+			data = append(data, toStatementLine(line))
+		}
+
+		c.JSON(http.StatusOK, &GetStatementLinesResp{
+			Data: data,
+		})
+	}
+}
+
+// @Tags Reconciliation
+// @Router /api/v1/admin/reconciliation/line/{lineId}/resolve [post]
+// @Summary Resolve Statement Line
+// @Description Match an unmatched or ambiguous line to a transaction, transactionId 0 ignores the line
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param lineId path uint true "statement line id"
+// @Param ResolveStatementLineReq body ResolveStatementLineReq true "resolve request"
+// @Success 200 {object} ResolveStatementLineResp "success"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 404 {object} v1.Problem "not found"
+// @Failure 409 {object} v1.Problem "conflict"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *ReconciliationHandler) ResolveStatementLine() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "ReconciliationHandler.ResolveStatementLine", "handler")
+		defer span.End()
+
+		lineID, err := strconv.ParseUint(c.Param("lineId"), 10, 64)
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid line id")
+			return
+		}
+
+		var input ResolveStatementLineReq
+		if err := c.ShouldBindJSON(&input); err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		line, err := h.reconciliationService.ResolveStatementLine(ctx, uint(lineID), input.TransactionID, c.GetUint("authedUserId"))
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &ResolveStatementLineResp{
+			Data: toStatementLine(line),
+		})
+	}
+}
+
+func toStatementLine(line *mysqlModel.BankStatementLine) *StatementLine {
+	return &StatementLine{
+		ID:            line.ID,
+		ValueDate:     line.ValueDate,
+		Direction:     line.Direction,
+		Amount:        line.Amount,
+		Currency:      line.Currency,
+		Reference:     line.Reference,
+		BankReference: line.BankReference,
+		Description:   line.Description,
+		Status:        line.Status,
+		TransactionID: line.TransactionID,
+		CandidateIDs:  line.CandidateIDs,
+	}
+}
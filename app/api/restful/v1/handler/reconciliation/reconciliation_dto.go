@@ -0,0 +1,51 @@
+package reconciliation
+
+import (
+	"time"
+
+	"banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+type StatementLine struct {
+	ID            uint                       `json:"id"`
+	ValueDate     time.Time                  `json:"valueDate"`
+	Direction     mysql.StatementDirection   `json:"direction"`
+	Amount        decimal.Decimal            `json:"amount"`
+	Currency      string                     `json:"currency"`
+	Reference     string                     `json:"reference"`
+	BankReference string                     `json:"bankReference"`
+	Description   string                     `json:"description"`
+	Status        mysql.ReconciliationStatus `json:"status"`
+	TransactionID *uint                      `json:"transactionId"`
+	CandidateIDs  string                     `json:"candidateIds,omitempty"`
+}
+
+type Statement struct {
+	ID           uint                  `json:"id"`
+	Format       mysql.StatementFormat `json:"format"`
+	StatementRef string                `json:"statementRef"`
+	Account      string                `json:"account"`
+	Matched      int                   `json:"matched"`
+	Unmatched    int                   `json:"unmatched"`
+	Ambiguous    int                   `json:"ambiguous"`
+	Lines        []*StatementLine      `json:"lines"`
+}
+
+type ImportStatementResp struct {
+	Data *Statement `json:"data"`
+}
+
+type GetStatementLinesResp struct {
+	Data []*StatementLine `json:"data"`
+}
+
+type ResolveStatementLineReq struct {
+	// TransactionID 0 marks the line as ignored
+	TransactionID uint `json:"transactionId" binding:"min=0"`
+}
+
+type ResolveStatementLineResp struct {
+	Data *StatementLine `json:"data"`
+}
@@ -0,0 +1,220 @@
+package stream
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	v1 "banking/app/api/restful/v1"
+	"banking/domain"
+	redisModel "banking/model/redis"
+	"banking/tracing"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/spf13/viper"
+)
+
+// writeWait limits the time to write a message to a websocket client
+const writeWait = 10 * time.Second
+
+type StreamHandler struct {
+	streamService domain.IStreamService
+	keepAlive     time.Duration
+	upgrader      websocket.Upgrader
+}
+
+func NewStreamHandler(StreamService domain.IStreamService) domain.IStreamHandler {
+	keepAlive := time.Duration(viper.GetInt("stream.keepAlive")) * time.Second
+	if keepAlive <= 0 {
+		keepAlive = 15 * time.Second
+	}
+
+	upgrader := websocket.Upgrader{}
+	// the default only accepts same origin requests
+	if origins := viper.GetStringSlice("stream.allowedOrigins"); len(origins) > 0 {
+		upgrader.CheckOrigin = func(r *http.Request) bool {
+			for _, origin := range origins {
+				if r.Header.Get("Origin") == origin {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	return &StreamHandler{
+		streamService: StreamService,
+		keepAlive:     keepAlive,
+		upgrader:      upgrader,
+	}
+}
+
+// @Tags Stream
+// @Router /api/v1/stream/sse [get]
+// @Summary Balance Event Stream
+// @Description Server-Sent Events of balance changes and new transactions of the authenticated user, resumes after the Last-Event-ID header or lastEventId
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param Last-Event-ID header string false "id of the last received event"
+// @Param lastEventId query string false "id of the last received event"
+// @Success 200 {object} Event "event stream"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *StreamHandler) SSE() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "StreamHandler.SSE", "handler")
+		defer span.End()
+
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("lastEventId")
+		}
+
+		events, ok := h.subscribe(ctx, c, lastEventID)
+		if !ok {
+			return
+		}
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no") // disable proxy buffering
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		ticker := time.NewTicker(h.keepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				// closed when the client fell behind, it reconnects with Last-Event-ID
+				if !ok {
+					return
+				}
+
+				c.Render(-1, sse.Event{
+					Id:    event.ID,
+					Event: event.Type,
+					Data:  toEvent(event),
+				})
+				c.Writer.Flush()
+			case <-ticker.C:
+				if _, err := c.Writer.WriteString(": keep-alive\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// @Tags Stream
+// @Router /api/v1/stream/ws [get]
+// @Summary Balance Event WebSocket
+// @Description WebSocket of balance changes and new transactions of the authenticated user as JSON messages, resumes after lastEventId
+// @Security BearerAuth
+// @Param lastEventId query string false "id of the last received event"
+// @Success 101 {object} Event "switching protocols"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 500 {object} v1.Problem "internal server error"
+func (h *StreamHandler) WebSocket() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "StreamHandler.WebSocket", "handler")
+		defer span.End()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// subscribe before the upgrade, so errors are still answered with a status code
+		events, ok := h.subscribe(ctx, c, c.Query("lastEventId"))
+		if !ok {
+			return
+		}
+
+		conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// the upgrader has answered the request already
+			tracing.CaptureError(ctx, err)
+			return
+		}
+		defer conn.Close()
+
+		// clients only send control frames, reading ends when the client goes away
+		conn.SetReadLimit(512)
+		_ = conn.SetReadDeadline(time.Now().Add(2 * h.keepAlive))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(2 * h.keepAlive))
+		})
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(h.keepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					// ask the client to reconnect with its last event id
+					_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "fell behind, resume from last event id"), time.Now().Add(writeWait))
+					return
+				}
+
+				_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+				if err := conn.WriteJSON(toEvent(event)); err != nil {
+					return
+				}
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// subscribe answers the request with an error status when the subscription fails
+func (h *StreamHandler) subscribe(ctx context.Context, c *gin.Context, lastEventID string) (<-chan *redisModel.BalanceEvent, bool) {
+	events, err := h.streamService.Subscribe(ctx, c.GetUint("authedUserId"), lastEventID)
+	if err != nil {
+		v1.AbortWithError(c, err)
+		return nil, false
+	}
+
+	return events, true
+}
+
+func toEvent(event *redisModel.BalanceEvent) *Event {
+	data := &Event{
+		ID:      event.ID,
+		Type:    event.Type,
+		Balance: event.Balance,
+	}
+	if t := event.Transaction; t != nil {
+		data.Transaction = &Transaction{
+			ID:              t.ID,
+			FromUserID:      t.FromUserID,
+			ToUserID:        t.ToUserID,
+			Amount:          t.Amount,
+			Fee:             t.Fee,
+			TransactionType: t.TransactionType,
+			Details:         t.Details,
+			CreatedAt:       t.CreatedAt,
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,25 @@
+package stream
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Event struct {
+	ID          string          `json:"id"` // pass as Last-Event-ID or lastEventId to resume after this event
+	Type        string          `json:"type"`
+	Balance     decimal.Decimal `json:"balance"`
+	Transaction *Transaction    `json:"transaction"`
+}
+
+type Transaction struct {
+	ID              uint            `json:"id"`
+	FromUserID      uint            `json:"fromUserId"`
+	ToUserID        uint            `json:"toUserId"`
+	Amount          decimal.Decimal `json:"amount"`
+	Fee             decimal.Decimal `json:"fee"`
+	TransactionType string          `json:"transactionType"`
+	Details         string          `json:"details"`
+	CreatedAt       time.Time       `json:"createdAt"`
+}
@@ -5,133 +5,197 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	v1 "banking/app/api/restful/v1"
-	transactionRepo "banking/app/repo/mysql/transaction"
+	fraudSrv "banking/app/service/fraud"
 	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shopspring/decimal"
-	"go.elastic.co/apm/v2"
 )
 
+// preferAsync in the Prefer header asks for a transfer to be queued instead of executed in the request, see RFC 7240
+const preferAsync = "respond-async"
+
 type TransactionHandler struct {
-	transactionService domain.ITransactionService
+	transactionService   domain.ITransactionService
+	transferQueueService domain.ITransferQueueService
+	aliasService         domain.IAliasService
 }
 
-func NewTransactionHandler(TransactionService domain.ITransactionService) domain.ITransactionHandler {
+func NewTransactionHandler(TransactionService domain.ITransactionService, TransferQueueService domain.ITransferQueueService, AliasService domain.IAliasService) domain.ITransactionHandler {
 	return &TransactionHandler{
-		transactionService: TransactionService,
+		transactionService:   TransactionService,
+		transferQueueService: TransferQueueService,
+		aliasService:         AliasService,
 	}
 }
 
 func (h *TransactionHandler) Transfer() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		span, ctx := apm.StartSpan(c.Request.Context(), "TransactionHandler.Transfer", "handler")
+		span, ctx := tracing.StartSpan(c.Request.Context(), "TransactionHandler.Transfer", "handler")
 		defer span.End()
 
 		var input struct {
-			FromUserID uint    `json:"fromUserId" binding:"required,min=1,number"`
-			ToUserID   uint    `json:"toUserId" binding:"required,min=1,number"`
-			Amount     float64 `json:"amount" binding:"required,gt=0,number"`
+			FromUserID  uint    `json:"fromUserId" binding:"required,min=1,number"`
+			ToUserID    uint    `json:"toUserId" binding:"required_without=ToAlias,omitempty,min=1,number"`
+			ToAlias     string  `json:"toAlias" binding:"required_without=ToUserID,max=255"` // email, phone number or handle of the payee
+			Amount      float64 `json:"amount" binding:"required,gt=0,number"`
+			CallbackURL string  `json:"callbackUrl"` // async only, posted the outcome
+			ReferenceReq
 		}
 
 		if err := c.ShouldBindJSON(&input); err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusBadRequest, &v1.ErrResponse{
-				Msg: err.Error(),
-			})
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		async := prefersAsync(c.GetHeader("Prefer"))
+		if input.CallbackURL != "" && !async {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "callbackUrl requires Prefer: respond-async")
+			return
+		}
+
+		if input.ToUserID != 0 && input.ToAlias != "" {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "toUserId and toAlias should not both be set")
 			return
 		}
 
 		if input.FromUserID != c.GetUint("authedUserId") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, &v1.ErrResponse{
-				Msg: "fromUserId is not authorized",
-			})
+			v1.AbortWithProblem(c, domain.CodeForbidden, "fromUserId is not authorized")
 			return
 		}
 
+		// the alias is resolved here, the transfer and its queue only know user ids
+		if input.ToAlias != "" {
+			payee, err := h.aliasService.Resolve(ctx, input.ToAlias)
+			if err != nil {
+				v1.AbortWithError(c, err)
+				return
+			}
+			input.ToUserID = payee.UserID
+		}
+
 		if input.FromUserID == input.ToUserID {
-			c.AbortWithStatusJSON(http.StatusBadRequest, &v1.ErrResponse{
-				Msg: "fromUserId and toUserId should not be the same",
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "fromUserId and toUserId should not be the same")
+			return
+		}
+
+		if async {
+			request, err := h.transferQueueService.Enqueue(ctx, input.FromUserID, input.ToUserID, decimal.NewFromFloat(input.Amount), input.toReference(), input.CallbackURL)
+			if err != nil {
+				v1.AbortWithError(c, err)
+				return
+			}
+
+			c.Header("Preference-Applied", preferAsync)
+			c.Header("Location", fmt.Sprintf("%s/%d", c.Request.URL.Path, request.ID))
+			c.JSON(http.StatusAccepted, &TransferRequestResp{
+				Data: toTransferRequest(request),
 			})
 			return
 		}
 
-		transaction, err := h.transactionService.Transfer(ctx, input.FromUserID, input.ToUserID, decimal.NewFromFloat(input.Amount))
+		transaction, err := h.transactionService.Transfer(ctx, input.FromUserID, input.ToUserID, decimal.NewFromFloat(input.Amount), input.toReference())
 		if err != nil {
-			if errors.Is(err, transactionRepo.ErrInsufficientBalance) {
-				apm.CaptureError(ctx, err).Send()
-				c.AbortWithStatusJSON(http.StatusBadRequest, &v1.ErrResponse{
-					Msg: transactionRepo.ErrInsufficientBalance.Error(),
+			var screeningErr *fraudSrv.ScreeningError
+			// held transfers are accepted, blocked ones are answered as errors below
+			if errors.As(err, &screeningErr) && !errors.Is(err, fraudSrv.ErrTransferBlocked) {
+				c.JSON(http.StatusAccepted, &TransferHeldResp{
+					Data: &TransferHeld{
+						ReviewID: screeningErr.Review.ID,
+						Status:   screeningErr.Review.Status,
+						Msg:      fraudSrv.ErrTransferUnderReview.Error(),
+					},
 				})
 				return
 			}
 
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, &v1.ErrResponse{
-				Msg: err.Error(),
-			})
+			v1.AbortWithError(c, err)
 			return
 		}
 
 		c.JSON(http.StatusOK, &TransferResp{
 			Data: &Transaction{
-				FromUserID:      transaction.FromUserID,
-				FromUserBalance: transaction.FromUserBalance,
-				ToUserID:        transaction.ToUserID,
-				Amount:          transaction.Amount,
-				TransactionType: transaction.TransactionType,
-				Details:         transaction.Details,
+				FromUserID:        transaction.FromUserID,
+				FromUserBalance:   transaction.FromUserBalance,
+				ToUserID:          transaction.ToUserID,
+				Amount:            transaction.Amount,
+				Fee:               transaction.Fee,
+				FeeBreakdown:      toFeeBreakdown(transaction.FeeBreakdown),
+				TransactionType:   transaction.TransactionType,
+				Details:           transaction.Details,
+				ExternalReference: transaction.ExternalReference,
 			},
 		})
 	}
 }
 
+// GetTransferRequest returns the status of an async transfer of the authenticated user
+func (h *TransactionHandler) GetTransferRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "TransactionHandler.GetTransferRequest", "handler")
+		defer span.End()
+
+		transferID, err := strconv.ParseUint(c.Param("transferId"), 10, 64)
+		if err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid transfer id")
+			return
+		}
+
+		request, err := h.transferQueueService.GetTransferRequest(ctx, c.GetUint("authedUserId"), uint(transferID))
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &TransferRequestResp{
+			Data: toTransferRequest(request),
+		})
+	}
+}
+
 func (h *TransactionHandler) Deposit() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		span, ctx := apm.StartSpan(c.Request.Context(), "TransactionHandler.Deposit", "handler")
+		span, ctx := tracing.StartSpan(c.Request.Context(), "TransactionHandler.Deposit", "handler")
 		defer span.End()
 
 		var input struct {
 			UserID uint    `json:"userId" binding:"required,min=1,number"`
 			Amount float64 `json:"amount" binding:"required,gt=0,number"`
+			ReferenceReq
 		}
 
 		if err := c.ShouldBindJSON(&input); err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusBadRequest, &v1.ErrResponse{
-				Msg: err.Error(),
-			})
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, err.Error())
 			return
 		}
 
 		authedUserID := c.GetUint("authedUserId")
 		if input.UserID != authedUserID {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, &v1.ErrResponse{
-				Msg: "userId is not authorized",
-			})
+			v1.AbortWithProblem(c, domain.CodeForbidden, "userId is not authorized")
 			return
 		}
 
-		transaction, err := h.transactionService.Deposit(ctx, input.UserID, decimal.NewFromFloat(input.Amount))
+		transaction, err := h.transactionService.Deposit(ctx, input.UserID, decimal.NewFromFloat(input.Amount), input.toReference())
 		if err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, &v1.ErrResponse{
-				Msg: err.Error(),
-			})
+			v1.AbortWithError(c, err)
 			return
 		}
 
 		c.JSON(http.StatusOK, &DepositResp{
 			Data: &Transaction{
-				FromUserID:      transaction.FromUserID,
-				FromUserBalance: transaction.FromUserBalance,
-				ToUserID:        transaction.ToUserID,
-				ToUserBalance:   transaction.ToUserBalance,
-				Amount:          transaction.Amount,
-				TransactionType: transaction.TransactionType,
-				Details:         transaction.Details,
+				FromUserID:        transaction.FromUserID,
+				FromUserBalance:   transaction.FromUserBalance,
+				ToUserID:          transaction.ToUserID,
+				ToUserBalance:     transaction.ToUserBalance,
+				Amount:            transaction.Amount,
+				TransactionType:   transaction.TransactionType,
+				Details:           transaction.Details,
+				ExternalReference: transaction.ExternalReference,
 			},
 		})
 	}
@@ -139,56 +203,44 @@ func (h *TransactionHandler) Deposit() gin.HandlerFunc {
 
 func (h *TransactionHandler) Withdraw() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		span, ctx := apm.StartSpan(c.Request.Context(), "TransactionHandler.Withdraw", "handler")
+		span, ctx := tracing.StartSpan(c.Request.Context(), "TransactionHandler.Withdraw", "handler")
 		defer span.End()
 
 		var input struct {
 			UserID uint    `json:"userId" binding:"required,min=1,number"`
 			Amount float64 `json:"amount" binding:"required,gt=0,number"`
+			ReferenceReq
 		}
 
 		if err := c.ShouldBindJSON(&input); err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusBadRequest, &v1.ErrResponse{
-				Msg: err.Error(),
-			})
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, err.Error())
 			return
 		}
 
 		authedUserID := c.GetUint("authedUserId")
 		if input.UserID != authedUserID {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, &v1.ErrResponse{
-				Msg: "userId is not authorized",
-			})
+			v1.AbortWithProblem(c, domain.CodeForbidden, "userId is not authorized")
 			return
 		}
 
-		transaction, err := h.transactionService.Withdraw(ctx, input.UserID, decimal.NewFromFloat(input.Amount))
+		transaction, err := h.transactionService.Withdraw(ctx, input.UserID, decimal.NewFromFloat(input.Amount), input.toReference())
 		if err != nil {
-			if errors.Is(err, transactionRepo.ErrInsufficientBalance) {
-				apm.CaptureError(ctx, err).Send()
-				c.AbortWithStatusJSON(http.StatusBadRequest, &v1.ErrResponse{
-					Msg: transactionRepo.ErrInsufficientBalance.Error(),
-				})
-				return
-			}
-
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, &v1.ErrResponse{
-				Msg: err.Error(),
-			})
+			v1.AbortWithError(c, err)
 			return
 		}
 
 		c.JSON(http.StatusOK, &WithdrawResp{
 			Data: &Transaction{
-				FromUserID:      transaction.FromUserID,
-				FromUserBalance: transaction.FromUserBalance,
-				ToUserID:        transaction.ToUserID,
-				ToUserBalance:   transaction.ToUserBalance,
-				Amount:          transaction.Amount,
-				TransactionType: transaction.TransactionType,
-				Details:         transaction.Details,
+				FromUserID:        transaction.FromUserID,
+				FromUserBalance:   transaction.FromUserBalance,
+				ToUserID:          transaction.ToUserID,
+				ToUserBalance:     transaction.ToUserBalance,
+				Amount:            transaction.Amount,
+				Fee:               transaction.Fee,
+				FeeBreakdown:      toFeeBreakdown(transaction.FeeBreakdown),
+				TransactionType:   transaction.TransactionType,
+				Details:           transaction.Details,
+				ExternalReference: transaction.ExternalReference,
 			},
 		})
 	}
@@ -196,7 +248,7 @@ func (h *TransactionHandler) Withdraw() gin.HandlerFunc {
 
 func (h *TransactionHandler) GetTransactions() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		span, ctx := apm.StartSpan(c.Request.Context(), "TransactionHandler.GetTransactions", "handler")
+		span, ctx := tracing.StartSpan(c.Request.Context(), "TransactionHandler.GetTransactions", "handler")
 		defer span.End()
 
 		userId := c.Param("userId")
@@ -204,40 +256,40 @@ func (h *TransactionHandler) GetTransactions() gin.HandlerFunc {
 
 		userIdUint, err := strconv.ParseUint(userId, 10, 64)
 		if err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusBadRequest, &v1.ErrResponse{
-				Msg: "invalid user id",
-			})
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid user id")
 			return
 		}
 
 		if uint(userIdUint) != authedUserID {
-			apm.CaptureError(ctx, fmt.Errorf("unauthorized")).Send()
-			c.AbortWithStatusJSON(http.StatusUnauthorized, &v1.ErrResponse{
-				Msg: "unauthorized",
-			})
+			v1.AbortWithProblem(c, domain.CodeForbidden, "unauthorized")
 			return
 		}
 
-		transactions, err := h.transactionService.GetTransactions(ctx, uint(userIdUint))
+		var transactions []*mysqlModel.Transaction
+		if reference := c.Query("reference"); reference != "" {
+			transactions, err = h.transactionService.GetTransactionsByReference(ctx, uint(userIdUint), reference)
+		} else {
+			transactions, err = h.transactionService.GetTransactions(ctx, uint(userIdUint))
+		}
 		if err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, &v1.ErrResponse{
-				Msg: err.Error(),
-			})
+			v1.AbortWithError(c, err)
 			return
 		}
 
 		transactionList := make([]*Transaction, 0, len(transactions))
 		for _, t := range transactions {
 			transactionList = append(transactionList, &Transaction{
-				FromUserID:      t.FromUserID,
-				FromUserBalance: t.FromUserBalance,
-				ToUserID:        t.ToUserID,
-				ToUserBalance:   t.ToUserBalance,
-				Amount:          t.Amount,
-				TransactionType: t.TransactionType,
-				Details:         t.Details,
+				FromUserID:        t.FromUserID,
+				FromUserBalance:   t.FromUserBalance,
+				ToUserID:          t.ToUserID,
+				ToUserBalance:     t.ToUserBalance,
+				Amount:            t.Amount,
+				Fee:               t.Fee,
+				TransactionType:   t.TransactionType,
+				Details:           t.Details,
+				ExternalReference: t.ExternalReference,
+				FromUserName:      t.FromUserName,
+				ToUserName:        t.ToUserName,
 			})
 		}
 		c.JSON(http.StatusOK, &GetTransactionsResp{
@@ -245,3 +297,82 @@ func (h *TransactionHandler) GetTransactions() gin.HandlerFunc {
 		})
 	}
 }
+
+func (h *TransactionHandler) QuoteFee() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span, ctx := tracing.StartSpan(c.Request.Context(), "TransactionHandler.QuoteFee", "handler")
+		defer span.End()
+
+		var input struct {
+			TransactionType mysqlModel.TransactionType `form:"transactionType" binding:"required,oneof=transfer withdraw"`
+			Amount          float64                    `form:"amount" binding:"required,gt=0,number"`
+		}
+
+		if err := c.ShouldBindQuery(&input); err != nil {
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		amount := decimal.NewFromFloat(input.Amount)
+		fee, err := h.transactionService.QuoteFee(ctx, c.GetUint("authedUserId"), input.TransactionType, amount)
+		if err != nil {
+			v1.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &QuoteFeeResp{
+			Data: &FeeQuote{
+				TransactionType: input.TransactionType,
+				Amount:          amount,
+				Fee:             toFeeBreakdown(fee),
+				TotalDebit:      amount.Add(fee.Total),
+			},
+		})
+	}
+}
+
+func toFeeBreakdown(fee *mysqlModel.FeeBreakdown) *FeeBreakdown {
+	if fee == nil {
+		return nil
+	}
+
+	return &FeeBreakdown{
+		Tier:       fee.Tier,
+		Flat:       fee.Flat,
+		Percentage: fee.Percentage,
+		Tiered:     fee.Tiered,
+		Adjustment: fee.Adjustment,
+		Total:      fee.Total,
+	}
+}
+
+func toTransferRequest(request *mysqlModel.TransferRequest) *TransferRequest {
+	return &TransferRequest{
+		TransferID:        request.ID,
+		Status:            request.Status,
+		FromUserID:        request.FromUserID,
+		ToUserID:          request.ToUserID,
+		Amount:            request.Amount,
+		Memo:              request.Memo,
+		ExternalReference: request.ExternalReference,
+		TransactionID:     request.TransactionID,
+		ReviewID:          request.ReviewID,
+		ErrorCode:         request.ErrorCode,
+		Error:             request.ErrorMessage,
+		CreatedAt:         request.CreatedAt,
+		UpdatedAt:         request.UpdatedAt,
+	}
+}
+
+// prefersAsync reports whether respond-async is one of the preferences of a Prefer header
+func prefersAsync(prefer string) bool {
+	for _, preference := range strings.Split(prefer, ",") {
+		token, _, _ := strings.Cut(preference, ";")
+		token, _, _ = strings.Cut(token, "=")
+		if strings.EqualFold(strings.TrimSpace(token), preferAsync) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,25 +1,90 @@
 package transaction
 
 import (
+	"time"
+
 	"banking/model/mysql"
 
 	"github.com/shopspring/decimal"
 )
 
 type Transaction struct {
-	FromUserID      uint                  `json:"fromUserId"`
-	FromUserBalance decimal.Decimal       `json:"fromUserBalance"`
-	ToUserID        uint                  `json:"toUserId"`
-	ToUserBalance   decimal.Decimal       `json:"toUserBalance,omitempty"`
-	Amount          decimal.Decimal       `json:"amount"`
+	FromUserID        uint                  `json:"fromUserId"`
+	FromUserBalance   decimal.Decimal       `json:"fromUserBalance"`
+	ToUserID          uint                  `json:"toUserId"`
+	ToUserBalance     decimal.Decimal       `json:"toUserBalance,omitempty"`
+	Amount            decimal.Decimal       `json:"amount"`
+	Fee               decimal.Decimal       `json:"fee"`
+	FeeBreakdown      *FeeBreakdown         `json:"feeBreakdown,omitempty"`
+	TransactionType   mysql.TransactionType `json:"transactionType"`
+	Details           string                `json:"details"`
+	ExternalReference string                `json:"externalReference,omitempty"`
+	FromUserName      string                `json:"fromUserName,omitempty"` // set in the transaction history
+	ToUserName        string                `json:"toUserName,omitempty"`
+}
+
+// ReferenceReq is the optional reference of a transfer, deposit or withdrawal, the memo is returned as details
+type ReferenceReq struct {
+	Memo              string `json:"memo"`
+	ExternalReference string `json:"externalReference"`
+}
+
+func (r *ReferenceReq) toReference() *mysql.TransactionReference {
+	return &mysql.TransactionReference{Memo: r.Memo, ExternalReference: r.ExternalReference}
+}
+
+type FeeBreakdown struct {
+	Tier       string          `json:"tier"`
+	Flat       decimal.Decimal `json:"flat"`
+	Percentage decimal.Decimal `json:"percentage"`
+	Tiered     decimal.Decimal `json:"tiered"`
+	Adjustment decimal.Decimal `json:"adjustment"`
+	Total      decimal.Decimal `json:"total"`
+}
+
+type FeeQuote struct {
 	TransactionType mysql.TransactionType `json:"transactionType"`
-	Details         string                `json:"details"`
+	Amount          decimal.Decimal       `json:"amount"`
+	Fee             *FeeBreakdown         `json:"fee"`
+	TotalDebit      decimal.Decimal       `json:"totalDebit"` // amount plus fee
 }
 
 type TransferResp struct {
 	Data *Transaction `json:"data"`
 }
 
+type TransferHeld struct {
+	ReviewID uint                    `json:"reviewId"`
+	Status   mysql.FraudReviewStatus `json:"status"`
+	Msg      string                  `json:"msg"`
+}
+
+type TransferHeldResp struct {
+	Data *TransferHeld `json:"data"`
+}
+
+// TransferRequest is an async transfer, TransactionID is set once it completed and ReviewID once it
+// was held for fraud review. A failed transfer carries the code and message of its error
+type TransferRequest struct {
+	TransferID        uint                        `json:"transferId"`
+	Status            mysql.TransferRequestStatus `json:"status"`
+	FromUserID        uint                        `json:"fromUserId"`
+	ToUserID          uint                        `json:"toUserId"`
+	Amount            decimal.Decimal             `json:"amount"`
+	Memo              string                      `json:"memo,omitempty"`
+	ExternalReference string                      `json:"externalReference,omitempty"`
+	TransactionID     *uint                       `json:"transactionId,omitempty"`
+	ReviewID          *uint                       `json:"reviewId,omitempty"`
+	ErrorCode         string                      `json:"errorCode,omitempty"`
+	Error             string                      `json:"error,omitempty"`
+	CreatedAt         time.Time                   `json:"createdAt"`
+	UpdatedAt         time.Time                   `json:"updatedAt"`
+}
+
+type TransferRequestResp struct {
+	Data *TransferRequest `json:"data"`
+}
+
 type DepositResp struct {
 	Data *Transaction `json:"data"`
 }
@@ -31,3 +96,7 @@ type WithdrawResp struct {
 type GetTransactionsResp struct {
 	Data []*Transaction `json:"data"`
 }
+
+type QuoteFeeResp struct {
+	Data *FeeQuote `json:"data"`
+}
@@ -0,0 +1,60 @@
+package transaction_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "banking/app/api/restful/v1"
+	transactionHdl "banking/app/api/restful/v1/handler/transaction"
+	transactionRepo "banking/app/repo/mysql/transaction"
+	"banking/domain"
+	domainMock "banking/domain/mock"
+	"banking/global"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func initialTransactionHandler(t *testing.T) (*gin.Context, *httptest.ResponseRecorder, *domainMock.MockITransactionService) {
+	gin.SetMode(gin.TestMode)
+	global.Logger = zap.NewNop().Sugar()
+
+	ctrl := gomock.NewController(t)
+	mockTransactionService := domainMock.NewMockITransactionService(ctrl)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("authedUserId", uint(1))
+
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	return c, w, mockTransactionService
+}
+
+func Test_Transfer_UserNotFound(t *testing.T) {
+	c, w, mockTransactionService := initialTransactionHandler(t)
+
+	mockTransactionService.EXPECT().
+		Transfer(gomock.Any(), uint(1), uint(99), gomock.Any(), gomock.Any()).
+		Return(nil, transactionRepo.ErrUserNotFound)
+
+	reqBody, err := json.Marshal(map[string]any{"fromUserId": 1, "toUserId": 99, "amount": 10})
+	require.NoError(t, err)
+	c.Request = httptest.NewRequest("POST", "/api/v1/transaction/transfer", bytes.NewReader(reqBody))
+
+	hdl := transactionHdl.NewTransactionHandler(mockTransactionService, nil, nil)
+	hdl.Transfer()(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	var problem v1.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, domain.CodeUserNotFound, problem.Code)
+}
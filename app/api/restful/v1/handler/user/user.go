@@ -9,12 +9,13 @@ import (
 
 	v1 "banking/app/api/restful/v1"
 	userRepo "banking/app/repo/mysql/user"
+	userSrv "banking/app/service/user"
 	"banking/domain"
 	mysqlModel "banking/model/mysql"
+	"banking/tracing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shopspring/decimal"
-	"go.elastic.co/apm/v2"
 )
 
 type UserHandler struct {
@@ -37,19 +38,17 @@ func NewUserHandler(UserService domain.IUserService, APIKeyService domain.IAPIKe
 // @Produce json
 // @Param CreateUserReq body CreateUserReq user "create user request"
 // @Success 201 {object} CreateUserResp "success created user"
-// @Failure 400 {object} v1.ErrResponse "bad request"
-// @Failure 500 {object} v1.ErrResponse "internal server error"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 403 {object} v1.Problem "rejected by compliance screening"
+// @Failure 500 {object} v1.Problem "internal server error"
 func (h *UserHandler) CreateUser() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		span, ctx := apm.StartSpan(c.Request.Context(), "UserHandler.CreateUser", "handler")
+		span, ctx := tracing.StartSpan(c.Request.Context(), "UserHandler.CreateUser", "handler")
 		defer span.End()
 
 		var input CreateUserReq
 		if err := c.ShouldBindJSON(&input); err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusBadRequest, &v1.ErrResponse{
-				Msg: err.Error(),
-			})
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -60,18 +59,7 @@ func (h *UserHandler) CreateUser() gin.HandlerFunc {
 			Password: input.Password,
 		}
 		if err := h.userService.CreateUser(ctx, user); err != nil {
-			if errors.Is(err, userRepo.ErrUserExisted) {
-				apm.CaptureError(ctx, err).Send()
-				c.AbortWithStatusJSON(http.StatusBadRequest, &v1.ErrResponse{
-					Msg: userRepo.ErrUserExisted.Error(),
-				})
-				return
-			}
-
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, &v1.ErrResponse{
-				Msg: err.Error(),
-			})
+			v1.AbortWithError(c, err)
 			return
 		}
 
@@ -94,21 +82,27 @@ func (h *UserHandler) CreateUser() gin.HandlerFunc {
 // @Produce json
 // @Param LoginReq body LoginReq true "login request"
 // @Success 200 {object} LoginResp "success"
-// @Failure 400 {object} v1.ErrResponse "bad request"
-// @Failure 401 {object} v1.ErrResponse "unauthorized"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 401 {object} v1.Problem "unauthorized"
 func (h *UserHandler) Login() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var input LoginReq
 
 		// Bind JSON input
 		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid input"})
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "Invalid input")
 			return
 		}
 
 		token, err := h.userService.Login(c.Request.Context(), input.Email, input.Password)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"msg": "Invalid credentials"})
+			// unknown emails are not told apart from wrong passwords
+			if errors.Is(err, userRepo.ErrUserNotFound) || errors.Is(err, userSrv.ErrPasswordIncorrect) {
+				v1.AbortWithProblem(c, domain.CodeInvalidCredentials, "Invalid credentials")
+				return
+			}
+
+			v1.AbortWithError(c, err)
 			return
 		}
 
@@ -128,11 +122,12 @@ func (h *UserHandler) Login() gin.HandlerFunc {
 // @Security BearerAuth
 // @Param userId path uint true "user id"
 // @Success 200 {object} GetUsersResp "success"
-// @Failure 400 {object} v1.ErrResponse "bad request"
-// @Failure 500 {object} v1.ErrResponse "internal server error"
+// @Failure 400 {object} v1.Problem "bad request"
+// @Failure 403 {object} v1.Problem "forbidden"
+// @Failure 500 {object} v1.Problem "internal server error"
 func (h *UserHandler) GetUsers() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		span, ctx := apm.StartSpan(c.Request.Context(), "UserHandler.GetUsers", "handler")
+		span, ctx := tracing.StartSpan(c.Request.Context(), "UserHandler.GetUsers", "handler")
 		defer span.End()
 
 		userId := c.Param("userId")
@@ -140,23 +135,18 @@ func (h *UserHandler) GetUsers() gin.HandlerFunc {
 
 		userIdUint, err := strconv.ParseUint(userId, 10, 64)
 		if err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid user id")
 			return
 		}
 
 		if !c.GetBool("isAdmin") && authedUserId != uint(userIdUint) {
-			apm.CaptureError(ctx, fmt.Errorf("unauthorized")).Send()
-			c.AbortWithStatusJSON(http.StatusUnauthorized, &v1.ErrResponse{
-				Msg: "unauthorized",
-			})
+			v1.AbortWithProblem(c, domain.CodeForbidden, "unauthorized")
 			return
 		}
 
 		users, err := h.userService.GetUsers(ctx, uint(userIdUint))
 		if err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			v1.AbortWithError(c, err)
 			return
 		}
 
@@ -183,17 +173,16 @@ func (h *UserHandler) GetUsers() gin.HandlerFunc {
 // @Produce json
 // @Security BearerAuth
 // @Success 201 {object} CreateAPIKeyResp "success created api key"
-// @Failure 500 {object} v1.ErrResponse "internal server error"
+// @Failure 500 {object} v1.Problem "internal server error"
 func (h *UserHandler) CreateAPIKey() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		span, ctx := apm.StartSpan(c.Request.Context(), "UserHandler.CreateAPIKey", "handler")
+		span, ctx := tracing.StartSpan(c.Request.Context(), "UserHandler.CreateAPIKey", "handler")
 		defer span.End()
 
 		authedUserId := c.GetUint("authedUserId")
 		apiKey, secretKey, err := h.apiKeyService.CreateAPIKey(ctx, authedUserId)
 		if err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			v1.AbortWithError(c, err)
 			return
 		}
 
@@ -209,7 +198,7 @@ func (h *UserHandler) CreateAPIKey() gin.HandlerFunc {
 
 func (h *UserHandler) DeleteAPIKey() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		span, ctx := apm.StartSpan(c.Request.Context(), "UserHandler.DeleteAPIKey", "handler")
+		span, ctx := tracing.StartSpan(c.Request.Context(), "UserHandler.DeleteAPIKey", "handler")
 		defer span.End()
 
 		input := struct {
@@ -217,33 +206,32 @@ func (h *UserHandler) DeleteAPIKey() gin.HandlerFunc {
 		}{}
 
 		if err := c.ShouldBindJSON(&input); err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, err.Error())
 			return
 		}
 
 		authedUserId := c.GetUint("authedUserId")
 		if err := h.apiKeyService.DeleteAPIKey(ctx, authedUserId, input.Key); err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			v1.AbortWithError(c, err)
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"msg": "API key deleted"})
+		c.JSON(http.StatusOK, &v1.MsgResponse{
+			Msg: "API key deleted",
+		})
 	}
 }
 
 func (h *UserHandler) GetAPIKeys() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		span, ctx := apm.StartSpan(c.Request.Context(), "UserHandler.GetAPIKeys", "handler")
+		span, ctx := tracing.StartSpan(c.Request.Context(), "UserHandler.GetAPIKeys", "handler")
 		defer span.End()
 
 		userId := c.Query("userId")
 		fmt.Printf("userId: %s\n", userId)
 		userIdUint, err := strconv.ParseUint(userId, 10, 64)
 		if err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid user id")
 			return
 		}
 
@@ -251,22 +239,19 @@ func (h *UserHandler) GetAPIKeys() gin.HandlerFunc {
 		authedUserId := c.GetUint("authedUserId")
 
 		if !isAdmin && authedUserId != uint(userIdUint) {
-			apm.CaptureError(ctx, fmt.Errorf("unauthorized")).Send()
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			v1.AbortWithProblem(c, domain.CodeForbidden, "unauthorized")
 			return
 		}
 
 		key, err := url.QueryUnescape(c.Query("key"))
 		if err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid key"})
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "invalid key")
 			return
 		}
 
 		apiKeys, err := h.apiKeyService.GetAPIKeys(ctx, uint(userIdUint), key)
 		if err != nil {
-			apm.CaptureError(ctx, err).Send()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			v1.AbortWithError(c, err)
 			return
 		}
 
@@ -11,20 +11,18 @@ import (
 	userHdl "banking/app/api/restful/v1/handler/user"
 	domainMock "banking/domain/mock"
 	mysqlModel "banking/model/mysql"
+	"banking/tracing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
-	"go.elastic.co/apm/v2"
 )
 
 func initialUserHandler(t *testing.T) (*gin.Context, *httptest.ResponseRecorder, *domainMock.MockIUserService, *domainMock.MockIAPIKeyService) {
 	gin.SetMode(gin.TestMode)
 
-	// Initialize APM tracer
-	tracer := apm.DefaultTracer()
-	router.InitRouter(gin.Default(), nil, nil, nil, tracer)
+	router.InitRouter(gin.Default(), &router.Services{}, tracing.NewNoopTracer())
 
 	ctrl := gomock.NewController(t)
 	mockUserService := domainMock.NewMockIUserService(ctrl)
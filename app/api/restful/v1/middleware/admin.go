@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	v1 "banking/app/api/restful/v1"
+	"banking/domain"
+)
+
+// AdminMiddleware restricts routes to administrators, it must run after JWTAuthMiddleware
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("isAdmin") {
+			v1.AbortWithProblem(c, domain.CodeForbidden, "Admin permission required")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuditorMiddleware restricts routes to auditors, it must run after JWTAuthMiddleware
+func AuditorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("isAuditor") {
+			v1.AbortWithProblem(c, domain.CodeForbidden, "Auditor permission required")
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,47 +1,87 @@
 package middleware
 
 import (
-	"net/http"
 	"strconv"
 	"strings"
 
+	v1 "banking/app/api/restful/v1"
 	"banking/domain"
+	"banking/metrics"
 	"banking/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	queryTokenParam = "access_token"
+	queryTokenKey   = "queryToken"
+)
+
 // JWTAuthMiddleware is a middleware to protect routes with JWT authentication
 func JWTAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get the token from the Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"msg": "Authorization token required"})
-			c.Abort()
+			metrics.ObserveAuthFailure(utils.AuthMethodJWT)
+			v1.AbortWithProblem(c, domain.CodeUnauthorized, "Authorization token required")
 			return
 		}
 
 		// Bearer token format
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"msg": "Authorization token format is Bearer {token}"})
-			c.Abort()
+			metrics.ObserveAuthFailure(utils.AuthMethodJWT)
+			v1.AbortWithProblem(c, domain.CodeUnauthorized, "Authorization token format is Bearer {token}")
 			return
 		}
 
 		// Parse the token
 		claims, err := utils.ParseJWT(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"msg": "Invalid or expired token"})
-			c.Abort()
+			metrics.ObserveAuthFailure(utils.AuthMethodJWT)
+			v1.AbortWithProblem(c, domain.CodeUnauthorized, "Invalid or expired token")
 			return
 		}
 
 		// Set user info in context
 		c.Set("authedUserId", claims.UserID)
 		c.Set("isAdmin", claims.IsAdmin)
+		c.Set("isAuditor", claims.IsAuditor)
 		c.Set("email", claims.Email)
+		c.Request = c.Request.WithContext(utils.ContextWithActor(c.Request.Context(), &utils.Actor{
+			UserID:     claims.UserID,
+			Email:      claims.Email,
+			AuthMethod: utils.AuthMethodJWT,
+			IsAdmin:    claims.IsAdmin,
+		}))
+		c.Next()
+	}
+}
+
+// StripQueryTokenMiddleware removes the access_token query parameter from the request URL and keeps
+// it in the context, so the access log and traces never record the token. It runs before the logger
+func StripQueryTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+		if token := query.Get(queryTokenParam); token != "" {
+			c.Set(queryTokenKey, token)
+			query.Del(queryTokenParam)
+			c.Request.URL.RawQuery = query.Encode()
+			c.Request.RequestURI = c.Request.URL.RequestURI()
+		}
+		c.Next()
+	}
+}
+
+// QueryTokenMiddleware accepts the JWT as the access_token query parameter for clients which
+// cannot set headers, such as the browser EventSource and WebSocket. The parameter is taken by
+// StripQueryTokenMiddleware
+func QueryTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.GetString(queryTokenKey); token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
 		c.Next()
 	}
 }
@@ -57,14 +97,14 @@ func APIKeyAuthMiddleware(
 		userIDStr := c.GetHeader("X-User-Id")
 		userID, err := strconv.ParseUint(userIDStr, 10, 64)
 		if err != nil || userID == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid User ID"})
-			c.Abort()
+			metrics.ObserveAuthFailure(utils.AuthMethodAPIKey)
+			v1.AbortWithProblem(c, domain.CodeInvalidRequest, "Invalid User ID")
 			return
 		}
 
 		if key == "" || secretKey == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"msg": "API Key and Secret Key are required"})
-			c.Abort()
+			metrics.ObserveAuthFailure(utils.AuthMethodAPIKey)
+			v1.AbortWithProblem(c, domain.CodeUnauthorized, "API Key and Secret Key are required")
 			return
 		}
 
@@ -73,14 +113,20 @@ func APIKeyAuthMiddleware(
 
 		// check if the API key and secret key are valid
 		if err := authService.APIKeyConfirmation(ctx, uint(userID), key, secretKey); err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"msg": "Invalid API Key or Secret Key"})
-			c.Abort()
+			metrics.ObserveAuthFailure(utils.AuthMethodAPIKey)
+			v1.AbortWithProblem(c, domain.CodeUnauthorized, "Invalid API Key or Secret Key")
 			return
 		}
 
 		c.Set("authedUserId", uint(userID))
 		c.Set("apiKey", key)
 		c.Set("secretKey", secretKey)
+		ctx = utils.ContextWithAPIKey(ctx, key)
+		ctx = utils.ContextWithActor(ctx, &utils.Actor{
+			UserID:     uint(userID),
+			AuthMethod: utils.AuthMethodAPIKey,
+		})
+		c.Request = c.Request.WithContext(ctx)
 
 		// Continue processing the request
 		c.Next()
@@ -0,0 +1,36 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"banking/app/api/restful/v1/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_QueryTokenMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var loggedURI, authorization string
+	router := gin.New()
+	router.Use(middleware.StripQueryTokenMiddleware(), func(c *gin.Context) {
+		// what the access log and the tracer see
+		loggedURI = c.Request.RequestURI + " " + c.Request.URL.String()
+		c.Next()
+	})
+	router.GET("/stream", middleware.QueryTokenMiddleware(), func(c *gin.Context) {
+		authorization = c.GetHeader("Authorization")
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/stream?lastEventId=1-0&access_token=secret", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.NotContains(t, loggedURI, "secret")
+	assert.Contains(t, loggedURI, "lastEventId=1-0")
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"banking/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientInfoMiddleware stores the client IP in the request context
+func ClientInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(utils.ContextWithClientIP(c.Request.Context(), c.ClientIP()))
+		c.Next()
+	}
+}
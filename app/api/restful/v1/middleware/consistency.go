@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"strings"
+
+	"banking/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConsistencyMiddleware routes the reads of requests with "X-Consistency: strong" to the master
+func ConsistencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.EqualFold(c.GetHeader(utils.ConsistencyHeader), utils.ConsistencyStrong) {
+			c.Request = c.Request.WithContext(utils.ContextWithStrongConsistency(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
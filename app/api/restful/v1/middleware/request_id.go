@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDMiddleware accepts the X-Request-ID header or generates one, stores it in the request
+// context and the request trace, and returns it in the response. It must run after the tracing middleware
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := utils.RequestIDOrNew(c.GetHeader(utils.RequestIDHeader))
+
+		ctx := utils.ContextWithRequestID(c.Request.Context(), requestID)
+		tracing.SetLabel(ctx, "request_id", requestID)
+
+		c.Header(utils.RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"banking/app/api/restful/v1/middleware"
+	"banking/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		header   string
+		wantKept bool
+	}{
+		{name: "client id is kept", header: "client-id:1", wantKept: true},
+		{name: "missing id is generated", header: "", wantKept: false},
+		{name: "invalid id is replaced", header: "bad id\n", wantKept: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requestID string
+			router := gin.New()
+			router.Use(middleware.RequestIDMiddleware())
+			router.GET("/", func(c *gin.Context) {
+				requestID = utils.RequestIDFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(utils.RequestIDHeader, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.NotEmpty(t, requestID)
+			assert.Equal(t, requestID, w.Header().Get(utils.RequestIDHeader))
+			if tt.wantKept {
+				assert.Equal(t, tt.header, requestID)
+			} else {
+				assert.NotEqual(t, tt.header, requestID)
+				assert.Len(t, requestID, 32)
+			}
+		})
+	}
+}
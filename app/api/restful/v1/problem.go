@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"banking/domain"
+	"banking/global"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of every error response, see RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix turns an error code into the problem type URI
+const problemTypePrefix = "urn:banking:problem:"
+
+// Problem is the body of every error response, clients should branch on Code
+type Problem struct {
+	Type      string           `json:"type"`
+	Title     string           `json:"title"`
+	Status    int              `json:"status"`
+	Detail    string           `json:"detail,omitempty"`
+	Instance  string           `json:"instance,omitempty"`
+	Code      domain.ErrorCode `json:"code"`
+	RequestID string           `json:"requestId,omitempty"`
+}
+
+var statusByCode = map[domain.ErrorCode]int{
+	domain.CodeInvalidRequest: http.StatusBadRequest,
+	domain.CodeUnauthorized:   http.StatusUnauthorized,
+	domain.CodeForbidden:      http.StatusForbidden,
+	domain.CodeNotFound:       http.StatusNotFound,
+	domain.CodeRateLimited:    http.StatusTooManyRequests,
+	domain.CodeInternal:       http.StatusInternalServerError,
+
+	domain.CodeUserExists:         http.StatusBadRequest,
+	domain.CodeUserNotFound:       http.StatusNotFound,
+	domain.CodeInvalidCredentials: http.StatusUnauthorized,
+
+	domain.CodeInsufficientBalance:        http.StatusBadRequest,
+	domain.CodeUnsupportedTransactionType: http.StatusBadRequest,
+	domain.CodeComplianceRejected:         http.StatusForbidden,
+	domain.CodeTransferBlocked:            http.StatusForbidden,
+	domain.CodeTransferUnderReview:        http.StatusConflict,
+
+	domain.CodeTransferRequestNotFound: http.StatusNotFound,
+
+	domain.CodeReviewNotFound:   http.StatusNotFound,
+	domain.CodeReviewNotPending: http.StatusConflict,
+
+	domain.CodeUnsupportedFormat:     http.StatusBadRequest,
+	domain.CodeInvalidStatement:      http.StatusBadRequest,
+	domain.CodeStatementLineNotFound: http.StatusNotFound,
+	domain.CodeTransactionNotFound:   http.StatusNotFound,
+	domain.CodeLineAlreadyReconciled: http.StatusConflict,
+	domain.CodeTransactionMismatch:   http.StatusBadRequest,
+	domain.CodeTransactionClaimed:    http.StatusConflict,
+
+	domain.CodeInvalidEventID: http.StatusBadRequest,
+
+	domain.CodeAliasNotFound:    http.StatusNotFound,
+	domain.CodeAliasTaken:       http.StatusConflict,
+	domain.CodeAliasCodeInvalid: http.StatusBadRequest,
+}
+
+// StatusOf returns the HTTP status of an error code, unknown codes are internal errors
+func StatusOf(code domain.ErrorCode) int {
+	if status, ok := statusByCode[code]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// AbortWithError answers the request with the problem of err. Errors without a code are reported
+// to APM and the log, the client only gets a generic internal error
+func AbortWithError(c *gin.Context, err error) {
+	ctx := c.Request.Context()
+
+	code, detail := domain.CodeInternal, http.StatusText(http.StatusInternalServerError)
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		// the text of wrapping errors adds detail, such as the malformed line of a statement
+		code, detail = domainErr.Code, err.Error()
+	}
+
+	status := StatusOf(code)
+	if status >= http.StatusInternalServerError {
+		tracing.CaptureError(ctx, err)
+		global.LoggerFromContext(ctx).Errorf("%s %s error: %s", c.Request.Method, c.Request.URL.Path, err)
+		detail = http.StatusText(status)
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(status, &Problem{
+		Type:      problemTypePrefix + string(code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestID: utils.RequestIDFromContext(ctx),
+	})
+}
+
+// AbortWithProblem answers the request with a problem found by the handler, such as invalid input
+func AbortWithProblem(c *gin.Context, code domain.ErrorCode, detail string) {
+	AbortWithError(c, domain.NewError(code, detail))
+}
@@ -0,0 +1,97 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "banking/app/api/restful/v1"
+	"banking/domain"
+	"banking/global"
+	"banking/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func initialProblemContext() (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	global.Logger = zap.NewNop().Sugar()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/transaction/transfer", nil)
+	c.Request = c.Request.WithContext(utils.ContextWithRequestID(c.Request.Context(), "request-1"))
+
+	return c, w
+}
+
+func Test_AbortWithError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   domain.ErrorCode
+		wantDetail string
+	}{
+		{
+			name:       "coded error",
+			err:        domain.NewError(domain.CodeInsufficientBalance, "insufficient balance"),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domain.CodeInsufficientBalance,
+			wantDetail: "insufficient balance",
+		},
+		{
+			name:       "wrapped coded error",
+			err:        fmt.Errorf("line 3: %w", domain.NewError(domain.CodeInvalidStatement, "invalid statement")),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domain.CodeInvalidStatement,
+			wantDetail: "line 3: invalid statement",
+		},
+		{
+			name:       "uncoded error is not leaked",
+			err:        errors.New("dial tcp 10.0.0.1:3306: connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   domain.CodeInternal,
+			wantDetail: http.StatusText(http.StatusInternalServerError),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := initialProblemContext()
+
+			v1.AbortWithError(c, tt.err)
+
+			assert.True(t, c.IsAborted())
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, v1.ProblemContentType, w.Header().Get("Content-Type"))
+
+			var problem v1.Problem
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.wantStatus, problem.Status)
+			assert.Equal(t, tt.wantCode, problem.Code)
+			assert.Equal(t, "urn:banking:problem:"+string(tt.wantCode), problem.Type)
+			assert.Equal(t, tt.wantDetail, problem.Detail)
+			assert.Equal(t, "/api/v1/transaction/transfer", problem.Instance)
+			assert.Equal(t, "request-1", problem.RequestID)
+		})
+	}
+}
+
+func Test_AbortWithProblem(t *testing.T) {
+	c, w := initialProblemContext()
+
+	v1.AbortWithProblem(c, domain.CodeRateLimited, "rate limit exceeded")
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	var problem v1.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, domain.CodeRateLimited, problem.Code)
+	assert.Equal(t, "rate limit exceeded", problem.Detail)
+}
@@ -1,5 +1,6 @@
 package v1
 
-type ErrResponse struct {
+// MsgResponse is the body of successful requests which return no data
+type MsgResponse struct {
 	Msg string `json:"msg"`
 }
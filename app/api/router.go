@@ -4,34 +4,35 @@ import (
 	"fmt"
 	"time"
 
+	restV1 "banking/app/api/restful/v1"
+	aliasHdl "banking/app/api/restful/v1/handler/alias"
+	auditHdl "banking/app/api/restful/v1/handler/audit"
+	balanceHistoryHdl "banking/app/api/restful/v1/handler/balancehistory"
+	bucketHdl "banking/app/api/restful/v1/handler/bucket"
+	fraudHdl "banking/app/api/restful/v1/handler/fraud"
+	healthHdl "banking/app/api/restful/v1/handler/health"
+	reconciliationHdl "banking/app/api/restful/v1/handler/reconciliation"
+	streamHdl "banking/app/api/restful/v1/handler/stream"
 	transactionHdl "banking/app/api/restful/v1/handler/transaction"
 	userHdl "banking/app/api/restful/v1/handler/user"
 	"banking/app/api/restful/v1/middleware"
-	apiKeyRepo "banking/app/repo/mysql/apikey"
-	transactionRepo "banking/app/repo/mysql/transaction"
-	userRepo "banking/app/repo/mysql/user"
-	apiKeyRedisRepo "banking/app/repo/redis/apikey"
-	jwtRedisRepo "banking/app/repo/redis/jwt"
-	apiKeySrv "banking/app/service/apikey"
-	authSrv "banking/app/service/auth"
-	transactionSrv "banking/app/service/transaction"
-	userSrv "banking/app/service/user"
 	_ "banking/docs"
+	"banking/domain"
+	"banking/tracing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-redis/redis/v8"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/viper"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
-	"go.elastic.co/apm/module/apmgin/v2"
-	"go.elastic.co/apm/v2"
-	"gorm.io/gorm"
 )
 
-func InitRouter(router *gin.Engine, masterDB *gorm.DB, slaveDB *gorm.DB, redisClient *redis.Client, tracer *apm.Tracer) *gin.Engine {
+func InitRouter(router *gin.Engine, services *Services, tracer tracing.Tracer) *gin.Engine {
 	// Middleware
-	router.Use(apmgin.Middleware(router, apmgin.WithTracer(tracer))) // APM gin middleware
+	router.Use(tracer.GinMiddleware(router))       // trace per request
+	router.Use(middleware.RequestIDMiddleware())   // X-Request-ID for logs, traces and responses
+	router.Use(middleware.ClientInfoMiddleware())  // client IP for the audit log
+	router.Use(middleware.ConsistencyMiddleware()) // X-Consistency: strong reads the master
 
 	// Swagger
 	// docs.SwaggerInfo.BasePath = fmt.Sprintf("/api/%s", viper.GetString("server.apiVersion"))
@@ -40,30 +41,26 @@ func InitRouter(router *gin.Engine, masterDB *gorm.DB, slaveDB *gorm.DB, redisCl
 	// Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
-	// User handler with master and slave DBs
-	userHandler := userHdl.NewUserHandler(
-		userSrv.NewUserService(
-			userRepo.NewUserCommandRepo(masterDB),            // Write operations
-			userRepo.NewUserQueryRepo(slaveDB),               // Read operations
-			jwtRedisRepo.NewRedisJWTCommandRepo(redisClient), // Write operations
-			jwtRedisRepo.NewRedisJWTQueryRepo(redisClient),   // Read operations
-		),
-		apiKeySrv.NewAPIKeyService(
-			apiKeyRedisRepo.NewRedisAPIKeyCommandRepo(redisClient), // Write operations
-			apiKeyRedisRepo.NewRedisAPIKeyQueryRepo(redisClient),   // Read operations
-			apiKeyRepo.NewAPIKeyCommandRepo(masterDB),              // Write operations
-			apiKeyRepo.NewAPIKeyQueryRepo(slaveDB),                 // Read operations
-
-		),
-	)
-
-	// Transaction handler with master DB and slave DB
-	transactionHandler := transactionHdl.NewTransactionHandler(
-		transactionSrv.NewTransactionService(
-			transactionRepo.NewTransactionCommandRepo(masterDB), // Write operations
-			transactionRepo.NewTransactionQueryRepo(slaveDB),    // Read operations
-		),
-	)
+	// Liveness and readiness probes
+	healthHandler := healthHdl.NewHealthHandler(services.Health)
+	router.GET("/healthz", healthHandler.Liveness())
+	router.GET("/readyz", healthHandler.Readiness())
+
+	// unknown routes get a problem body like every other error
+	router.NoRoute(func(c *gin.Context) {
+		restV1.AbortWithProblem(c, domain.CodeNotFound, "route not found")
+	})
+
+	// Handlers
+	auditHandler := auditHdl.NewAuditHandler(services.Audit)
+	userHandler := userHdl.NewUserHandler(services.User, services.APIKey)
+	fraudHandler := fraudHdl.NewFraudHandler(services.Fraud, services.Transaction)
+	bucketHandler := bucketHdl.NewBucketHandler(services.Bucket)
+	aliasHandler := aliasHdl.NewAliasHandler(services.Alias)
+	transactionHandler := transactionHdl.NewTransactionHandler(services.Transaction, services.TransferQueue, services.Alias)
+	balanceHistoryHandler := balanceHistoryHdl.NewBalanceHistoryHandler(services.BalanceHistory)
+	reconciliationHandler := reconciliationHdl.NewReconciliationHandler(services.Reconciliation)
+	streamHandler := streamHdl.NewStreamHandler(services.Stream)
 
 	// v1 group
 	v1 := router.Group(fmt.Sprintf("/api/%s", viper.GetString("server.apiVersion")))
@@ -78,15 +75,48 @@ func InitRouter(router *gin.Engine, masterDB *gorm.DB, slaveDB *gorm.DB, redisCl
 	userAuthenticated.POST("/apikey", userHandler.CreateAPIKey())
 	userAuthenticated.GET("/apikey", userHandler.GetAPIKeys())
 
-	transaction := v1.Group("/transaction", middleware.RateLimitMiddleware(redisClient, 10, time.Minute), middleware.APIKeyAuthMiddleware(authSrv.NewAuthService(
-		apiKeyRedisRepo.NewRedisAPIKeyCommandRepo(redisClient), // Write operations
-		apiKeyRedisRepo.NewRedisAPIKeyQueryRepo(redisClient),   // Read operations
-		apiKeyRepo.NewAPIKeyQueryRepo(slaveDB),                 // Read operations
-	)))
+	// payee aliases the user is paid by
+	userAuthenticated.POST("/alias", aliasHandler.CreateAlias())
+	userAuthenticated.GET("/alias", aliasHandler.GetAliases())
+	userAuthenticated.POST("/alias/:aliasId/verify", aliasHandler.VerifyAlias())
+	userAuthenticated.DELETE("/alias/:aliasId", aliasHandler.DeleteAlias())
+
+	transaction := v1.Group("/transaction", middleware.RateLimitMiddleware(services.RateLimit, 10, time.Minute), middleware.APIKeyAuthMiddleware(services.Auth))
 	transaction.POST("/transfer", transactionHandler.Transfer())
+	transaction.GET("/transfer/:transferId", transactionHandler.GetTransferRequest())
 	transaction.POST("/deposit", transactionHandler.Deposit())
 	transaction.POST("/withdraw", transactionHandler.Withdraw())
+	transaction.GET("/fee/quote", transactionHandler.QuoteFee())
+	transaction.GET("/payee", aliasHandler.ResolveAlias())
 	transaction.GET("/:userId", transactionHandler.GetTransactions())
+	transaction.GET("/:userId/balance", balanceHistoryHandler.GetBalanceAt())
+	transaction.GET("/:userId/balance/daily", balanceHistoryHandler.GetDailyBalances())
+
+	// balance event stream of the authenticated user
+	stream := v1.Group("/stream", middleware.QueryTokenMiddleware(), middleware.JWTAuthMiddleware())
+	stream.GET("/sse", streamHandler.SSE())
+	stream.GET("/ws", streamHandler.WebSocket())
+
+	// admin router
+	admin := v1.Group("/admin", middleware.JWTAuthMiddleware(), middleware.AdminMiddleware())
+
+	reconciliation := admin.Group("/reconciliation")
+	reconciliation.POST("/import", reconciliationHandler.ImportStatement())
+	reconciliation.GET("/:statementId/lines", reconciliationHandler.GetStatementLines())
+	reconciliation.POST("/line/:lineId/resolve", reconciliationHandler.ResolveStatementLine())
+
+	fraud := admin.Group("/fraud")
+	fraud.GET("/reviews", fraudHandler.GetReviews())
+	fraud.POST("/reviews/:reviewId/approve", fraudHandler.ApproveReview())
+	fraud.POST("/reviews/:reviewId/reject", fraudHandler.RejectReview())
+
+	// hot accounts spread their credits over balance buckets
+	admin.PUT("/user/:userId/buckets", bucketHandler.SetBalanceBuckets())
+
+	// audit router, auditors only
+	audit := v1.Group("/audit", middleware.JWTAuthMiddleware(), middleware.AuditorMiddleware())
+	audit.GET("/logs", auditHandler.GetAuditLogs())
+	audit.GET("/verify", auditHandler.VerifyAuditChain())
 
 	return router
 }
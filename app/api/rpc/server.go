@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"time"
+
+	rest "banking/app/api"
+	apiKeyHdl "banking/app/api/rpc/v1/handler/apikey"
+	transactionHdl "banking/app/api/rpc/v1/handler/transaction"
+	userHdl "banking/app/api/rpc/v1/handler/user"
+	"banking/app/api/rpc/v1/interceptor"
+	"banking/app/api/rpc/v1/pb"
+	"banking/tracing"
+
+	"google.golang.org/grpc"
+)
+
+// NewServer serves the user, api key and transaction services over gRPC, sharing the service
+// instances and the authentication rules of the REST API
+func NewServer(services *rest.Services, tracer tracing.Tracer) *grpc.Server {
+	// the tracer options come first, so the interceptors below run inside the trace of the call
+	options := append(tracer.ServerOptions(), grpc.ChainUnaryInterceptor(
+		interceptor.RequestIDInterceptor(),   // x-request-id for logs, traces and responses
+		interceptor.ClientInfoInterceptor(),  // client IP for the audit log
+		interceptor.ConsistencyInterceptor(), // x-consistency: strong reads the master
+		interceptor.Apply(interceptor.JWTAuthInterceptor(),
+			"/banking.v1.UserService/GetUser",
+			"/banking.v1.APIKeyService/",
+		),
+		interceptor.Apply(interceptor.RateLimitInterceptor(services.RateLimit, 10, time.Minute),
+			"/banking.v1.TransactionService/",
+		),
+		interceptor.Apply(interceptor.APIKeyAuthInterceptor(services.Auth),
+			"/banking.v1.TransactionService/",
+		),
+	))
+	server := grpc.NewServer(options...)
+
+	pb.RegisterUserServiceServer(server, userHdl.NewUserServer(services.User))
+	pb.RegisterAPIKeyServiceServer(server, apiKeyHdl.NewAPIKeyServer(services.APIKey))
+	pb.RegisterTransactionServiceServer(server, transactionHdl.NewTransactionServer(services.Transaction, services.Alias))
+
+	return server
+}
@@ -0,0 +1,101 @@
+package apikey
+
+import (
+	"context"
+
+	"banking/app/api/rpc/v1/pb"
+	"banking/domain"
+	"banking/tracing"
+	"banking/utils"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type APIKeyServer struct {
+	pb.UnimplementedAPIKeyServiceServer
+	apiKeyService domain.IAPIKeyService
+}
+
+func NewAPIKeyServer(APIKeyService domain.IAPIKeyService) pb.APIKeyServiceServer {
+	return &APIKeyServer{
+		apiKeyService: APIKeyService,
+	}
+}
+
+func (s *APIKeyServer) CreateAPIKey(ctx context.Context, _ *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyResponse, error) {
+	span, ctx := tracing.StartSpan(ctx, "APIKeyServer.CreateAPIKey", "handler")
+	defer span.End()
+
+	actor := utils.ActorFromContext(ctx)
+	if actor == nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	key, secret, err := s.apiKeyService.CreateAPIKey(ctx, actor.UserID)
+	if err != nil {
+		tracing.CaptureError(ctx, err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.CreateAPIKeyResponse{
+		ApiKey: &pb.APIKey{
+			Key:    key,
+			Secret: secret,
+			UserId: uint64(actor.UserID),
+		},
+	}, nil
+}
+
+func (s *APIKeyServer) GetAPIKeys(ctx context.Context, req *pb.GetAPIKeysRequest) (*pb.GetAPIKeysResponse, error) {
+	span, ctx := tracing.StartSpan(ctx, "APIKeyServer.GetAPIKeys", "handler")
+	defer span.End()
+
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	actor := utils.ActorFromContext(ctx)
+	if actor == nil || (!actor.IsAdmin && actor.UserID != uint(req.GetUserId())) {
+		return nil, status.Error(codes.PermissionDenied, "unauthorized")
+	}
+
+	apiKeys, err := s.apiKeyService.GetAPIKeys(ctx, uint(req.GetUserId()), req.GetKey())
+	if err != nil {
+		tracing.CaptureError(ctx, err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	data := make([]*pb.APIKey, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		data = append(data, &pb.APIKey{
+			Key:    apiKey.APIKey,
+			UserId: uint64(apiKey.UserID),
+		})
+	}
+
+	return &pb.GetAPIKeysResponse{
+		ApiKeys: data,
+	}, nil
+}
+
+func (s *APIKeyServer) DeleteAPIKey(ctx context.Context, req *pb.DeleteAPIKeyRequest) (*pb.DeleteAPIKeyResponse, error) {
+	span, ctx := tracing.StartSpan(ctx, "APIKeyServer.DeleteAPIKey", "handler")
+	defer span.End()
+
+	if req.GetKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	actor := utils.ActorFromContext(ctx)
+	if actor == nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	if err := s.apiKeyService.DeleteAPIKey(ctx, actor.UserID, req.GetKey()); err != nil {
+		tracing.CaptureError(ctx, err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.DeleteAPIKeyResponse{}, nil
+}
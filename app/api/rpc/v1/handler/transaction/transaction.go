@@ -0,0 +1,291 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+
+	"banking/app/api/rpc/v1/pb"
+	aliasRepo "banking/app/repo/mysql/alias"
+	transactionRepo "banking/app/repo/mysql/transaction"
+	userRepo "banking/app/repo/mysql/user"
+	aliasSrv "banking/app/service/alias"
+	feeSrv "banking/app/service/fee"
+	fraudSrv "banking/app/service/fraud"
+	transactionSrv "banking/app/service/transaction"
+	watchlistSrv "banking/app/service/watchlist"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type TransactionServer struct {
+	pb.UnimplementedTransactionServiceServer
+	transactionService domain.ITransactionService
+	aliasService       domain.IAliasService
+}
+
+func NewTransactionServer(TransactionService domain.ITransactionService, AliasService domain.IAliasService) pb.TransactionServiceServer {
+	return &TransactionServer{
+		transactionService: TransactionService,
+		aliasService:       AliasService,
+	}
+}
+
+func (s *TransactionServer) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResponse, error) {
+	span, ctx := tracing.StartSpan(ctx, "TransactionServer.Transfer", "handler")
+	defer span.End()
+
+	if req.GetFromUserId() == 0 || (req.GetToUserId() == 0 && req.GetToAlias() == "") {
+		return nil, status.Error(codes.InvalidArgument, "fromUserId and toUserId or toAlias are required")
+	}
+
+	if req.GetToUserId() != 0 && req.GetToAlias() != "" {
+		return nil, status.Error(codes.InvalidArgument, "toUserId and toAlias should not both be set")
+	}
+
+	amount, err := parseAmount(req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorize(ctx, req.GetFromUserId()); err != nil {
+		return nil, status.Error(codes.PermissionDenied, "fromUserId is not authorized")
+	}
+
+	// the alias is resolved here, the transfer only knows user ids
+	toUserID := uint(req.GetToUserId())
+	if req.GetToAlias() != "" {
+		payee, err := s.aliasService.Resolve(ctx, req.GetToAlias())
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		toUserID = payee.UserID
+	}
+
+	if uint(req.GetFromUserId()) == toUserID {
+		return nil, status.Error(codes.InvalidArgument, "fromUserId and toUserId should not be the same")
+	}
+
+	transaction, err := s.transactionService.Transfer(ctx, uint(req.GetFromUserId()), toUserID, amount, toReference(req))
+	if err != nil {
+		var screeningErr *fraudSrv.ScreeningError
+		if errors.As(err, &screeningErr) {
+			if errors.Is(err, fraudSrv.ErrTransferBlocked) {
+				return nil, status.Error(codes.PermissionDenied, fraudSrv.ErrTransferBlocked.Error())
+			}
+
+			return &pb.TransferResponse{
+				Result: &pb.TransferResponse_Held{
+					Held: &pb.TransferHeld{
+						ReviewId: uint64(screeningErr.Review.ID),
+						Status:   string(screeningErr.Review.Status),
+						Msg:      fraudSrv.ErrTransferUnderReview.Error(),
+					},
+				},
+			}, nil
+		}
+
+		tracing.CaptureError(ctx, err)
+		return nil, toStatusError(err)
+	}
+
+	return &pb.TransferResponse{
+		Result: &pb.TransferResponse_Transaction{
+			Transaction: toTransaction(transaction),
+		},
+	}, nil
+}
+
+func (s *TransactionServer) Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.DepositResponse, error) {
+	span, ctx := tracing.StartSpan(ctx, "TransactionServer.Deposit", "handler")
+	defer span.End()
+
+	amount, err := parseAmount(req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorize(ctx, req.GetUserId()); err != nil {
+		return nil, status.Error(codes.PermissionDenied, "userId is not authorized")
+	}
+
+	transaction, err := s.transactionService.Deposit(ctx, uint(req.GetUserId()), amount, toReference(req))
+	if err != nil {
+		tracing.CaptureError(ctx, err)
+		return nil, toStatusError(err)
+	}
+
+	return &pb.DepositResponse{
+		Transaction: toTransaction(transaction),
+	}, nil
+}
+
+func (s *TransactionServer) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.WithdrawResponse, error) {
+	span, ctx := tracing.StartSpan(ctx, "TransactionServer.Withdraw", "handler")
+	defer span.End()
+
+	amount, err := parseAmount(req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorize(ctx, req.GetUserId()); err != nil {
+		return nil, status.Error(codes.PermissionDenied, "userId is not authorized")
+	}
+
+	transaction, err := s.transactionService.Withdraw(ctx, uint(req.GetUserId()), amount, toReference(req))
+	if err != nil {
+		tracing.CaptureError(ctx, err)
+		return nil, toStatusError(err)
+	}
+
+	return &pb.WithdrawResponse{
+		Transaction: toTransaction(transaction),
+	}, nil
+}
+
+func (s *TransactionServer) GetTransactions(ctx context.Context, req *pb.GetTransactionsRequest) (*pb.GetTransactionsResponse, error) {
+	span, ctx := tracing.StartSpan(ctx, "TransactionServer.GetTransactions", "handler")
+	defer span.End()
+
+	if err := authorize(ctx, req.GetUserId()); err != nil {
+		return nil, status.Error(codes.PermissionDenied, "unauthorized")
+	}
+
+	transactions, err := s.transactionService.GetTransactions(ctx, uint(req.GetUserId()))
+	if err != nil {
+		tracing.CaptureError(ctx, err)
+		return nil, toStatusError(err)
+	}
+
+	data := make([]*pb.Transaction, 0, len(transactions))
+	for _, t := range transactions {
+		data = append(data, toTransaction(t))
+	}
+
+	return &pb.GetTransactionsResponse{
+		Transactions: data,
+	}, nil
+}
+
+func (s *TransactionServer) QuoteFee(ctx context.Context, req *pb.QuoteFeeRequest) (*pb.QuoteFeeResponse, error) {
+	span, ctx := tracing.StartSpan(ctx, "TransactionServer.QuoteFee", "handler")
+	defer span.End()
+
+	transactionType := mysqlModel.TransactionType(req.GetTransactionType())
+	if transactionType != mysqlModel.Transfer && transactionType != mysqlModel.Withdraw {
+		return nil, status.Error(codes.InvalidArgument, feeSrv.ErrUnsupportedTransactionType.Error())
+	}
+
+	amount, err := parseAmount(req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+
+	actor := utils.ActorFromContext(ctx)
+	if actor == nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	fee, err := s.transactionService.QuoteFee(ctx, actor.UserID, transactionType, amount)
+	if err != nil {
+		tracing.CaptureError(ctx, err)
+		return nil, toStatusError(err)
+	}
+
+	return &pb.QuoteFeeResponse{
+		TransactionType: string(transactionType),
+		Amount:          amount.String(),
+		Fee:             toFeeBreakdown(fee),
+		TotalDebit:      amount.Add(fee.Total).String(),
+	}, nil
+}
+
+// authorize checks the user in the request is the one authenticated by the api key
+func authorize(ctx context.Context, userID uint64) error {
+	actor := utils.ActorFromContext(ctx)
+	if userID == 0 || actor == nil || actor.UserID != uint(userID) {
+		return status.Error(codes.PermissionDenied, "unauthorized")
+	}
+
+	return nil
+}
+
+// parseAmount parses the decimal string of a request amount, which must be positive
+func parseAmount(value string) (decimal.Decimal, error) {
+	amount, err := decimal.NewFromString(value)
+	if err != nil || !amount.IsPositive() {
+		return decimal.Zero, status.Error(codes.InvalidArgument, "amount should be a positive decimal")
+	}
+
+	return amount, nil
+}
+
+// referenceReq is a request which takes the optional reference of a transfer, deposit or withdrawal
+type referenceReq interface {
+	GetMemo() string
+	GetExternalReference() string
+}
+
+func toReference(req referenceReq) *mysqlModel.TransactionReference {
+	return &mysqlModel.TransactionReference{Memo: req.GetMemo(), ExternalReference: req.GetExternalReference()}
+}
+
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, watchlistSrv.ErrWatchlistMatch):
+		return status.Error(codes.PermissionDenied, "transfer rejected by compliance screening")
+	case errors.Is(err, transactionRepo.ErrInsufficientBalance):
+		return status.Error(codes.FailedPrecondition, transactionRepo.ErrInsufficientBalance.Error())
+	case errors.Is(err, transactionRepo.ErrUserNotFound), errors.Is(err, userRepo.ErrUserNotFound):
+		return status.Error(codes.NotFound, transactionRepo.ErrUserNotFound.Error())
+	case errors.Is(err, aliasRepo.ErrAliasNotFound):
+		return status.Error(codes.NotFound, aliasRepo.ErrAliasNotFound.Error())
+	case errors.Is(err, feeSrv.ErrUnsupportedTransactionType),
+		errors.Is(err, transactionSrv.ErrMemoInvalid),
+		errors.Is(err, transactionSrv.ErrExternalReferenceInvalid),
+		errors.Is(err, aliasSrv.ErrEmailInvalid),
+		errors.Is(err, aliasSrv.ErrPhoneInvalid),
+		errors.Is(err, aliasSrv.ErrHandleInvalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+
+func toTransaction(transaction *mysqlModel.Transaction) *pb.Transaction {
+	return &pb.Transaction{
+		FromUserId:        uint64(transaction.FromUserID),
+		FromUserBalance:   transaction.FromUserBalance.String(),
+		ToUserId:          uint64(transaction.ToUserID),
+		ToUserBalance:     transaction.ToUserBalance.String(),
+		Amount:            transaction.Amount.String(),
+		Fee:               transaction.Fee.String(),
+		FeeBreakdown:      toFeeBreakdown(transaction.FeeBreakdown),
+		TransactionType:   string(transaction.TransactionType),
+		Details:           transaction.Details,
+		ExternalReference: transaction.ExternalReference,
+		FromUserName:      transaction.FromUserName,
+		ToUserName:        transaction.ToUserName,
+	}
+}
+
+func toFeeBreakdown(fee *mysqlModel.FeeBreakdown) *pb.FeeBreakdown {
+	if fee == nil {
+		return nil
+	}
+
+	return &pb.FeeBreakdown{
+		Tier:       fee.Tier,
+		Flat:       fee.Flat.String(),
+		Percentage: fee.Percentage.String(),
+		Tiered:     fee.Tiered.String(),
+		Adjustment: fee.Adjustment.String(),
+		Total:      fee.Total.String(),
+	}
+}
@@ -0,0 +1,213 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+
+	transactionHdl "banking/app/api/rpc/v1/handler/transaction"
+	"banking/app/api/rpc/v1/pb"
+	aliasRepo "banking/app/repo/mysql/alias"
+	transactionRepo "banking/app/repo/mysql/transaction"
+	aliasSrv "banking/app/service/alias"
+	fraudSrv "banking/app/service/fraud"
+	transactionSrv "banking/app/service/transaction"
+	domainMock "banking/domain/mock"
+	mysqlModel "banking/model/mysql"
+	"banking/utils"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+func initialTransactionServer(t *testing.T) (pb.TransactionServiceServer, *domainMock.MockITransactionService, *domainMock.MockIAliasService, context.Context) {
+	ctrl := gomock.NewController(t)
+	mockTransactionService := domainMock.NewMockITransactionService(ctrl)
+	mockAliasService := domainMock.NewMockIAliasService(ctrl)
+
+	ctx := utils.ContextWithActor(context.Background(), &utils.Actor{
+		UserID:     1,
+		AuthMethod: utils.AuthMethodAPIKey,
+	})
+
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	return transactionHdl.NewTransactionServer(mockTransactionService, mockAliasService), mockTransactionService, mockAliasService, ctx
+}
+
+func Test_Transfer(t *testing.T) {
+	server, mockTransactionService, _, ctx := initialTransactionServer(t)
+
+	reference := &mysqlModel.TransactionReference{Memo: "Rent March", ExternalReference: "INV-2026-03"}
+	mockTransactionService.EXPECT().Transfer(gomock.Any(), uint(1), uint(2), decimal.RequireFromString("10.50"), reference).Return(&mysqlModel.Transaction{
+		FromUserID:        1,
+		FromUserBalance:   decimal.NewFromInt(89),
+		ToUserID:          2,
+		Amount:            decimal.RequireFromString("10.50"),
+		Fee:               decimal.RequireFromString("0.50"),
+		TransactionType:   mysqlModel.Transfer,
+		Details:           "Rent March",
+		ExternalReference: "INV-2026-03",
+	}, nil)
+
+	resp, err := server.Transfer(ctx, &pb.TransferRequest{FromUserId: 1, ToUserId: 2, Amount: "10.50", Memo: "Rent March", ExternalReference: "INV-2026-03"})
+	assert.NoError(t, err)
+	assert.Equal(t, "10.5", resp.GetTransaction().GetAmount())
+	assert.Equal(t, "0.5", resp.GetTransaction().GetFee())
+	assert.Equal(t, "89", resp.GetTransaction().GetFromUserBalance())
+	assert.Equal(t, "Rent March", resp.GetTransaction().GetDetails())
+	assert.Equal(t, "INV-2026-03", resp.GetTransaction().GetExternalReference())
+}
+
+func Test_Deposit_InvalidReference(t *testing.T) {
+	server, mockTransactionService, _, ctx := initialTransactionServer(t)
+
+	mockTransactionService.EXPECT().Deposit(gomock.Any(), uint(1), gomock.Any(), &mysqlModel.TransactionReference{ExternalReference: "INV 1"}).Return(nil, transactionSrv.ErrExternalReferenceInvalid)
+
+	_, err := server.Deposit(ctx, &pb.DepositRequest{UserId: 1, Amount: "1", ExternalReference: "INV 1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, transactionSrv.ErrExternalReferenceInvalid.Error(), status.Convert(err).Message())
+}
+
+func Test_GetTransactions(t *testing.T) {
+	server, mockTransactionService, _, ctx := initialTransactionServer(t)
+
+	mockTransactionService.EXPECT().GetTransactions(gomock.Any(), uint(1)).Return([]*mysqlModel.Transaction{
+		{FromUserID: 1, ToUserID: 2, TransactionType: mysqlModel.Transfer, ExternalReference: "INV-1", FromUserName: "alice", ToUserName: "bob"},
+	}, nil)
+
+	resp, err := server.GetTransactions(ctx, &pb.GetTransactionsRequest{UserId: 1})
+	assert.NoError(t, err)
+	assert.Len(t, resp.GetTransactions(), 1)
+	assert.Equal(t, "INV-1", resp.GetTransactions()[0].GetExternalReference())
+	assert.Equal(t, "alice", resp.GetTransactions()[0].GetFromUserName())
+	assert.Equal(t, "bob", resp.GetTransactions()[0].GetToUserName())
+}
+
+func Test_Transfer_Held(t *testing.T) {
+	server, mockTransactionService, _, ctx := initialTransactionServer(t)
+
+	review := &mysqlModel.FraudReview{
+		Model:    gorm.Model{ID: 7},
+		Decision: mysqlModel.Review,
+		Status:   mysqlModel.ReviewPending,
+	}
+	mockTransactionService.EXPECT().Transfer(gomock.Any(), uint(1), uint(2), gomock.Any(), gomock.Any()).Return(nil, &fraudSrv.ScreeningError{Review: review})
+
+	resp, err := server.Transfer(ctx, &pb.TransferRequest{FromUserId: 1, ToUserId: 2, Amount: "5000"})
+	assert.NoError(t, err)
+	assert.Nil(t, resp.GetTransaction())
+	assert.Equal(t, uint64(7), resp.GetHeld().GetReviewId())
+	assert.Equal(t, string(mysqlModel.ReviewPending), resp.GetHeld().GetStatus())
+}
+
+func Test_Transfer_ToAlias(t *testing.T) {
+	server, mockTransactionService, mockAliasService, ctx := initialTransactionServer(t)
+
+	mockAliasService.EXPECT().Resolve(gomock.Any(), "@bob").Return(&mysqlModel.Payee{UserID: 2, AliasType: mysqlModel.AliasHandle, MaskedName: "B**"}, nil)
+	mockTransactionService.EXPECT().Transfer(gomock.Any(), uint(1), uint(2), decimal.RequireFromString("10"), gomock.Any()).Return(&mysqlModel.Transaction{
+		FromUserID:      1,
+		ToUserID:        2,
+		Amount:          decimal.RequireFromString("10"),
+		TransactionType: mysqlModel.Transfer,
+	}, nil)
+
+	resp, err := server.Transfer(ctx, &pb.TransferRequest{FromUserId: 1, ToAlias: "@bob", Amount: "10"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), resp.GetTransaction().GetToUserId())
+}
+
+func Test_Transfer_Errors(t *testing.T) {
+	server, mockTransactionService, mockAliasService, ctx := initialTransactionServer(t)
+
+	tests := []struct {
+		name string
+		req  *pb.TransferRequest
+		mock func()
+		code codes.Code
+	}{
+		{
+			name: "invalid amount",
+			req:  &pb.TransferRequest{FromUserId: 1, ToUserId: 2, Amount: "-1"},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "other user",
+			req:  &pb.TransferRequest{FromUserId: 3, ToUserId: 2, Amount: "1"},
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "same user",
+			req:  &pb.TransferRequest{FromUserId: 1, ToUserId: 1, Amount: "1"},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "no payee",
+			req:  &pb.TransferRequest{FromUserId: 1, Amount: "1"},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "user id and alias",
+			req:  &pb.TransferRequest{FromUserId: 1, ToUserId: 2, ToAlias: "@bob", Amount: "1"},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "unknown alias",
+			req:  &pb.TransferRequest{FromUserId: 1, ToAlias: "@nobody", Amount: "1"},
+			mock: func() {
+				mockAliasService.EXPECT().Resolve(gomock.Any(), "@nobody").Return(nil, aliasRepo.ErrAliasNotFound)
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "invalid alias",
+			req:  &pb.TransferRequest{FromUserId: 1, ToAlias: "+0612", Amount: "1"},
+			mock: func() {
+				mockAliasService.EXPECT().Resolve(gomock.Any(), "+0612").Return(nil, aliasSrv.ErrPhoneInvalid)
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "alias of the payer",
+			req:  &pb.TransferRequest{FromUserId: 1, ToAlias: "@alice", Amount: "1"},
+			mock: func() {
+				mockAliasService.EXPECT().Resolve(gomock.Any(), "@alice").Return(&mysqlModel.Payee{UserID: 1}, nil)
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "insufficient balance",
+			req:  &pb.TransferRequest{FromUserId: 1, ToUserId: 2, Amount: "1"},
+			mock: func() {
+				mockTransactionService.EXPECT().Transfer(gomock.Any(), uint(1), uint(2), gomock.Any(), gomock.Any()).Return(nil, transactionRepo.ErrInsufficientBalance)
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "blocked",
+			req:  &pb.TransferRequest{FromUserId: 1, ToUserId: 2, Amount: "1"},
+			mock: func() {
+				mockTransactionService.EXPECT().Transfer(gomock.Any(), uint(1), uint(2), gomock.Any(), gomock.Any()).Return(nil, &fraudSrv.ScreeningError{
+					Review: &mysqlModel.FraudReview{Decision: mysqlModel.Block, Status: mysqlModel.ReviewBlocked},
+				})
+			},
+			code: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mock != nil {
+				tt.mock()
+			}
+
+			_, err := server.Transfer(ctx, tt.req)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
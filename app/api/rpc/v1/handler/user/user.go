@@ -0,0 +1,125 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"banking/app/api/rpc/v1/pb"
+	userRepo "banking/app/repo/mysql/user"
+	watchlistSrv "banking/app/service/watchlist"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type UserServer struct {
+	pb.UnimplementedUserServiceServer
+	userService domain.IUserService
+}
+
+func NewUserServer(UserService domain.IUserService) pb.UserServiceServer {
+	return &UserServer{
+		userService: UserService,
+	}
+}
+
+func (s *UserServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	span, ctx := tracing.StartSpan(ctx, "UserServer.Register", "handler")
+	defer span.End()
+
+	// same rules as the REST CreateUserReq
+	input := struct {
+		Name     string `binding:"required,min=3,max=20,alphanumunicode"`
+		Email    string `binding:"required,email"`
+		Password string `binding:"required,min=8,max=20"`
+	}{
+		Name:     req.GetName(),
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	}
+	if err := binding.Validator.ValidateStruct(&input); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	user := &mysqlModel.User{
+		Name:     input.Name,
+		Email:    input.Email,
+		Balance:  decimal.NewFromFloat(0),
+		Password: input.Password,
+	}
+	if err := s.userService.CreateUser(ctx, user); err != nil {
+		tracing.CaptureError(ctx, err)
+		switch {
+		case errors.Is(err, userRepo.ErrUserExisted):
+			return nil, status.Error(codes.AlreadyExists, userRepo.ErrUserExisted.Error())
+		case errors.Is(err, watchlistSrv.ErrWatchlistMatch):
+			return nil, status.Error(codes.PermissionDenied, "registration rejected by compliance screening")
+		default:
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &pb.RegisterResponse{
+		User: toUser(user),
+	}, nil
+}
+
+func (s *UserServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	if req.GetEmail() == "" || req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Invalid input")
+	}
+
+	token, err := s.userService.Login(ctx, req.GetEmail(), req.GetPassword())
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "Invalid credentials")
+	}
+
+	return &pb.LoginResponse{
+		Token: token,
+	}, nil
+}
+
+func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	span, ctx := tracing.StartSpan(ctx, "UserServer.GetUser", "handler")
+	defer span.End()
+
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	actor := utils.ActorFromContext(ctx)
+	if actor == nil || (!actor.IsAdmin && actor.UserID != uint(req.GetUserId())) {
+		return nil, status.Error(codes.PermissionDenied, "unauthorized")
+	}
+
+	users, err := s.userService.GetUsers(ctx, uint(req.GetUserId()))
+	if errors.Is(err, userRepo.ErrUserNotFound) {
+		return nil, status.Error(codes.NotFound, userRepo.ErrUserNotFound.Error())
+	}
+	if err != nil {
+		tracing.CaptureError(ctx, err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	if len(users) == 0 {
+		return nil, status.Error(codes.NotFound, userRepo.ErrUserNotFound.Error())
+	}
+
+	return &pb.GetUserResponse{
+		User: toUser(users[0]),
+	}, nil
+}
+
+func toUser(user *mysqlModel.User) *pb.User {
+	return &pb.User{
+		Id:      uint64(user.ID),
+		Name:    user.Name,
+		Email:   user.Email,
+		Balance: user.Balance.String(),
+	}
+}
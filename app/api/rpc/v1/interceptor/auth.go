@@ -0,0 +1,79 @@
+package interceptor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"banking/domain"
+	"banking/metrics"
+	"banking/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// JWTAuthInterceptor authenticates calls with the "authorization: Bearer {token}" metadata
+func JWTAuthInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		authHeader := metadataValue(ctx, "authorization")
+		if authHeader == "" {
+			metrics.ObserveAuthFailure(utils.AuthMethodJWT)
+			return nil, status.Error(codes.Unauthenticated, "Authorization token required")
+		}
+
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		if tokenString == authHeader {
+			metrics.ObserveAuthFailure(utils.AuthMethodJWT)
+			return nil, status.Error(codes.Unauthenticated, "Authorization token format is Bearer {token}")
+		}
+
+		claims, err := utils.ParseJWT(tokenString)
+		if err != nil {
+			metrics.ObserveAuthFailure(utils.AuthMethodJWT)
+			return nil, status.Error(codes.Unauthenticated, "Invalid or expired token")
+		}
+
+		ctx = utils.ContextWithActor(ctx, &utils.Actor{
+			UserID:     claims.UserID,
+			Email:      claims.Email,
+			AuthMethod: utils.AuthMethodJWT,
+			IsAdmin:    claims.IsAdmin,
+		})
+
+		return handler(ctx, req)
+	}
+}
+
+// APIKeyAuthInterceptor authenticates calls with the "x-api-key", "x-secret-key" and "x-user-id" metadata
+func APIKeyAuthInterceptor(authService domain.IAuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		key := metadataValue(ctx, "x-api-key")
+		secretKey := metadataValue(ctx, "x-secret-key")
+
+		userID, err := strconv.ParseUint(metadataValue(ctx, "x-user-id"), 10, 64)
+		if err != nil || userID == 0 {
+			metrics.ObserveAuthFailure(utils.AuthMethodAPIKey)
+			return nil, status.Error(codes.InvalidArgument, "Invalid User ID")
+		}
+
+		if key == "" || secretKey == "" {
+			metrics.ObserveAuthFailure(utils.AuthMethodAPIKey)
+			return nil, status.Error(codes.Unauthenticated, "API Key and Secret Key are required")
+		}
+
+		if err := authService.APIKeyConfirmation(ctx, uint(userID), key, secretKey); err != nil {
+			metrics.ObserveAuthFailure(utils.AuthMethodAPIKey)
+			return nil, status.Error(codes.Unauthenticated, "Invalid API Key or Secret Key")
+		}
+
+		ctx = utils.ContextWithAPIKey(ctx, key)
+		ctx = utils.ContextWithActor(ctx, &utils.Actor{
+			UserID:     uint(userID),
+			AuthMethod: utils.AuthMethodAPIKey,
+		})
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,42 @@
+package interceptor
+
+import (
+	"context"
+	"net"
+
+	"banking/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientInfoInterceptor stores the peer IP in the request context
+func ClientInfoInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			ip := p.Addr.String()
+			if host, _, err := net.SplitHostPort(ip); err == nil {
+				ip = host
+			}
+			ctx = utils.ContextWithClientIP(ctx, ip)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// metadataValue returns the first value of the incoming metadata key, keys are lower case in gRPC
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,23 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"banking/utils"
+
+	"google.golang.org/grpc"
+)
+
+// ConsistencyInterceptor routes the reads of calls with "x-consistency: strong" metadata to the master
+func ConsistencyInterceptor() grpc.UnaryServerInterceptor {
+	key := strings.ToLower(utils.ConsistencyHeader)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.EqualFold(metadataValue(ctx, key), utils.ConsistencyStrong) {
+			ctx = utils.ContextWithStrongConsistency(ctx)
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,45 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"banking/domain"
+	"banking/metrics"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimitInterceptor limits calls per "x-api-key" metadata, sharing the budget with the REST API
+func RateLimitInterceptor(rateLimitRepo domain.IRedisRateLimitCommandRepo, limit int64, duration time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		key := metadataValue(ctx, "x-api-key")
+		if key == "" {
+			return nil, status.Error(codes.Unauthenticated, "missing API key")
+		}
+
+		remaining, reset, err := rateLimitRepo.TakeRateLimit(ctx, key, limit, duration)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+
+		// Rate limit exceeded
+		if remaining < 0 {
+			metrics.ObserveRateLimitRejection(key)
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", fmt.Sprintf("%d", reset/1000)))
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs(
+			"x-ratelimit-remaining", fmt.Sprintf("%d", remaining),
+			"x-ratelimit-reset", fmt.Sprintf("%d", time.Now().Add(time.Duration(reset)*time.Millisecond).Unix()),
+			"x-ratelimit-limit", fmt.Sprintf("%d", limit),
+		))
+
+		return handler(ctx, req)
+	}
+}
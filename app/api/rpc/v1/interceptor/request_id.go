@@ -0,0 +1,30 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"banking/tracing"
+	"banking/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDInterceptor accepts the x-request-id metadata or generates one, stores it in the request
+// context and the call trace, and returns it as header metadata. It must run inside the tracer server options
+func RequestIDInterceptor() grpc.UnaryServerInterceptor {
+	key := strings.ToLower(utils.RequestIDHeader)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		requestID := utils.RequestIDOrNew(metadataValue(ctx, key))
+
+		ctx = utils.ContextWithRequestID(ctx, requestID)
+		tracing.SetLabel(ctx, "request_id", requestID)
+
+		// the header is sent with the response or the error status, whichever comes first
+		_ = grpc.SetHeader(ctx, metadata.Pairs(key, requestID))
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,22 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// Apply runs the interceptor only for methods matching one of the prefixes, a prefix is either a
+// full method name such as "/banking.v1.UserService/GetUser" or a service such as "/banking.v1.UserService/"
+func Apply(interceptor grpc.UnaryServerInterceptor, prefixes ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(info.FullMethod, prefix) {
+				return interceptor(ctx, req, info, handler)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
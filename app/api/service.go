@@ -0,0 +1,365 @@
+package rest
+
+import (
+	"banking/app/repo/memory"
+	aliasRepo "banking/app/repo/mysql/alias"
+	apiKeyRepo "banking/app/repo/mysql/apikey"
+	auditRepo "banking/app/repo/mysql/audit"
+	balanceHistoryRepo "banking/app/repo/mysql/balancehistory"
+	bucketRepo "banking/app/repo/mysql/bucket"
+	fraudRepo "banking/app/repo/mysql/fraud"
+	healthRepo "banking/app/repo/mysql/health"
+	projectionRepo "banking/app/repo/mysql/projection"
+	reconciliationRepo "banking/app/repo/mysql/reconciliation"
+	routingRepo "banking/app/repo/mysql/routing"
+	transactionRepo "banking/app/repo/mysql/transaction"
+	transferQueueRepo "banking/app/repo/mysql/transferqueue"
+	userRepo "banking/app/repo/mysql/user"
+	watchlistRepo "banking/app/repo/mysql/watchlist"
+	apiKeyRedisRepo "banking/app/repo/redis/apikey"
+	healthRedisRepo "banking/app/repo/redis/health"
+	jwtRedisRepo "banking/app/repo/redis/jwt"
+	rateLimitRedisRepo "banking/app/repo/redis/ratelimit"
+	routingRedisRepo "banking/app/repo/redis/routing"
+	streamRedisRepo "banking/app/repo/redis/stream"
+	aliasSrv "banking/app/service/alias"
+	apiKeySrv "banking/app/service/apikey"
+	auditSrv "banking/app/service/audit"
+	authSrv "banking/app/service/auth"
+	balanceHistorySrv "banking/app/service/balancehistory"
+	bucketSrv "banking/app/service/bucket"
+	feeSrv "banking/app/service/fee"
+	fraudSrv "banking/app/service/fraud"
+	healthSrv "banking/app/service/health"
+	projectionSrv "banking/app/service/projection"
+	reconciliationSrv "banking/app/service/reconciliation"
+	streamSrv "banking/app/service/stream"
+	transactionSrv "banking/app/service/transaction"
+	transferQueueSrv "banking/app/service/transferqueue"
+	userSrv "banking/app/service/user"
+	watchlistSrv "banking/app/service/watchlist"
+	"banking/domain"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// Services are shared by the REST and gRPC servers, so both run on the same instances
+type Services struct {
+	Audit          domain.IAuditService
+	Watchlist      domain.IWatchlistService
+	User           domain.IUserService
+	APIKey         domain.IAPIKeyService
+	Alias          domain.IAliasService
+	Auth           domain.IAuthService
+	Fee            domain.IFeeService
+	Fraud          domain.IFraudService
+	Bucket         domain.IBucketService
+	Transaction    domain.ITransactionService
+	TransferQueue  domain.ITransferQueueService
+	Projection     domain.IProjectionService
+	BalanceHistory domain.IBalanceHistoryService
+	Reconciliation domain.IReconciliationService
+	Stream         domain.IStreamService
+	Health         domain.IHealthService
+	ReadRouter     domain.IReadRouter
+	RateLimit      domain.IRedisRateLimitCommandRepo
+}
+
+// Repos are the storage behind the services, a SQL database and redis or the in-memory store
+type Repos struct {
+	MasterHealthQuery   domain.IHealthQueryRepo
+	SlaveHealthQuery    domain.IHealthQueryRepo
+	RedisHealthQuery    domain.IRedisHealthQueryRepo
+	ReadRouter          domain.IReadRouter
+	AuditCmd            domain.IAuditCommandRepo
+	AuditQuery          domain.IAuditQueryRepo
+	WatchlistCmd        domain.IWatchlistCommandRepo
+	UserCmd             domain.IUserCommandRepo
+	UserQuery           domain.IUserQueryRepo
+	JWTRedisCmd         domain.IRedisJWTCommandRepo
+	JWTRedisQuery       domain.IRedisJWTQueryRepo
+	APIKeyCmd           domain.IAPIKeyCommandRepo
+	APIKeyQuery         domain.IAPIKeyQueryRepo
+	APIKeyRedisCmd      domain.IRedisAPIKeyCommandRepo
+	APIKeyRedisQuery    domain.IRedisAPIKeyQueryRepo
+	AliasCmd            domain.IAliasCommandRepo
+	AliasQuery          domain.IAliasQueryRepo
+	StreamRedisCmd      domain.IRedisStreamCommandRepo
+	StreamRedisQuery    domain.IRedisStreamQueryRepo
+	FraudCmd            domain.IFraudCommandRepo
+	FraudQuery          domain.IFraudQueryRepo
+	TransactionCmd      domain.ITransactionCommandRepo
+	TransactionQuery    domain.ITransactionQueryRepo
+	TransferQueueCmd    domain.ITransferQueueCommandRepo
+	TransferQueueQuery  domain.ITransferQueueQueryRepo
+	ProjectionCmd       domain.IProjectionCommandRepo
+	ProjectionQuery     domain.IProjectionQueryRepo
+	BalanceHistoryQuery domain.IBalanceHistoryQueryRepo
+	BucketCmd           domain.IBucketCommandRepo
+	BucketQuery         domain.IBucketQueryRepo
+	ReconciliationCmd   domain.IReconciliationCommandRepo
+	ReconciliationQuery domain.IReconciliationQueryRepo
+	RateLimitRedisCmd   domain.IRedisRateLimitCommandRepo
+}
+
+// NewSQLRepos writes to master, reads from slave and caches in redis, the gorm repos serve every
+// database.driver
+func NewSQLRepos(masterDB *gorm.DB, slaveDB *gorm.DB, redisClient *redis.Client) *Repos {
+	// Users and transactions are read from slave, or from master after a write of the user or while slave lags
+	readRouter := routingRepo.NewReadRouter(
+		masterDB,
+		slaveDB,
+		routingRedisRepo.NewRedisRoutingCommandRepo(redisClient), // Write operations
+		routingRedisRepo.NewRedisRoutingQueryRepo(redisClient),   // Read operations
+		healthRepo.NewHealthQueryRepo(slaveDB),                   // Read operations
+	)
+
+	repos := &Repos{
+		// Readiness checks both databases and redis
+		MasterHealthQuery: healthRepo.NewHealthQueryRepo(masterDB),
+		SlaveHealthQuery:  healthRepo.NewHealthQueryRepo(slaveDB),
+		RedisHealthQuery:  healthRedisRepo.NewRedisHealthQueryRepo(redisClient),
+		ReadRouter:        readRouter,
+
+		// Audit log is appended on master, queries read the slave
+		AuditCmd:   auditRepo.NewAuditCommandRepo(masterDB),
+		AuditQuery: auditRepo.NewAuditQueryRepo(slaveDB),
+
+		WatchlistCmd: watchlistRepo.NewWatchlistCommandRepo(masterDB),
+
+		UserCmd:       userRepo.NewUserCommandRepo(masterDB),
+		UserQuery:     userRepo.NewUserQueryRepo(readRouter),
+		JWTRedisCmd:   jwtRedisRepo.NewRedisJWTCommandRepo(redisClient),
+		JWTRedisQuery: jwtRedisRepo.NewRedisJWTQueryRepo(redisClient),
+
+		APIKeyCmd:        apiKeyRepo.NewAPIKeyCommandRepo(masterDB),
+		APIKeyQuery:      apiKeyRepo.NewAPIKeyQueryRepo(slaveDB),
+		APIKeyRedisCmd:   apiKeyRedisRepo.NewRedisAPIKeyCommandRepo(redisClient),
+		APIKeyRedisQuery: apiKeyRedisRepo.NewRedisAPIKeyQueryRepo(redisClient),
+
+		// Aliases are verified and resolved on master, a transfer must not miss an alias just verified
+		AliasCmd:   aliasRepo.NewAliasCommandRepo(masterDB),
+		AliasQuery: aliasRepo.NewAliasQueryRepo(masterDB),
+
+		// Balance events are kept in redis streams and fanned out with redis pub/sub
+		StreamRedisCmd:   streamRedisRepo.NewRedisStreamCommandRepo(redisClient),
+		StreamRedisQuery: streamRedisRepo.NewRedisStreamQueryRepo(redisClient),
+
+		// Fraud screening reads transfer history from master, so rapid transfers are counted without replica lag
+		FraudCmd:   fraudRepo.NewFraudCommandRepo(masterDB),
+		FraudQuery: fraudRepo.NewFraudQueryRepo(masterDB),
+
+		TransactionCmd:   transactionRepo.NewTransactionCommandRepo(masterDB),
+		TransactionQuery: transactionRepo.NewTransactionQueryRepo(readRouter),
+
+		// Async transfers are claimed and polled on master, a status must not lag behind its worker
+		TransferQueueCmd:   transferQueueRepo.NewTransferQueueCommandRepo(masterDB),
+		TransferQueueQuery: transferQueueRepo.NewTransferQueueQueryRepo(masterDB),
+
+		// Ledger events are projected on master, the read models are read from slave
+		ProjectionCmd:   projectionRepo.NewProjectionCommandRepo(masterDB),
+		ProjectionQuery: projectionRepo.NewProjectionQueryRepo(slaveDB),
+
+		// Past balances are read from the balances recorded with the transactions
+		BalanceHistoryQuery: balanceHistoryRepo.NewBalanceHistoryQueryRepo(readRouter),
+
+		// Hot accounts are configured and found on master
+		BucketCmd:   bucketRepo.NewBucketCommandRepo(masterDB),
+		BucketQuery: bucketRepo.NewBucketQueryRepo(masterDB),
+
+		ReconciliationCmd:   reconciliationRepo.NewReconciliationCommandRepo(masterDB),
+		ReconciliationQuery: reconciliationRepo.NewReconciliationQueryRepo(slaveDB),
+
+		// REST and gRPC requests of every replica share the budget of an API key
+		RateLimitRedisCmd: rateLimitRedisRepo.NewRedisRateLimitCommandRepo(redisClient),
+	}
+	readProjections(repos)
+
+	return repos
+}
+
+// NewMemoryRepos keeps everything in store, for development and tests without a database and redis
+func NewMemoryRepos(store *memory.Store) *Repos {
+	repos := &Repos{
+		MasterHealthQuery: memory.NewHealthQueryRepo(),
+		SlaveHealthQuery:  memory.NewHealthQueryRepo(),
+		RedisHealthQuery:  memory.NewRedisHealthQueryRepo(),
+		ReadRouter:        memory.NewReadRouter(),
+
+		AuditCmd:   memory.NewAuditCommandRepo(store),
+		AuditQuery: memory.NewAuditQueryRepo(store),
+
+		WatchlistCmd: memory.NewWatchlistCommandRepo(store),
+
+		UserCmd:       memory.NewUserCommandRepo(store),
+		UserQuery:     memory.NewUserQueryRepo(store),
+		JWTRedisCmd:   memory.NewJWTCommandRepo(store),
+		JWTRedisQuery: memory.NewJWTQueryRepo(store),
+
+		APIKeyCmd:        memory.NewAPIKeyCommandRepo(store),
+		APIKeyQuery:      memory.NewAPIKeyQueryRepo(store),
+		APIKeyRedisCmd:   memory.NewAPIKeyCacheCommandRepo(store),
+		APIKeyRedisQuery: memory.NewAPIKeyCacheQueryRepo(store),
+
+		AliasCmd:   memory.NewAliasCommandRepo(store),
+		AliasQuery: memory.NewAliasQueryRepo(store),
+
+		StreamRedisCmd:   memory.NewStreamCommandRepo(store),
+		StreamRedisQuery: memory.NewStreamQueryRepo(store),
+
+		FraudCmd:   memory.NewFraudCommandRepo(store),
+		FraudQuery: memory.NewFraudQueryRepo(store),
+
+		TransactionCmd:   memory.NewTransactionCommandRepo(store),
+		TransactionQuery: memory.NewTransactionQueryRepo(store),
+
+		TransferQueueCmd:   memory.NewTransferQueueCommandRepo(store),
+		TransferQueueQuery: memory.NewTransferQueueQueryRepo(store),
+
+		ProjectionCmd:   memory.NewProjectionCommandRepo(store),
+		ProjectionQuery: memory.NewProjectionQueryRepo(store),
+
+		BalanceHistoryQuery: memory.NewBalanceHistoryQueryRepo(store),
+
+		BucketCmd:   memory.NewBucketCommandRepo(store),
+		BucketQuery: memory.NewBucketQueryRepo(store),
+
+		ReconciliationCmd:   memory.NewReconciliationCommandRepo(store),
+		ReconciliationQuery: memory.NewReconciliationQueryRepo(store),
+
+		RateLimitRedisCmd: memory.NewRateLimitCommandRepo(store),
+	}
+	readProjections(repos)
+
+	return repos
+}
+
+// readProjections serves the balances and transactions from the read models of the ledger events when
+// projection.reads is set, the writes keep locking and checking the user rows
+func readProjections(repos *Repos) {
+	if !viper.GetBool("projection.reads") {
+		return
+	}
+
+	repos.UserQuery = projectionRepo.NewUserQueryRepo(repos.UserQuery, repos.ProjectionQuery)
+	repos.TransactionQuery = projectionRepo.NewTransactionQueryRepo(repos.ProjectionQuery)
+}
+
+func InitServices(repos *Repos) *Services {
+	services := &Services{
+		ReadRouter: repos.ReadRouter,
+		RateLimit:  repos.RateLimitRedisCmd,
+	}
+
+	// Readiness checks both databases and redis
+	services.Health = healthSrv.NewHealthService(
+		repos.MasterHealthQuery, // Read operations
+		repos.SlaveHealthQuery,  // Read operations
+		repos.RedisHealthQuery,  // Read operations
+	)
+
+	services.Audit = auditSrv.NewAuditService(
+		repos.AuditCmd,   // Write operations
+		repos.AuditQuery, // Read operations
+	)
+
+	// Watchlist screening for registrations and transfers
+	services.Watchlist = watchlistSrv.NewWatchlistService(
+		repos.WatchlistCmd, // Write operations
+		repos.UserQuery,    // Read operations
+	)
+
+	services.User = userSrv.NewUserService(
+		repos.UserCmd,       // Write operations
+		repos.UserQuery,     // Read operations
+		repos.JWTRedisCmd,   // Write operations
+		repos.JWTRedisQuery, // Read operations
+		services.Watchlist,
+		services.Audit,
+	)
+	services.APIKey = apiKeySrv.NewAPIKeyService(
+		repos.APIKeyRedisCmd,   // Write operations
+		repos.APIKeyRedisQuery, // Read operations
+		repos.APIKeyCmd,        // Write operations
+		repos.APIKeyQuery,      // Read operations
+		services.Audit,
+	)
+	services.Auth = authSrv.NewAuthService(
+		repos.APIKeyRedisCmd,   // Write operations
+		repos.APIKeyRedisQuery, // Read operations
+		repos.APIKeyQuery,      // Read operations
+	)
+
+	// Payees are found by email, phone number or handle, the codes of new aliases go out through alias.sender
+	services.Alias = aliasSrv.NewAliasService(
+		repos.AliasCmd,   // Write operations
+		repos.AliasQuery, // Read operations
+		repos.UserQuery,  // Read operations
+		aliasSrv.NewCodeSender(),
+		services.Audit,
+	)
+
+	// Fee schedule reads the user tier and house account
+	services.Fee = feeSrv.NewFeeService(
+		repos.UserQuery, // Read operations
+	)
+
+	services.Stream = streamSrv.NewStreamService(
+		repos.StreamRedisCmd,   // Write operations
+		repos.StreamRedisQuery, // Read operations
+	)
+
+	services.Fraud = fraudSrv.NewFraudService(
+		repos.FraudCmd,   // Write operations
+		repos.FraudQuery, // Read operations
+		services.Audit,
+	)
+
+	// Transaction history shows the names of the counterparties
+	services.Transaction = transactionSrv.NewTransactionService(
+		repos.TransactionCmd,   // Write operations
+		repos.TransactionQuery, // Read operations
+		repos.UserQuery,        // Read operations
+		services.Fraud,
+		services.Watchlist,
+		services.Audit,
+		services.Fee,
+		services.Stream,
+		services.ReadRouter,
+	)
+
+	// Async transfers are executed by workers through the transaction service
+	services.TransferQueue = transferQueueSrv.NewTransferQueueService(
+		repos.TransferQueueCmd,   // Write operations
+		repos.TransferQueueQuery, // Read operations
+		services.Transaction,
+	)
+
+	// Balances and transaction history are projected from the ledger events
+	services.Projection = projectionSrv.NewProjectionService(
+		repos.ProjectionCmd,   // Write operations
+		repos.ProjectionQuery, // Read operations
+	)
+
+	// Balances at a point in time and end of day balances of the transaction history
+	services.BalanceHistory = balanceHistorySrv.NewBalanceHistoryService(
+		repos.BalanceHistoryQuery, // Read operations
+		repos.UserQuery,           // Read operations
+	)
+
+	services.Bucket = bucketSrv.NewBucketService(
+		repos.BucketCmd,   // Write operations
+		repos.BucketQuery, // Read operations
+		services.Audit,
+	)
+
+	services.Reconciliation = reconciliationSrv.NewReconciliationService(
+		repos.ReconciliationCmd,   // Write operations
+		repos.ReconciliationQuery, // Read operations
+		services.Audit,
+	)
+
+	return services
+}
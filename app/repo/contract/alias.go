@@ -0,0 +1,143 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	aliasRepo "banking/app/repo/mysql/alias"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAlias checks an alias value resolves to the one user who verified it first
+func testAlias(t *testing.T, newRepos Factory) {
+	claim := func(t *testing.T, repos *Repos, userID uint, value string) *mysqlModel.PayeeAlias {
+		expiresAt := time.Now().Add(time.Minute)
+		alias := &mysqlModel.PayeeAlias{UserID: userID, Type: mysqlModel.AliasEmail, Value: value, CodeHash: "hash", CodeExpiresAt: &expiresAt}
+		require.NoError(t, repos.AliasCmd.CreateAlias(context.Background(), alias))
+		assert.NotZero(t, alias.ID)
+		return alias
+	}
+	verify := func(alias *mysqlModel.PayeeAlias) *mysqlModel.PayeeAlias {
+		now := time.Now()
+		alias.VerifiedValue, alias.VerifiedAt = &alias.Value, &now
+		return alias
+	}
+
+	t.Run("verified aliases resolve", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.AliasCmd, repos.AliasQuery)
+		users := newUsers(t, repos, 0, 0)
+
+		alias := claim(t, repos, users[0].ID, "alice@yopmail.com")
+		_, err := repos.AliasQuery.GetVerifiedAlias(context.Background(), "alice@yopmail.com")
+		assert.ErrorIs(t, err, aliasRepo.ErrAliasNotFound, "unverified")
+
+		require.NoError(t, repos.AliasCmd.CountAttempt(context.Background(), alias.ID, 3))
+		require.NoError(t, repos.AliasCmd.VerifyAlias(context.Background(), verify(alias)))
+
+		got, err := repos.AliasQuery.GetVerifiedAlias(context.Background(), "alice@yopmail.com")
+		require.NoError(t, err)
+		assert.Equal(t, users[0].ID, got.UserID)
+		assert.True(t, got.Verified())
+		assert.Equal(t, 1, got.VerificationAttempts)
+		assert.Empty(t, got.CodeHash)
+
+		aliases, err := repos.AliasQuery.GetAliases(context.Background(), users[0].ID)
+		require.NoError(t, err)
+		require.Len(t, aliases, 1)
+		aliases, err = repos.AliasQuery.GetAliases(context.Background(), users[1].ID)
+		require.NoError(t, err)
+		assert.Empty(t, aliases)
+	})
+
+	t.Run("an unverified alias gets a new code", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.AliasCmd, repos.AliasQuery)
+		users := newUsers(t, repos, 0)
+
+		alias := claim(t, repos, users[0].ID, "dave@yopmail.com")
+		require.NoError(t, repos.AliasCmd.CountAttempt(context.Background(), alias.ID, 3))
+
+		expiresAt := time.Now().Add(time.Hour)
+		again := &mysqlModel.PayeeAlias{UserID: users[0].ID, Type: mysqlModel.AliasEmail, Value: "dave@yopmail.com", CodeHash: "new", CodeExpiresAt: &expiresAt}
+		require.NoError(t, repos.AliasCmd.RenewCode(context.Background(), again))
+		assert.Equal(t, alias.ID, again.ID)
+
+		got, err := repos.AliasQuery.GetAlias(context.Background(), alias.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "new", got.CodeHash)
+		assert.WithinDuration(t, expiresAt, *got.CodeExpiresAt, time.Second)
+		assert.Zero(t, got.VerificationAttempts)
+
+		// a verified alias keeps its value
+		require.NoError(t, repos.AliasCmd.VerifyAlias(context.Background(), verify(got)))
+		assert.ErrorIs(t, repos.AliasCmd.RenewCode(context.Background(), again), aliasRepo.ErrAliasTaken)
+	})
+
+	t.Run("attempts are counted up to the limit", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.AliasCmd, repos.AliasQuery)
+		users := newUsers(t, repos, 0)
+
+		alias := claim(t, repos, users[0].ID, "carol@yopmail.com")
+		for i := 0; i < 2; i++ {
+			require.NoError(t, repos.AliasCmd.CountAttempt(context.Background(), alias.ID, 2))
+		}
+		assert.ErrorIs(t, repos.AliasCmd.CountAttempt(context.Background(), alias.ID, 2), aliasRepo.ErrAttemptsExhausted)
+		assert.ErrorIs(t, repos.AliasCmd.CountAttempt(context.Background(), alias.ID+1, 2), aliasRepo.ErrAliasNotFound)
+
+		got, err := repos.AliasQuery.GetAlias(context.Background(), alias.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.VerificationAttempts)
+	})
+
+	t.Run("a value is verified by one user", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.AliasCmd, repos.AliasQuery)
+		users := newUsers(t, repos, 0, 0)
+
+		// both may claim it, the second verification fails
+		first := claim(t, repos, users[0].ID, "shared@yopmail.com")
+		second := claim(t, repos, users[1].ID, "shared@yopmail.com")
+		require.NoError(t, repos.AliasCmd.VerifyAlias(context.Background(), verify(first)))
+		assert.ErrorIs(t, repos.AliasCmd.VerifyAlias(context.Background(), verify(second)), aliasRepo.ErrAliasTaken)
+
+		got, err := repos.AliasQuery.GetAlias(context.Background(), second.ID)
+		require.NoError(t, err)
+		assert.False(t, got.Verified())
+
+		// nor is a verified alias created with it
+		handle := verify(&mysqlModel.PayeeAlias{UserID: users[1].ID, Type: mysqlModel.AliasHandle, Value: "shared@yopmail.com"})
+		assert.ErrorIs(t, repos.AliasCmd.CreateAlias(context.Background(), handle), aliasRepo.ErrAliasTaken)
+
+		// a user claims a value once
+		again := &mysqlModel.PayeeAlias{UserID: users[0].ID, Type: mysqlModel.AliasEmail, Value: "shared@yopmail.com"}
+		assert.ErrorIs(t, repos.AliasCmd.CreateAlias(context.Background(), again), aliasRepo.ErrAliasTaken)
+	})
+
+	t.Run("deleted aliases no longer resolve", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.AliasCmd, repos.AliasQuery)
+		users := newUsers(t, repos, 0, 0)
+
+		alias := claim(t, repos, users[0].ID, "bob@yopmail.com")
+		require.NoError(t, repos.AliasCmd.VerifyAlias(context.Background(), verify(alias)))
+
+		// only by their user
+		assert.ErrorIs(t, repos.AliasCmd.DeleteAlias(context.Background(), users[1].ID, alias.ID), aliasRepo.ErrAliasNotFound)
+		require.NoError(t, repos.AliasCmd.DeleteAlias(context.Background(), users[0].ID, alias.ID))
+
+		_, err := repos.AliasQuery.GetVerifiedAlias(context.Background(), "bob@yopmail.com")
+		assert.ErrorIs(t, err, aliasRepo.ErrAliasNotFound)
+		_, err = repos.AliasQuery.GetAlias(context.Background(), alias.ID)
+		assert.ErrorIs(t, err, aliasRepo.ErrAliasNotFound)
+
+		// and the value is free again
+		other := claim(t, repos, users[1].ID, "bob@yopmail.com")
+		assert.NoError(t, repos.AliasCmd.VerifyAlias(context.Background(), verify(other)))
+	})
+}
@@ -0,0 +1,77 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func testAPIKey(t *testing.T, newRepos Factory) {
+	t.Run("create and get", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.APIKeyCmd, repos.APIKeyQuery)
+		users := newUsers(t, repos, 0, 0)
+
+		require.NoError(t, repos.APIKeyCmd.CreateAPIKey(context.Background(), users[0].ID, "key1", "secret1"))
+		require.NoError(t, repos.APIKeyCmd.CreateAPIKey(context.Background(), users[0].ID, "key2", "secret2"))
+		require.NoError(t, repos.APIKeyCmd.CreateAPIKey(context.Background(), users[1].ID, "key3", "secret3"))
+
+		// by key, whoever owns it
+		apiKeys, err := repos.APIKeyQuery.GetAPIKeys(context.Background(), 0, "key3")
+		require.NoError(t, err)
+		require.Len(t, apiKeys, 1)
+		assert.Equal(t, users[1].ID, apiKeys[0].UserID)
+		assert.Equal(t, "secret3", apiKeys[0].Secret)
+
+		apiKeys, err = repos.APIKeyQuery.GetAPIKeys(context.Background(), users[0].ID, "")
+		require.NoError(t, err)
+		assert.Len(t, apiKeys, 2)
+
+		apiKeys, err = repos.APIKeyQuery.GetAPIKeys(context.Background(), 0, "")
+		require.NoError(t, err)
+		assert.Len(t, apiKeys, 3)
+	})
+
+	t.Run("keys are unique", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.APIKeyCmd, repos.APIKeyQuery)
+		users := newUsers(t, repos, 0, 0)
+
+		require.NoError(t, repos.APIKeyCmd.CreateAPIKey(context.Background(), users[0].ID, "key1", "secret1"))
+		err := repos.APIKeyCmd.CreateAPIKey(context.Background(), users[1].ID, "key1", "secret2")
+		assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+	})
+
+	t.Run("get a missing key", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.APIKeyCmd, repos.APIKeyQuery)
+
+		_, err := repos.APIKeyQuery.GetAPIKeys(context.Background(), 0, "missing")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("delete only the key of the user", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.APIKeyCmd, repos.APIKeyQuery)
+		users := newUsers(t, repos, 0, 0)
+		require.NoError(t, repos.APIKeyCmd.CreateAPIKey(context.Background(), users[0].ID, "key1", "secret1"))
+		require.NoError(t, repos.APIKeyCmd.CreateAPIKey(context.Background(), users[0].ID, "key2", "secret2"))
+
+		// another user cannot delete it
+		require.NoError(t, repos.APIKeyCmd.DeleteAPIKey(context.Background(), users[1].ID, "key1"))
+		_, err := repos.APIKeyQuery.GetAPIKeys(context.Background(), 0, "key1")
+		assert.NoError(t, err)
+
+		require.NoError(t, repos.APIKeyCmd.DeleteAPIKey(context.Background(), users[0].ID, "key1"))
+		_, err = repos.APIKeyQuery.GetAPIKeys(context.Background(), 0, "key1")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		apiKeys, err := repos.APIKeyQuery.GetAPIKeys(context.Background(), users[0].ID, "")
+		require.NoError(t, err)
+		require.Len(t, apiKeys, 1)
+		assert.Equal(t, "key2", apiKeys[0].APIKey)
+	})
+}
@@ -0,0 +1,147 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+	"banking/utils"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAudit(t *testing.T, newRepos Factory) {
+	t.Run("empty chain starts at genesis", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.AuditCmd, repos.AuditQuery)
+
+		head, err := repos.AuditQuery.GetAuditChainHead(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, head.LastID)
+		assert.Equal(t, mysqlModel.AuditGenesisHash, head.Hash)
+	})
+
+	t.Run("appends link to the previous entry", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.AuditCmd, repos.AuditQuery)
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, repos.AuditCmd.AppendAuditLog(context.Background(), newAuditLog(i)))
+		}
+
+		assertChain(t, repos, 3)
+
+		// filters and paging
+		logs, err := repos.AuditQuery.GetAuditLogs(context.Background(), 0, "action1", time.Time{}, time.Time{}, 0, 100)
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, "target1", logs[0].Target)
+
+		first, err := repos.AuditQuery.GetAuditLogs(context.Background(), 0, "", time.Time{}, time.Time{}, 0, 2)
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+		rest, err := repos.AuditQuery.GetAuditLogs(context.Background(), 0, "", time.Time{}, time.Time{}, first[1].ID, 2)
+		require.NoError(t, err)
+		require.Len(t, rest, 1)
+		assert.Equal(t, "target2", rest[0].Target)
+	})
+
+	t.Run("concurrent appends keep one chain", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.AuditCmd, repos.AuditQuery)
+
+		const appends = 20
+		errs := make(chan error, appends)
+		var wg sync.WaitGroup
+		for i := 0; i < appends; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs <- repos.AuditCmd.AppendAuditLog(context.Background(), newAuditLog(i))
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		assertChain(t, repos, appends)
+	})
+
+	t.Run("transactions append their entry when they commit", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.AuditCmd, repos.AuditQuery)
+		users := newUsers(t, repos, 100, 0)
+
+		entry := newAuditLog(0)
+		transaction, err := repos.TransactionCmd.Transfer(utils.ContextWithAuditEntry(context.Background(), entry), users[0].ID, users[1].ID, decimal.NewFromFloat(30), nil, nil)
+		require.NoError(t, err)
+		assert.NotZero(t, entry.ID)
+		assert.Contains(t, entry.After, fmt.Sprintf(`"ID":%d`, transaction.ID))
+
+		// a rolled back transfer leaves no entry
+		_, err = repos.TransactionCmd.Transfer(utils.ContextWithAuditEntry(context.Background(), newAuditLog(1)), users[0].ID, users[1].ID, decimal.NewFromFloat(500), nil, nil)
+		require.Error(t, err)
+
+		assertChain(t, repos, 1)
+
+		// deposits and plain appends extend the same chain
+		const appends = 10
+		errs := make(chan error, 2*appends)
+		var wg sync.WaitGroup
+		for i := 0; i < appends; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				_, err := repos.TransactionCmd.Deposit(utils.ContextWithAuditEntry(context.Background(), newAuditLog(i)), users[1].ID, decimal.NewFromFloat(1), nil)
+				errs <- err
+			}(i)
+			go func(i int) {
+				defer wg.Done()
+				errs <- repos.AuditCmd.AppendAuditLog(context.Background(), newAuditLog(i))
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		assertChain(t, repos, 1+2*appends)
+	})
+}
+
+func newAuditLog(i int) *mysqlModel.AuditLog {
+	// the audit service truncates to the millisecond precision of the column, so the hash verifies after a read
+	return &mysqlModel.AuditLog{
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+		Action:    fmt.Sprintf("action%d", i),
+		Target:    fmt.Sprintf("target%d", i),
+		Outcome:   mysqlModel.AuditSuccess,
+	}
+}
+
+// assertChain checks every entry links to the one before it and the head points at the last
+func assertChain(t *testing.T, repos *Repos, length int) {
+	logs, err := repos.AuditQuery.GetAuditLogs(context.Background(), 0, "", time.Time{}, time.Time{}, 0, length+1)
+	require.NoError(t, err)
+	require.Len(t, logs, length)
+
+	prevHash := mysqlModel.AuditGenesisHash
+	for _, log := range logs {
+		assert.Equal(t, prevHash, log.PrevHash, "entry %d", log.ID)
+		assert.Equal(t, log.ComputeHash(), log.Hash, "entry %d", log.ID)
+		prevHash = log.Hash
+	}
+
+	head, err := repos.AuditQuery.GetAuditChainHead(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, logs[length-1].ID, head.LastID)
+	assert.Equal(t, prevHash, head.Hash)
+}
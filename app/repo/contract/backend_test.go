@@ -0,0 +1,128 @@
+package contract_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"banking/app/repo/contract"
+	"banking/app/repo/memory"
+	"banking/database/driver"
+	"banking/database/sqlite"
+	"banking/global"
+	"banking/tracing"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func Test_Memory(t *testing.T) {
+	global.Logger = zap.NewNop().Sugar()
+
+	contract.Run(t, func(t *testing.T) *contract.Repos {
+		store := memory.NewStore()
+		return &contract.Repos{
+			UserCmd:             memory.NewUserCommandRepo(store),
+			UserQuery:           memory.NewUserQueryRepo(store),
+			TransactionCmd:      memory.NewTransactionCommandRepo(store),
+			TransactionQuery:    memory.NewTransactionQueryRepo(store),
+			APIKeyCmd:           memory.NewAPIKeyCommandRepo(store),
+			APIKeyQuery:         memory.NewAPIKeyQueryRepo(store),
+			AuditCmd:            memory.NewAuditCommandRepo(store),
+			AuditQuery:          memory.NewAuditQueryRepo(store),
+			BucketCmd:           memory.NewBucketCommandRepo(store),
+			BucketQuery:         memory.NewBucketQueryRepo(store),
+			TransferQueueCmd:    memory.NewTransferQueueCommandRepo(store),
+			TransferQueueQuery:  memory.NewTransferQueueQueryRepo(store),
+			ProjectionCmd:       memory.NewProjectionCommandRepo(store),
+			ProjectionQuery:     memory.NewProjectionQueryRepo(store),
+			BalanceHistoryQuery: memory.NewBalanceHistoryQueryRepo(store),
+			InterestCmd:         memory.NewInterestCommandRepo(store),
+			AliasCmd:            memory.NewAliasCommandRepo(store),
+			AliasQuery:          memory.NewAliasQueryRepo(store),
+			ReconciliationCmd:   memory.NewReconciliationCommandRepo(store),
+			ReconciliationQuery: memory.NewReconciliationQueryRepo(store),
+			FraudCmd:            memory.NewFraudCommandRepo(store),
+			FraudQuery:          memory.NewFraudQueryRepo(store),
+		}
+	})
+}
+
+func Test_SQLite(t *testing.T) {
+	global.Logger = zap.NewNop().Sugar()
+
+	contract.Run(t, func(t *testing.T) *contract.Repos {
+		viper.Set("sqlite.path", filepath.Join(t.TempDir(), "banking.db"))
+		db, err := sqlite.NewSQLite(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			sqlDB, _ := db.DB.DB()
+			sqlDB.Close()
+		})
+
+		resetSchema(t, db.DB, driver.SQLite)
+		return contract.GormRepos(db.DB)
+	})
+}
+
+func Test_Postgres(t *testing.T) {
+	global.Logger = zap.NewNop().Sugar()
+
+	pool, resource := runDocker(t, &dockertest.RunOptions{
+		Repository:   "postgres",
+		Tag:          "16",
+		Env:          []string{"POSTGRES_PASSWORD=postgres_password", "POSTGRES_DB=banking"},
+		ExposedPorts: []string{"5432/tcp"},
+	})
+	dsn := fmt.Sprintf("postgres://postgres:postgres_password@%s/banking?sslmode=disable&TimeZone=UTC", resource.GetHostPort("5432/tcp"))
+	db := openDocker(t, pool, tracing.Default().PostgresDialector(dsn), dsn)
+
+	contract.Run(t, func(t *testing.T) *contract.Repos {
+		resetSchema(t, db, driver.Postgres)
+		return contract.GormRepos(db)
+	})
+}
+
+func Test_MySQL(t *testing.T) {
+	global.Logger = zap.NewNop().Sugar()
+
+	pool, resource := runDocker(t, &dockertest.RunOptions{
+		Repository:   "mysql",
+		Tag:          "8.0",
+		Env:          []string{"MYSQL_ROOT_PASSWORD=root_password", "MYSQL_DATABASE=banking"},
+		ExposedPorts: []string{"3306/tcp"},
+	})
+	dsn := fmt.Sprintf("root:root_password@tcp(%s)/banking?charset=utf8mb4&parseTime=True&loc=Local", resource.GetHostPort("3306/tcp"))
+	db := openDocker(t, pool, tracing.Default().MySQLDialector(dsn), dsn)
+
+	contract.Run(t, func(t *testing.T) *contract.Repos {
+		resetSchema(t, db, driver.MySQL)
+		return contract.GormRepos(db)
+	})
+}
+
+// openDocker waits until the server in the container accepts connections, then opens it like the apiserver does
+func openDocker(t *testing.T, pool *dockertest.Pool, dialector gorm.Dialector, dsn string) *gorm.DB {
+	pool.MaxWait = 2 * time.Minute
+	require.NoError(t, pool.Retry(func() error {
+		db, err := gorm.Open(dialector, &gorm.Config{})
+		if err != nil {
+			return err
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		defer sqlDB.Close()
+		return sqlDB.Ping()
+	}))
+
+	db, err := driver.Open(context.Background(), dialector, dsn)
+	require.NoError(t, err)
+	return db
+}
@@ -0,0 +1,132 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBalanceHistory checks the transactions of a user are found on either side, in the order their
+// balances were recorded
+func testBalanceHistory(t *testing.T, newRepos Factory) {
+	t.Run("transactions around a point in time", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.BalanceHistoryQuery)
+		users := newUsers(t, repos, 100, 50, 0)
+		payer, payee := users[0], users[1]
+
+		_, err := repos.TransactionCmd.Deposit(context.Background(), payer.ID, decimal.NewFromFloat(20), nil)
+		require.NoError(t, err)
+		_, err = repos.TransactionCmd.Transfer(context.Background(), payer.ID, payee.ID, decimal.NewFromFloat(30), nil, nil)
+		require.NoError(t, err)
+		_, err = repos.TransactionCmd.Transfer(context.Background(), payee.ID, payer.ID, decimal.NewFromFloat(5), nil, nil)
+		require.NoError(t, err)
+
+		// the times are those stored by the backend
+		epoch, future := time.Unix(0, 0), time.Now().Add(time.Hour)
+		transactions, err := repos.BalanceHistoryQuery.GetTransactionsBetween(context.Background(), payer.ID, epoch, future)
+		require.NoError(t, err)
+		require.Len(t, transactions, 3)
+		assert.Equal(t, mysqlModel.Deposit, transactions[0].TransactionType, "the deposit is on both sides and listed once")
+		assert.Equal(t, "120", transactions[0].BalanceOf(payer.ID).String())
+		assert.Equal(t, "90", transactions[1].BalanceOf(payer.ID).String())
+		assert.Equal(t, "95", transactions[2].BalanceOf(payer.ID).String())
+		assert.Equal(t, "100", transactions[0].BalanceBefore(payer.ID).String())
+
+		payeeTransactions, err := repos.BalanceHistoryQuery.GetTransactionsBetween(context.Background(), payee.ID, epoch, future)
+		require.NoError(t, err)
+		require.Len(t, payeeTransactions, 2)
+		assert.Equal(t, transactions[1].ID, payeeTransactions[0].ID)
+		assert.Equal(t, "75", payeeTransactions[1].BalanceOf(payee.ID).String())
+
+		last, err := repos.BalanceHistoryQuery.GetLastTransaction(context.Background(), payer.ID, future)
+		require.NoError(t, err)
+		require.NotNil(t, last)
+		assert.Equal(t, transactions[2].ID, last.ID)
+		assert.True(t, last.BalanceOf(payer.ID).Equal(balanceOf(t, repos, payer.ID)))
+
+		last, err = repos.BalanceHistoryQuery.GetLastTransaction(context.Background(), payer.ID, transactions[0].CreatedAt)
+		require.NoError(t, err)
+		assert.Nil(t, last)
+
+		next, err := repos.BalanceHistoryQuery.GetNextTransaction(context.Background(), payer.ID, transactions[0].CreatedAt)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, transactions[0].ID, next.ID)
+
+		next, err = repos.BalanceHistoryQuery.GetNextTransaction(context.Background(), payer.ID, future)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+
+		between, err := repos.BalanceHistoryQuery.GetTransactionsBetween(context.Background(), payer.ID, epoch, transactions[0].CreatedAt)
+		require.NoError(t, err)
+		assert.Empty(t, between, "to is excluded")
+	})
+
+	t.Run("hot account", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.BalanceHistoryQuery, repos.BucketCmd)
+		users := newUsers(t, repos, 50, 100)
+		merchant, payer := users[0], users[1]
+
+		_, err := repos.BucketCmd.SetBalanceBuckets(context.Background(), merchant.ID, 4)
+		require.NoError(t, err)
+		_, err = repos.TransactionCmd.Transfer(context.Background(), payer.ID, merchant.ID, decimal.NewFromFloat(30), nil, nil)
+		require.NoError(t, err)
+		_, err = repos.TransactionCmd.Deposit(context.Background(), merchant.ID, decimal.NewFromFloat(20), nil)
+		require.NoError(t, err)
+		_, err = repos.TransactionCmd.Transfer(context.Background(), merchant.ID, payer.ID, decimal.NewFromFloat(5), nil, nil)
+		require.NoError(t, err)
+
+		epoch, future := time.Unix(0, 0), time.Now().Add(time.Hour)
+		transactions, err := repos.BalanceHistoryQuery.GetTransactionsBetween(context.Background(), merchant.ID, epoch, future)
+		require.NoError(t, err)
+		require.Len(t, transactions, 3)
+
+		// the balances recorded by credits through buckets may be partial, they are walked from the postings
+		balance := decimal.NewFromFloat(50)
+		for i, want := range []string{"80", "100", "95"} {
+			balance = transactions[i].BalanceAfter(merchant.ID, balance)
+			assert.Equal(t, want, balance.String())
+		}
+		assert.True(t, transactions[2].BalanceExact(merchant.ID), "a debit drains the buckets")
+
+		// the transactions to start a balance from recorded it exactly
+		last, err := repos.BalanceHistoryQuery.GetLastTransaction(context.Background(), merchant.ID, future)
+		require.NoError(t, err)
+		require.NotNil(t, last)
+		assert.Equal(t, transactions[2].ID, last.ID)
+		assert.True(t, last.BalanceOf(merchant.ID).Equal(balanceOf(t, repos, merchant.ID)))
+
+		last, err = repos.BalanceHistoryQuery.GetLastTransaction(context.Background(), merchant.ID, transactions[2].CreatedAt)
+		require.NoError(t, err)
+		if last != nil {
+			assert.True(t, last.BalanceExact(merchant.ID))
+		}
+
+		next, err := repos.BalanceHistoryQuery.GetNextTransaction(context.Background(), merchant.ID, epoch)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.True(t, next.BalanceExact(merchant.ID))
+	})
+
+	t.Run("user without transactions", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.BalanceHistoryQuery)
+		users := newUsers(t, repos, 10)
+
+		last, err := repos.BalanceHistoryQuery.GetLastTransaction(context.Background(), users[0].ID, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Nil(t, last)
+
+		transactions, err := repos.BalanceHistoryQuery.GetTransactionsBetween(context.Background(), users[0].ID, time.Unix(0, 0), time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, transactions)
+	})
+}
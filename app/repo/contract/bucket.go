@@ -0,0 +1,172 @@
+package contract
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	bucketRepo "banking/app/repo/mysql/bucket"
+	transactionRepo "banking/app/repo/mysql/transaction"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBucket checks hot accounts behave like any other account, however their balance is stored
+func testBucket(t *testing.T, newRepos Factory) {
+	t.Run("credits of a hot account are all counted", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.BucketCmd, repos.BucketQuery)
+		users := newUsers(t, repos, 100, 0, 1000)
+		merchant, house, payer := users[0], users[1], users[2]
+
+		user, err := repos.BucketCmd.SetBalanceBuckets(context.Background(), merchant.ID, 4)
+		require.NoError(t, err)
+		assert.Equal(t, 4, user.BalanceBuckets)
+		assert.True(t, user.Balance.Equal(decimal.NewFromFloat(100)))
+
+		ids, err := repos.BucketQuery.GetHotAccountIDs(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []uint{merchant.ID}, ids)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, err := repos.TransactionCmd.Deposit(context.Background(), merchant.ID, decimal.NewFromFloat(5), nil)
+				assert.NoError(t, err)
+			}()
+			go func() {
+				defer wg.Done()
+				fee := &mysqlModel.FeeBreakdown{Total: decimal.NewFromFloat(0.5), HouseAccountID: house.ID}
+				_, err := repos.TransactionCmd.Transfer(context.Background(), payer.ID, merchant.ID, decimal.NewFromFloat(3), fee, nil)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.True(t, balanceOf(t, repos, merchant.ID).Equal(decimal.NewFromFloat(260)))
+		assert.True(t, balanceOf(t, repos, payer.ID).Equal(decimal.NewFromFloat(930)))
+		assert.True(t, balanceOf(t, repos, house.ID).Equal(decimal.NewFromFloat(10)))
+	})
+
+	t.Run("a hot house account collects fees", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.BucketCmd, repos.BucketQuery)
+		users := newUsers(t, repos, 0, 100)
+		house, payer := users[0], users[1]
+		_, err := repos.BucketCmd.SetBalanceBuckets(context.Background(), house.ID, 2)
+		require.NoError(t, err)
+
+		fee := &mysqlModel.FeeBreakdown{Total: decimal.NewFromFloat(1), HouseAccountID: house.ID}
+		for i := 0; i < 3; i++ {
+			_, err := repos.TransactionCmd.Withdraw(context.Background(), payer.ID, decimal.NewFromFloat(10), fee, nil)
+			require.NoError(t, err)
+		}
+
+		assert.True(t, balanceOf(t, repos, house.ID).Equal(decimal.NewFromFloat(3)))
+		assert.True(t, balanceOf(t, repos, payer.ID).Equal(decimal.NewFromFloat(67)))
+	})
+
+	t.Run("debits of a hot account draw on its buckets", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.BucketCmd, repos.BucketQuery)
+		users := newUsers(t, repos, 0, 0)
+		merchant, supplier := users[0], users[1]
+		_, err := repos.BucketCmd.SetBalanceBuckets(context.Background(), merchant.ID, 4)
+		require.NoError(t, err)
+
+		for i := 0; i < 4; i++ {
+			_, err := repos.TransactionCmd.Deposit(context.Background(), merchant.ID, decimal.NewFromFloat(10), nil)
+			require.NoError(t, err)
+		}
+
+		transaction, err := repos.TransactionCmd.Withdraw(context.Background(), merchant.ID, decimal.NewFromFloat(25), nil, nil)
+		require.NoError(t, err)
+		assert.True(t, transaction.FromUserBalance.Equal(decimal.NewFromFloat(15)))
+
+		transaction, err = repos.TransactionCmd.Transfer(context.Background(), merchant.ID, supplier.ID, decimal.NewFromFloat(15), nil, nil)
+		require.NoError(t, err)
+		assert.True(t, transaction.FromUserBalance.IsZero())
+
+		_, err = repos.TransactionCmd.Withdraw(context.Background(), merchant.ID, decimal.NewFromFloat(0.01), nil, nil)
+		assert.ErrorIs(t, err, transactionRepo.ErrInsufficientBalance)
+		assert.True(t, balanceOf(t, repos, merchant.ID).IsZero())
+	})
+
+	t.Run("consolidation and turning buckets off keep the balance", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.BucketCmd, repos.BucketQuery)
+		users := newUsers(t, repos, 50)
+		merchant := users[0]
+		_, err := repos.BucketCmd.SetBalanceBuckets(context.Background(), merchant.ID, 3)
+		require.NoError(t, err)
+		for i := 0; i < 5; i++ {
+			_, err := repos.TransactionCmd.Deposit(context.Background(), merchant.ID, decimal.NewFromFloat(2), nil)
+			require.NoError(t, err)
+		}
+
+		_, err = repos.BucketCmd.Consolidate(context.Background(), merchant.ID)
+		require.NoError(t, err)
+		assert.True(t, balanceOf(t, repos, merchant.ID).Equal(decimal.NewFromFloat(60)))
+
+		// fewer buckets, then none
+		_, err = repos.TransactionCmd.Deposit(context.Background(), merchant.ID, decimal.NewFromFloat(2), nil)
+		require.NoError(t, err)
+		user, err := repos.BucketCmd.SetBalanceBuckets(context.Background(), merchant.ID, 1)
+		require.NoError(t, err)
+		assert.True(t, user.Balance.Equal(decimal.NewFromFloat(62)))
+
+		_, err = repos.TransactionCmd.Deposit(context.Background(), merchant.ID, decimal.NewFromFloat(2), nil)
+		require.NoError(t, err)
+		user, err = repos.BucketCmd.SetBalanceBuckets(context.Background(), merchant.ID, 0)
+		require.NoError(t, err)
+		assert.Zero(t, user.BalanceBuckets)
+		assert.True(t, user.Balance.Equal(decimal.NewFromFloat(64)))
+		assert.True(t, balanceOf(t, repos, merchant.ID).Equal(decimal.NewFromFloat(64)))
+
+		ids, err := repos.BucketQuery.GetHotAccountIDs(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+
+	t.Run("interest capitalized to a hot account counts its buckets", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.BucketCmd, repos.BucketQuery, repos.InterestCmd)
+		users := newUsers(t, repos, 0)
+		merchant := users[0]
+		_, err := repos.BucketCmd.SetBalanceBuckets(context.Background(), merchant.ID, 2)
+		require.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			_, err := repos.TransactionCmd.Deposit(context.Background(), merchant.ID, decimal.NewFromFloat(10), nil)
+			require.NoError(t, err)
+		}
+
+		capitalization := &mysqlModel.InterestCapitalization{UserID: merchant.ID, Period: "2026-01", Posted: decimal.NewFromFloat(1.5)}
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, repos.InterestCmd.Capitalize(context.Background(), capitalization, from, from.AddDate(0, 1, 0)))
+
+		transactions, err := repos.TransactionQuery.GetTransactions(context.Background(), merchant.ID)
+		require.NoError(t, err)
+		for _, transaction := range transactions {
+			if transaction.TransactionType == mysqlModel.Interest {
+				assert.True(t, transaction.FromUserBalance.Equal(decimal.NewFromFloat(21.5)), transaction.FromUserBalance.String())
+			}
+		}
+		assert.True(t, balanceOf(t, repos, merchant.ID).Equal(decimal.NewFromFloat(21.5)))
+	})
+
+	t.Run("missing user", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.BucketCmd, repos.BucketQuery)
+
+		_, err := repos.BucketCmd.SetBalanceBuckets(context.Background(), 999, 4)
+		assert.ErrorIs(t, err, bucketRepo.ErrUserNotFound)
+		_, err = repos.BucketCmd.Consolidate(context.Background(), 999)
+		assert.ErrorIs(t, err, bucketRepo.ErrUserNotFound)
+	})
+}
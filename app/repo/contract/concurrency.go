@@ -0,0 +1,134 @@
+package contract
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	transactionRepo "banking/app/repo/mysql/transaction"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConcurrency runs balance updates of the same users at once, whatever interleaving the backend
+// allows, money is neither created nor lost and no balance goes below zero
+func testConcurrency(t *testing.T, newRepos Factory) {
+	t.Run("transfers conserve money", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100, 100, 100, 100)
+
+		// every pair in both directions, 7 does not divide 100 so some transfers run out of balance
+		const transfers = 200
+		var succeeded atomic.Int64
+		var wg sync.WaitGroup
+		for i := 0; i < transfers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				from, to := users[i%4], users[(i+1+(i/4)%3)%4]
+				if _, err := repos.TransactionCmd.Transfer(context.Background(), from.ID, to.ID, decimal.NewFromFloat(7), nil, nil); err == nil {
+					succeeded.Add(1)
+				} else {
+					// a deadlock or lock timeout is retried by the backend, it never reaches the caller
+					assert.ErrorIs(t, err, transactionRepo.ErrInsufficientBalance, "transfer %d -> %d", from.ID, to.ID)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		total, recorded := decimal.Zero, 0
+		for _, user := range users {
+			balance := balanceOf(t, repos, user.ID)
+			assert.False(t, balance.IsNegative(), "balance of user %d is %s", user.ID, balance)
+			total = total.Add(balance)
+
+			transactions, err := repos.TransactionQuery.GetTransactions(context.Background(), user.ID)
+			require.NoError(t, err)
+			recorded += len(transactions)
+		}
+		assert.True(t, total.Equal(decimal.NewFromFloat(400)), "total is %s", total)
+		assert.Positive(t, succeeded.Load())
+		assert.Equal(t, int(succeeded.Load()), recorded, "every transfer which succeeded is recorded once")
+	})
+
+	t.Run("opposite transfers with fees do not deadlock", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 0, 1000, 1000)
+		house, a, b := users[0], users[1], users[2]
+
+		// A -> B and B -> A at once, each also credits the house account. Locking in the order the
+		// rows are named would deadlock, the house account has the lowest id of the three
+		const transfers = 100
+		var wg sync.WaitGroup
+		for i := 0; i < transfers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				from, to := a, b
+				if i%2 == 1 {
+					from, to = b, a
+				}
+				fee := &mysqlModel.FeeBreakdown{Total: decimal.NewFromFloat(0.5), HouseAccountID: house.ID}
+				_, err := repos.TransactionCmd.Transfer(context.Background(), from.ID, to.ID, decimal.NewFromFloat(3), fee, nil)
+				assert.NoError(t, err, "transfer %d -> %d", from.ID, to.ID)
+			}(i)
+		}
+		wg.Wait()
+
+		assert.True(t, balanceOf(t, repos, house.ID).Equal(decimal.NewFromFloat(50)))
+		assert.True(t, balanceOf(t, repos, a.ID).Equal(decimal.NewFromFloat(975)))
+		assert.True(t, balanceOf(t, repos, b.ID).Equal(decimal.NewFromFloat(975)))
+	})
+
+	t.Run("withdrawals never overdraw", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100)
+
+		var succeeded atomic.Int64
+		var wg sync.WaitGroup
+		for i := 0; i < 30; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repos.TransactionCmd.Withdraw(context.Background(), users[0].ID, decimal.NewFromFloat(10), nil, nil)
+				if err == nil {
+					succeeded.Add(1)
+				} else {
+					assert.ErrorIs(t, err, transactionRepo.ErrInsufficientBalance)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int64(10), succeeded.Load())
+		assert.True(t, balanceOf(t, repos, users[0].ID).IsZero())
+	})
+
+	t.Run("deposits are not lost", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 0)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 30; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repos.TransactionCmd.Deposit(context.Background(), users[0].ID, decimal.NewFromFloat(1.5), nil)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.True(t, balanceOf(t, repos, users[0].ID).Equal(decimal.NewFromFloat(45)))
+		transactions, err := repos.TransactionQuery.GetTransactions(context.Background(), users[0].ID)
+		require.NoError(t, err)
+		assert.Len(t, transactions, 30)
+		for _, transaction := range transactions {
+			assert.Equal(t, mysqlModel.Deposit, transaction.TransactionType)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+// Package contract is the behaviour every storage backend of the repos has to share, so the services
+// work the same on MySQL, PostgreSQL, SQLite and the in-memory store. A backend runs the suite with
+// Run and a factory of empty repos
+package contract
+
+import (
+	"testing"
+
+	"banking/domain"
+)
+
+// Repos are the repos of one backend under test, they share one empty storage. Users and
+// transactions are required, the tests of a nil group are skipped
+type Repos struct {
+	UserCmd   domain.IUserCommandRepo
+	UserQuery domain.IUserQueryRepo
+
+	TransactionCmd   domain.ITransactionCommandRepo
+	TransactionQuery domain.ITransactionQueryRepo
+
+	APIKeyCmd   domain.IAPIKeyCommandRepo
+	APIKeyQuery domain.IAPIKeyQueryRepo
+
+	AuditCmd   domain.IAuditCommandRepo
+	AuditQuery domain.IAuditQueryRepo
+
+	BucketCmd   domain.IBucketCommandRepo
+	BucketQuery domain.IBucketQueryRepo
+
+	TransferQueueCmd   domain.ITransferQueueCommandRepo
+	TransferQueueQuery domain.ITransferQueueQueryRepo
+
+	ProjectionCmd   domain.IProjectionCommandRepo
+	ProjectionQuery domain.IProjectionQueryRepo
+
+	BalanceHistoryQuery domain.IBalanceHistoryQueryRepo
+
+	InterestCmd domain.IInterestCommandRepo
+
+	AliasCmd   domain.IAliasCommandRepo
+	AliasQuery domain.IAliasQueryRepo
+
+	ReconciliationCmd   domain.IReconciliationCommandRepo
+	ReconciliationQuery domain.IReconciliationQueryRepo
+
+	FraudCmd   domain.IFraudCommandRepo
+	FraudQuery domain.IFraudQueryRepo
+}
+
+// Factory returns repos on a new empty storage, it is called once per test
+type Factory func(t *testing.T) *Repos
+
+// Run runs every contract test against the backend of newRepos
+func Run(t *testing.T, newRepos Factory) {
+	t.Run("User", func(t *testing.T) {
+		testUser(t, newRepos)
+	})
+	t.Run("Transaction", func(t *testing.T) {
+		testTransaction(t, newRepos)
+	})
+	t.Run("Concurrency", func(t *testing.T) {
+		testConcurrency(t, newRepos)
+	})
+	t.Run("APIKey", func(t *testing.T) {
+		testAPIKey(t, newRepos)
+	})
+	t.Run("Audit", func(t *testing.T) {
+		testAudit(t, newRepos)
+	})
+	t.Run("Bucket", func(t *testing.T) {
+		testBucket(t, newRepos)
+	})
+	t.Run("TransferQueue", func(t *testing.T) {
+		testTransferQueue(t, newRepos)
+	})
+	t.Run("Projection", func(t *testing.T) {
+		testProjection(t, newRepos)
+	})
+	t.Run("BalanceHistory", func(t *testing.T) {
+		testBalanceHistory(t, newRepos)
+	})
+	t.Run("Alias", func(t *testing.T) {
+		testAlias(t, newRepos)
+	})
+	t.Run("Reconciliation", func(t *testing.T) {
+		testReconciliation(t, newRepos)
+	})
+	t.Run("Fraud", func(t *testing.T) {
+		testFraud(t, newRepos)
+	})
+}
+
+// requireRepos skips the test when the backend does not implement one of the repos
+func requireRepos(t *testing.T, repos ...interface{}) {
+	for _, repo := range repos {
+		if repo == nil {
+			t.Skip("the backend does not implement the repo")
+		}
+	}
+}
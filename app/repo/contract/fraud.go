@@ -0,0 +1,63 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	fraudRepo "banking/app/repo/mysql/fraud"
+	mysqlModel "banking/model/mysql"
+	"banking/utils"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFraud checks the transfer of an approved review commits only together with the link to its review
+func testFraud(t *testing.T, newRepos Factory) {
+	newReview := func(t *testing.T, repos *Repos, users []*mysqlModel.User, status mysqlModel.FraudReviewStatus) *mysqlModel.FraudReview {
+		review := &mysqlModel.FraudReview{FromUserID: users[0].ID, ToUserID: users[1].ID, Amount: decimal.NewFromFloat(30), Score: 60, Decision: mysqlModel.Review, Status: status}
+		require.NoError(t, repos.FraudCmd.CreateReview(context.Background(), review))
+		return review
+	}
+
+	t.Run("the transfer of an approved review is linked to it", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.FraudCmd, repos.FraudQuery)
+		users := newUsers(t, repos, 100, 0)
+		review := newReview(t, repos, users, mysqlModel.ReviewApproved)
+
+		ctx := utils.ContextWithApprovedReview(context.Background(), review.ID)
+		transaction, err := repos.TransactionCmd.Transfer(ctx, users[0].ID, users[1].ID, review.Amount, nil, review.Reference())
+		require.NoError(t, err)
+
+		got, err := repos.FraudQuery.GetReview(context.Background(), review.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got.TransactionID)
+		assert.Equal(t, transaction.ID, *got.TransactionID)
+
+		// executing it again fails, the review has its transaction
+		_, err = repos.TransactionCmd.Transfer(ctx, users[0].ID, users[1].ID, review.Amount, nil, review.Reference())
+		assert.ErrorIs(t, err, fraudRepo.ErrReviewNotApproved)
+		assert.True(t, balanceOf(t, repos, users[0].ID).Equal(decimal.NewFromFloat(70)))
+	})
+
+	t.Run("a review which is not approved fails the transfer", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.FraudCmd, repos.FraudQuery)
+		users := newUsers(t, repos, 100, 0)
+		review := newReview(t, repos, users, mysqlModel.ReviewPending)
+
+		ctx := utils.ContextWithApprovedReview(context.Background(), review.ID)
+		_, err := repos.TransactionCmd.Transfer(ctx, users[0].ID, users[1].ID, review.Amount, nil, review.Reference())
+		assert.ErrorIs(t, err, fraudRepo.ErrReviewNotApproved)
+
+		// no money moved
+		assert.True(t, balanceOf(t, repos, users[0].ID).Equal(decimal.NewFromFloat(100)))
+		assert.True(t, balanceOf(t, repos, users[1].ID).Equal(decimal.Zero))
+
+		got, err := repos.FraudQuery.GetReview(context.Background(), review.ID)
+		require.NoError(t, err)
+		assert.Nil(t, got.TransactionID)
+	})
+}
@@ -0,0 +1,72 @@
+package contract
+
+import (
+	"context"
+
+	aliasRepo "banking/app/repo/mysql/alias"
+	apiKeyRepo "banking/app/repo/mysql/apikey"
+	auditRepo "banking/app/repo/mysql/audit"
+	balanceHistoryRepo "banking/app/repo/mysql/balancehistory"
+	bucketRepo "banking/app/repo/mysql/bucket"
+	fraudRepo "banking/app/repo/mysql/fraud"
+	interestRepo "banking/app/repo/mysql/interest"
+	projectionRepo "banking/app/repo/mysql/projection"
+	reconciliationRepo "banking/app/repo/mysql/reconciliation"
+	transactionRepo "banking/app/repo/mysql/transaction"
+	transferQueueRepo "banking/app/repo/mysql/transferqueue"
+	userRepo "banking/app/repo/mysql/user"
+
+	"gorm.io/gorm"
+)
+
+// GormRepos are the gorm repos of every SQL driver on db, which serves as master and slave
+func GormRepos(db *gorm.DB) *Repos {
+	router := &masterRouter{db: db}
+
+	return &Repos{
+		UserCmd:   userRepo.NewUserCommandRepo(db),
+		UserQuery: userRepo.NewUserQueryRepo(router),
+
+		TransactionCmd:   transactionRepo.NewTransactionCommandRepo(db),
+		TransactionQuery: transactionRepo.NewTransactionQueryRepo(router),
+
+		APIKeyCmd:   apiKeyRepo.NewAPIKeyCommandRepo(db),
+		APIKeyQuery: apiKeyRepo.NewAPIKeyQueryRepo(db),
+
+		AuditCmd:   auditRepo.NewAuditCommandRepo(db),
+		AuditQuery: auditRepo.NewAuditQueryRepo(db),
+
+		BucketCmd:   bucketRepo.NewBucketCommandRepo(db),
+		BucketQuery: bucketRepo.NewBucketQueryRepo(db),
+
+		TransferQueueCmd:   transferQueueRepo.NewTransferQueueCommandRepo(db),
+		TransferQueueQuery: transferQueueRepo.NewTransferQueueQueryRepo(db),
+
+		ProjectionCmd:   projectionRepo.NewProjectionCommandRepo(db),
+		ProjectionQuery: projectionRepo.NewProjectionQueryRepo(db),
+
+		BalanceHistoryQuery: balanceHistoryRepo.NewBalanceHistoryQueryRepo(router),
+
+		InterestCmd: interestRepo.NewInterestCommandRepo(db),
+
+		AliasCmd:   aliasRepo.NewAliasCommandRepo(db),
+		AliasQuery: aliasRepo.NewAliasQueryRepo(db),
+
+		ReconciliationCmd:   reconciliationRepo.NewReconciliationCommandRepo(db),
+		ReconciliationQuery: reconciliationRepo.NewReconciliationQueryRepo(db),
+
+		FraudCmd:   fraudRepo.NewFraudCommandRepo(db),
+		FraudQuery: fraudRepo.NewFraudQueryRepo(db),
+	}
+}
+
+// masterRouter reads from the one database of the test, routing is covered by the routing tests
+type masterRouter struct {
+	db *gorm.DB
+}
+
+func (r *masterRouter) Reader(ctx context.Context, userID uint) *gorm.DB {
+	return r.db
+}
+
+func (r *masterRouter) Pin(ctx context.Context, userIDs ...uint) {}
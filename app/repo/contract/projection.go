@@ -0,0 +1,153 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	projectionRepo "banking/app/repo/mysql/projection"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testProjection checks the read models projected from the ledger events agree with the user rows and
+// transactions they replace, and that a rebuild projects them again the same
+func testProjection(t *testing.T, newRepos Factory) {
+	// transact moves money every way a balance can change, the house account collects the fees
+	transact := func(t *testing.T, repos *Repos, users []*mysqlModel.User) {
+		house, payer, payee := users[0], users[1], users[2]
+		fee := &mysqlModel.FeeBreakdown{Total: decimal.NewFromFloat(0.5), HouseAccountID: house.ID}
+
+		_, err := repos.TransactionCmd.Deposit(context.Background(), payer.ID, decimal.NewFromFloat(20), nil)
+		require.NoError(t, err)
+		_, err = repos.TransactionCmd.Withdraw(context.Background(), payer.ID, decimal.NewFromFloat(10), fee, nil)
+		require.NoError(t, err)
+		_, err = repos.TransactionCmd.Transfer(context.Background(), payer.ID, payee.ID, decimal.NewFromFloat(30), fee, nil)
+		require.NoError(t, err)
+		_, err = repos.TransactionCmd.Transfer(context.Background(), payee.ID, payer.ID, decimal.NewFromFloat(5), nil, nil)
+		require.NoError(t, err)
+	}
+
+	// project applies every event in batches of two
+	project := func(t *testing.T, repos *Repos) int {
+		var applied int
+		for {
+			_, batch, err := repos.ProjectionCmd.Project(context.Background(), 2, time.Minute)
+			require.NoError(t, err)
+			applied += batch
+			if batch < 2 {
+				return applied
+			}
+		}
+	}
+
+	// readModels returns the projected balances and transactions of users
+	readModels := func(t *testing.T, repos *Repos, users []*mysqlModel.User) (map[uint]decimal.Decimal, map[uint][]*mysqlModel.Transaction) {
+		userIDs := []uint{}
+		for _, user := range users {
+			userIDs = append(userIDs, user.ID)
+		}
+		balances, err := repos.ProjectionQuery.GetBalances(context.Background(), userIDs)
+		require.NoError(t, err)
+
+		transactions := make(map[uint][]*mysqlModel.Transaction)
+		for _, userID := range userIDs {
+			transactions[userID], err = projectionRepo.NewTransactionQueryRepo(repos.ProjectionQuery).GetTransactions(context.Background(), userID)
+			require.NoError(t, err)
+		}
+		return balances, transactions
+	}
+
+	t.Run("read models agree with the user rows and transactions", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.ProjectionCmd, repos.ProjectionQuery)
+		users := newUsers(t, repos, 0, 100, 50)
+		transact(t, repos, users)
+
+		// 3 opened accounts with a balance, 4 transactions and 2 fees
+		assert.Equal(t, 8, project(t, repos))
+
+		checkpoint, err := repos.ProjectionQuery.GetCheckpoint(context.Background())
+		require.NoError(t, err)
+		assert.NotZero(t, checkpoint.Position)
+
+		balances, transactions := readModels(t, repos, users)
+		for _, user := range users {
+			assert.True(t, balanceOf(t, repos, user.ID).Equal(balances[user.ID]), "user %d", user.ID)
+
+			want, err := repos.TransactionQuery.GetTransactions(context.Background(), user.ID)
+			require.NoError(t, err)
+			require.Len(t, transactions[user.ID], len(want), "user %d", user.ID)
+			for i, transaction := range want {
+				got := transactions[user.ID][i]
+				assert.Equal(t, transaction.ID, got.ID)
+				assert.Equal(t, transaction.TransactionType, got.TransactionType)
+				assert.Equal(t, transaction.ToUserID, got.ToUserID)
+				assert.True(t, transaction.Amount.Equal(got.Amount))
+				assert.True(t, transaction.Fee.Equal(got.Fee))
+				assert.True(t, transaction.FromUserBalance.Equal(got.FromUserBalance), "transaction %d", transaction.ID)
+				assert.True(t, transaction.ToUserBalance.Equal(got.ToUserBalance), "transaction %d", transaction.ID)
+			}
+		}
+
+		// the projection reader serves the same balances
+		users0, err := projectionRepo.NewUserQueryRepo(repos.UserQuery, repos.ProjectionQuery).GetUsers(context.Background(), 0)
+		require.NoError(t, err)
+		assert.Len(t, users0, 3)
+		for _, user := range users0 {
+			assert.True(t, balances[user.ID].Equal(user.Balance), "user %d", user.ID)
+		}
+
+		// nothing is applied twice
+		assert.Equal(t, 0, project(t, repos))
+	})
+
+	t.Run("rebuild projects the same read models", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.ProjectionCmd, repos.ProjectionQuery)
+		users := newUsers(t, repos, 0, 100, 50)
+		transact(t, repos, users)
+		project(t, repos)
+
+		position, err := repos.ProjectionCmd.Snapshot(context.Background())
+		require.NoError(t, err)
+		assert.NotZero(t, position)
+
+		transact(t, repos, users)
+		project(t, repos)
+		balances, transactions := readModels(t, repos, users)
+
+		applied, err := repos.ProjectionCmd.Rebuild(context.Background(), false, 2, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 14, applied)
+		rebuiltBalances, rebuiltTransactions := readModels(t, repos, users)
+		assert.Equal(t, balances, rebuiltBalances)
+		assert.Equal(t, transactions, rebuiltTransactions)
+
+		// a full rebuild drops the snapshots
+		checkpoint, err := repos.ProjectionQuery.GetCheckpoint(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, checkpoint.SnapshotPosition)
+
+		position, err = repos.ProjectionCmd.Snapshot(context.Background())
+		require.NoError(t, err)
+		transact(t, repos, users)
+		project(t, repos)
+		balances, transactions = readModels(t, repos, users)
+
+		applied, err = repos.ProjectionCmd.Rebuild(context.Background(), true, 2, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 6, applied, "only the events after the snapshot are replayed")
+		rebuiltBalances, rebuiltTransactions = readModels(t, repos, users)
+		assert.Equal(t, balances, rebuiltBalances)
+		assert.Equal(t, transactions, rebuiltTransactions)
+
+		checkpoint, err = repos.ProjectionQuery.GetCheckpoint(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, position, checkpoint.SnapshotPosition)
+		assert.Equal(t, position+6, checkpoint.Position)
+	})
+}
@@ -0,0 +1,58 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	reconciliationRepo "banking/app/repo/mysql/reconciliation"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testReconciliation checks a transaction reconciles one statement line, whichever import or
+// resolution links it first
+func testReconciliation(t *testing.T, newRepos Factory) {
+	statement := func(lines ...mysqlModel.BankStatementLine) *mysqlModel.BankStatement {
+		return &mysqlModel.BankStatement{Format: mysqlModel.MT940, StatementRef: "STMT-1", Account: "FR76", ImportedBy: 1, Lines: lines}
+	}
+	line := func(transactionID *uint) mysqlModel.BankStatementLine {
+		status := mysqlModel.Unmatched
+		if transactionID != nil {
+			status = mysqlModel.Matched
+		}
+		return mysqlModel.BankStatementLine{ValueDate: time.Now(), Direction: mysqlModel.Credit, Amount: decimal.NewFromFloat(20), Status: status, TransactionID: transactionID}
+	}
+
+	t.Run("a transaction is claimed by one line", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.ReconciliationCmd, repos.ReconciliationQuery)
+		users := newUsers(t, repos, 0)
+		deposit, err := repos.TransactionCmd.Deposit(context.Background(), users[0].ID, decimal.NewFromFloat(20), nil)
+		require.NoError(t, err)
+
+		// lines without a transaction do not claim any
+		first := statement(line(&deposit.ID), line(nil), line(nil))
+		require.NoError(t, repos.ReconciliationCmd.CreateStatement(context.Background(), first))
+
+		// an import matching it meanwhile fails as a whole
+		assert.ErrorIs(t, repos.ReconciliationCmd.CreateStatement(context.Background(), statement(line(nil), line(&deposit.ID))), reconciliationRepo.ErrTransactionClaimed)
+
+		// so does a resolution, the line keeps its status
+		unmatched := first.Lines[1]
+		unmatched.Status, unmatched.TransactionID = mysqlModel.Resolved, &deposit.ID
+		assert.ErrorIs(t, repos.ReconciliationCmd.UpdateStatementLine(context.Background(), &unmatched), reconciliationRepo.ErrTransactionClaimed)
+
+		got, err := repos.ReconciliationQuery.GetStatementLine(context.Background(), unmatched.ID)
+		require.NoError(t, err)
+		assert.Equal(t, mysqlModel.Unmatched, got.Status)
+		assert.Nil(t, got.TransactionID)
+
+		claimed, err := repos.ReconciliationQuery.IsTransactionClaimed(context.Background(), deposit.ID)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+	})
+}
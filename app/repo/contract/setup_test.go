@@ -0,0 +1,51 @@
+package contract_test
+
+import (
+	"context"
+	"testing"
+
+	"banking/database/migration"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// resetSchema reverts every migration of driverName applied to db and applies them again, so each
+// test of the suite starts from empty tables
+func resetSchema(t *testing.T, db *gorm.DB, driverName string) {
+	migrations, err := migration.Embedded(driverName)
+	require.NoError(t, err)
+
+	migrator := migration.NewMigrator(db, migrations)
+	_, err = migrator.Down(context.Background(), len(migrations))
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background(), 0)
+	require.NoError(t, err)
+}
+
+// runDocker starts a container for the backends of a server, the test is skipped without docker.
+// The container is removed when the test ends
+func runDocker(t *testing.T, options *dockertest.RunOptions) (*dockertest.Pool, *dockertest.Resource) {
+	pool, err := dockertest.NewPool("")
+	if err == nil {
+		err = pool.Client.Ping()
+	}
+	if err != nil {
+		t.Skipf("docker is not available: %s", err)
+	}
+
+	resource, err := pool.RunWithOptions(options, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := pool.Purge(resource); err != nil {
+			t.Logf("Could not purge resource: %s", err)
+		}
+	})
+
+	return pool, resource
+}
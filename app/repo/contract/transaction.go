@@ -0,0 +1,166 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	transactionRepo "banking/app/repo/mysql/transaction"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTransaction(t *testing.T, newRepos Factory) {
+	t.Run("transfer moves the amount", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100, 200)
+
+		transaction, err := repos.TransactionCmd.Transfer(context.Background(), users[0].ID, users[1].ID, decimal.NewFromFloat(30.5), nil, nil)
+		require.NoError(t, err)
+		assert.NotZero(t, transaction.ID)
+		assert.Equal(t, mysqlModel.Transfer, transaction.TransactionType)
+		assert.True(t, transaction.FromUserBalance.Equal(decimal.NewFromFloat(69.5)))
+		assert.True(t, transaction.ToUserBalance.Equal(decimal.NewFromFloat(230.5)))
+
+		assert.True(t, balanceOf(t, repos, users[0].ID).Equal(decimal.NewFromFloat(69.5)))
+		assert.True(t, balanceOf(t, repos, users[1].ID).Equal(decimal.NewFromFloat(230.5)))
+	})
+
+	t.Run("transfer with insufficient balance changes nothing", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100, 200)
+
+		_, err := repos.TransactionCmd.Transfer(context.Background(), users[0].ID, users[1].ID, decimal.NewFromFloat(100.01), nil, nil)
+		assert.ErrorIs(t, err, transactionRepo.ErrInsufficientBalance)
+
+		assert.True(t, balanceOf(t, repos, users[0].ID).Equal(decimal.NewFromFloat(100)))
+		assert.True(t, balanceOf(t, repos, users[1].ID).Equal(decimal.NewFromFloat(200)))
+		transactions, err := repos.TransactionQuery.GetTransactions(context.Background(), users[0].ID)
+		require.NoError(t, err)
+		assert.Empty(t, transactions)
+	})
+
+	t.Run("transfer to a missing user changes nothing", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100)
+
+		_, err := repos.TransactionCmd.Transfer(context.Background(), users[0].ID, 99, decimal.NewFromFloat(10), nil, nil)
+		assert.ErrorIs(t, err, transactionRepo.ErrUserNotFound)
+
+		assert.True(t, balanceOf(t, repos, users[0].ID).Equal(decimal.NewFromFloat(100)))
+	})
+
+	t.Run("transfer fee is credited to the house account", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100, 0, 0)
+		from, to, house := users[0], users[1], users[2]
+		fee := &mysqlModel.FeeBreakdown{Total: decimal.NewFromFloat(1.5), HouseAccountID: house.ID}
+
+		transaction, err := repos.TransactionCmd.Transfer(context.Background(), from.ID, to.ID, decimal.NewFromFloat(10), fee, nil)
+		require.NoError(t, err)
+		assert.True(t, transaction.Fee.Equal(decimal.NewFromFloat(1.5)))
+		assert.True(t, transaction.FromUserBalance.Equal(decimal.NewFromFloat(88.5)))
+
+		assert.True(t, balanceOf(t, repos, from.ID).Equal(decimal.NewFromFloat(88.5)))
+		assert.True(t, balanceOf(t, repos, to.ID).Equal(decimal.NewFromFloat(10)))
+		assert.True(t, balanceOf(t, repos, house.ID).Equal(decimal.NewFromFloat(1.5)))
+
+		// the payer sees the transfer and the fee charge
+		transactions, err := repos.TransactionQuery.GetTransactions(context.Background(), from.ID)
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+		types := []mysqlModel.TransactionType{transactions[0].TransactionType, transactions[1].TransactionType}
+		assert.ElementsMatch(t, []mysqlModel.TransactionType{mysqlModel.Transfer, mysqlModel.FeeCharge}, types)
+	})
+
+	t.Run("transfer fee to a missing house account changes nothing", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100, 0)
+		fee := &mysqlModel.FeeBreakdown{Total: decimal.NewFromFloat(1), HouseAccountID: 99}
+
+		_, err := repos.TransactionCmd.Transfer(context.Background(), users[0].ID, users[1].ID, decimal.NewFromFloat(10), fee, nil)
+		assert.ErrorIs(t, err, transactionRepo.ErrHouseAccountNotFound)
+
+		assert.True(t, balanceOf(t, repos, users[0].ID).Equal(decimal.NewFromFloat(100)))
+		assert.True(t, balanceOf(t, repos, users[1].ID).Equal(decimal.Zero))
+	})
+
+	t.Run("deposit", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100)
+
+		transaction, err := repos.TransactionCmd.Deposit(context.Background(), users[0].ID, decimal.NewFromFloat(0.25), nil)
+		require.NoError(t, err)
+		assert.Equal(t, mysqlModel.Deposit, transaction.TransactionType)
+		assert.True(t, transaction.ToUserBalance.Equal(decimal.NewFromFloat(100.25)))
+		assert.True(t, balanceOf(t, repos, users[0].ID).Equal(decimal.NewFromFloat(100.25)))
+
+		_, err = repos.TransactionCmd.Deposit(context.Background(), 99, decimal.NewFromFloat(1), nil)
+		assert.ErrorIs(t, err, transactionRepo.ErrUserNotFound)
+	})
+
+	t.Run("withdraw", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100, 0)
+		fee := &mysqlModel.FeeBreakdown{Total: decimal.NewFromFloat(2), HouseAccountID: users[1].ID}
+
+		transaction, err := repos.TransactionCmd.Withdraw(context.Background(), users[0].ID, decimal.NewFromFloat(40), fee, nil)
+		require.NoError(t, err)
+		assert.Equal(t, mysqlModel.Withdraw, transaction.TransactionType)
+		assert.True(t, transaction.FromUserBalance.Equal(decimal.NewFromFloat(58)))
+		assert.True(t, balanceOf(t, repos, users[0].ID).Equal(decimal.NewFromFloat(58)))
+		assert.True(t, balanceOf(t, repos, users[1].ID).Equal(decimal.NewFromFloat(2)))
+
+		// amount plus fee must be covered
+		_, err = repos.TransactionCmd.Withdraw(context.Background(), users[0].ID, decimal.NewFromFloat(57), fee, nil)
+		assert.ErrorIs(t, err, transactionRepo.ErrInsufficientBalance)
+		assert.True(t, balanceOf(t, repos, users[0].ID).Equal(decimal.NewFromFloat(58)))
+	})
+
+	t.Run("get transactions of the payer", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100, 100)
+
+		_, err := repos.TransactionCmd.Transfer(context.Background(), users[0].ID, users[1].ID, decimal.NewFromFloat(1), nil, nil)
+		require.NoError(t, err)
+		_, err = repos.TransactionCmd.Deposit(context.Background(), users[0].ID, decimal.NewFromFloat(1), nil)
+		require.NoError(t, err)
+		_, err = repos.TransactionCmd.Transfer(context.Background(), users[1].ID, users[0].ID, decimal.NewFromFloat(1), nil, nil)
+		require.NoError(t, err)
+
+		transactions, err := repos.TransactionQuery.GetTransactions(context.Background(), users[0].ID)
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+		for _, transaction := range transactions {
+			assert.Equal(t, users[0].ID, transaction.FromUserID)
+			assert.False(t, transaction.CreatedAt.IsZero())
+		}
+	})
+
+	t.Run("transactions are searched by reference", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100, 100)
+
+		reference := &mysqlModel.TransactionReference{Memo: "rent march", ExternalReference: "INV-2024-03"}
+		transaction, err := repos.TransactionCmd.Transfer(context.Background(), users[0].ID, users[1].ID, decimal.NewFromFloat(10), nil, reference)
+		require.NoError(t, err)
+		assert.Equal(t, "rent march", transaction.Details)
+		assert.Equal(t, "INV-2024-03", transaction.ExternalReference)
+		_, err = repos.TransactionCmd.Transfer(context.Background(), users[0].ID, users[1].ID, decimal.NewFromFloat(1), nil, nil)
+		require.NoError(t, err)
+
+		for _, search := range []string{"INV-2024-03", "rent march"} {
+			transactions, err := repos.TransactionQuery.GetTransactionsByReference(context.Background(), users[0].ID, search)
+			require.NoError(t, err)
+			require.Len(t, transactions, 1, search)
+			assert.Equal(t, transaction.ID, transactions[0].ID)
+		}
+
+		// only the transactions of the payer are searched
+		transactions, err := repos.TransactionQuery.GetTransactionsByReference(context.Background(), users[1].ID, "INV-2024-03")
+		require.NoError(t, err)
+		assert.Empty(t, transactions)
+	})
+}
@@ -0,0 +1,147 @@
+package contract
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	transferQueueRepo "banking/app/repo/mysql/transferqueue"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/utils"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTransferQueue checks async transfers leave the queue in order and each is claimed by one worker
+func testTransferQueue(t *testing.T, newRepos Factory) {
+	enqueue := func(t *testing.T, repos *Repos, count int) []*mysqlModel.TransferRequest {
+		requests := make([]*mysqlModel.TransferRequest, 0, count)
+		for i := 0; i < count; i++ {
+			request := &mysqlModel.TransferRequest{FromUserID: 1, ToUserID: 2, Amount: decimal.NewFromFloat(5), RequestID: "req"}
+			require.NoError(t, repos.TransferQueueCmd.Enqueue(context.Background(), request))
+			assert.Equal(t, mysqlModel.TransferQueued, request.Status)
+			requests = append(requests, request)
+		}
+		return requests
+	}
+
+	t.Run("claimed in order and finished", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.TransferQueueCmd, repos.TransferQueueQuery)
+		requests := enqueue(t, repos, 2)
+
+		claimed, err := repos.TransferQueueCmd.Claim(context.Background())
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, requests[0].ID, claimed.ID)
+		assert.Equal(t, mysqlModel.TransferProcessing, claimed.Status)
+		assert.NotNil(t, claimed.ClaimedAt)
+
+		transactionID := uint(42)
+		claimed.Status, claimed.TransactionID = mysqlModel.TransferCompleted, &transactionID
+		require.NoError(t, repos.TransferQueueCmd.Finish(context.Background(), claimed))
+
+		got, err := repos.TransferQueueQuery.GetTransferRequest(context.Background(), claimed.ID)
+		require.NoError(t, err)
+		assert.Equal(t, mysqlModel.TransferCompleted, got.Status)
+		assert.Equal(t, transactionID, *got.TransactionID)
+		assert.Equal(t, "req", got.RequestID)
+
+		// a finished request is not finished again
+		assert.ErrorIs(t, repos.TransferQueueCmd.Finish(context.Background(), claimed), transferQueueRepo.ErrTransferRequestNotProcessing)
+
+		claimed, err = repos.TransferQueueCmd.Claim(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, requests[1].ID, claimed.ID)
+
+		claimed, err = repos.TransferQueueCmd.Claim(context.Background())
+		require.NoError(t, err)
+		assert.Nil(t, claimed)
+
+		_, err = repos.TransferQueueQuery.GetTransferRequest(context.Background(), 999)
+		assert.ErrorIs(t, err, transferQueueRepo.ErrTransferRequestNotFound)
+	})
+
+	t.Run("concurrent workers claim each request once", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.TransferQueueCmd, repos.TransferQueueQuery)
+		enqueue(t, repos, 20)
+
+		var mu sync.Mutex
+		claims := make(map[uint]int)
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					claimed, err := repos.TransferQueueCmd.Claim(context.Background())
+					if !assert.NoError(t, err) || claimed == nil {
+						return
+					}
+					mu.Lock()
+					claims[claimed.ID]++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, claims, 20)
+		for id, count := range claims {
+			assert.Equal(t, 1, count, "request %d", id)
+		}
+	})
+
+	t.Run("stale claims are failed", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.TransferQueueCmd, repos.TransferQueueQuery)
+		enqueue(t, repos, 2)
+
+		stale, err := repos.TransferQueueCmd.Claim(context.Background())
+		require.NoError(t, err)
+
+		completed, failed, err := repos.TransferQueueCmd.FailStale(context.Background(), time.Now().Add(time.Minute), domain.CodeTransferInterrupted, "interrupted")
+		require.NoError(t, err)
+		assert.Zero(t, completed)
+		assert.Equal(t, int64(1), failed, "only the claimed request is stale, the queued one waits")
+
+		got, err := repos.TransferQueueQuery.GetTransferRequest(context.Background(), stale.ID)
+		require.NoError(t, err)
+		assert.Equal(t, mysqlModel.TransferFailed, got.Status)
+		assert.Equal(t, string(domain.CodeTransferInterrupted), got.ErrorCode)
+
+		// its worker comes back too late
+		stale.Status = mysqlModel.TransferCompleted
+		assert.ErrorIs(t, repos.TransferQueueCmd.Finish(context.Background(), stale), transferQueueRepo.ErrTransferRequestNotProcessing)
+	})
+	t.Run("stale claims whose transfer committed are completed", func(t *testing.T) {
+		repos := newRepos(t)
+		requireRepos(t, repos.TransferQueueCmd, repos.TransferQueueQuery)
+		users := newUsers(t, repos, 100, 0)
+		request := &mysqlModel.TransferRequest{FromUserID: users[0].ID, ToUserID: users[1].ID, Amount: decimal.NewFromFloat(5), RequestID: "req-1"}
+		require.NoError(t, repos.TransferQueueCmd.Enqueue(context.Background(), request))
+
+		// the worker commits the transfer and stops before finishing the request
+		stale, err := repos.TransferQueueCmd.Claim(context.Background())
+		require.NoError(t, err)
+		transaction, err := repos.TransactionCmd.Transfer(utils.ContextWithRequestID(context.Background(), "req-1"), users[0].ID, users[1].ID, request.Amount, nil, nil)
+		require.NoError(t, err)
+
+		completed, failed, err := repos.TransferQueueCmd.FailStale(context.Background(), time.Now().Add(time.Minute), domain.CodeTransferInterrupted, "interrupted")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), completed)
+		assert.Zero(t, failed)
+
+		got, err := repos.TransferQueueQuery.GetTransferRequest(context.Background(), stale.ID)
+		require.NoError(t, err)
+		assert.Equal(t, mysqlModel.TransferCompleted, got.Status)
+		require.NotNil(t, got.TransactionID)
+		assert.Equal(t, transaction.ID, *got.TransactionID)
+		assert.Empty(t, got.ErrorCode)
+	})
+}
@@ -0,0 +1,107 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	userRepo "banking/app/repo/mysql/user"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newUsers creates a user per balance, ids are left to the backend
+func newUsers(t *testing.T, repos *Repos, balances ...float64) []*mysqlModel.User {
+	users := make([]*mysqlModel.User, 0, len(balances))
+	for i, balance := range balances {
+		user := &mysqlModel.User{
+			Name:     fmt.Sprintf("user%d", i+1),
+			Email:    fmt.Sprintf("user%d@yopmail.com", i+1),
+			Password: "password",
+			Balance:  decimal.NewFromFloat(balance),
+		}
+		require.NoError(t, repos.UserCmd.CreateUser(context.Background(), user))
+		users = append(users, user)
+	}
+	return users
+}
+
+// balanceOf reads the balance of the user back from the backend
+func balanceOf(t *testing.T, repos *Repos, userID uint) decimal.Decimal {
+	users, err := repos.UserQuery.GetUsers(context.Background(), userID)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	return users[0].Balance
+}
+
+func testUser(t *testing.T, newRepos Factory) {
+	t.Run("create assigns ids and defaults", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100, 200)
+
+		assert.NotZero(t, users[0].ID)
+		assert.NotEqual(t, users[0].ID, users[1].ID)
+
+		found, err := repos.UserQuery.GetUsers(context.Background(), users[1].ID)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, users[1].Email, found[0].Email)
+		assert.Equal(t, "standard", found[0].Tier)
+		assert.True(t, found[0].Balance.Equal(decimal.NewFromFloat(200)))
+		assert.False(t, found[0].IsAdmin)
+	})
+
+	t.Run("emails are unique", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100)
+
+		err := repos.UserCmd.CreateUser(context.Background(), &mysqlModel.User{Name: "again", Email: users[0].Email, Password: "password"})
+		assert.ErrorIs(t, err, userRepo.ErrUserExisted)
+
+		found, err := repos.UserQuery.GetUsers(context.Background(), 0)
+		require.NoError(t, err)
+		assert.Len(t, found, 1)
+	})
+
+	t.Run("get users lists all without an id", func(t *testing.T) {
+		repos := newRepos(t)
+		newUsers(t, repos, 100, 200, 300)
+
+		found, err := repos.UserQuery.GetUsers(context.Background(), 0)
+		require.NoError(t, err)
+		assert.Len(t, found, 3)
+	})
+
+	t.Run("get users of a missing id", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.UserQuery.GetUsers(context.Background(), 99)
+		assert.ErrorIs(t, err, userRepo.ErrUserNotFound)
+	})
+
+	t.Run("get user by email", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 100)
+
+		found, err := repos.UserQuery.GetUserByEmail(context.Background(), users[0].Email)
+		require.NoError(t, err)
+		assert.Equal(t, users[0].ID, found.ID)
+		assert.Equal(t, "password", found.Password)
+
+		_, err = repos.UserQuery.GetUserByEmail(context.Background(), "missing@yopmail.com")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("user names", func(t *testing.T) {
+		repos := newRepos(t)
+		users := newUsers(t, repos, 0, 0)
+
+		names, err := repos.UserQuery.GetUserNames(context.Background(), []uint{users[0].ID, users[1].ID, 99})
+		require.NoError(t, err)
+		assert.Equal(t, map[uint]string{users[0].ID: "user1", users[1].ID: "user2"}, names)
+	})
+}
@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	aliasRepo "banking/app/repo/mysql/alias"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+)
+
+type aliasCommandRepo struct {
+	store *Store
+}
+
+func NewAliasCommandRepo(store *Store) domain.IAliasCommandRepo {
+	return &aliasCommandRepo{
+		store: store,
+	}
+}
+
+// CreateAlias keeps the unique indexes of MySQL, on user and value and on the verified value
+func (r *aliasCommandRepo) CreateAlias(ctx context.Context, alias *mysqlModel.PayeeAlias) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.aliasCommandRepo.CreateAlias", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.aliases {
+		if (existing.UserID == alias.UserID && existing.Value == alias.Value) || verifiedAs(existing, alias.VerifiedValue) {
+			return aliasRepo.ErrAliasTaken
+		}
+	}
+
+	now := time.Now()
+	alias.ID = r.store.nextID("payee_alias")
+	alias.CreatedAt, alias.UpdatedAt = now, now
+
+	stored := *alias
+	r.store.aliases = append(r.store.aliases, &stored)
+	return nil
+}
+
+func (r *aliasCommandRepo) RenewCode(ctx context.Context, alias *mysqlModel.PayeeAlias) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.aliasCommandRepo.RenewCode", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.aliases {
+		if stored.UserID != alias.UserID || stored.Value != alias.Value {
+			continue
+		}
+		if stored.Verified() {
+			return aliasRepo.ErrAliasTaken
+		}
+
+		stored.CodeHash, stored.CodeExpiresAt, stored.VerificationAttempts = alias.CodeHash, alias.CodeExpiresAt, 0
+		stored.UpdatedAt = time.Now()
+		*alias = *stored
+		return nil
+	}
+
+	return aliasRepo.ErrAliasTaken
+}
+
+func (r *aliasCommandRepo) CountAttempt(ctx context.Context, aliasID uint, maxAttempts int) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.aliasCommandRepo.CountAttempt", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := r.store.findAlias(aliasID)
+	if stored == nil {
+		return aliasRepo.ErrAliasNotFound
+	}
+	if stored.VerificationAttempts >= maxAttempts {
+		return aliasRepo.ErrAttemptsExhausted
+	}
+
+	stored.VerificationAttempts++
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *aliasCommandRepo) VerifyAlias(ctx context.Context, alias *mysqlModel.PayeeAlias) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.aliasCommandRepo.VerifyAlias", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := r.store.findAlias(alias.ID)
+	if stored == nil || stored.Verified() {
+		return aliasRepo.ErrAliasNotFound
+	}
+
+	for _, existing := range r.store.aliases {
+		if verifiedAs(existing, alias.VerifiedValue) {
+			return aliasRepo.ErrAliasTaken
+		}
+	}
+
+	stored.VerifiedValue, stored.VerifiedAt = alias.VerifiedValue, alias.VerifiedAt
+	stored.CodeHash, stored.CodeExpiresAt, stored.UpdatedAt = "", nil, time.Now()
+	return nil
+}
+
+func (r *aliasCommandRepo) DeleteAlias(ctx context.Context, userID, aliasID uint) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.aliasCommandRepo.DeleteAlias", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	kept := r.store.aliases[:0]
+	deleted := false
+	for _, alias := range r.store.aliases {
+		if alias.ID == aliasID && alias.UserID == userID {
+			deleted = true
+			continue
+		}
+		kept = append(kept, alias)
+	}
+	r.store.aliases = kept
+
+	if !deleted {
+		return aliasRepo.ErrAliasNotFound
+	}
+	return nil
+}
+
+type aliasQueryRepo struct {
+	store *Store
+}
+
+func NewAliasQueryRepo(store *Store) domain.IAliasQueryRepo {
+	return &aliasQueryRepo{
+		store: store,
+	}
+}
+
+func (r *aliasQueryRepo) GetAliases(ctx context.Context, userID uint) (aliases []*mysqlModel.PayeeAlias, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.aliasQueryRepo.GetAliases", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	aliases = []*mysqlModel.PayeeAlias{}
+	for _, alias := range r.store.aliases {
+		if alias.UserID == userID {
+			copied := *alias
+			aliases = append(aliases, &copied)
+		}
+	}
+	return aliases, nil
+}
+
+func (r *aliasQueryRepo) GetAlias(ctx context.Context, aliasID uint) (alias *mysqlModel.PayeeAlias, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.aliasQueryRepo.GetAlias", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := r.store.findAlias(aliasID)
+	if stored == nil {
+		return nil, aliasRepo.ErrAliasNotFound
+	}
+
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *aliasQueryRepo) GetVerifiedAlias(ctx context.Context, value string) (alias *mysqlModel.PayeeAlias, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.aliasQueryRepo.GetVerifiedAlias", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.aliases {
+		if verifiedAs(stored, &value) {
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	return nil, aliasRepo.ErrAliasNotFound
+}
+
+// findAlias returns the stored alias, not a copy, the caller holds the lock
+func (s *Store) findAlias(aliasID uint) *mysqlModel.PayeeAlias {
+	for _, alias := range s.aliases {
+		if alias.ID == aliasID {
+			return alias
+		}
+	}
+	return nil
+}
+
+// verifiedAs tells whether alias is verified with value
+func verifiedAs(alias *mysqlModel.PayeeAlias, value *string) bool {
+	return value != nil && alias.VerifiedValue != nil && *alias.VerifiedValue == *value
+}
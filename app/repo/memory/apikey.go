@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type apikeyCommandRepo struct {
+	store *Store
+}
+
+func NewAPIKeyCommandRepo(store *Store) domain.IAPIKeyCommandRepo {
+	return &apikeyCommandRepo{store: store}
+}
+
+func (r *apikeyCommandRepo) CreateAPIKey(ctx context.Context, userID uint, key string, secret string) error {
+	span, _ := tracing.StartSpan(ctx, "memory.apikeyCommandRepo.CreateAPIKey", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.apiKeys {
+		if existing.APIKey == key {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	now := time.Now()
+	apiKey := &mysqlModel.APIKey{UserID: userID, APIKey: key, Secret: secret}
+	apiKey.ID = r.store.nextID("api_key")
+	apiKey.CreatedAt, apiKey.UpdatedAt = now, now
+	r.store.apiKeys = append(r.store.apiKeys, apiKey)
+
+	return nil
+}
+
+func (r *apikeyCommandRepo) DeleteAPIKey(ctx context.Context, userID uint, key string) error {
+	span, _ := tracing.StartSpan(ctx, "memory.apikeyCommandRepo.DeleteAPIKey", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	kept := r.store.apiKeys[:0]
+	for _, apiKey := range r.store.apiKeys {
+		if apiKey.UserID != userID || apiKey.APIKey != key {
+			kept = append(kept, apiKey)
+		}
+	}
+	r.store.apiKeys = kept
+
+	return nil
+}
+
+type apikeyQueryRepo struct {
+	store *Store
+}
+
+func NewAPIKeyQueryRepo(store *Store) domain.IAPIKeyQueryRepo {
+	return &apikeyQueryRepo{store: store}
+}
+
+// GetAPIKeys returns the key when it is set, otherwise the keys of userID, otherwise all keys
+func (r *apikeyQueryRepo) GetAPIKeys(ctx context.Context, userID uint, key string) ([]*mysqlModel.APIKey, error) {
+	span, _ := tracing.StartSpan(ctx, "memory.apikeyQueryRepo.GetAPIKeys", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	apiKeys := make([]*mysqlModel.APIKey, 0)
+	for _, apiKey := range r.store.apiKeys {
+		if (key != "" && apiKey.APIKey != key) || (key == "" && userID != 0 && apiKey.UserID != userID) {
+			continue
+		}
+		copied := *apiKey
+		apiKeys = append(apiKeys, &copied)
+	}
+
+	if key != "" && len(apiKeys) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return apiKeys, nil
+}
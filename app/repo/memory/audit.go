@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+)
+
+type auditCommandRepo struct {
+	store *Store
+}
+
+func NewAuditCommandRepo(store *Store) domain.IAuditCommandRepo {
+	return &auditCommandRepo{
+		store: store,
+	}
+}
+
+// AppendAuditLog extends the hash chain under the store lock, so concurrent writers append one after another
+func (r *auditCommandRepo) AppendAuditLog(ctx context.Context, log *mysqlModel.AuditLog) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.auditCommandRepo.AppendAuditLog", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.appendAuditLog(log)
+	return nil
+}
+
+// appendAuditEntry appends the entry an action prepared with the result of the action as after value,
+// nothing for actions without an entry, the caller holds the lock
+func (s *Store) appendAuditEntry(log *mysqlModel.AuditLog, after interface{}) {
+	if log == nil {
+		return
+	}
+
+	// the store cannot roll back the action, an after value which does not marshal is left empty
+	data, _ := json.Marshal(after)
+	log.After = string(data)
+
+	s.appendAuditLog(log)
+}
+
+// appendAuditLog links the entry to the chain head and stores it, the caller holds the lock
+func (s *Store) appendAuditLog(log *mysqlModel.AuditLog) {
+	if s.auditHead == nil {
+		s.auditHead = &mysqlModel.AuditChainHead{ID: 1, Hash: mysqlModel.AuditGenesisHash}
+	}
+
+	log.PrevHash = s.auditHead.Hash
+	log.Hash = log.ComputeHash()
+	log.ID = s.nextID("audit_log")
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+
+	stored := *log
+	s.auditLogs = append(s.auditLogs, &stored)
+	s.auditHead.LastID, s.auditHead.Hash = log.ID, log.Hash
+}
+
+type auditQueryRepo struct {
+	store *Store
+}
+
+func NewAuditQueryRepo(store *Store) domain.IAuditQueryRepo {
+	return &auditQueryRepo{
+		store: store,
+	}
+}
+
+func (r *auditQueryRepo) GetAuditLogs(ctx context.Context, actorID uint, action string, from, to time.Time, afterID uint, limit int) (logs []*mysqlModel.AuditLog, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.auditQueryRepo.GetAuditLogs", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, log := range r.store.auditLogs {
+		if limit > 0 && len(logs) == limit {
+			break
+		}
+		switch {
+		case log.ID <= afterID,
+			actorID != 0 && (log.ActorID == nil || *log.ActorID != actorID),
+			action != "" && log.Action != action,
+			!from.IsZero() && log.CreatedAt.Before(from),
+			!to.IsZero() && !log.CreatedAt.Before(to):
+			continue
+		}
+
+		copied := *log
+		logs = append(logs, &copied)
+	}
+	return logs, nil
+}
+
+func (r *auditQueryRepo) GetAuditChainHead(ctx context.Context) (head *mysqlModel.AuditChainHead, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.auditQueryRepo.GetAuditChainHead", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.auditHead == nil {
+		return &mysqlModel.AuditChainHead{ID: 1, Hash: mysqlModel.AuditGenesisHash}, nil
+	}
+
+	copied := *r.store.auditHead
+	return &copied, nil
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+)
+
+// balanceHistoryQueryRepo scans the transactions, they are stored in the order their balances were recorded
+type balanceHistoryQueryRepo struct {
+	store *Store
+}
+
+func NewBalanceHistoryQueryRepo(store *Store) domain.IBalanceHistoryQueryRepo {
+	return &balanceHistoryQueryRepo{
+		store: store,
+	}
+}
+
+func (r *balanceHistoryQueryRepo) GetLastTransaction(ctx context.Context, userID uint, before time.Time) (transaction *mysqlModel.Transaction, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.balanceHistoryQueryRepo.GetLastTransaction", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := len(r.store.transactions) - 1; i >= 0; i-- {
+		stored := r.store.transactions[i]
+		if involves(stored, userID) && stored.BalanceExact(userID) && stored.CreatedAt.Before(before) {
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *balanceHistoryQueryRepo) GetNextTransaction(ctx context.Context, userID uint, from time.Time) (transaction *mysqlModel.Transaction, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.balanceHistoryQueryRepo.GetNextTransaction", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.transactions {
+		if involves(stored, userID) && stored.BalanceExact(userID) && !stored.CreatedAt.Before(from) {
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *balanceHistoryQueryRepo) GetTransactionsBetween(ctx context.Context, userID uint, from, to time.Time) (transactions []*mysqlModel.Transaction, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.balanceHistoryQueryRepo.GetTransactionsBetween", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.transactions {
+		if involves(stored, userID) && !stored.CreatedAt.Before(from) && stored.CreatedAt.Before(to) {
+			copied := *stored
+			transactions = append(transactions, &copied)
+		}
+	}
+	return transactions, nil
+}
+
+func involves(transaction *mysqlModel.Transaction, userID uint) bool {
+	return transaction.FromUserID == userID || transaction.ToUserID == userID
+}
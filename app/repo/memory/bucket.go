@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+
+	bucketRepo "banking/app/repo/mysql/bucket"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+)
+
+// The store mutex serializes every balance update, there is no row lock to spread over buckets. Hot
+// accounts keep their setting and their whole balance in the user
+type bucketCommandRepo struct {
+	store *Store
+}
+
+func NewBucketCommandRepo(store *Store) domain.IBucketCommandRepo {
+	return &bucketCommandRepo{
+		store: store,
+	}
+}
+
+func (r *bucketCommandRepo) SetBalanceBuckets(ctx context.Context, userID uint, buckets int) (user *mysqlModel.User, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.bucketCommandRepo.SetBalanceBuckets", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := r.store.findUser(userID)
+	if stored == nil {
+		return nil, bucketRepo.ErrUserNotFound
+	}
+	stored.BalanceBuckets = buckets
+
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *bucketCommandRepo) Consolidate(ctx context.Context, userID uint) (moved decimal.Decimal, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.bucketCommandRepo.Consolidate", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.findUser(userID) == nil {
+		return decimal.Zero, bucketRepo.ErrUserNotFound
+	}
+	return decimal.Zero, nil
+}
+
+type bucketQueryRepo struct {
+	store *Store
+}
+
+func NewBucketQueryRepo(store *Store) domain.IBucketQueryRepo {
+	return &bucketQueryRepo{
+		store: store,
+	}
+}
+
+func (r *bucketQueryRepo) GetHotAccountIDs(ctx context.Context) (userIDs []uint, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.bucketQueryRepo.GetHotAccountIDs", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, user := range r.store.users {
+		if user.BalanceBuckets > 0 {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+	return userIDs, nil
+}
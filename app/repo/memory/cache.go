@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"banking/domain"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// cacheTTL matches the expiry of the JWT and API key secrets cached in redis
+const cacheTTL = 1 * time.Hour
+
+// The caches return redis.Nil on a miss, the services tell a miss from a failure by it
+
+type jwtCommandRepo struct {
+	store *Store
+}
+
+func NewJWTCommandRepo(store *Store) domain.IRedisJWTCommandRepo {
+	return &jwtCommandRepo{store: store}
+}
+
+func (r *jwtCommandRepo) SetRedisJWT(ctx context.Context, email, token string) (err error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.setCache(fmt.Sprintf("jwt:%s", email), token, cacheTTL)
+	return nil
+}
+
+func (r *jwtCommandRepo) DeleteRedisJWT(ctx context.Context, email string) (err error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.cache, fmt.Sprintf("jwt:%s", email))
+	return nil
+}
+
+type jwtQueryRepo struct {
+	store *Store
+}
+
+func NewJWTQueryRepo(store *Store) domain.IRedisJWTQueryRepo {
+	return &jwtQueryRepo{store: store}
+}
+
+func (r *jwtQueryRepo) GetRedisJWT(ctx context.Context, email string) (token string, err error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	token, ok := r.store.getCache(fmt.Sprintf("jwt:%s", email))
+	if !ok {
+		return "", redis.Nil
+	}
+	return token, nil
+}
+
+type apikeyCacheCommandRepo struct {
+	store *Store
+}
+
+func NewAPIKeyCacheCommandRepo(store *Store) domain.IRedisAPIKeyCommandRepo {
+	return &apikeyCacheCommandRepo{store: store}
+}
+
+func (r *apikeyCacheCommandRepo) SetRedisAPIKey(ctx context.Context, userID uint, key string, secret string) (err error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.setCache(fmt.Sprintf("apiAuthKey:%d:%s", userID, key), secret, cacheTTL)
+	return nil
+}
+
+func (r *apikeyCacheCommandRepo) DeleteRedisAPIKey(ctx context.Context, userID uint, key string) (err error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.cache, fmt.Sprintf("apiAuthKey:%d:%s", userID, key))
+	return nil
+}
+
+type apikeyCacheQueryRepo struct {
+	store *Store
+}
+
+func NewAPIKeyCacheQueryRepo(store *Store) domain.IRedisAPIKeyQueryRepo {
+	return &apikeyCacheQueryRepo{store: store}
+}
+
+func (r *apikeyCacheQueryRepo) GetRedisAPIKey(ctx context.Context, userID uint, key string) (secret string, err error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	secret, ok := r.store.getCache(fmt.Sprintf("apiAuthKey:%d:%s", userID, key))
+	if !ok {
+		return "", redis.Nil
+	}
+	return secret, nil
+}
@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	fraudRepo "banking/app/repo/mysql/fraud"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+)
+
+type fraudCommandRepo struct {
+	store *Store
+}
+
+func NewFraudCommandRepo(store *Store) domain.IFraudCommandRepo {
+	return &fraudCommandRepo{
+		store: store,
+	}
+}
+
+func (r *fraudCommandRepo) CreateReview(ctx context.Context, review *mysqlModel.FraudReview) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.fraudCommandRepo.CreateReview", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	review.ID = r.store.nextID("fraud_review")
+	review.CreatedAt, review.UpdatedAt = now, now
+
+	stored := *review
+	r.store.fraudReviews = append(r.store.fraudReviews, &stored)
+	return nil
+}
+
+// UpdateReviewStatus moves the review from one status to another, a review in any other status is not pending
+func (r *fraudCommandRepo) UpdateReviewStatus(ctx context.Context, reviewID uint, from, to mysqlModel.FraudReviewStatus, adminID *uint) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.fraudCommandRepo.UpdateReviewStatus", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	review := r.store.findReview(reviewID)
+	if review == nil || review.Status != from {
+		return fraudRepo.ErrReviewNotPending
+	}
+
+	review.Status, review.ReviewedBy, review.UpdatedAt = to, adminID, time.Now()
+	return nil
+}
+
+// findReview returns the stored review, the caller holds the lock
+func (s *Store) findReview(reviewID uint) *mysqlModel.FraudReview {
+	for _, review := range s.fraudReviews {
+		if review.ID == reviewID {
+			return review
+		}
+	}
+	return nil
+}
+
+type fraudQueryRepo struct {
+	store *Store
+}
+
+func NewFraudQueryRepo(store *Store) domain.IFraudQueryRepo {
+	return &fraudQueryRepo{
+		store: store,
+	}
+}
+
+func (r *fraudQueryRepo) CountTransfers(ctx context.Context, fromUserID, toUserID uint, since time.Time) (count int64, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.fraudQueryRepo.CountTransfers", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, transaction := range r.store.transfersSince(fromUserID, since) {
+		if toUserID == 0 || transaction.ToUserID == toUserID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fraudQueryRepo) GetAverageTransferAmount(ctx context.Context, userID uint, since time.Time) (average decimal.Decimal, count int64, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.fraudQueryRepo.GetAverageTransferAmount", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	sum := decimal.Zero
+	for _, transaction := range r.store.transfersSince(userID, since) {
+		sum = sum.Add(transaction.Amount)
+		count++
+	}
+	if count == 0 {
+		return decimal.Zero, 0, nil
+	}
+
+	return sum.Div(decimal.NewFromInt(count)), count, nil
+}
+
+func (r *fraudQueryRepo) GetAPIKeyCreatedAt(ctx context.Context, key string) (createdAt time.Time, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.fraudQueryRepo.GetAPIKeyCreatedAt", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, apiKey := range r.store.apiKeys {
+		if apiKey.APIKey == key {
+			return apiKey.CreatedAt, nil
+		}
+	}
+	return time.Time{}, fraudRepo.ErrAPIKeyNotFound
+}
+
+func (r *fraudQueryRepo) GetReviews(ctx context.Context, status mysqlModel.FraudReviewStatus) (reviews []*mysqlModel.FraudReview, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.fraudQueryRepo.GetReviews", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, review := range r.store.fraudReviews {
+		if status == "" || review.Status == status {
+			copied := *review
+			reviews = append(reviews, &copied)
+		}
+	}
+	return reviews, nil
+}
+
+func (r *fraudQueryRepo) GetReview(ctx context.Context, reviewID uint) (review *mysqlModel.FraudReview, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.fraudQueryRepo.GetReview", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.fraudReviews {
+		if stored.ID == reviewID {
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	return nil, fraudRepo.ErrReviewNotFound
+}
+
+// transfersSince returns the stored transfers of fromUserID created at or after since, the caller holds the lock
+func (s *Store) transfersSince(fromUserID uint, since time.Time) []*mysqlModel.Transaction {
+	var transfers []*mysqlModel.Transaction
+	for _, transaction := range s.transactions {
+		if transaction.FromUserID == fromUserID && transaction.TransactionType == mysqlModel.Transfer && !transaction.CreatedAt.Before(since) {
+			transfers = append(transfers, transaction)
+		}
+	}
+	return transfers
+}
@@ -0,0 +1,28 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"banking/domain"
+)
+
+// healthQueryRepo stands in for the databases and redis in readiness checks, the store is always
+// reachable and has no replica to lag behind
+type healthQueryRepo struct{}
+
+func NewHealthQueryRepo() domain.IHealthQueryRepo {
+	return &healthQueryRepo{}
+}
+
+func NewRedisHealthQueryRepo() domain.IRedisHealthQueryRepo {
+	return &healthQueryRepo{}
+}
+
+func (r *healthQueryRepo) Ping(ctx context.Context) (err error) {
+	return nil
+}
+
+func (r *healthQueryRepo) ReplicaLag(ctx context.Context) (lag time.Duration, err error) {
+	return 0, nil
+}
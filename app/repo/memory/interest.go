@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	interestRepo "banking/app/repo/mysql/interest"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+type interestCommandRepo struct {
+	store *Store
+}
+
+func NewInterestCommandRepo(store *Store) domain.IInterestCommandRepo {
+	return &interestCommandRepo{
+		store: store,
+	}
+}
+
+func (r *interestCommandRepo) CreateAccrual(ctx context.Context, accrual *mysqlModel.InterestAccrual) (created bool, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.interestCommandRepo.CreateAccrual", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// one accrual per user and date makes re-running the batch for a day a no-op
+	for _, existing := range r.store.accruals {
+		if existing.UserID == accrual.UserID && existing.Date.Equal(accrual.Date) {
+			return false, nil
+		}
+	}
+
+	now := time.Now()
+	accrual.ID = r.store.nextID("interest_accrual")
+	accrual.CreatedAt, accrual.UpdatedAt = now, now
+
+	stored := *accrual
+	r.store.accruals = append(r.store.accruals, &stored)
+	return true, nil
+}
+
+func (r *interestCommandRepo) Capitalize(ctx context.Context, capitalization *mysqlModel.InterestCapitalization, from, to time.Time) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "memory.interestCommandRepo.Capitalize", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.capitalizations {
+		if existing.UserID == capitalization.UserID && existing.Period == capitalization.Period {
+			return interestRepo.ErrAlreadyCapitalized
+		}
+	}
+
+	user := r.store.findUser(capitalization.UserID)
+	if user == nil && capitalization.Posted.IsPositive() {
+		return interestRepo.ErrUserNotFound
+	}
+
+	now := time.Now()
+	capitalization.ID = r.store.nextID("interest_capitalization")
+	capitalization.CreatedAt, capitalization.UpdatedAt = now, now
+	for _, accrual := range r.store.accruals {
+		if accrual.UserID == capitalization.UserID && accrual.CapitalizationID == nil && !accrual.Date.Before(from) && accrual.Date.Before(to) {
+			capitalizationID := capitalization.ID
+			accrual.CapitalizationID = &capitalizationID
+		}
+	}
+
+	if capitalization.Posted.IsPositive() {
+		user.Balance = user.Balance.Add(capitalization.Posted)
+
+		transaction := &mysqlModel.Transaction{
+			FromUserID:      user.ID,
+			ToUserID:        user.ID,
+			Amount:          capitalization.Posted,
+			FromUserBalance: user.Balance,
+			ToUserBalance:   user.Balance,
+			TransactionType: mysqlModel.Interest,
+			Details:         fmt.Sprintf("interest for %s", capitalization.Period),
+			RequestID:       utils.RequestIDFromContext(ctx),
+		}
+		r.store.createTransaction(transaction)
+		capitalization.TransactionID = &transaction.ID
+	}
+
+	stored := *capitalization
+	r.store.capitalizations = append(r.store.capitalizations, &stored)
+	return nil
+}
+
+type interestQueryRepo struct {
+	store *Store
+}
+
+func NewInterestQueryRepo(store *Store) domain.IInterestQueryRepo {
+	return &interestQueryRepo{
+		store: store,
+	}
+}
+
+// GetInterestUsers pages through users with a rate plan ordered by id
+func (r *interestQueryRepo) GetInterestUsers(ctx context.Context, afterID uint, limit int) (users []*mysqlModel.User, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.interestQueryRepo.GetInterestUsers", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, user := range r.store.users {
+		if limit > 0 && len(users) == limit {
+			break
+		}
+		if user.ID > afterID && user.InterestPlan != "" {
+			users = append(users, &mysqlModel.User{Model: user.Model, Balance: user.Balance, InterestPlan: user.InterestPlan})
+		}
+	}
+	return users, nil
+}
+
+func (r *interestQueryRepo) GetAccrualUserIDs(ctx context.Context, from, to time.Time) (userIDs []uint, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.interestQueryRepo.GetAccrualUserIDs", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	seen := make(map[uint]bool)
+	for _, accrual := range r.store.accruals {
+		if accrual.CapitalizationID == nil && !accrual.Date.Before(from) && accrual.Date.Before(to) && !seen[accrual.UserID] {
+			seen[accrual.UserID] = true
+			userIDs = append(userIDs, accrual.UserID)
+		}
+	}
+	sort.Slice(userIDs, func(i, j int) bool {
+		return userIDs[i] < userIDs[j]
+	})
+
+	return userIDs, nil
+}
+
+func (r *interestQueryRepo) SumUncapitalizedAccruals(ctx context.Context, userID uint, from, to time.Time) (sum decimal.Decimal, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.interestQueryRepo.SumUncapitalizedAccruals", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	sum = decimal.Zero
+	for _, accrual := range r.store.accruals {
+		if accrual.UserID == userID && accrual.CapitalizationID == nil && accrual.Date.Before(to) && (from.IsZero() || !accrual.Date.Before(from)) {
+			sum = sum.Add(accrual.Amount)
+		}
+	}
+	return sum, nil
+}
+
+func (r *interestQueryRepo) GetLastCarry(ctx context.Context, userID uint) (carry decimal.Decimal, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.interestQueryRepo.GetLastCarry", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var last *mysqlModel.InterestCapitalization
+	for _, capitalization := range r.store.capitalizations {
+		if capitalization.UserID == userID && (last == nil || capitalization.Period > last.Period) {
+			last = capitalization
+		}
+	}
+	if last == nil {
+		return decimal.Zero, nil
+	}
+
+	return last.Carry, nil
+}
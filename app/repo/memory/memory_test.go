@@ -0,0 +1,182 @@
+package memory_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"banking/app/repo/memory"
+	transactionRepo "banking/app/repo/mysql/transaction"
+	userRepo "banking/app/repo/mysql/user"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	redisModel "banking/model/redis"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newUsers(t *testing.T, store *memory.Store, balances ...float64) []*mysqlModel.User {
+	users := make([]*mysqlModel.User, 0, len(balances))
+	for i, balance := range balances {
+		user := &mysqlModel.User{
+			Name:    "user",
+			Email:   string(rune('a'+i)) + "@yopmail.com",
+			Balance: decimal.NewFromFloat(balance),
+		}
+		require.NoError(t, memory.NewUserCommandRepo(store).CreateUser(context.Background(), user))
+		users = append(users, user)
+	}
+	return users
+}
+
+func Test_CreateUser(t *testing.T) {
+	store := memory.NewStore()
+	users := newUsers(t, store, 100, 200)
+
+	assert.Equal(t, uint(1), users[0].ID)
+	assert.Equal(t, uint(2), users[1].ID)
+	assert.Equal(t, "standard", users[0].Tier)
+
+	err := memory.NewUserCommandRepo(store).CreateUser(context.Background(), &mysqlModel.User{Email: users[0].Email})
+	assert.ErrorIs(t, err, userRepo.ErrUserExisted)
+
+	found, err := memory.NewUserQueryRepo(store).GetUsers(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	_, err = memory.NewUserQueryRepo(store).GetUserByEmail(context.Background(), "missing@yopmail.com")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// callers get copies, changing them does not change the store
+	found[0].Balance = decimal.Zero
+	again, err := memory.NewUserQueryRepo(store).GetUsers(context.Background(), users[0].ID)
+	assert.NoError(t, err)
+	assert.True(t, again[0].Balance.Equal(decimal.NewFromFloat(100)))
+}
+
+func Test_Transfer(t *testing.T) {
+	store := memory.NewStore()
+	users := newUsers(t, store, 100, 0, 0)
+	from, to, house := users[0], users[1], users[2]
+	repo := memory.NewTransactionCommandRepo(store)
+
+	t.Run("insufficient balance leaves balances untouched", func(t *testing.T) {
+		_, err := repo.Transfer(context.Background(), from.ID, to.ID, decimal.NewFromFloat(100), &mysqlModel.FeeBreakdown{Total: decimal.NewFromFloat(1), HouseAccountID: house.ID}, nil)
+		assert.ErrorIs(t, err, transactionRepo.ErrInsufficientBalance)
+	})
+
+	t.Run("missing house account leaves balances untouched", func(t *testing.T) {
+		_, err := repo.Transfer(context.Background(), from.ID, to.ID, decimal.NewFromFloat(10), &mysqlModel.FeeBreakdown{Total: decimal.NewFromFloat(1), HouseAccountID: 99}, nil)
+		assert.ErrorIs(t, err, transactionRepo.ErrHouseAccountNotFound)
+	})
+
+	t.Run("fee is credited to the house account", func(t *testing.T) {
+		transaction, err := repo.Transfer(context.Background(), from.ID, to.ID, decimal.NewFromFloat(10), &mysqlModel.FeeBreakdown{Total: decimal.NewFromFloat(1), HouseAccountID: house.ID}, nil)
+		assert.NoError(t, err)
+		assert.True(t, transaction.FromUserBalance.Equal(decimal.NewFromFloat(89)))
+		assert.True(t, transaction.ToUserBalance.Equal(decimal.NewFromFloat(10)))
+
+		transactions, err := memory.NewTransactionQueryRepo(store).GetTransactions(context.Background(), from.ID)
+		assert.NoError(t, err)
+		assert.Len(t, transactions, 2)
+		assert.Equal(t, mysqlModel.FeeCharge, transactions[1].TransactionType)
+		assert.True(t, transactions[1].ToUserBalance.Equal(decimal.NewFromFloat(1)))
+	})
+}
+
+func Test_Transfer_Concurrent(t *testing.T) {
+	store := memory.NewStore()
+	users := newUsers(t, store, 100, 100)
+	repo := memory.NewTransactionCommandRepo(store)
+
+	// transfers in both directions at once, every one either moves the amount or fails whole
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			from, to := users[i%2], users[(i+1)%2]
+			_, _ = repo.Transfer(context.Background(), from.ID, to.ID, decimal.NewFromFloat(3), nil, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	found, err := memory.NewUserQueryRepo(store).GetUsers(context.Background(), 0)
+	require.NoError(t, err)
+	assert.True(t, found[0].Balance.Add(found[1].Balance).Equal(decimal.NewFromFloat(200)))
+	assert.False(t, found[0].Balance.IsNegative())
+	assert.False(t, found[1].Balance.IsNegative())
+}
+
+func Test_Caches(t *testing.T) {
+	store := memory.NewStore()
+	cmdRepo, queryRepo := memory.NewJWTCommandRepo(store), memory.NewJWTQueryRepo(store)
+
+	_, err := queryRepo.GetRedisJWT(context.Background(), "user1@yopmail.com")
+	assert.Equal(t, redis.Nil, err)
+
+	assert.NoError(t, cmdRepo.SetRedisJWT(context.Background(), "user1@yopmail.com", "token"))
+	token, err := queryRepo.GetRedisJWT(context.Background(), "user1@yopmail.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token)
+
+	assert.NoError(t, cmdRepo.DeleteRedisJWT(context.Background(), "user1@yopmail.com"))
+	_, err = queryRepo.GetRedisJWT(context.Background(), "user1@yopmail.com")
+	assert.Equal(t, redis.Nil, err)
+}
+
+func Test_TakeRateLimit(t *testing.T) {
+	repo := memory.NewRateLimitCommandRepo(memory.NewStore())
+
+	for want := int64(1); want >= 0; want-- {
+		remaining, _, err := repo.TakeRateLimit(context.Background(), "key", 2, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, want, remaining)
+	}
+
+	remaining, reset, err := repo.TakeRateLimit(context.Background(), "key", 2, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), remaining)
+	assert.Positive(t, reset)
+
+	// budgets are per key
+	remaining, _, err = repo.TakeRateLimit(context.Background(), "other", 2, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), remaining)
+}
+
+func Test_Stream(t *testing.T) {
+	global.Logger = zap.NewNop().Sugar()
+
+	store := memory.NewStore()
+	cmdRepo, queryRepo := memory.NewStreamCommandRepo(store), memory.NewStreamQueryRepo(store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	live, err := queryRepo.SubscribeEvents(ctx)
+	require.NoError(t, err)
+
+	first := &redisModel.BalanceEvent{UserID: 1, Balance: decimal.NewFromFloat(10)}
+	second := &redisModel.BalanceEvent{UserID: 1, Balance: decimal.NewFromFloat(20)}
+	assert.NoError(t, cmdRepo.AppendEvent(context.Background(), first))
+	assert.NoError(t, cmdRepo.AppendEvent(context.Background(), second))
+	assert.NotEqual(t, first.ID, second.ID)
+
+	events, err := queryRepo.GetEventsAfter(context.Background(), 1, first.ID)
+	assert.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, second.ID, events[0].ID)
+
+	assert.Equal(t, first.ID, (<-live).ID)
+	assert.Equal(t, second.ID, (<-live).ID)
+
+	cancel()
+	_, open := <-live
+	assert.False(t, open)
+}
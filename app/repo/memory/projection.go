@@ -0,0 +1,232 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	projectionRepo "banking/app/repo/mysql/projection"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+)
+
+type projectionCommandRepo struct {
+	store *Store
+}
+
+func NewProjectionCommandRepo(store *Store) domain.IProjectionCommandRepo {
+	return &projectionCommandRepo{
+		store: store,
+	}
+}
+
+func (r *projectionCommandRepo) Project(ctx context.Context, limit int, gapTimeout time.Duration) (last *mysqlModel.LedgerEvent, applied int, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.projectionCommandRepo.Project", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	last, applied = r.project(limit, gapTimeout)
+	return last, applied, nil
+}
+
+// ExpireGaps has nothing to expire, events are appended under the lock of the store and never leave a gap
+func (r *projectionCommandRepo) ExpireGaps(ctx context.Context, before time.Time) (expired int, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.projectionCommandRepo.ExpireGaps", "repo")
+	defer span.End()
+
+	return 0, nil
+}
+
+func (r *projectionCommandRepo) Snapshot(ctx context.Context) (position uint, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.projectionCommandRepo.Snapshot", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	checkpoint := r.store.projectionCheckpoint()
+	position = checkpoint.Position
+	if position == checkpoint.SnapshotPosition {
+		return position, nil
+	}
+
+	// the previous snapshot is kept, like the gorm repo
+	snapshots := []*mysqlModel.BalanceSnapshot{}
+	for _, snapshot := range r.store.balanceSnapshots {
+		if snapshot.Position >= checkpoint.SnapshotPosition {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	now := time.Now()
+	for _, userID := range r.store.projectedUserIDs() {
+		snapshots = append(snapshots, &mysqlModel.BalanceSnapshot{
+			Position:  position,
+			UserID:    userID,
+			Balance:   r.store.balanceProjections[userID].Balance,
+			CreatedAt: now,
+		})
+	}
+	r.store.balanceSnapshots = snapshots
+
+	checkpoint.SnapshotPosition, checkpoint.UpdatedAt = position, now
+	return position, nil
+}
+
+func (r *projectionCommandRepo) Rebuild(ctx context.Context, fromSnapshot bool, limit int, gapTimeout time.Duration) (applied int, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.projectionCommandRepo.Rebuild", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	checkpoint := r.store.projectionCheckpoint()
+	position := uint(0)
+	if fromSnapshot {
+		position = checkpoint.SnapshotPosition
+	}
+
+	r.store.balanceProjections = make(map[uint]*mysqlModel.BalanceProjection)
+	history := []*mysqlModel.TransactionHistory{}
+	for _, row := range r.store.history {
+		if row.EventID <= position {
+			history = append(history, row)
+		}
+	}
+	r.store.history = history
+
+	if position > 0 {
+		for _, snapshot := range r.store.balanceSnapshots {
+			if snapshot.Position == position {
+				r.store.balanceProjections[snapshot.UserID] = &mysqlModel.BalanceProjection{UserID: snapshot.UserID, Balance: snapshot.Balance, EventID: position}
+			}
+		}
+	} else {
+		r.store.balanceSnapshots = nil
+		checkpoint.SnapshotPosition = 0
+	}
+
+	checkpoint.Position = position
+	for {
+		_, batch := r.project(limit, gapTimeout)
+		applied += batch
+		if batch < limit {
+			return applied, nil
+		}
+	}
+}
+
+// project applies at most limit events after the checkpoint, the caller holds the lock
+func (r *projectionCommandRepo) project(limit int, gapTimeout time.Duration) (last *mysqlModel.LedgerEvent, applied int) {
+	checkpoint := r.store.projectionCheckpoint()
+
+	// the events are appended in id order
+	start := sort.Search(len(r.store.ledgerEvents), func(i int) bool {
+		return r.store.ledgerEvents[i].ID > checkpoint.Position
+	})
+	end := min(start+limit, len(r.store.ledgerEvents))
+	// the events are appended under the lock, no id is ever skipped
+	events, _ := projectionRepo.Contiguous(r.store.ledgerEvents[start:end], checkpoint.Position, gapTimeout)
+	if len(events) == 0 {
+		return nil, 0
+	}
+
+	balances := make(map[uint]decimal.Decimal)
+	for userID, projection := range r.store.balanceProjections {
+		balances[userID] = projection.Balance
+	}
+
+	now := time.Now()
+	for _, event := range events {
+		for _, row := range projectionRepo.Apply(balances, event) {
+			row.ID = r.store.nextID("transaction_history")
+			r.store.history = append(r.store.history, row)
+		}
+		for userID := range event.Postings() {
+			r.store.balanceProjections[userID] = &mysqlModel.BalanceProjection{UserID: userID, Balance: balances[userID], EventID: event.ID, UpdatedAt: now}
+		}
+	}
+
+	copied := *events[len(events)-1]
+	checkpoint.Position, checkpoint.UpdatedAt = copied.ID, now
+	return &copied, len(events)
+}
+
+// projectionCheckpoint returns the stored checkpoint and creates it on the first projection, the
+// caller holds the lock
+func (s *Store) projectionCheckpoint() *mysqlModel.ProjectionCheckpoint {
+	if s.checkpoint == nil {
+		s.checkpoint = &mysqlModel.ProjectionCheckpoint{Name: projectionRepo.Checkpoint, UpdatedAt: time.Now()}
+	}
+	return s.checkpoint
+}
+
+// projectedUserIDs returns the users of the balance projection in id order, the caller holds the lock
+func (s *Store) projectedUserIDs() []uint {
+	userIDs := make([]uint, 0, len(s.balanceProjections))
+	for userID := range s.balanceProjections {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+	return userIDs
+}
+
+type projectionQueryRepo struct {
+	store *Store
+}
+
+func NewProjectionQueryRepo(store *Store) domain.IProjectionQueryRepo {
+	return &projectionQueryRepo{
+		store: store,
+	}
+}
+
+func (r *projectionQueryRepo) GetCheckpoint(ctx context.Context) (checkpoint *mysqlModel.ProjectionCheckpoint, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.projectionQueryRepo.GetCheckpoint", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.checkpoint == nil {
+		return &mysqlModel.ProjectionCheckpoint{Name: projectionRepo.Checkpoint}, nil
+	}
+	copied := *r.store.checkpoint
+	return &copied, nil
+}
+
+func (r *projectionQueryRepo) GetBalances(ctx context.Context, userIDs []uint) (balances map[uint]decimal.Decimal, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.projectionQueryRepo.GetBalances", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	balances = make(map[uint]decimal.Decimal)
+	for _, userID := range userIDs {
+		if projection, ok := r.store.balanceProjections[userID]; ok {
+			balances[userID] = projection.Balance
+		}
+	}
+	return balances, nil
+}
+
+func (r *projectionQueryRepo) GetHistory(ctx context.Context, fromUserID uint) (history []*mysqlModel.TransactionHistory, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.projectionQueryRepo.GetHistory", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, row := range r.store.history {
+		if row.FromUserID == fromUserID && row.TransactionID != nil {
+			copied := *row
+			history = append(history, &copied)
+		}
+	}
+	return history, nil
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"banking/domain"
+)
+
+type rateLimitCommandRepo struct {
+	store *Store
+}
+
+func NewRateLimitCommandRepo(store *Store) domain.IRedisRateLimitCommandRepo {
+	return &rateLimitCommandRepo{store: store}
+}
+
+// TakeRateLimit keeps the fixed window of the redis script, the window starts with the first request
+// and its budget is not refilled before it ends
+func (r *rateLimitCommandRepo) TakeRateLimit(ctx context.Context, key string, limit int64, duration time.Duration) (remaining, reset int64, err error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	cacheKey := fmt.Sprintf("rate_limit:%s", key)
+	current, ok := r.store.getCache(cacheKey)
+	if !ok {
+		r.store.setCache(cacheKey, strconv.FormatInt(limit-1, 10), duration)
+		return limit - 1, duration.Milliseconds(), nil
+	}
+
+	entry := r.store.cache[cacheKey]
+	reset = time.Until(entry.expiresAt).Milliseconds()
+	left, err := strconv.ParseInt(current, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if left <= 0 {
+		return -1, reset, nil
+	}
+
+	entry.value = strconv.FormatInt(left-1, 10)
+	return left - 1, reset, nil
+}
@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	reconciliationRepo "banking/app/repo/mysql/reconciliation"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+)
+
+type reconciliationCommandRepo struct {
+	store *Store
+}
+
+func NewReconciliationCommandRepo(store *Store) domain.IReconciliationCommandRepo {
+	return &reconciliationCommandRepo{
+		store: store,
+	}
+}
+
+// CreateStatement stores the statement and its lines together, like the association save of gorm
+func (r *reconciliationCommandRepo) CreateStatement(ctx context.Context, statement *mysqlModel.BankStatement) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.reconciliationCommandRepo.CreateStatement", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, line := range statement.Lines {
+		if r.store.claimedBy(line.TransactionID) != nil {
+			return reconciliationRepo.ErrTransactionClaimed
+		}
+	}
+
+	now := time.Now()
+	statement.ID = r.store.nextID("bank_statement")
+	statement.CreatedAt, statement.UpdatedAt = now, now
+	for i := range statement.Lines {
+		line := &statement.Lines[i]
+		line.ID = r.store.nextID("bank_statement_line")
+		line.BankStatementID = statement.ID
+		line.CreatedAt, line.UpdatedAt = now, now
+
+		stored := *line
+		r.store.statementLines = append(r.store.statementLines, &stored)
+	}
+
+	stored := *statement
+	stored.Lines = nil
+	r.store.statements = append(r.store.statements, &stored)
+	return nil
+}
+
+func (r *reconciliationCommandRepo) UpdateStatementLine(ctx context.Context, line *mysqlModel.BankStatementLine) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.reconciliationCommandRepo.UpdateStatementLine", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := r.store.findStatementLine(line.ID)
+	if stored == nil {
+		return reconciliationRepo.ErrStatementLineNotFound
+	}
+	if claimer := r.store.claimedBy(line.TransactionID); claimer != nil && claimer.ID != line.ID {
+		return reconciliationRepo.ErrTransactionClaimed
+	}
+
+	stored.Status, stored.TransactionID, stored.CandidateIDs, stored.ResolvedBy = line.Status, line.TransactionID, line.CandidateIDs, line.ResolvedBy
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+type reconciliationQueryRepo struct {
+	store *Store
+}
+
+func NewReconciliationQueryRepo(store *Store) domain.IReconciliationQueryRepo {
+	return &reconciliationQueryRepo{
+		store: store,
+	}
+}
+
+func (r *reconciliationQueryRepo) GetStatementLines(ctx context.Context, statementID uint, status mysqlModel.ReconciliationStatus) (lines []*mysqlModel.BankStatementLine, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.reconciliationQueryRepo.GetStatementLines", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, line := range r.store.statementLines {
+		if line.BankStatementID == statementID && (status == "" || line.Status == status) {
+			copied := *line
+			lines = append(lines, &copied)
+		}
+	}
+	return lines, nil
+}
+
+func (r *reconciliationQueryRepo) GetStatementLine(ctx context.Context, lineID uint) (line *mysqlModel.BankStatementLine, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.reconciliationQueryRepo.GetStatementLine", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := r.store.findStatementLine(lineID)
+	if stored == nil {
+		return nil, reconciliationRepo.ErrStatementLineNotFound
+	}
+
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *reconciliationQueryRepo) GetTransaction(ctx context.Context, transactionID uint) (transaction *mysqlModel.Transaction, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.reconciliationQueryRepo.GetTransaction", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.transactions {
+		if stored.ID == transactionID {
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	return nil, reconciliationRepo.ErrTransactionNotFound
+}
+
+// GetCandidateTransactions returns transactions of the given type inside the amount and date window
+// which have not been claimed by another statement line yet
+func (r *reconciliationQueryRepo) GetCandidateTransactions(ctx context.Context, transactionType mysqlModel.TransactionType, minAmount, maxAmount decimal.Decimal, from, to time.Time) (transactions []*mysqlModel.Transaction, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.reconciliationQueryRepo.GetCandidateTransactions", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, transaction := range r.store.transactions {
+		switch {
+		case transaction.TransactionType != transactionType,
+			transaction.Amount.LessThan(minAmount) || transaction.Amount.GreaterThan(maxAmount),
+			transaction.CreatedAt.Before(from) || transaction.CreatedAt.After(to),
+			r.store.isClaimed(transaction.ID):
+			continue
+		}
+
+		copied := *transaction
+		transactions = append(transactions, &copied)
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].CreatedAt.Before(transactions[j].CreatedAt)
+	})
+
+	return transactions, nil
+}
+
+func (r *reconciliationQueryRepo) IsTransactionClaimed(ctx context.Context, transactionID uint) (claimed bool, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.reconciliationQueryRepo.IsTransactionClaimed", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.isClaimed(transactionID), nil
+}
+
+// findStatementLine returns the stored line, not a copy, the caller holds the lock
+func (s *Store) findStatementLine(lineID uint) *mysqlModel.BankStatementLine {
+	for _, line := range s.statementLines {
+		if line.ID == lineID {
+			return line
+		}
+	}
+	return nil
+}
+
+// isClaimed reports whether a statement line is linked to the transaction, the caller holds the lock
+func (s *Store) isClaimed(transactionID uint) bool {
+	return s.claimedBy(&transactionID) != nil
+}
+
+// claimedBy returns the statement line linked to the transaction, like the unique index of MySQL a
+// nil transaction id is linked to none. The caller holds the lock
+func (s *Store) claimedBy(transactionID *uint) *mysqlModel.BankStatementLine {
+	if transactionID == nil {
+		return nil
+	}
+	for _, line := range s.statementLines {
+		if line.TransactionID != nil && *line.TransactionID == *transactionID {
+			return line
+		}
+	}
+	return nil
+}
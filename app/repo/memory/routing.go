@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"banking/domain"
+
+	"gorm.io/gorm"
+)
+
+type routingCommandRepo struct {
+	store *Store
+}
+
+func NewRoutingCommandRepo(store *Store) domain.IRedisRoutingCommandRepo {
+	return &routingCommandRepo{store: store}
+}
+
+// SetPin starts or extends the pin of the user, it expires after window
+func (r *routingCommandRepo) SetPin(ctx context.Context, userID uint, window time.Duration) (err error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.setCache(pinKey(userID), "1", window)
+	return nil
+}
+
+type routingQueryRepo struct {
+	store *Store
+}
+
+func NewRoutingQueryRepo(store *Store) domain.IRedisRoutingQueryRepo {
+	return &routingQueryRepo{store: store}
+}
+
+func (r *routingQueryRepo) IsPinned(ctx context.Context, userID uint) (pinned bool, err error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	_, pinned = r.store.getCache(pinKey(userID))
+	return pinned, nil
+}
+
+func pinKey(userID uint) string {
+	return fmt.Sprintf("read_pin:%d", userID)
+}
+
+// readRouter has nothing to route, every read of the store sees every write. The memory repos do
+// not read through a router, so Reader returns nil
+type readRouter struct{}
+
+func NewReadRouter() domain.IReadRouter {
+	return &readRouter{}
+}
+
+func (r *readRouter) Reader(ctx context.Context, userID uint) *gorm.DB {
+	return nil
+}
+
+func (r *readRouter) Pin(ctx context.Context, userIDs ...uint) {}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+	redisModel "banking/model/redis"
+)
+
+// Store holds the tables and caches of the in-memory repos, every repo built on the same store
+// sees the writes of the others. A single mutex stands in for the row locks of MySQL, so a balance
+// update reads, checks and writes its users without another update in between
+type Store struct {
+	mu sync.Mutex
+
+	lastIDs map[string]uint
+
+	users           []*mysqlModel.User
+	transactions    []*mysqlModel.Transaction
+	apiKeys         []*mysqlModel.APIKey
+	auditLogs       []*mysqlModel.AuditLog
+	auditHead       *mysqlModel.AuditChainHead
+	fraudReviews    []*mysqlModel.FraudReview
+	transferQueue   []*mysqlModel.TransferRequest
+	statements      []*mysqlModel.BankStatement
+	statementLines  []*mysqlModel.BankStatementLine
+	screenings      []*mysqlModel.ScreeningResult
+	accruals        []*mysqlModel.InterestAccrual
+	capitalizations []*mysqlModel.InterestCapitalization
+	aliases         []*mysqlModel.PayeeAlias
+
+	// the event store and the read models projected from it
+	ledgerEvents       []*mysqlModel.LedgerEvent
+	balanceProjections map[uint]*mysqlModel.BalanceProjection
+	history            []*mysqlModel.TransactionHistory
+	checkpoint         *mysqlModel.ProjectionCheckpoint
+	balanceSnapshots   []*mysqlModel.BalanceSnapshot
+
+	// redis keys with their expiry, a zero expiry never expires
+	cache map[string]*cacheEntry
+
+	// balance streams by user and the subscribers of new events
+	streams       map[uint][]*redisModel.BalanceEvent
+	lastEventMs   int64
+	lastEventSeq  int64
+	subscriptions map[*subscription]struct{}
+}
+
+type cacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+func NewStore() *Store {
+	return &Store{
+		lastIDs:            make(map[string]uint),
+		balanceProjections: make(map[uint]*mysqlModel.BalanceProjection),
+		cache:              make(map[string]*cacheEntry),
+		streams:            make(map[uint][]*redisModel.BalanceEvent),
+		subscriptions:      make(map[*subscription]struct{}),
+	}
+}
+
+// nextID returns the next auto increment id of table, the caller holds the lock
+func (s *Store) nextID(table string) uint {
+	s.lastIDs[table]++
+	return s.lastIDs[table]
+}
+
+// findUser returns the stored user, not a copy, the caller holds the lock
+func (s *Store) findUser(userID uint) *mysqlModel.User {
+	for _, user := range s.users {
+		if user.ID == userID {
+			return user
+		}
+	}
+	return nil
+}
+
+// getCache returns the value of a live key, the caller holds the lock
+func (s *Store) getCache(key string) (string, bool) {
+	entry, ok := s.cache[key]
+	if !ok {
+		return "", false
+	}
+	if !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt) {
+		delete(s.cache, key)
+		return "", false
+	}
+	return entry.value, true
+}
+
+// setCache stores key for ttl, the caller holds the lock
+func (s *Store) setCache(key, value string, ttl time.Duration) {
+	entry := &cacheEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	s.cache[key] = entry
+}
+
+// createTransaction stores a copy of transaction with a new id and timestamps and appends its event,
+// the caller holds the lock
+func (s *Store) createTransaction(transaction *mysqlModel.Transaction) {
+	now := time.Now()
+	transaction.ID = s.nextID("transaction")
+	transaction.CreatedAt, transaction.UpdatedAt = now, now
+
+	stored := *transaction
+	stored.FeeBreakdown = nil
+	s.transactions = append(s.transactions, &stored)
+	s.appendEvent(mysqlModel.NewLedgerEvent(&stored))
+}
+
+// appendEvent stores event with a new id and timestamp, the caller holds the lock
+func (s *Store) appendEvent(event *mysqlModel.LedgerEvent) {
+	event.ID = s.nextID("ledger_event")
+	event.CreatedAt = time.Now()
+	s.ledgerEvents = append(s.ledgerEvents, event)
+}
@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"banking/domain"
+	"banking/global"
+	redisModel "banking/model/redis"
+	"banking/tracing"
+
+	"github.com/spf13/viper"
+)
+
+// subscriptionBuffer is how many events a subscription holds before new ones are dropped, like a
+// redis pub/sub client which does not keep up
+const subscriptionBuffer = 1000
+
+type subscription struct {
+	events chan *redisModel.BalanceEvent
+}
+
+type streamCommandRepo struct {
+	store *Store
+}
+
+func NewStreamCommandRepo(store *Store) domain.IRedisStreamCommandRepo {
+	return &streamCommandRepo{store: store}
+}
+
+// AppendEvent adds the event to the stream of the user, which keeps the last stream.maxLen events,
+// then hands it to every subscription with an id in the "<milliseconds>-<sequence>" format of redis
+func (r *streamCommandRepo) AppendEvent(ctx context.Context, event *redisModel.BalanceEvent) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.streamCommandRepo.AppendEvent", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	ms := time.Now().UnixMilli()
+	if ms <= r.store.lastEventMs {
+		ms, r.store.lastEventSeq = r.store.lastEventMs, r.store.lastEventSeq+1
+	} else {
+		r.store.lastEventSeq = 0
+	}
+	r.store.lastEventMs = ms
+	event.ID = fmt.Sprintf("%d-%d", ms, r.store.lastEventSeq)
+
+	stored := *event
+	stream := append(r.store.streams[event.UserID], &stored)
+	if maxLen := viper.GetInt("stream.maxLen"); maxLen > 0 && len(stream) > maxLen {
+		stream = stream[len(stream)-maxLen:]
+	}
+	r.store.streams[event.UserID] = stream
+
+	for sub := range r.store.subscriptions {
+		copied := stored
+		select {
+		case sub.events <- &copied:
+		default:
+			global.Logger.Warnf("balance event %s dropped, subscription fell behind", stored.ID)
+		}
+	}
+
+	return nil
+}
+
+type streamQueryRepo struct {
+	store *Store
+}
+
+func NewStreamQueryRepo(store *Store) domain.IRedisStreamQueryRepo {
+	return &streamQueryRepo{store: store}
+}
+
+// GetEventsAfter returns the events of the user stream newer than lastEventID, events trimmed
+// from the stream are no longer returned
+func (r *streamQueryRepo) GetEventsAfter(ctx context.Context, userID uint, lastEventID string) (events []*redisModel.BalanceEvent, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.streamQueryRepo.GetEventsAfter", "repo")
+	defer span.End()
+
+	lastMs, lastSeq, err := parseEventID(lastEventID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	events = make([]*redisModel.BalanceEvent, 0)
+	for _, event := range r.store.streams[userID] {
+		ms, seq, _ := parseEventID(event.ID)
+		if ms > lastMs || (ms == lastMs && seq > lastSeq) {
+			copied := *event
+			events = append(events, &copied)
+		}
+	}
+	return events, nil
+}
+
+// SubscribeEvents receives the events of all users appended to the store until ctx is done
+func (r *streamQueryRepo) SubscribeEvents(ctx context.Context) (events <-chan *redisModel.BalanceEvent, err error) {
+	sub := &subscription{events: make(chan *redisModel.BalanceEvent, subscriptionBuffer)}
+
+	r.store.mu.Lock()
+	r.store.subscriptions[sub] = struct{}{}
+	r.store.mu.Unlock()
+
+	out := make(chan *redisModel.BalanceEvent)
+	go func() {
+		defer close(out)
+		defer func() {
+			r.store.mu.Lock()
+			delete(r.store.subscriptions, sub)
+			r.store.mu.Unlock()
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-sub.events:
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// parseEventID splits a stream id "<milliseconds>-<sequence>", a bare "<milliseconds>" has sequence 0 like in redis
+func parseEventID(id string) (ms, seq int64, err error) {
+	parts := strings.SplitN(id, "-", 2)
+	if ms, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid stream id %q", id)
+	}
+	if len(parts) == 2 {
+		if seq, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid stream id %q", id)
+		}
+	}
+	return ms, seq, nil
+}
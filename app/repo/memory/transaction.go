@@ -0,0 +1,238 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fraudRepo "banking/app/repo/mysql/fraud"
+	transactionRepo "banking/app/repo/mysql/transaction"
+	"banking/domain"
+	"banking/metrics"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+type transactionCommandRepo struct {
+	store *Store
+}
+
+func NewTransactionCommandRepo(store *Store) domain.ITransactionCommandRepo {
+	return &transactionCommandRepo{
+		store: store,
+	}
+}
+
+// Transfer checks every precondition before the first balance changes, so a failed transfer
+// leaves the store untouched like a rolled back MySQL transaction
+func (r *transactionCommandRepo) Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, fee *mysqlModel.FeeBreakdown, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "memory.transactionCommandRepo.Transfer", "repo")
+	defer span.End()
+
+	lockStart := time.Now()
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	metrics.ObserveLockWait("transfer", lockStart)
+
+	fromUser := r.store.findUser(fromUserID)
+	if fromUser == nil {
+		return nil, transactionRepo.ErrUserNotFound
+	} else if fromUser.Balance.LessThan(amount.Add(feeTotal(fee))) {
+		return nil, transactionRepo.ErrInsufficientBalance
+	}
+
+	toUser := r.store.findUser(toUserID)
+	if toUser == nil {
+		return nil, transactionRepo.ErrUserNotFound
+	}
+	if err := r.checkHouseAccount(fee); err != nil {
+		return nil, err
+	}
+	// the transfer of an approved fraud review is linked to the review under the same lock
+	var review *mysqlModel.FraudReview
+	if reviewID := utils.ApprovedReviewFromContext(ctx); reviewID != 0 {
+		review = r.store.findReview(reviewID)
+		if review == nil || review.Status != mysqlModel.ReviewApproved || review.TransactionID != nil {
+			return nil, fraudRepo.ErrReviewNotApproved
+		}
+	}
+
+	fromUser.Balance = fromUser.Balance.Sub(amount).Sub(feeTotal(fee))
+	toUser.Balance = toUser.Balance.Add(amount)
+
+	transaction = &mysqlModel.Transaction{
+		FromUserID:      fromUserID,
+		ToUserID:        toUserID,
+		Amount:          amount,
+		FromUserBalance: fromUser.Balance,
+		ToUserBalance:   toUser.Balance,
+		Fee:             feeTotal(fee),
+		TransactionType: mysqlModel.Transfer,
+		FeeBreakdown:    fee,
+		RequestID:       utils.RequestIDFromContext(ctx),
+	}
+	reference.ApplyTo(transaction)
+	r.store.createTransaction(transaction)
+	r.chargeFee(transaction, fromUserID, fromUser.Balance, fee)
+	if review != nil {
+		transactionID := transaction.ID
+		review.TransactionID, review.UpdatedAt = &transactionID, time.Now()
+	}
+	r.store.appendAuditEntry(utils.AuditEntryFromContext(ctx), transaction)
+
+	return transaction, nil
+}
+
+func (r *transactionCommandRepo) Deposit(ctx context.Context, userID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "memory.transactionCommandRepo.Deposit", "repo")
+	defer span.End()
+
+	lockStart := time.Now()
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	metrics.ObserveLockWait("deposit", lockStart)
+
+	user := r.store.findUser(userID)
+	if user == nil {
+		return nil, transactionRepo.ErrUserNotFound
+	}
+
+	user.Balance = user.Balance.Add(amount)
+
+	transaction = &mysqlModel.Transaction{
+		FromUserID:      userID,
+		ToUserID:        userID,
+		Amount:          amount,
+		FromUserBalance: user.Balance,
+		ToUserBalance:   user.Balance,
+		TransactionType: mysqlModel.Deposit,
+		RequestID:       utils.RequestIDFromContext(ctx),
+	}
+	reference.ApplyTo(transaction)
+	r.store.createTransaction(transaction)
+	r.store.appendAuditEntry(utils.AuditEntryFromContext(ctx), transaction)
+
+	return transaction, nil
+}
+
+func (r *transactionCommandRepo) Withdraw(ctx context.Context, userID uint, amount decimal.Decimal, fee *mysqlModel.FeeBreakdown, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "memory.transactionCommandRepo.Withdraw", "repo")
+	defer span.End()
+
+	lockStart := time.Now()
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	metrics.ObserveLockWait("withdraw", lockStart)
+
+	user := r.store.findUser(userID)
+	if user == nil {
+		return nil, transactionRepo.ErrUserNotFound
+	} else if user.Balance.LessThan(amount.Add(feeTotal(fee))) {
+		return nil, transactionRepo.ErrInsufficientBalance
+	}
+	if err := r.checkHouseAccount(fee); err != nil {
+		return nil, err
+	}
+
+	user.Balance = user.Balance.Sub(amount).Sub(feeTotal(fee))
+
+	transaction = &mysqlModel.Transaction{
+		FromUserID:      userID,
+		ToUserID:        userID,
+		Amount:          amount,
+		FromUserBalance: user.Balance,
+		ToUserBalance:   user.Balance,
+		Fee:             feeTotal(fee),
+		TransactionType: mysqlModel.Withdraw,
+		FeeBreakdown:    fee,
+		RequestID:       utils.RequestIDFromContext(ctx),
+	}
+	reference.ApplyTo(transaction)
+	r.store.createTransaction(transaction)
+	r.chargeFee(transaction, userID, user.Balance, fee)
+	r.store.appendAuditEntry(utils.AuditEntryFromContext(ctx), transaction)
+
+	return transaction, nil
+}
+
+func (r *transactionCommandRepo) checkHouseAccount(fee *mysqlModel.FeeBreakdown) error {
+	if feeTotal(fee).IsPositive() && r.store.findUser(fee.HouseAccountID) == nil {
+		return transactionRepo.ErrHouseAccountNotFound
+	}
+	return nil
+}
+
+// chargeFee credits the fee to the house account and records it as a fee transaction of the payer,
+// the house account was checked before any balance changed
+func (r *transactionCommandRepo) chargeFee(charged *mysqlModel.Transaction, payerID uint, payerBalance decimal.Decimal, fee *mysqlModel.FeeBreakdown) {
+	if !feeTotal(fee).IsPositive() {
+		return
+	}
+
+	house := r.store.findUser(fee.HouseAccountID)
+	house.Balance = house.Balance.Add(fee.Total)
+
+	r.store.createTransaction(&mysqlModel.Transaction{
+		FromUserID:      payerID,
+		ToUserID:        fee.HouseAccountID,
+		Amount:          fee.Total,
+		FromUserBalance: payerBalance,
+		ToUserBalance:   house.Balance,
+		TransactionType: mysqlModel.FeeCharge,
+		Details:         fmt.Sprintf("%s fee for transaction %d", charged.TransactionType, charged.ID),
+		RequestID:       charged.RequestID,
+	})
+}
+
+func feeTotal(fee *mysqlModel.FeeBreakdown) decimal.Decimal {
+	if fee == nil {
+		return decimal.Zero
+	}
+
+	return fee.Total
+}
+
+type transactionQueryRepo struct {
+	store *Store
+}
+
+func NewTransactionQueryRepo(store *Store) domain.ITransactionQueryRepo {
+	return &transactionQueryRepo{
+		store: store,
+	}
+}
+
+func (r *transactionQueryRepo) GetTransactions(ctx context.Context, userID uint) (transactions []*mysqlModel.Transaction, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.transactionQueryRepo.GetTransactions", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, transaction := range r.store.transactions {
+		if transaction.FromUserID == userID {
+			copied := *transaction
+			transactions = append(transactions, &copied)
+		}
+	}
+	return transactions, nil
+}
+
+func (r *transactionQueryRepo) GetTransactionsByReference(ctx context.Context, userID uint, reference string) (transactions []*mysqlModel.Transaction, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.transactionQueryRepo.GetTransactionsByReference", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, transaction := range r.store.transactions {
+		if transaction.FromUserID == userID && transaction.MatchesReference(reference) {
+			copied := *transaction
+			transactions = append(transactions, &copied)
+		}
+	}
+	return transactions, nil
+}
@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	transferQueueRepo "banking/app/repo/mysql/transferqueue"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+)
+
+type transferQueueCommandRepo struct {
+	store *Store
+}
+
+func NewTransferQueueCommandRepo(store *Store) domain.ITransferQueueCommandRepo {
+	return &transferQueueCommandRepo{
+		store: store,
+	}
+}
+
+func (r *transferQueueCommandRepo) Enqueue(ctx context.Context, request *mysqlModel.TransferRequest) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.transferQueueCommandRepo.Enqueue", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	request.ID = r.store.nextID("transfer_request")
+	request.CreatedAt, request.UpdatedAt = now, now
+	request.Status = mysqlModel.TransferQueued
+
+	stored := *request
+	r.store.transferQueue = append(r.store.transferQueue, &stored)
+	return nil
+}
+
+// Claim takes the oldest queued request, the store lock makes the claim exclusive
+func (r *transferQueueCommandRepo) Claim(ctx context.Context) (request *mysqlModel.TransferRequest, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.transferQueueCommandRepo.Claim", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.transferQueue {
+		if stored.Status == mysqlModel.TransferQueued {
+			now := time.Now()
+			stored.Status, stored.ClaimedAt, stored.UpdatedAt = mysqlModel.TransferProcessing, &now, now
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *transferQueueCommandRepo) Finish(ctx context.Context, request *mysqlModel.TransferRequest) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.transferQueueCommandRepo.Finish", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := r.store.findTransferRequest(request.ID)
+	if stored == nil || stored.Status != mysqlModel.TransferProcessing {
+		return transferQueueRepo.ErrTransferRequestNotProcessing
+	}
+
+	stored.Status, stored.TransactionID, stored.ReviewID = request.Status, request.TransactionID, request.ReviewID
+	stored.ErrorCode, stored.ErrorMessage, stored.UpdatedAt = request.ErrorCode, request.ErrorMessage, time.Now()
+	return nil
+}
+
+func (r *transferQueueCommandRepo) FailStale(ctx context.Context, claimedBefore time.Time, code domain.ErrorCode, msg string) (completed, failed int64, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.transferQueueCommandRepo.FailStale", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	for _, stored := range r.store.transferQueue {
+		if stored.Status != mysqlModel.TransferProcessing || stored.ClaimedAt == nil || !stored.ClaimedAt.Before(claimedBefore) {
+			continue
+		}
+
+		if transaction := r.store.committedTransfer(stored); transaction != nil {
+			transactionID := transaction.ID
+			stored.Status, stored.TransactionID, stored.UpdatedAt = mysqlModel.TransferCompleted, &transactionID, now
+			completed++
+			continue
+		}
+
+		stored.Status, stored.ErrorCode, stored.ErrorMessage, stored.UpdatedAt = mysqlModel.TransferFailed, string(code), msg, now
+		failed++
+	}
+	return completed, failed, nil
+}
+
+// committedTransfer returns the transfer executed with the request id of request, the caller holds the lock
+func (s *Store) committedTransfer(request *mysqlModel.TransferRequest) *mysqlModel.Transaction {
+	if request.RequestID == "" {
+		return nil
+	}
+
+	for _, transaction := range s.transactions {
+		if transaction.RequestID == request.RequestID && transaction.TransactionType == mysqlModel.Transfer &&
+			transaction.FromUserID == request.FromUserID && transaction.ToUserID == request.ToUserID {
+			return transaction
+		}
+	}
+	return nil
+}
+
+type transferQueueQueryRepo struct {
+	store *Store
+}
+
+func NewTransferQueueQueryRepo(store *Store) domain.ITransferQueueQueryRepo {
+	return &transferQueueQueryRepo{
+		store: store,
+	}
+}
+
+func (r *transferQueueQueryRepo) GetTransferRequest(ctx context.Context, requestID uint) (request *mysqlModel.TransferRequest, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.transferQueueQueryRepo.GetTransferRequest", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := r.store.findTransferRequest(requestID)
+	if stored == nil {
+		return nil, transferQueueRepo.ErrTransferRequestNotFound
+	}
+
+	copied := *stored
+	return &copied, nil
+}
+
+// findTransferRequest returns the stored request, not a copy, the caller holds the lock
+func (s *Store) findTransferRequest(requestID uint) *mysqlModel.TransferRequest {
+	for _, request := range s.transferQueue {
+		if request.ID == requestID {
+			return request
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	userRepo "banking/app/repo/mysql/user"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type userCommandRepo struct {
+	store *Store
+}
+
+func NewUserCommandRepo(store *Store) domain.IUserCommandRepo {
+	return &userCommandRepo{
+		store: store,
+	}
+}
+
+// CreateUser sets the id and timestamps of user like the MySQL insert, emails are unique
+func (r *userCommandRepo) CreateUser(ctx context.Context, user *mysqlModel.User) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.userCommandRepo.CreateUser", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.users {
+		if existing.Email == user.Email {
+			return userRepo.ErrUserExisted
+		}
+	}
+
+	now := time.Now()
+	user.ID = r.store.nextID("user")
+	user.CreatedAt, user.UpdatedAt = now, now
+	if user.Tier == "" {
+		user.Tier = "standard"
+	}
+
+	stored := *user
+	r.store.users = append(r.store.users, &stored)
+	if !user.Balance.IsZero() {
+		r.store.appendEvent(mysqlModel.NewAccountOpened(user))
+	}
+	return nil
+}
+
+type userQueryRepo struct {
+	store *Store
+}
+
+func NewUserQueryRepo(store *Store) domain.IUserQueryRepo {
+	return &userQueryRepo{
+		store: store,
+	}
+}
+
+func (r *userQueryRepo) GetUsers(ctx context.Context, userID uint) (users []*mysqlModel.User, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.userQueryRepo.GetUsers", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if userID != 0 {
+		user := r.store.findUser(userID)
+		if user == nil {
+			return nil, userRepo.ErrUserNotFound
+		}
+		copied := *user
+		return []*mysqlModel.User{&copied}, nil
+	}
+
+	users = make([]*mysqlModel.User, 0, len(r.store.users))
+	for _, user := range r.store.users {
+		copied := *user
+		users = append(users, &copied)
+	}
+	return users, nil
+}
+
+func (r *userQueryRepo) GetUserByEmail(ctx context.Context, email string) (user *mysqlModel.User, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.userQueryRepo.GetUserByEmail", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.users {
+		if existing.Email == email {
+			copied := *existing
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *userQueryRepo) GetUserNames(ctx context.Context, userIDs []uint) (names map[uint]string, err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.userQueryRepo.GetUserNames", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	names = make(map[uint]string, len(userIDs))
+	for _, userID := range userIDs {
+		if user := r.store.findUser(userID); user != nil {
+			names[userID] = user.Name
+		}
+	}
+	return names, nil
+}
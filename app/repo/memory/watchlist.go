@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+)
+
+type watchlistCommandRepo struct {
+	store *Store
+}
+
+func NewWatchlistCommandRepo(store *Store) domain.IWatchlistCommandRepo {
+	return &watchlistCommandRepo{
+		store: store,
+	}
+}
+
+func (r *watchlistCommandRepo) CreateScreeningResult(ctx context.Context, result *mysqlModel.ScreeningResult) (err error) {
+	span, _ := tracing.StartSpan(ctx, "memory.watchlistCommandRepo.CreateScreeningResult", "repo")
+	defer span.End()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	result.ID = r.store.nextID("screening_result")
+	result.CreatedAt, result.UpdatedAt = now, now
+
+	stored := *result
+	r.store.screenings = append(r.store.screenings, &stored)
+	return nil
+}
@@ -0,0 +1,125 @@
+package alias
+
+import (
+	"context"
+	"errors"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type aliasCommandRepo struct {
+	db *gorm.DB
+}
+
+func NewAliasCommandRepo(db *gorm.DB) domain.IAliasCommandRepo {
+	return &aliasCommandRepo{
+		db: db,
+	}
+}
+
+// CreateAlias fails with ErrAliasTaken when the user has the alias already, or when a verified alias
+// is created with a value another user verified
+func (r *aliasCommandRepo) CreateAlias(ctx context.Context, alias *mysqlModel.PayeeAlias) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasCommandRepo.CreateAlias", "repo")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(alias).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrAliasTaken
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *aliasCommandRepo) RenewCode(ctx context.Context, alias *mysqlModel.PayeeAlias) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasCommandRepo.RenewCode", "repo")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&mysqlModel.PayeeAlias{}).
+			Where("user_id = ? AND value = ? AND verified_at IS NULL", alias.UserID, alias.Value).
+			Updates(map[string]interface{}{
+				"code_hash":             alias.CodeHash,
+				"code_expires_at":       alias.CodeExpiresAt,
+				"verification_attempts": 0,
+			})
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return ErrAliasTaken
+		}
+
+		return tx.Where("user_id = ? AND value = ?", alias.UserID, alias.Value).Take(alias).Error
+	})
+}
+
+// CountAttempt counts the attempt in the update checking the limit, of concurrent codes entered for
+// the alias only maxAttempts are counted
+func (r *aliasCommandRepo) CountAttempt(ctx context.Context, aliasID uint, maxAttempts int) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasCommandRepo.CountAttempt", "repo")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	result := db.Model(&mysqlModel.PayeeAlias{}).Where("id = ? AND verification_attempts < ?", aliasID, maxAttempts).
+		Update("verification_attempts", gorm.Expr("verification_attempts + 1"))
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var count int64
+	if err := db.Model(&mysqlModel.PayeeAlias{}).Where("id = ?", aliasID).Count(&count).Error; err != nil {
+		return err
+	} else if count == 0 {
+		return ErrAliasNotFound
+	}
+
+	return ErrAttemptsExhausted
+}
+
+// VerifyAlias clears the code of the alias, the unique verified_value lets the first of two users
+// verifying the same value win
+func (r *aliasCommandRepo) VerifyAlias(ctx context.Context, alias *mysqlModel.PayeeAlias) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasCommandRepo.VerifyAlias", "repo")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&mysqlModel.PayeeAlias{}).
+		Where("id = ? AND verified_at IS NULL", alias.ID).
+		Updates(map[string]interface{}{
+			"verified_value":  alias.VerifiedValue,
+			"verified_at":     alias.VerifiedAt,
+			"code_hash":       "",
+			"code_expires_at": nil,
+		})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrAliasTaken
+		}
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrAliasNotFound
+	}
+
+	return nil
+}
+
+func (r *aliasCommandRepo) DeleteAlias(ctx context.Context, userID, aliasID uint) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasCommandRepo.DeleteAlias", "repo")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", aliasID, userID).Delete(&mysqlModel.PayeeAlias{})
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrAliasNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package alias
+
+import "banking/domain"
+
+var (
+	ErrAliasNotFound     = domain.NewError(domain.CodeAliasNotFound, "alias not found")
+	ErrAliasTaken        = domain.NewError(domain.CodeAliasTaken, "alias is taken")
+	ErrAttemptsExhausted = domain.NewError(domain.CodeAliasCodeInvalid, "verification code was entered too often")
+)
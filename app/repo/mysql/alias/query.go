@@ -0,0 +1,64 @@
+package alias
+
+import (
+	"context"
+	"errors"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type aliasQueryRepo struct {
+	db *gorm.DB
+}
+
+func NewAliasQueryRepo(db *gorm.DB) domain.IAliasQueryRepo {
+	return &aliasQueryRepo{
+		db: db,
+	}
+}
+
+func (r *aliasQueryRepo) GetAliases(ctx context.Context, userID uint) (aliases []*mysqlModel.PayeeAlias, err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasQueryRepo.GetAliases", "repo")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&aliases).Error; err != nil {
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+func (r *aliasQueryRepo) GetAlias(ctx context.Context, aliasID uint) (alias *mysqlModel.PayeeAlias, err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasQueryRepo.GetAlias", "repo")
+	defer span.End()
+
+	alias = &mysqlModel.PayeeAlias{}
+	if err := r.db.WithContext(ctx).Where("id = ?", aliasID).Take(alias).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAliasNotFound
+		}
+		return nil, err
+	}
+
+	return alias, nil
+}
+
+// GetVerifiedAlias returns the alias verified with the normalized value, unverified claims are not found
+func (r *aliasQueryRepo) GetVerifiedAlias(ctx context.Context, value string) (alias *mysqlModel.PayeeAlias, err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasQueryRepo.GetVerifiedAlias", "repo")
+	defer span.End()
+
+	alias = &mysqlModel.PayeeAlias{}
+	if err := r.db.WithContext(ctx).Where("verified_value = ?", value).Take(alias).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAliasNotFound
+		}
+		return nil, err
+	}
+
+	return alias, nil
+}
@@ -5,8 +5,8 @@ import (
 
 	"banking/domain"
 	mysqlModel "banking/model/mysql"
+	"banking/tracing"
 
-	"go.elastic.co/apm/v2"
 	"gorm.io/gorm"
 )
 
@@ -19,7 +19,7 @@ func NewAPIKeyCommandRepo(db *gorm.DB) domain.IAPIKeyCommandRepo {
 }
 
 func (r *apikeyCommandRepo) CreateAPIKey(ctx context.Context, userID uint, key string, secret string) error {
-	span, ctx := apm.StartSpan(ctx, "apikeyQueryRepo.GetAPIKey", "repo")
+	span, ctx := tracing.StartSpan(ctx, "apikeyQueryRepo.GetAPIKey", "repo")
 	defer span.End()
 
 	apiKey := &mysqlModel.APIKey{
@@ -36,10 +36,10 @@ func (r *apikeyCommandRepo) CreateAPIKey(ctx context.Context, userID uint, key s
 }
 
 func (r *apikeyCommandRepo) DeleteAPIKey(ctx context.Context, userID uint, key string) error {
-	span, ctx := apm.StartSpan(ctx, "apikeyQueryRepo.GetAPIKey", "repo")
+	span, ctx := tracing.StartSpan(ctx, "apikeyQueryRepo.GetAPIKey", "repo")
 	defer span.End()
 
-	result := r.db.WithContext(ctx).Where("userId = ? AND key = ?", userID, key).Delete(&mysqlModel.APIKey{})
+	result := r.db.WithContext(ctx).Where("user_id = ? AND api_key = ?", userID, key).Delete(&mysqlModel.APIKey{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -5,8 +5,8 @@ import (
 
 	"banking/domain"
 	mysqlModel "banking/model/mysql"
+	"banking/tracing"
 
-	"go.elastic.co/apm/v2"
 	"gorm.io/gorm"
 )
 
@@ -19,7 +19,7 @@ func NewAPIKeyQueryRepo(db *gorm.DB) domain.IAPIKeyQueryRepo {
 }
 
 // func (r *apikeyQueryRepo) GetAPIKey(ctx context.Context, userID uint, key string) (*mysqlModel.APIKey, error) {
-// 	span, ctx := apm.StartSpan(ctx, "apikeyQueryRepo.GetAPIKey", "repo")
+// 	span, ctx := tracing.StartSpan(ctx, "apikeyQueryRepo.GetAPIKey", "repo")
 // 	defer span.End()
 
 // 	apiKey := &mysqlModel.APIKey{}
@@ -32,7 +32,7 @@ func NewAPIKeyQueryRepo(db *gorm.DB) domain.IAPIKeyQueryRepo {
 // }
 
 func (r *apikeyQueryRepo) GetAPIKeys(ctx context.Context, userID uint, key string) ([]*mysqlModel.APIKey, error) {
-	span, ctx := apm.StartSpan(ctx, "apikeyQueryRepo.GetAPIKeys", "repo")
+	span, ctx := tracing.StartSpan(ctx, "apikeyQueryRepo.GetAPIKeys", "repo")
 	defer span.End()
 
 	if key != "" {
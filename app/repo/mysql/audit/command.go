@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"banking/database/driver"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type auditCommandRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditCommandRepo(db *gorm.DB) domain.IAuditCommandRepo {
+	return &auditCommandRepo{
+		db: db,
+	}
+}
+
+// AppendAuditLog links the entry to the current chain head and inserts it, there is
+// deliberately no update or delete method for audit logs
+func (r *auditCommandRepo) AppendAuditLog(ctx context.Context, log *mysqlModel.AuditLog) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "auditCommandRepo.AppendAuditLog", "repo")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return Append(tx, log)
+	})
+}
+
+// Append links the entry to the chain head and inserts it inside tx. The head row stays locked until tx
+// ends, every other audited action waits for it
+func Append(tx *gorm.DB, log *mysqlModel.AuditLog) error {
+	// make sure the head row exists before locking it
+	head := &mysqlModel.AuditChainHead{ID: 1, Hash: mysqlModel.AuditGenesisHash}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(head).Error; err != nil {
+		return err
+	}
+
+	if err := driver.LockForUpdate(tx).Where("id = ?", 1).Take(head).Error; err != nil {
+		return err
+	}
+
+	// the entry of a rolled back attempt is inserted again
+	log.ID = 0
+	log.PrevHash = head.Hash
+	log.Hash = log.ComputeHash()
+	if err := tx.Create(log).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(head).Updates(map[string]interface{}{"last_id": log.ID, "hash": log.Hash}).Error
+}
+
+// AppendEntry appends the entry an action prepared, with the result of the action as after value, inside
+// the database transaction of the action. Actions without an entry are audited after they end
+func AppendEntry(tx *gorm.DB, log *mysqlModel.AuditLog, after interface{}) error {
+	if log == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+	log.After = string(data)
+
+	return Append(tx, log)
+}
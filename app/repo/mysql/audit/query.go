@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type auditQueryRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditQueryRepo(db *gorm.DB) domain.IAuditQueryRepo {
+	return &auditQueryRepo{
+		db: db,
+	}
+}
+
+// GetAuditLogs returns entries in chain order after afterID, zero values disable a filter
+func (r *auditQueryRepo) GetAuditLogs(ctx context.Context, actorID uint, action string, from, to time.Time, afterID uint, limit int) (logs []*mysqlModel.AuditLog, err error) {
+	span, ctx := tracing.StartSpan(ctx, "auditQueryRepo.GetAuditLogs", "repo")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Where("id > ?", afterID)
+	if actorID != 0 {
+		query = query.Where("actor_id = ?", actorID)
+	}
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("created_at < ?", to)
+	}
+
+	if err := query.Order("id").Limit(limit).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// GetAuditChainHead returns the head row, an empty chain has LastID 0 and the genesis hash
+func (r *auditQueryRepo) GetAuditChainHead(ctx context.Context) (head *mysqlModel.AuditChainHead, err error) {
+	span, ctx := tracing.StartSpan(ctx, "auditQueryRepo.GetAuditChainHead", "repo")
+	defer span.End()
+
+	head = &mysqlModel.AuditChainHead{}
+	if err := r.db.WithContext(ctx).Where("id = ?", 1).Take(head).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &mysqlModel.AuditChainHead{ID: 1, Hash: mysqlModel.AuditGenesisHash}, nil
+		}
+		return nil, err
+	}
+
+	return head, nil
+}
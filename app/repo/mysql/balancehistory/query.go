@@ -0,0 +1,106 @@
+package balancehistory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+)
+
+// balanceHistoryQueryRepo reads the replica through router, unless the read has to see recent writes.
+// Each side of a transaction is read on its own, so the queries use the indexes on (from_user_id,
+// created_at, id) and (to_user_id, created_at, id) and stay fast for users with long histories
+type balanceHistoryQueryRepo struct {
+	router domain.IReadRouter
+}
+
+func NewBalanceHistoryQueryRepo(router domain.IReadRouter) domain.IBalanceHistoryQueryRepo {
+	return &balanceHistoryQueryRepo{
+		router: router,
+	}
+}
+
+// sides are the columns of the user on either side of a transaction
+var sides = []string{"from_user_id", "to_user_id"}
+
+// exactOn are the conditions of the transactions whose balance of the user on side is exact, see
+// Transaction.BalanceExact
+var exactOn = map[string]string{
+	"from_user_id": "(to_user_id <> from_user_id OR to_user_balance_partial = ?)",
+	"to_user_id":   "to_user_balance_partial = ?",
+}
+
+func (r *balanceHistoryQueryRepo) GetLastTransaction(ctx context.Context, userID uint, before time.Time) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "balanceHistoryQueryRepo.GetLastTransaction", "repo")
+	defer span.End()
+
+	db := r.router.Reader(ctx, userID).WithContext(ctx)
+	for _, side := range sides {
+		found := []*mysqlModel.Transaction{}
+		err := db.Where(side+" = ? AND created_at < ?", userID, before.UTC()).Where(exactOn[side], false).
+			Order("created_at DESC").Order("id DESC").
+			Limit(1).Find(&found).Error
+		if err != nil {
+			return nil, err
+		}
+
+		if len(found) > 0 && (transaction == nil || transaction.RecordedBefore(found[0])) {
+			transaction = found[0]
+		}
+	}
+
+	return transaction, nil
+}
+
+func (r *balanceHistoryQueryRepo) GetNextTransaction(ctx context.Context, userID uint, from time.Time) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "balanceHistoryQueryRepo.GetNextTransaction", "repo")
+	defer span.End()
+
+	db := r.router.Reader(ctx, userID).WithContext(ctx)
+	for _, side := range sides {
+		found := []*mysqlModel.Transaction{}
+		err := db.Where(side+" = ? AND created_at >= ?", userID, from.UTC()).Where(exactOn[side], false).
+			Order("created_at").Order("id").
+			Limit(1).Find(&found).Error
+		if err != nil {
+			return nil, err
+		}
+
+		if len(found) > 0 && (transaction == nil || found[0].RecordedBefore(transaction)) {
+			transaction = found[0]
+		}
+	}
+
+	return transaction, nil
+}
+
+func (r *balanceHistoryQueryRepo) GetTransactionsBetween(ctx context.Context, userID uint, from, to time.Time) (transactions []*mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "balanceHistoryQueryRepo.GetTransactionsBetween", "repo")
+	defer span.End()
+
+	db := r.router.Reader(ctx, userID).WithContext(ctx)
+	seen := make(map[uint]bool)
+	for _, side := range sides {
+		found := []*mysqlModel.Transaction{}
+		err := db.Where(side+" = ? AND created_at >= ? AND created_at < ?", userID, from.UTC(), to.UTC()).
+			Order("created_at").Order("id").
+			Find(&found).Error
+		if err != nil {
+			return nil, err
+		}
+
+		// deposits, withdrawals and interest are on both sides
+		for _, transaction := range found {
+			if !seen[transaction.ID] {
+				seen[transaction.ID] = true
+				transactions = append(transactions, transaction)
+			}
+		}
+	}
+
+	sort.Slice(transactions, func(i, j int) bool { return transactions[i].RecordedBefore(transactions[j]) })
+	return transactions, nil
+}
@@ -0,0 +1,105 @@
+package bucket
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"banking/database/driver"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// next counts the credits of each hot account in this process, the next credit goes to bucket
+// next % buckets
+var next sync.Map // user id -> *atomic.Uint64
+
+func nextBucket(userID uint, buckets int) int {
+	counter, _ := next.LoadOrStore(userID, &atomic.Uint64{})
+	return int((counter.(*atomic.Uint64).Add(1) - 1) % uint64(buckets))
+}
+
+// Credit adds amount to the next of the buckets of a hot account inside tx, it locks that bucket row
+// only. It returns false when the bucket does not exist, the buckets of the user changed after buckets
+// was read and the caller credits the user row instead
+func Credit(tx *gorm.DB, userID uint, buckets int, amount decimal.Decimal) (credited bool, err error) {
+	result := tx.Model(&mysqlModel.BalanceBucket{}).
+		Where("user_id = ? AND bucket = ?", userID, nextBucket(userID, buckets)).
+		Update("balance", gorm.Expr("balance + ?", amount))
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Drain moves the balances of the buckets of user to user.Balance inside tx and returns the sum moved.
+// The caller has locked the user row, the bucket rows are locked after it, and saves user.Balance
+func Drain(tx *gorm.DB, user *mysqlModel.User) (moved decimal.Decimal, err error) {
+	buckets := []*mysqlModel.BalanceBucket{}
+	if err := driver.LockForUpdate(tx).Where("user_id = ?", user.ID).Order("bucket").Find(&buckets).Error; err != nil {
+		return decimal.Zero, err
+	}
+
+	for _, bucket := range buckets {
+		moved = moved.Add(bucket.Balance)
+	}
+	if moved.IsZero() {
+		return moved, nil
+	}
+
+	err = tx.Model(&mysqlModel.BalanceBucket{}).Where("user_id = ? AND balance <> 0", user.ID).Update("balance", decimal.Zero).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	user.Balance = user.Balance.Add(moved)
+	return moved, nil
+}
+
+// AddBalances adds the balances of the buckets of the hot accounts among users to their Balance
+func AddBalances(db *gorm.DB, users ...*mysqlModel.User) error {
+	hot := map[uint]*mysqlModel.User{}
+	userIDs := []uint{}
+	for _, user := range users {
+		if user.BalanceBuckets > 0 {
+			hot[user.ID] = user
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	sums := []*mysqlModel.BalanceBucket{}
+	err := db.Model(&mysqlModel.BalanceBucket{}).
+		Select("user_id", "SUM(balance) AS balance").
+		Where("user_id IN ?", userIDs).
+		Group("user_id").
+		Scan(&sums).Error
+	if err != nil {
+		return err
+	}
+
+	for _, sum := range sums {
+		hot[sum.UserID].Balance = hot[sum.UserID].Balance.Add(sum.Balance)
+	}
+
+	return nil
+}
+
+// Balance reads the balance of the user inside tx, the user row plus its buckets. Buckets credited by
+// transactions which are not committed yet are not included
+func Balance(tx *gorm.DB, userID uint) (balance decimal.Decimal, err error) {
+	user := &mysqlModel.User{}
+	if err := tx.Select("id", "balance", "balance_buckets").Where("id = ?", userID).Take(user).Error; err != nil {
+		return decimal.Zero, err
+	}
+
+	if err := AddBalances(tx, user); err != nil {
+		return decimal.Zero, err
+	}
+
+	return user.Balance, nil
+}
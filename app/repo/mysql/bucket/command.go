@@ -0,0 +1,111 @@
+package bucket
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"banking/database/driver"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type bucketCommandRepo struct {
+	db *gorm.DB
+}
+
+func NewBucketCommandRepo(db *gorm.DB) domain.IBucketCommandRepo {
+	return &bucketCommandRepo{
+		db: db,
+	}
+}
+
+// SetBalanceBuckets folds the buckets of the user into its user row and leaves it with the given
+// number of empty buckets. Credits which read the old count and find their bucket gone credit the user row
+func (r *bucketCommandRepo) SetBalanceBuckets(ctx context.Context, userID uint, buckets int) (user *mysqlModel.User, err error) {
+	span, ctx := tracing.StartSpan(ctx, "bucketCommandRepo.SetBalanceBuckets", "repo")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err = driver.RetryOnConflict(ctx, "buckets", func() error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			user, err = lockUser(tx, userID)
+			if err != nil {
+				return err
+			}
+
+			if _, err := Drain(tx, user); err != nil {
+				return err
+			}
+
+			if err := tx.Where("user_id = ? AND bucket >= ?", userID, buckets).Delete(&mysqlModel.BalanceBucket{}).Error; err != nil {
+				return err
+			}
+
+			if buckets > 0 {
+				rows := make([]*mysqlModel.BalanceBucket, 0, buckets)
+				for i := 0; i < buckets; i++ {
+					rows = append(rows, &mysqlModel.BalanceBucket{UserID: userID, Bucket: i, Balance: decimal.Zero})
+				}
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+					return err
+				}
+			}
+
+			user.BalanceBuckets = buckets
+			return tx.Model(user).Updates(map[string]interface{}{"balance": user.Balance, "balance_buckets": buckets}).Error
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *bucketCommandRepo) Consolidate(ctx context.Context, userID uint) (moved decimal.Decimal, err error) {
+	span, ctx := tracing.StartSpan(ctx, "bucketCommandRepo.Consolidate", "repo")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err = driver.RetryOnConflict(ctx, "consolidate", func() error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			user, err := lockUser(tx, userID)
+			if err != nil {
+				return err
+			}
+
+			if moved, err = Drain(tx, user); err != nil || moved.IsZero() {
+				return err
+			}
+
+			return tx.Model(user).Update("balance", user.Balance).Error
+		})
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return moved, nil
+}
+
+func lockUser(tx *gorm.DB, userID uint) (*mysqlModel.User, error) {
+	user := &mysqlModel.User{}
+	err := driver.LockForUpdate(tx).Where("id = ?", userID).Take(user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
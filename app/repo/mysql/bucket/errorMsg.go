@@ -0,0 +1,9 @@
+package bucket
+
+import (
+	"banking/domain"
+)
+
+var (
+	ErrUserNotFound = domain.NewError(domain.CodeUserNotFound, "user not found")
+)
@@ -0,0 +1,33 @@
+package bucket
+
+import (
+	"context"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type bucketQueryRepo struct {
+	db *gorm.DB
+}
+
+func NewBucketQueryRepo(db *gorm.DB) domain.IBucketQueryRepo {
+	return &bucketQueryRepo{
+		db: db,
+	}
+}
+
+func (r *bucketQueryRepo) GetHotAccountIDs(ctx context.Context) (userIDs []uint, err error) {
+	span, ctx := tracing.StartSpan(ctx, "bucketQueryRepo.GetHotAccountIDs", "repo")
+	defer span.End()
+
+	err = r.db.WithContext(ctx).Model(&mysqlModel.User{}).Where("balance_buckets > 0").Order("id").Pluck("id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
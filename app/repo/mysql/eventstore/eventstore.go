@@ -0,0 +1,33 @@
+// Package eventstore appends the ledger events of balance changes and reads them back in order. The
+// events are appended inside the database transaction of the change, so a change without its event
+// is never committed
+package eventstore
+
+import (
+	mysqlModel "banking/model/mysql"
+
+	"gorm.io/gorm"
+)
+
+// Append records events inside tx
+func Append(tx *gorm.DB, events ...*mysqlModel.LedgerEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return tx.Create(events).Error
+}
+
+// AppendTransaction records the event of a transaction created inside tx
+func AppendTransaction(tx *gorm.DB, transaction *mysqlModel.Transaction) error {
+	return Append(tx, mysqlModel.NewLedgerEvent(transaction))
+}
+
+// Events returns at most limit events after the event position, in the order they were appended
+func Events(tx *gorm.DB, position uint, limit int) (events []*mysqlModel.LedgerEvent, err error) {
+	if err := tx.Where("id > ?", position).Order("id").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
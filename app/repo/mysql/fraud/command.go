@@ -0,0 +1,65 @@
+package fraud
+
+import (
+	"context"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type fraudCommandRepo struct {
+	db *gorm.DB
+}
+
+func NewFraudCommandRepo(db *gorm.DB) domain.IFraudCommandRepo {
+	return &fraudCommandRepo{
+		db: db,
+	}
+}
+
+func (r *fraudCommandRepo) CreateReview(ctx context.Context, review *mysqlModel.FraudReview) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudCommandRepo.CreateReview", "repo")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(review).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateReviewStatus moves a review from one status to another, the status condition makes
+// concurrent approvals of the same review fail instead of executing the transfer twice
+func (r *fraudCommandRepo) UpdateReviewStatus(ctx context.Context, reviewID uint, from, to mysqlModel.FraudReviewStatus, adminID *uint) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudCommandRepo.UpdateReviewStatus", "repo")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&mysqlModel.FraudReview{}).
+		Where("id = ? AND status = ?", reviewID, from).
+		Updates(map[string]interface{}{"status": to, "reviewed_by": adminID})
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrReviewNotPending
+	}
+
+	return nil
+}
+
+// LinkTransaction records the transaction executing an approved review inside tx, so the transfer
+// commits only together with its review
+func LinkTransaction(tx *gorm.DB, reviewID, transactionID uint) error {
+	result := tx.Model(&mysqlModel.FraudReview{}).
+		Where("id = ? AND status = ? AND transaction_id IS NULL", reviewID, mysqlModel.ReviewApproved).
+		Update("transaction_id", transactionID)
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrReviewNotApproved
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package fraud
+
+import (
+	"errors"
+
+	"banking/domain"
+)
+
+var (
+	ErrReviewNotFound   = domain.NewError(domain.CodeReviewNotFound, "fraud review not found")
+	ErrReviewNotPending = domain.NewError(domain.CodeReviewNotPending, "fraud review is not pending")
+	// ErrReviewNotApproved is returned for transfers of reviews which are not approved or already executed
+	ErrReviewNotApproved = domain.NewError(domain.CodeReviewNotPending, "fraud review is not approved")
+	ErrAPIKeyNotFound    = errors.New("api key not found")
+)
@@ -0,0 +1,107 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type fraudQueryRepo struct {
+	db *gorm.DB
+}
+
+func NewFraudQueryRepo(db *gorm.DB) domain.IFraudQueryRepo {
+	return &fraudQueryRepo{
+		db: db,
+	}
+}
+
+// CountTransfers counts transfers sent by fromUserID since the given time, toUserID 0 counts transfers to any recipient
+func (r *fraudQueryRepo) CountTransfers(ctx context.Context, fromUserID, toUserID uint, since time.Time) (count int64, err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudQueryRepo.CountTransfers", "repo")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Model(&mysqlModel.Transaction{}).
+		Where("from_user_id = ? AND transaction_type = ? AND created_at >= ?", fromUserID, mysqlModel.Transfer, since)
+	if toUserID != 0 {
+		query = query.Where("to_user_id = ?", toUserID)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *fraudQueryRepo) GetAverageTransferAmount(ctx context.Context, userID uint, since time.Time) (average decimal.Decimal, count int64, err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudQueryRepo.GetAverageTransferAmount", "repo")
+	defer span.End()
+
+	var result struct {
+		Average decimal.Decimal
+		Count   int64
+	}
+	err = r.db.WithContext(ctx).Model(&mysqlModel.Transaction{}).
+		Select("COALESCE(AVG(amount), 0) AS average, COUNT(*) AS count").
+		Where("from_user_id = ? AND transaction_type = ? AND created_at >= ?", userID, mysqlModel.Transfer, since).
+		Scan(&result).Error
+	if err != nil {
+		return decimal.Zero, 0, err
+	}
+
+	return result.Average, result.Count, nil
+}
+
+func (r *fraudQueryRepo) GetAPIKeyCreatedAt(ctx context.Context, key string) (createdAt time.Time, err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudQueryRepo.GetAPIKeyCreatedAt", "repo")
+	defer span.End()
+
+	apiKey := &mysqlModel.APIKey{}
+	if err := r.db.WithContext(ctx).Select("created_at").Where("api_key = ?", key).Take(apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, ErrAPIKeyNotFound
+		}
+		return time.Time{}, err
+	}
+
+	return apiKey.CreatedAt, nil
+}
+
+func (r *fraudQueryRepo) GetReviews(ctx context.Context, status mysqlModel.FraudReviewStatus) (reviews []*mysqlModel.FraudReview, err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudQueryRepo.GetReviews", "repo")
+	defer span.End()
+
+	query := r.db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("id").Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+func (r *fraudQueryRepo) GetReview(ctx context.Context, reviewID uint) (review *mysqlModel.FraudReview, err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudQueryRepo.GetReview", "repo")
+	defer span.End()
+
+	review = &mysqlModel.FraudReview{}
+	if err := r.db.WithContext(ctx).Where("id = ?", reviewID).Take(review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	return review, nil
+}
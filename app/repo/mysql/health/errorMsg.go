@@ -0,0 +1,5 @@
+package health
+
+import "errors"
+
+var ErrReplicationStopped = errors.New("replication is not running")
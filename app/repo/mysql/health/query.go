@@ -0,0 +1,121 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"banking/database/driver"
+	"banking/domain"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type healthQueryRepo struct {
+	db *gorm.DB
+}
+
+func NewHealthQueryRepo(db *gorm.DB) domain.IHealthQueryRepo {
+	return &healthQueryRepo{
+		db: db,
+	}
+}
+
+func (r *healthQueryRepo) Ping(ctx context.Context) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "healthQueryRepo.Ping", "repo")
+	defer span.End()
+
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.PingContext(ctx)
+}
+
+// ReplicaLag asks the replica how far behind it is in the way of its driver. A SQLite database
+// is its own slave and never lags
+func (r *healthQueryRepo) ReplicaLag(ctx context.Context) (lag time.Duration, err error) {
+	span, ctx := tracing.StartSpan(ctx, "healthQueryRepo.ReplicaLag", "repo")
+	defer span.End()
+
+	switch r.db.Dialector.Name() {
+	case driver.Postgres:
+		return r.postgresReplicaLag(ctx)
+	case driver.SQLite:
+		return 0, nil
+	default:
+		return r.mysqlReplicaLag(ctx)
+	}
+}
+
+// mysqlReplicaLag reads Seconds_Behind_Source of SHOW REPLICA STATUS, which is NULL while the
+// replication threads are stopped
+func (r *healthQueryRepo) mysqlReplicaLag(ctx context.Context) (lag time.Duration, err error) {
+	rows, err := r.db.WithContext(ctx).Raw("SHOW REPLICA STATUS").Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		return 0, ErrReplicationStopped
+	}
+
+	// the status has dozens of columns which differ between versions, only the lag is read
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return 0, err
+	}
+
+	for i, column := range columns {
+		if column != "Seconds_Behind_Source" {
+			continue
+		}
+		if values[i] == nil {
+			return 0, ErrReplicationStopped
+		}
+		seconds, err := strconv.ParseInt(string(values[i]), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	return 0, ErrReplicationStopped
+}
+
+// postgresReplicaLag is the age of the last replayed transaction, zero when the replica replayed
+// all the WAL it received since an idle primary writes no transactions to compare with
+func (r *healthQueryRepo) postgresReplicaLag(ctx context.Context) (lag time.Duration, err error) {
+	var status struct {
+		InRecovery bool
+		Receiving  bool
+		Seconds    float64
+	}
+	err = r.db.WithContext(ctx).Raw(`SELECT pg_is_in_recovery() AS in_recovery,
+		EXISTS (SELECT 1 FROM pg_stat_wal_receiver) AS receiving,
+		COALESCE(CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END, 0) AS seconds`).Scan(&status).Error
+	if err != nil {
+		return 0, err
+	}
+	if !status.InRecovery || !status.Receiving {
+		return 0, ErrReplicationStopped
+	}
+
+	return time.Duration(status.Seconds * float64(time.Second)), nil
+}
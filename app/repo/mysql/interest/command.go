@@ -0,0 +1,105 @@
+package interest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"banking/app/repo/mysql/bucket"
+	"banking/app/repo/mysql/eventstore"
+	"banking/database/driver"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type interestCommandRepo struct {
+	db *gorm.DB
+}
+
+func NewInterestCommandRepo(db *gorm.DB) domain.IInterestCommandRepo {
+	return &interestCommandRepo{
+		db: db,
+	}
+}
+
+func (r *interestCommandRepo) CreateAccrual(ctx context.Context, accrual *mysqlModel.InterestAccrual) (created bool, err error) {
+	span, ctx := tracing.StartSpan(ctx, "interestCommandRepo.CreateAccrual", "repo")
+	defer span.End()
+
+	// the unique user and date index makes re-running the batch for a day a no-op
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(accrual)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *interestCommandRepo) Capitalize(ctx context.Context, capitalization *mysqlModel.InterestCapitalization, from, to time.Time) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "interestCommandRepo.Capitalize", "repo")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(capitalization)
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return ErrAlreadyCapitalized
+		}
+
+		err := tx.Model(&mysqlModel.InterestAccrual{}).
+			Where("user_id = ? AND date >= ? AND date < ? AND capitalization_id IS NULL", capitalization.UserID, from, to).
+			Update("capitalization_id", capitalization.ID).Error
+		if err != nil {
+			return err
+		}
+
+		if !capitalization.Posted.IsPositive() {
+			return nil
+		}
+
+		user := &mysqlModel.User{}
+		result = driver.LockForUpdate(tx).Where("id = ?", capitalization.UserID).Take(user)
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		// the balance of a hot account includes its buckets, they are moved to the locked user row
+		if user.BalanceBuckets > 0 {
+			if _, err := bucket.Drain(tx, user); err != nil {
+				return err
+			}
+		}
+
+		balance := user.Balance.Add(capitalization.Posted)
+		if err := tx.Model(user).Update("balance", balance).Error; err != nil {
+			return err
+		}
+
+		transaction := &mysqlModel.Transaction{
+			FromUserID:      user.ID,
+			ToUserID:        user.ID,
+			Amount:          capitalization.Posted,
+			FromUserBalance: balance,
+			ToUserBalance:   balance,
+			TransactionType: mysqlModel.Interest,
+			Details:         fmt.Sprintf("interest for %s", capitalization.Period),
+			RequestID:       utils.RequestIDFromContext(ctx),
+		}
+		if err := tx.Create(transaction).Error; err != nil {
+			return err
+		}
+		if err := eventstore.AppendTransaction(tx, transaction); err != nil {
+			return err
+		}
+
+		capitalization.TransactionID = &transaction.ID
+		return tx.Model(capitalization).Update("transaction_id", transaction.ID).Error
+	})
+}
@@ -0,0 +1,8 @@
+package interest
+
+import "errors"
+
+var (
+	ErrAlreadyCapitalized = errors.New("interest already capitalized for this period")
+	ErrUserNotFound       = errors.New("user not found")
+)
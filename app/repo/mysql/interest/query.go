@@ -0,0 +1,101 @@
+package interest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"banking/app/repo/mysql/bucket"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type interestQueryRepo struct {
+	db *gorm.DB
+}
+
+func NewInterestQueryRepo(db *gorm.DB) domain.IInterestQueryRepo {
+	return &interestQueryRepo{
+		db: db,
+	}
+}
+
+// GetInterestUsers pages through users with a rate plan ordered by id
+func (r *interestQueryRepo) GetInterestUsers(ctx context.Context, afterID uint, limit int) (users []*mysqlModel.User, err error) {
+	span, ctx := tracing.StartSpan(ctx, "interestQueryRepo.GetInterestUsers", "repo")
+	defer span.End()
+
+	err = r.db.WithContext(ctx).
+		Select("id", "balance", "balance_buckets", "interest_plan").
+		Where("id > ? AND interest_plan <> ''", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// hot accounts earn on their buckets too
+	if err := bucket.AddBalances(r.db.WithContext(ctx), users...); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *interestQueryRepo) GetAccrualUserIDs(ctx context.Context, from, to time.Time) (userIDs []uint, err error) {
+	span, ctx := tracing.StartSpan(ctx, "interestQueryRepo.GetAccrualUserIDs", "repo")
+	defer span.End()
+
+	err = r.db.WithContext(ctx).Model(&mysqlModel.InterestAccrual{}).
+		Distinct("user_id").
+		Where("date >= ? AND date < ? AND capitalization_id IS NULL", from, to).
+		Order("user_id").
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+func (r *interestQueryRepo) SumUncapitalizedAccruals(ctx context.Context, userID uint, from, to time.Time) (sum decimal.Decimal, err error) {
+	span, ctx := tracing.StartSpan(ctx, "interestQueryRepo.SumUncapitalizedAccruals", "repo")
+	defer span.End()
+
+	var result struct {
+		Sum decimal.Decimal
+	}
+	query := r.db.WithContext(ctx).Model(&mysqlModel.InterestAccrual{}).
+		Select("COALESCE(SUM(amount), 0) AS sum").
+		Where("user_id = ? AND date < ? AND capitalization_id IS NULL", userID, to)
+	if !from.IsZero() {
+		query = query.Where("date >= ?", from)
+	}
+
+	if err := query.Scan(&result).Error; err != nil {
+		return decimal.Zero, err
+	}
+
+	return result.Sum, nil
+}
+
+func (r *interestQueryRepo) GetLastCarry(ctx context.Context, userID uint) (carry decimal.Decimal, err error) {
+	span, ctx := tracing.StartSpan(ctx, "interestQueryRepo.GetLastCarry", "repo")
+	defer span.End()
+
+	capitalization := &mysqlModel.InterestCapitalization{}
+	err = r.db.WithContext(ctx).Where("user_id = ?", userID).Order("period DESC").Take(capitalization).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return decimal.Zero, nil
+		}
+		return decimal.Zero, err
+	}
+
+	return capitalization.Carry, nil
+}
@@ -0,0 +1,519 @@
+package projection
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"banking/app/repo/mysql/eventstore"
+	"banking/database/driver"
+	"banking/domain"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// batchSize is the number of rows written per insert statement
+const batchSize = 500
+
+// readModels are the tables a checkpoint projects into
+type readModels struct {
+	checkpoint string
+	balances   string
+	history    string
+}
+
+var (
+	live = readModels{checkpoint: Checkpoint, balances: "balance_projection", history: "transaction_history"}
+	// shadow is filled by a rebuild, then copied over live
+	shadow = readModels{checkpoint: RebuildCheckpoint, balances: "balance_projection_rebuild", history: "transaction_history_rebuild"}
+)
+
+func (m readModels) balancesOf(tx *gorm.DB) *gorm.DB {
+	return tx.Table(tx.NamingStrategy.TableName(m.balances))
+}
+
+func (m readModels) historyOf(tx *gorm.DB) *gorm.DB {
+	return tx.Table(tx.NamingStrategy.TableName(m.history))
+}
+
+type projectionCommandRepo struct {
+	db *gorm.DB
+}
+
+func NewProjectionCommandRepo(db *gorm.DB) domain.IProjectionCommandRepo {
+	return &projectionCommandRepo{
+		db: db,
+	}
+}
+
+// Project locks the checkpoint row, so the projectors of every replica apply each event once
+func (r *projectionCommandRepo) Project(ctx context.Context, limit int, gapTimeout time.Duration) (last *mysqlModel.LedgerEvent, applied int, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projectionCommandRepo.Project", "repo")
+	defer span.End()
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		checkpoint, err := lockCheckpoint(tx, live)
+		if err != nil {
+			return err
+		}
+
+		late, err := projectLate(tx, live, checkpoint)
+		if err != nil {
+			return err
+		}
+
+		last, applied, err = project(tx, live, checkpoint, limit, gapTimeout)
+		applied += late
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return last, applied, nil
+}
+
+// ExpireGaps deletes the gaps of the rebuild checkpoint too, they are copies of the gaps of the live one
+func (r *projectionCommandRepo) ExpireGaps(ctx context.Context, before time.Time) (expired int, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projectionCommandRepo.ExpireGaps", "repo")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&mysqlModel.ProjectionGap{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// Snapshot keeps the snapshot before the new one, a rebuild reading it is never left without one
+func (r *projectionCommandRepo) Snapshot(ctx context.Context) (position uint, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projectionCommandRepo.Snapshot", "repo")
+	defer span.End()
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		checkpoint, err := lockCheckpoint(tx, live)
+		if err != nil {
+			return err
+		}
+
+		position = checkpoint.Position
+		if position == checkpoint.SnapshotPosition {
+			return nil
+		}
+
+		projections := []*mysqlModel.BalanceProjection{}
+		if err := tx.Order("user_id").Find(&projections).Error; err != nil {
+			return err
+		}
+
+		snapshots := make([]*mysqlModel.BalanceSnapshot, 0, len(projections))
+		for _, projection := range projections {
+			snapshots = append(snapshots, &mysqlModel.BalanceSnapshot{Position: position, UserID: projection.UserID, Balance: projection.Balance})
+		}
+		if len(snapshots) > 0 {
+			if err := tx.CreateInBatches(snapshots, batchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("position < ?", checkpoint.SnapshotPosition).Delete(&mysqlModel.BalanceSnapshot{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(checkpoint).Update("snapshot_position", position).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return position, nil
+}
+
+// Rebuild replays the events into the shadow read models in batches, one database transaction each,
+// while the projector keeps the read models current. The last transaction catches up and copies the
+// shadow read models over the read models while it holds the checkpoint
+func (r *projectionCommandRepo) Rebuild(ctx context.Context, fromSnapshot bool, limit int, gapTimeout time.Duration) (applied int, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projectionCommandRepo.Rebuild", "repo")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return resetShadow(tx, fromSnapshot)
+	}); err != nil {
+		return 0, err
+	}
+
+	for {
+		var batch int
+		err := db.Transaction(func(tx *gorm.DB) error {
+			checkpoint, err := lockCheckpoint(tx, shadow)
+			if err != nil {
+				return err
+			}
+
+			_, batch, err = project(tx, shadow, checkpoint, limit, gapTimeout)
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		applied += batch
+		if batch < limit {
+			break
+		}
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		caughtUp, err := swapShadow(tx, limit, gapTimeout)
+		applied += caughtUp
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// the rows are copied, the next rebuild empties the shadow read models anyway
+	if err := db.Transaction(emptyShadow); err != nil {
+		global.LoggerFromContext(ctx).Warnf("projection %s could not empty the shadow read models: %v", shadow.checkpoint, err)
+	}
+
+	return applied, nil
+}
+
+// resetShadow empties the shadow read models, or resets them to the latest snapshot with the ids
+// missing before it, and points the shadow checkpoint at the start of the rebuild
+func resetShadow(tx *gorm.DB, fromSnapshot bool) error {
+	checkpoint, err := lockCheckpoint(tx, live)
+	if err != nil {
+		return err
+	}
+	shadowCheckpoint, err := lockCheckpoint(tx, shadow)
+	if err != nil {
+		return err
+	}
+
+	position := uint(0)
+	if fromSnapshot {
+		position = checkpoint.SnapshotPosition
+	}
+
+	if err := emptyShadow(tx); err != nil {
+		return err
+	}
+
+	if position > 0 {
+		snapshots := []*mysqlModel.BalanceSnapshot{}
+		if err := tx.Where("position = ?", position).Order("user_id").Find(&snapshots).Error; err != nil {
+			return err
+		}
+
+		projections := make([]*mysqlModel.BalanceProjection, 0, len(snapshots))
+		for _, snapshot := range snapshots {
+			projections = append(projections, &mysqlModel.BalanceProjection{UserID: snapshot.UserID, Balance: snapshot.Balance, EventID: position})
+		}
+		if len(projections) > 0 {
+			if err := shadow.balancesOf(tx).CreateInBatches(projections, batchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		// the ids missing before the snapshot are still re-checked, the replay finds the others again
+		gaps := []*mysqlModel.ProjectionGap{}
+		if err := tx.Where("checkpoint = ? AND event_id <= ?", live.checkpoint, position).Find(&gaps).Error; err != nil {
+			return err
+		}
+		for _, gap := range gaps {
+			gap.Checkpoint = shadow.checkpoint
+		}
+		if len(gaps) > 0 {
+			if err := tx.CreateInBatches(gaps, batchSize).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	// SnapshotPosition of the shadow checkpoint is where the rebuild started
+	shadowCheckpoint.Position, shadowCheckpoint.SnapshotPosition = position, position
+	return tx.Model(shadowCheckpoint).Updates(map[string]interface{}{"position": position, "snapshot_position": position}).Error
+}
+
+// swapShadow applies the events appended since the last batch to the shadow read models and copies
+// them over the read models. The history before the start of the rebuild is kept but for the events
+// the rebuild applied late
+func swapShadow(tx *gorm.DB, limit int, gapTimeout time.Duration) (applied int, err error) {
+	checkpoint, err := lockCheckpoint(tx, live)
+	if err != nil {
+		return 0, err
+	}
+	shadowCheckpoint, err := lockCheckpoint(tx, shadow)
+	if err != nil {
+		return 0, err
+	}
+	position := shadowCheckpoint.SnapshotPosition
+
+	applied, err = projectLate(tx, shadow, shadowCheckpoint)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		_, batch, err := project(tx, shadow, shadowCheckpoint, limit, gapTimeout)
+		if err != nil {
+			return 0, err
+		}
+
+		applied += batch
+		if batch < limit {
+			break
+		}
+	}
+
+	rebuilt := shadow.historyOf(tx).Select("event_id")
+	if err := live.historyOf(tx).Where("event_id > ? OR event_id IN (?)", position, rebuilt).Delete(&mysqlModel.TransactionHistory{}).Error; err != nil {
+		return 0, err
+	}
+	if err := copyRows(tx, &mysqlModel.TransactionHistory{}, shadow.history, live.history, "id"); err != nil {
+		return 0, err
+	}
+
+	if err := live.balancesOf(tx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&mysqlModel.BalanceProjection{}).Error; err != nil {
+		return 0, err
+	}
+	if err := copyRows(tx, &mysqlModel.BalanceProjection{}, shadow.balances, live.balances, "user_id"); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Where("checkpoint = ?", live.checkpoint).Delete(&mysqlModel.ProjectionGap{}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&mysqlModel.ProjectionGap{}).Where("checkpoint = ?", shadow.checkpoint).Update("checkpoint", live.checkpoint).Error; err != nil {
+		return 0, err
+	}
+
+	checkpoint.Position = shadowCheckpoint.Position
+	if position == 0 {
+		// the snapshots of the replaced read models may be as wrong as they are
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&mysqlModel.BalanceSnapshot{}).Error; err != nil {
+			return 0, err
+		}
+		checkpoint.SnapshotPosition = 0
+	} else {
+		// the snapshots taken without the events the rebuild applied late
+		var late *uint
+		if err := shadow.historyOf(tx).Where("event_id <= ?", position).Select("MIN(event_id)").Scan(&late).Error; err != nil {
+			return 0, err
+		}
+		if late != nil {
+			if err := dropSnapshotsAfter(tx, checkpoint, *late); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := tx.Model(checkpoint).Updates(map[string]interface{}{"position": checkpoint.Position, "snapshot_position": checkpoint.SnapshotPosition}).Error; err != nil {
+		return 0, err
+	}
+
+	return applied, nil
+}
+
+// emptyShadow deletes the rows of the shadow read models and the ids missing from them
+func emptyShadow(tx *gorm.DB) error {
+	all := tx.Session(&gorm.Session{AllowGlobalUpdate: true})
+	if err := shadow.balancesOf(all).Delete(&mysqlModel.BalanceProjection{}).Error; err != nil {
+		return err
+	}
+	if err := shadow.historyOf(all).Delete(&mysqlModel.TransactionHistory{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Where("checkpoint = ?", shadow.checkpoint).Delete(&mysqlModel.ProjectionGap{}).Error
+}
+
+// copyRows inserts the rows of the table from into the table to in the order of orderBy, the columns
+// are those of model but its auto-increment id
+func copyRows(tx *gorm.DB, model interface{}, from, to, orderBy string) error {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+
+	columns := make([]string, 0, len(stmt.Schema.DBNames))
+	for _, name := range stmt.Schema.DBNames {
+		if field := stmt.Schema.LookUpField(name); field.PrimaryKey && field.AutoIncrement {
+			continue
+		}
+		columns = append(columns, tx.Statement.Quote(name))
+	}
+	list := strings.Join(columns, ", ")
+
+	return tx.Exec(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s ORDER BY %s",
+		tx.Statement.Quote(tx.NamingStrategy.TableName(to)), list, list,
+		tx.Statement.Quote(tx.NamingStrategy.TableName(from)), tx.Statement.Quote(orderBy))).Error
+}
+
+// lockCheckpoint creates the checkpoint row of models on the first projection and locks it inside tx
+func lockCheckpoint(tx *gorm.DB, models readModels) (*mysqlModel.ProjectionCheckpoint, error) {
+	checkpoint := &mysqlModel.ProjectionCheckpoint{Name: models.checkpoint}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(checkpoint).Error; err != nil {
+		return nil, err
+	}
+
+	if err := driver.LockForUpdate(tx).Where("name = ?", models.checkpoint).Take(checkpoint).Error; err != nil {
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+// project applies at most limit events after checkpoint to models inside tx and moves checkpoint to
+// the last one
+func project(tx *gorm.DB, models readModels, checkpoint *mysqlModel.ProjectionCheckpoint, limit int, gapTimeout time.Duration) (last *mysqlModel.LedgerEvent, applied int, err error) {
+	events, err := eventstore.Events(tx, checkpoint.Position, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	events, skipped := Contiguous(events, checkpoint.Position, gapTimeout)
+	if len(events) == 0 {
+		return nil, 0, nil
+	}
+
+	if len(skipped) > 0 {
+		gaps := make([]*mysqlModel.ProjectionGap, 0, len(skipped))
+		for _, eventID := range skipped {
+			gaps = append(gaps, &mysqlModel.ProjectionGap{Checkpoint: checkpoint.Name, EventID: eventID})
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(gaps, batchSize).Error; err != nil {
+			return nil, 0, err
+		}
+		global.LoggerFromContext(tx.Statement.Context).Warnf("projection %s skipped the missing ledger events %v, they are applied if they commit later", checkpoint.Name, skipped)
+	}
+
+	if err := apply(tx, models, events); err != nil {
+		return nil, 0, err
+	}
+
+	last = events[len(events)-1]
+	checkpoint.Position = last.ID
+	if err := tx.Model(checkpoint).Update("position", checkpoint.Position).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return last, len(events), nil
+}
+
+// projectLate applies the events of the ids skipped by checkpoint which were committed since, after
+// the events which were applied meanwhile. The snapshots taken without them are dropped
+func projectLate(tx *gorm.DB, models readModels, checkpoint *mysqlModel.ProjectionCheckpoint) (applied int, err error) {
+	gaps := []uint{}
+	if err := tx.Model(&mysqlModel.ProjectionGap{}).Where("checkpoint = ?", checkpoint.Name).Pluck("event_id", &gaps).Error; err != nil {
+		return 0, err
+	}
+	if len(gaps) == 0 {
+		return 0, nil
+	}
+
+	events := []*mysqlModel.LedgerEvent{}
+	if err := tx.Where("id IN ?", gaps).Order("id").Find(&events).Error; err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	if err := apply(tx, models, events); err != nil {
+		return 0, err
+	}
+
+	eventIDs := make([]uint, 0, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.ID)
+	}
+	if err := tx.Where("checkpoint = ? AND event_id IN ?", checkpoint.Name, eventIDs).Delete(&mysqlModel.ProjectionGap{}).Error; err != nil {
+		return 0, err
+	}
+	global.LoggerFromContext(tx.Statement.Context).Warnf("projection %s applied the late ledger events %v", checkpoint.Name, eventIDs)
+
+	if err := dropSnapshotsAfter(tx, checkpoint, eventIDs[0]); err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+// apply adds events to the projected balances and the history of models inside tx
+func apply(tx *gorm.DB, models readModels, events []*mysqlModel.LedgerEvent) error {
+	userIDs := []uint{}
+	for _, event := range events {
+		userIDs = append(userIDs, event.FromUserID, event.ToUserID)
+	}
+
+	projected := []*mysqlModel.BalanceProjection{}
+	if err := models.balancesOf(tx).Where("user_id IN ?", userIDs).Find(&projected).Error; err != nil {
+		return err
+	}
+
+	balances := make(map[uint]decimal.Decimal)
+	eventIDs := make(map[uint]uint)
+	for _, projection := range projected {
+		balances[projection.UserID] = projection.Balance
+		eventIDs[projection.UserID] = projection.EventID
+	}
+
+	history := []*mysqlModel.TransactionHistory{}
+	touched := make(map[uint]bool)
+	for _, event := range events {
+		history = append(history, Apply(balances, event)...)
+		for userID := range event.Postings() {
+			// a late event is older than the events applied before it
+			eventIDs[userID] = max(eventIDs[userID], event.ID)
+			touched[userID] = true
+		}
+	}
+
+	projections := make([]*mysqlModel.BalanceProjection, 0, len(touched))
+	for userID := range touched {
+		projections = append(projections, &mysqlModel.BalanceProjection{UserID: userID, Balance: balances[userID], EventID: eventIDs[userID]})
+	}
+	sort.Slice(projections, func(i, j int) bool { return projections[i].UserID < projections[j].UserID })
+	if len(projections) > 0 {
+		if err := models.balancesOf(tx).Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(projections, batchSize).Error; err != nil {
+			return err
+		}
+	}
+
+	return models.historyOf(tx).CreateInBatches(history, batchSize).Error
+}
+
+// dropSnapshotsAfter deletes the snapshots at or after the event eventID, which were taken before it
+// was applied, and moves the snapshot position of checkpoint back to the latest one left. Only the
+// read models are snapshotted, the shadow checkpoint keeps where its rebuild started
+func dropSnapshotsAfter(tx *gorm.DB, checkpoint *mysqlModel.ProjectionCheckpoint, eventID uint) error {
+	if checkpoint.Name != live.checkpoint || eventID > checkpoint.SnapshotPosition {
+		return nil
+	}
+
+	if err := tx.Where("position >= ?", eventID).Delete(&mysqlModel.BalanceSnapshot{}).Error; err != nil {
+		return err
+	}
+
+	var position *uint
+	if err := tx.Model(&mysqlModel.BalanceSnapshot{}).Select("MAX(position)").Scan(&position).Error; err != nil {
+		return err
+	}
+	checkpoint.SnapshotPosition = 0
+	if position != nil {
+		checkpoint.SnapshotPosition = *position
+	}
+
+	return tx.Model(checkpoint).Update("snapshot_position", checkpoint.SnapshotPosition).Error
+}
@@ -0,0 +1,221 @@
+package projection_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	projectionRepo "banking/app/repo/mysql/projection"
+	"banking/database/driver"
+	"banking/database/migration"
+	"banking/database/sqlite"
+	"banking/domain"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// newSQLite opens a migrated SQLite database, the ledger events are inserted with the ids of the test
+func newSQLite(t *testing.T) *gorm.DB {
+	global.Logger = zap.NewNop().Sugar()
+
+	viper.Set("sqlite.path", filepath.Join(t.TempDir(), "banking.db"))
+	db, err := sqlite.NewSQLite(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB.DB()
+		sqlDB.Close()
+	})
+
+	migrations, err := migration.Embedded(driver.SQLite)
+	require.NoError(t, err)
+	_, err = migration.NewMigrator(db.DB, migrations).Up(context.Background(), 0)
+	require.NoError(t, err)
+
+	return db.DB
+}
+
+func deposited(id uint, amount float64) *mysqlModel.LedgerEvent {
+	return &mysqlModel.LedgerEvent{
+		ID:         id,
+		CreatedAt:  time.Now().Add(-time.Minute),
+		Type:       mysqlModel.EventMoneyDeposited,
+		FromUserID: 1,
+		ToUserID:   1,
+		Amount:     decimal.NewFromFloat(amount),
+	}
+}
+
+func Test_Contiguous(t *testing.T) {
+	old, recent := time.Now().Add(-time.Minute), time.Now()
+	events := []*mysqlModel.LedgerEvent{{ID: 4, CreatedAt: old}, {ID: 7, CreatedAt: old}, {ID: 9, CreatedAt: recent}}
+
+	contiguous, skipped := projectionRepo.Contiguous(events, 3, 5*time.Second)
+	assert.Len(t, contiguous, 2, "the events after a recent gap wait")
+	assert.Equal(t, []uint{5, 6}, skipped)
+
+	contiguous, skipped = projectionRepo.Contiguous(events[:1], 3, 5*time.Second)
+	assert.Len(t, contiguous, 1)
+	assert.Empty(t, skipped)
+}
+
+func Test_Project_LateEvent(t *testing.T) {
+	db := newSQLite(t)
+	repo := projectionRepo.NewProjectionCommandRepo(db)
+	queryRepo := projectionRepo.NewProjectionQueryRepo(db)
+
+	// event 2 is not committed yet when the projector passes it
+	require.NoError(t, db.Create([]*mysqlModel.LedgerEvent{deposited(1, 100), deposited(3, 10)}).Error)
+	_, applied, err := repo.Project(context.Background(), 10, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	_, err = repo.Snapshot(context.Background())
+	require.NoError(t, err)
+
+	// it commits after the gap timeout
+	require.NoError(t, db.Create(deposited(2, 5)).Error)
+	_, applied, err = repo.Project(context.Background(), 10, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied)
+
+	balances, err := queryRepo.GetBalances(context.Background(), []uint{1})
+	require.NoError(t, err)
+	assert.Equal(t, "115", balances[1].String())
+
+	var history int64
+	require.NoError(t, db.Model(&mysqlModel.TransactionHistory{}).Where("event_id = ?", 2).Count(&history).Error)
+	assert.Equal(t, int64(1), history)
+
+	// the snapshot without it is dropped, and it is applied once
+	checkpoint, err := queryRepo.GetCheckpoint(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), checkpoint.Position)
+	assert.Zero(t, checkpoint.SnapshotPosition)
+
+	_, applied, err = repo.Project(context.Background(), 10, 5*time.Second)
+	require.NoError(t, err)
+	assert.Zero(t, applied)
+}
+
+func Test_ExpireGaps(t *testing.T) {
+	db := newSQLite(t)
+	repo := projectionRepo.NewProjectionCommandRepo(db)
+
+	// event 2 is rolled back, its id is skipped and never committed
+	require.NoError(t, db.Create([]*mysqlModel.LedgerEvent{deposited(1, 100), deposited(3, 10)}).Error)
+	_, _, err := repo.Project(context.Background(), 10, 5*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&mysqlModel.ProjectionGap{Checkpoint: projectionRepo.RebuildCheckpoint, EventID: 2}).Error)
+
+	gaps := func() int64 {
+		var n int64
+		require.NoError(t, db.Model(&mysqlModel.ProjectionGap{}).Count(&n).Error)
+		return n
+	}
+	require.Equal(t, int64(2), gaps())
+
+	expired, err := repo.ExpireGaps(context.Background(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, expired, "recent gaps are kept")
+
+	expired, err = repo.ExpireGaps(context.Background(), time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 2, expired, "of both checkpoints")
+	assert.Zero(t, gaps())
+}
+
+func Test_Rebuild(t *testing.T) {
+	setup := func(t *testing.T) (*gorm.DB, domain.IProjectionCommandRepo, domain.IProjectionQueryRepo) {
+		db := newSQLite(t)
+		repo := projectionRepo.NewProjectionCommandRepo(db)
+		require.NoError(t, db.Create([]*mysqlModel.LedgerEvent{deposited(1, 100), deposited(2, 10), deposited(3, 5)}).Error)
+		_, _, err := repo.Project(context.Background(), 10, 5*time.Second)
+		require.NoError(t, err)
+		return db, repo, projectionRepo.NewProjectionQueryRepo(db)
+	}
+	count := func(t *testing.T, db *gorm.DB, table string) int64 {
+		var n int64
+		require.NoError(t, db.Table(table).Count(&n).Error)
+		return n
+	}
+
+	t.Run("replaces the read models", func(t *testing.T) {
+		db, repo, queryRepo := setup(t)
+		require.NoError(t, db.Model(&mysqlModel.BalanceProjection{}).Where("user_id = ?", 1).Update("balance", 1).Error)
+
+		// in batches smaller than the event store
+		applied, err := repo.Rebuild(context.Background(), false, 2, 5*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, 3, applied)
+
+		balances, err := queryRepo.GetBalances(context.Background(), []uint{1})
+		require.NoError(t, err)
+		assert.Equal(t, "115", balances[1].String())
+		assert.Equal(t, int64(3), count(t, db, "transaction_history"))
+
+		checkpoint, err := queryRepo.GetCheckpoint(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), checkpoint.Position)
+
+		// the shadow read models are emptied once copied
+		assert.Zero(t, count(t, db, "balance_projection_rebuild"))
+		assert.Zero(t, count(t, db, "transaction_history_rebuild"))
+	})
+
+	t.Run("from the snapshot", func(t *testing.T) {
+		db, repo, queryRepo := setup(t)
+		_, err := repo.Snapshot(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, db.Create(deposited(4, 1)).Error)
+
+		applied, err := repo.Rebuild(context.Background(), true, 2, 5*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, 1, applied)
+
+		balances, err := queryRepo.GetBalances(context.Background(), []uint{1})
+		require.NoError(t, err)
+		assert.Equal(t, "116", balances[1].String())
+		// the history before the snapshot is kept
+		assert.Equal(t, int64(4), count(t, db, "transaction_history"))
+
+		checkpoint, err := queryRepo.GetCheckpoint(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint(4), checkpoint.Position)
+		assert.Equal(t, uint(3), checkpoint.SnapshotPosition)
+	})
+	t.Run("applies the late events before the snapshot", func(t *testing.T) {
+		db := newSQLite(t)
+		repo := projectionRepo.NewProjectionCommandRepo(db)
+		queryRepo := projectionRepo.NewProjectionQueryRepo(db)
+		require.NoError(t, db.Create([]*mysqlModel.LedgerEvent{deposited(1, 100), deposited(3, 10)}).Error)
+		_, _, err := repo.Project(context.Background(), 10, 5*time.Second)
+		require.NoError(t, err)
+		_, err = repo.Snapshot(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, db.Create(deposited(2, 5)).Error)
+
+		applied, err := repo.Rebuild(context.Background(), true, 2, 5*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, 1, applied)
+
+		balances, err := queryRepo.GetBalances(context.Background(), []uint{1})
+		require.NoError(t, err)
+		assert.Equal(t, "115", balances[1].String())
+		assert.Equal(t, int64(3), count(t, db, "transaction_history"))
+
+		// the snapshot without it is dropped, the live projector does not apply it again
+		checkpoint, err := queryRepo.GetCheckpoint(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, checkpoint.SnapshotPosition)
+		_, applied, err = repo.Project(context.Background(), 10, 5*time.Second)
+		require.NoError(t, err)
+		assert.Zero(t, applied)
+	})
+}
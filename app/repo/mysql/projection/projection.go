@@ -0,0 +1,72 @@
+// Package projection builds the balance and transaction history read models from the event store.
+// The projector applies the events after its checkpoint in id order, the readers serve the users and
+// transactions from the read models when projection.reads is set
+package projection
+
+import (
+	"sort"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// Checkpoint is the name of the checkpoint of the balance and history read models
+	Checkpoint = "balances"
+	// RebuildCheckpoint is the name of the checkpoint of the read models a rebuild fills
+	RebuildCheckpoint = "balances_rebuild"
+)
+
+// Contiguous returns the leading events which follow position without a gap of ids. An id is missing
+// while the transaction which appended it is not committed, so the events after a gap wait until the
+// event after it is older than gapTimeout. The missing ids are then skipped, they are re-checked
+// until their events show up
+func Contiguous(events []*mysqlModel.LedgerEvent, position uint, gapTimeout time.Duration) (contiguous []*mysqlModel.LedgerEvent, skipped []uint) {
+	for i, event := range events {
+		if event.ID != position+1 {
+			if time.Since(event.CreatedAt) < gapTimeout {
+				return events[:i], skipped
+			}
+			for id := position + 1; id < event.ID; id++ {
+				skipped = append(skipped, id)
+			}
+		}
+		position = event.ID
+	}
+	return events, skipped
+}
+
+// Apply adds the postings of event to balances and returns the history rows of its users, ordered by user
+func Apply(balances map[uint]decimal.Decimal, event *mysqlModel.LedgerEvent) []*mysqlModel.TransactionHistory {
+	for userID, amount := range event.Postings() {
+		balances[userID] = balances[userID].Add(amount)
+	}
+
+	userIDs := []uint{event.FromUserID}
+	if event.ToUserID != event.FromUserID {
+		userIDs = append(userIDs, event.ToUserID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	history := make([]*mysqlModel.TransactionHistory, 0, len(userIDs))
+	for _, userID := range userIDs {
+		history = append(history, &mysqlModel.TransactionHistory{
+			EventID:           event.ID,
+			UserID:            userID,
+			Type:              event.Type,
+			TransactionID:     event.TransactionID,
+			FromUserID:        event.FromUserID,
+			ToUserID:          event.ToUserID,
+			Amount:            event.Amount,
+			Fee:               event.Fee,
+			Balance:           balances[userID],
+			Details:           event.Details,
+			ExternalReference: event.ExternalReference,
+			RequestID:         event.RequestID,
+			CreatedAt:         event.CreatedAt,
+		})
+	}
+	return history
+}
@@ -0,0 +1,71 @@
+package projection
+
+import (
+	"context"
+	"errors"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type projectionQueryRepo struct {
+	db *gorm.DB
+}
+
+func NewProjectionQueryRepo(db *gorm.DB) domain.IProjectionQueryRepo {
+	return &projectionQueryRepo{
+		db: db,
+	}
+}
+
+// GetCheckpoint returns a checkpoint at position 0 before the first projection
+func (r *projectionQueryRepo) GetCheckpoint(ctx context.Context) (checkpoint *mysqlModel.ProjectionCheckpoint, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projectionQueryRepo.GetCheckpoint", "repo")
+	defer span.End()
+
+	checkpoint = &mysqlModel.ProjectionCheckpoint{}
+	if err := r.db.WithContext(ctx).Where("name = ?", Checkpoint).Take(checkpoint).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &mysqlModel.ProjectionCheckpoint{Name: Checkpoint}, nil
+		}
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+func (r *projectionQueryRepo) GetBalances(ctx context.Context, userIDs []uint) (balances map[uint]decimal.Decimal, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projectionQueryRepo.GetBalances", "repo")
+	defer span.End()
+
+	projections := []*mysqlModel.BalanceProjection{}
+	if err := r.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&projections).Error; err != nil {
+		return nil, err
+	}
+
+	balances = make(map[uint]decimal.Decimal, len(projections))
+	for _, projection := range projections {
+		balances[projection.UserID] = projection.Balance
+	}
+
+	return balances, nil
+}
+
+func (r *projectionQueryRepo) GetHistory(ctx context.Context, fromUserID uint) (history []*mysqlModel.TransactionHistory, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projectionQueryRepo.GetHistory", "repo")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).
+		Where("from_user_id = ? AND transaction_id IS NOT NULL", fromUserID).
+		Order("event_id").Order("user_id").
+		Find(&history)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return history, nil
+}
@@ -0,0 +1,151 @@
+package projection
+
+import (
+	"context"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+// userQueryRepo reads the users of users with the balances of the projection, which lag the
+// writes by up to projection.interval. A user without events has a zero balance
+type userQueryRepo struct {
+	users       domain.IUserQueryRepo
+	projections domain.IProjectionQueryRepo
+}
+
+func NewUserQueryRepo(users domain.IUserQueryRepo, projections domain.IProjectionQueryRepo) domain.IUserQueryRepo {
+	return &userQueryRepo{
+		users:       users,
+		projections: projections,
+	}
+}
+
+func (r *userQueryRepo) GetUsers(ctx context.Context, userID uint) (users []*mysqlModel.User, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projection.userQueryRepo.GetUsers", "repo")
+	defer span.End()
+
+	users, err = r.users.GetUsers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.projectBalances(ctx, users...); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *userQueryRepo) GetUserByEmail(ctx context.Context, email string) (user *mysqlModel.User, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projection.userQueryRepo.GetUserByEmail", "repo")
+	defer span.End()
+
+	user, err = r.users.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.projectBalances(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUserNames has no balances to project
+func (r *userQueryRepo) GetUserNames(ctx context.Context, userIDs []uint) (names map[uint]string, err error) {
+	return r.users.GetUserNames(ctx, userIDs)
+}
+
+func (r *userQueryRepo) projectBalances(ctx context.Context, users ...*mysqlModel.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	userIDs := make([]uint, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	balances, err := r.projections.GetBalances(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		user.Balance = balances[user.ID]
+	}
+	return nil
+}
+
+// transactionQueryRepo reads the transactions of the history read model, the balances of a
+// transaction are those of the rows of its two users
+type transactionQueryRepo struct {
+	projections domain.IProjectionQueryRepo
+}
+
+func NewTransactionQueryRepo(projections domain.IProjectionQueryRepo) domain.ITransactionQueryRepo {
+	return &transactionQueryRepo{
+		projections: projections,
+	}
+}
+
+func (r *transactionQueryRepo) GetTransactions(ctx context.Context, userID uint) (transactions []*mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projection.transactionQueryRepo.GetTransactions", "repo")
+	defer span.End()
+
+	history, err := r.projections.GetHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(history); {
+		row := history[i]
+		transaction := &mysqlModel.Transaction{
+			Model:             gorm.Model{ID: *row.TransactionID, CreatedAt: row.CreatedAt, UpdatedAt: row.CreatedAt},
+			FromUserID:        row.FromUserID,
+			ToUserID:          row.ToUserID,
+			Amount:            row.Amount,
+			Fee:               row.Fee,
+			TransactionType:   row.Type.TransactionType(),
+			Details:           row.Details,
+			ExternalReference: row.ExternalReference,
+			RequestID:         row.RequestID,
+		}
+
+		// the rows of one event are adjacent, one per user
+		for ; i < len(history) && history[i].EventID == row.EventID; i++ {
+			if history[i].UserID == row.FromUserID {
+				transaction.FromUserBalance = history[i].Balance
+			}
+			if history[i].UserID == row.ToUserID {
+				transaction.ToUserBalance = history[i].Balance
+			}
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+// GetTransactionsByReference filters the history, the read model keeps no index on the references
+func (r *transactionQueryRepo) GetTransactionsByReference(ctx context.Context, userID uint, reference string) (transactions []*mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projection.transactionQueryRepo.GetTransactionsByReference", "repo")
+	defer span.End()
+
+	history, err := r.GetTransactions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transaction := range history {
+		if transaction.MatchesReference(reference) {
+			transactions = append(transactions, transaction)
+		}
+	}
+	return transactions, nil
+}
@@ -0,0 +1,55 @@
+package reconciliation
+
+import (
+	"context"
+	"errors"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type reconciliationCommandRepo struct {
+	db *gorm.DB
+}
+
+func NewReconciliationCommandRepo(db *gorm.DB) domain.IReconciliationCommandRepo {
+	return &reconciliationCommandRepo{
+		db: db,
+	}
+}
+
+func (r *reconciliationCommandRepo) CreateStatement(ctx context.Context, statement *mysqlModel.BankStatement) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "reconciliationCommandRepo.CreateStatement", "repo")
+	defer span.End()
+
+	// statement and its lines are created in one transaction by gorm association saving, a line
+	// matched to a transaction another import claimed meanwhile fails all of them
+	if err := r.db.WithContext(ctx).Create(statement).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrTransactionClaimed
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *reconciliationCommandRepo) UpdateStatementLine(ctx context.Context, line *mysqlModel.BankStatementLine) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "reconciliationCommandRepo.UpdateStatementLine", "repo")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(line).Select("Status", "TransactionID", "CandidateIDs", "ResolvedBy").Updates(line)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrTransactionClaimed
+		}
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrStatementLineNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package reconciliation
+
+import "banking/domain"
+
+var (
+	ErrStatementLineNotFound = domain.NewError(domain.CodeStatementLineNotFound, "statement line not found")
+	ErrTransactionNotFound   = domain.NewError(domain.CodeTransactionNotFound, "transaction not found")
+	ErrTransactionClaimed    = domain.NewError(domain.CodeTransactionClaimed, "transaction already matched to another statement line")
+)
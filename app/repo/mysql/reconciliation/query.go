@@ -0,0 +1,105 @@
+package reconciliation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type reconciliationQueryRepo struct {
+	db *gorm.DB
+}
+
+func NewReconciliationQueryRepo(db *gorm.DB) domain.IReconciliationQueryRepo {
+	return &reconciliationQueryRepo{
+		db: db,
+	}
+}
+
+func (r *reconciliationQueryRepo) GetStatementLines(ctx context.Context, statementID uint, status mysqlModel.ReconciliationStatus) (lines []*mysqlModel.BankStatementLine, err error) {
+	span, ctx := tracing.StartSpan(ctx, "reconciliationQueryRepo.GetStatementLines", "repo")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Where("bank_statement_id = ?", statementID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("id").Find(&lines).Error; err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func (r *reconciliationQueryRepo) GetStatementLine(ctx context.Context, lineID uint) (line *mysqlModel.BankStatementLine, err error) {
+	span, ctx := tracing.StartSpan(ctx, "reconciliationQueryRepo.GetStatementLine", "repo")
+	defer span.End()
+
+	line = &mysqlModel.BankStatementLine{}
+	if err := r.db.WithContext(ctx).Where("id = ?", lineID).Take(line).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStatementLineNotFound
+		}
+		return nil, err
+	}
+
+	return line, nil
+}
+
+func (r *reconciliationQueryRepo) GetTransaction(ctx context.Context, transactionID uint) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "reconciliationQueryRepo.GetTransaction", "repo")
+	defer span.End()
+
+	transaction = &mysqlModel.Transaction{}
+	if err := r.db.WithContext(ctx).Where("id = ?", transactionID).Take(transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// GetCandidateTransactions returns transactions of the given type inside the amount and date window
+// which have not been claimed by another statement line yet
+func (r *reconciliationQueryRepo) GetCandidateTransactions(ctx context.Context, transactionType mysqlModel.TransactionType, minAmount, maxAmount decimal.Decimal, from, to time.Time) (transactions []*mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "reconciliationQueryRepo.GetCandidateTransactions", "repo")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	claimed := db.Model(&mysqlModel.BankStatementLine{}).Select("transaction_id").Where("transaction_id IS NOT NULL")
+
+	result := db.
+		Where("transaction_type = ?", transactionType).
+		Where("amount BETWEEN ? AND ?", minAmount, maxAmount).
+		Where("created_at BETWEEN ? AND ?", from, to).
+		Where("id NOT IN (?)", claimed).
+		Order("created_at").
+		Find(&transactions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return transactions, nil
+}
+
+func (r *reconciliationQueryRepo) IsTransactionClaimed(ctx context.Context, transactionID uint) (claimed bool, err error) {
+	span, ctx := tracing.StartSpan(ctx, "reconciliationQueryRepo.IsTransactionClaimed", "repo")
+	defer span.End()
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&mysqlModel.BankStatementLine{}).Where("transaction_id = ?", transactionID).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,125 @@
+package routing
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"banking/domain"
+	"banking/global"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// readRouter sends reads to the slave unless they could miss a write. Writes of a user pin their reads
+// to the master in redis, so every replica of the apiserver routes them the same way. The replica lag
+// is checked at most once per routing.lagCheckInterval, a failed check counts as lagging
+type readRouter struct {
+	masterDB         *gorm.DB
+	slaveDB          *gorm.DB
+	pinCmdRepo       domain.IRedisRoutingCommandRepo
+	pinQueryRepo     domain.IRedisRoutingQueryRepo
+	slaveHealthRepo  domain.IHealthQueryRepo
+	pinWindow        time.Duration
+	maxReplicaLag    time.Duration
+	lagCheckInterval time.Duration
+
+	mu           sync.Mutex
+	lagCheckedAt time.Time
+	lagging      atomic.Bool
+}
+
+func NewReadRouter(MasterDB *gorm.DB, SlaveDB *gorm.DB, PinCmdRepo domain.IRedisRoutingCommandRepo, PinQueryRepo domain.IRedisRoutingQueryRepo, SlaveHealthRepo domain.IHealthQueryRepo) domain.IReadRouter {
+	pinWindow := time.Duration(viper.GetInt("routing.pinWindow")) * time.Second
+	if pinWindow <= 0 {
+		panic("routing.pinWindow must be positive")
+	}
+	maxReplicaLag := time.Duration(viper.GetInt("routing.maxReplicaLag")) * time.Second
+	if maxReplicaLag <= 0 {
+		panic("routing.maxReplicaLag must be positive")
+	}
+	lagCheckInterval := time.Duration(viper.GetInt("routing.lagCheckInterval")) * time.Second
+	if lagCheckInterval <= 0 {
+		panic("routing.lagCheckInterval must be positive")
+	}
+
+	return &readRouter{
+		masterDB:         MasterDB,
+		slaveDB:          SlaveDB,
+		pinCmdRepo:       PinCmdRepo,
+		pinQueryRepo:     PinQueryRepo,
+		slaveHealthRepo:  SlaveHealthRepo,
+		pinWindow:        pinWindow,
+		maxReplicaLag:    maxReplicaLag,
+		lagCheckInterval: lagCheckInterval,
+	}
+}
+
+func (r *readRouter) Reader(ctx context.Context, userID uint) *gorm.DB {
+	span, ctx := tracing.StartSpan(ctx, "readRouter.Reader", "repo")
+	defer span.End()
+
+	if utils.StrongConsistencyFromContext(ctx) {
+		tracing.SetLabel(ctx, "read_route", "master:strong")
+		return r.masterDB
+	}
+
+	if userID != 0 {
+		pinned, err := r.pinQueryRepo.IsPinned(ctx, userID)
+		if err != nil {
+			// the user may have written, reading the master is always safe
+			global.LoggerFromContext(ctx).Warnf("read pin of user %d error: %s", userID, err)
+			pinned = true
+		}
+		if pinned {
+			tracing.SetLabel(ctx, "read_route", "master:pinned")
+			return r.masterDB
+		}
+	}
+
+	if r.replicaLagging(ctx) {
+		tracing.SetLabel(ctx, "read_route", "master:lag")
+		return r.masterDB
+	}
+
+	return r.slaveDB
+}
+
+func (r *readRouter) Pin(ctx context.Context, userIDs ...uint) {
+	span, ctx := tracing.StartSpan(ctx, "readRouter.Pin", "repo")
+	defer span.End()
+
+	for _, userID := range userIDs {
+		if err := r.pinCmdRepo.SetPin(ctx, userID, r.pinWindow); err != nil {
+			tracing.CaptureError(ctx, err)
+			global.LoggerFromContext(ctx).Errorf("pin reads of user %d error: %s", userID, err)
+		}
+	}
+}
+
+// replicaLagging returns the result of the last lag check, checking again when it is older than
+// routing.lagCheckInterval. Concurrent readers do not wait for a check in progress
+func (r *readRouter) replicaLagging(ctx context.Context) bool {
+	if !r.mu.TryLock() {
+		return r.lagging.Load()
+	}
+	defer r.mu.Unlock()
+
+	if time.Since(r.lagCheckedAt) < r.lagCheckInterval {
+		return r.lagging.Load()
+	}
+
+	lag, err := r.slaveHealthRepo.ReplicaLag(ctx)
+	if err != nil {
+		global.LoggerFromContext(ctx).Warnf("replica lag check error: %s", err)
+	}
+	lagging := err != nil || lag > r.maxReplicaLag
+	r.lagging.Store(lagging)
+	r.lagCheckedAt = time.Now()
+
+	return lagging
+}
@@ -0,0 +1,99 @@
+package routing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	healthRepo "banking/app/repo/mysql/health"
+	routingRepo "banking/app/repo/mysql/routing"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	"banking/utils"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	masterDB = &gorm.DB{}
+	slaveDB  = &gorm.DB{}
+)
+
+func initialReadRouter(t *testing.T) (*domainMock.MockIRedisRoutingCommandRepo, *domainMock.MockIRedisRoutingQueryRepo, *domainMock.MockIHealthQueryRepo) {
+	global.Logger = zap.NewNop().Sugar()
+	viper.Set("routing.pinWindow", 5)
+	viper.Set("routing.maxReplicaLag", 2)
+	viper.Set("routing.lagCheckInterval", 60)
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	return domainMock.NewMockIRedisRoutingCommandRepo(ctrl), domainMock.NewMockIRedisRoutingQueryRepo(ctrl), domainMock.NewMockIHealthQueryRepo(ctrl)
+}
+
+func Test_Reader(t *testing.T) {
+	tests := []struct {
+		name      string
+		strong    bool
+		pinned    bool
+		pinnedErr error
+		lag       time.Duration
+		lagErr    error
+		want      *gorm.DB
+	}{
+		{name: "slave", want: slaveDB},
+		{name: "strong consistency", strong: true, want: masterDB},
+		{name: "pinned after write", pinned: true, want: masterDB},
+		{name: "pin unknown", pinnedErr: errors.New("redis down"), want: masterDB},
+		{name: "replica lag", lag: 3 * time.Second, want: masterDB},
+		{name: "replication stopped", lagErr: healthRepo.ErrReplicationStopped, want: masterDB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPinCmdRepo, mockPinQueryRepo, mockSlaveHealthRepo := initialReadRouter(t)
+
+			ctx := context.Background()
+			if tt.strong {
+				ctx = utils.ContextWithStrongConsistency(ctx)
+			} else {
+				mockPinQueryRepo.EXPECT().IsPinned(gomock.Any(), uint(1)).Return(tt.pinned, tt.pinnedErr)
+				if !tt.pinned && tt.pinnedErr == nil {
+					mockSlaveHealthRepo.EXPECT().ReplicaLag(gomock.Any()).Return(tt.lag, tt.lagErr)
+				}
+			}
+
+			router := routingRepo.NewReadRouter(masterDB, slaveDB, mockPinCmdRepo, mockPinQueryRepo, mockSlaveHealthRepo)
+			assert.Same(t, tt.want, router.Reader(ctx, 1))
+		})
+	}
+}
+
+func Test_Reader_LagCheckedOncePerInterval(t *testing.T) {
+	mockPinCmdRepo, mockPinQueryRepo, mockSlaveHealthRepo := initialReadRouter(t)
+
+	mockSlaveHealthRepo.EXPECT().ReplicaLag(gomock.Any()).Return(3*time.Second, nil).Times(1)
+
+	// reads without a user are never pinned
+	router := routingRepo.NewReadRouter(masterDB, slaveDB, mockPinCmdRepo, mockPinQueryRepo, mockSlaveHealthRepo)
+	assert.Same(t, masterDB, router.Reader(context.Background(), 0))
+	assert.Same(t, masterDB, router.Reader(context.Background(), 0))
+}
+
+func Test_Pin(t *testing.T) {
+	mockPinCmdRepo, mockPinQueryRepo, mockSlaveHealthRepo := initialReadRouter(t)
+
+	// a failed pin is logged, the other users are still pinned
+	mockPinCmdRepo.EXPECT().SetPin(gomock.Any(), uint(1), 5*time.Second).Return(errors.New("redis down"))
+	mockPinCmdRepo.EXPECT().SetPin(gomock.Any(), uint(2), 5*time.Second).Return(nil)
+
+	router := routingRepo.NewReadRouter(masterDB, slaveDB, mockPinCmdRepo, mockPinQueryRepo, mockSlaveHealthRepo)
+	router.Pin(context.Background(), 1, 2)
+}
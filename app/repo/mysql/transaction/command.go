@@ -2,16 +2,24 @@ package transaction
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"time"
 
+	auditRepo "banking/app/repo/mysql/audit"
+	"banking/app/repo/mysql/bucket"
+	"banking/app/repo/mysql/eventstore"
+	fraudRepo "banking/app/repo/mysql/fraud"
+	"banking/database/driver"
 	domain "banking/domain"
 	"banking/global"
+	"banking/metrics"
 	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
 
 	"github.com/shopspring/decimal"
-	"go.elastic.co/apm/v2"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 type transactionCommandRepo struct {
@@ -25,7 +33,7 @@ func NewTransactionCommandRepo(db *gorm.DB) domain.ITransactionCommandRepo {
 }
 
 // func (r *transactionCommandRepo) Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error) {
-// 	span, ctx := apm.StartSpan(ctx, "userCommandRepo.Transfer", "repo")
+// 	span, ctx := tracing.StartSpan(ctx, "userCommandRepo.Transfer", "repo")
 // 	defer span.End()
 
 // 	tx := r.db.WithContext(ctx).Begin()
@@ -93,72 +101,145 @@ func NewTransactionCommandRepo(db *gorm.DB) domain.ITransactionCommandRepo {
 // }
 
 // clause lock
-func (r *transactionCommandRepo) Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error) {
-	span, ctx := apm.StartSpan(ctx, "userCommandRepo.Transfer", "repo")
+func (r *transactionCommandRepo) Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, fee *mysqlModel.FeeBreakdown, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "userCommandRepo.Transfer", "repo")
 	defer span.End()
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	err = driver.RetryOnConflict(ctx, "transfer", func() error {
+		transaction, err = r.transfer(ctx, fromUserID, toUserID, amount, fee, reference)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func (r *transactionCommandRepo) transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, fee *mysqlModel.FeeBreakdown, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	start := time.Now()
 	tx := r.db.WithContext(ctx).Begin()
 	if err = tx.Error; err != nil {
 		return nil, err
 	}
 
+	defer func() {
+		metrics.ObserveDBTransaction("transfer", start, err)
+	}()
+
 	defer func() {
 		if r := recover(); r != nil || err != nil {
 			tx.Rollback()
 		}
 	}()
 
-	fromUser := &mysqlModel.User{}
-	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", fromUserID).Take(fromUser)
-	if result.Error != nil {
-		return nil, result.Error
-	} else if result.RowsAffected == 0 {
-		return nil, ErrUserNotFound
-	} else if fromUser.Balance.LessThan(amount) {
-		return nil, ErrInsufficientBalance
+	// credits to hot accounts go to one of their buckets, their user rows are not locked
+	var houseID uint
+	if feeTotal(fee).IsPositive() {
+		houseID = fee.HouseAccountID
+	}
+	hot, err := hotAccounts(tx, toUserID, houseID)
+	if err != nil {
+		return nil, err
 	}
 
-	toUser := &mysqlModel.User{}
-	result = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", toUserID).Take(toUser)
-	if result.Error != nil {
-		return nil, result.Error
-	} else if result.RowsAffected == 0 {
-		return nil, ErrUserNotFound
+	// the house account is locked with the users, it is credited below
+	ids := []uint{fromUserID}
+	if hot[toUserID] == 0 {
+		ids = append(ids, toUserID)
+	}
+	if houseID != 0 && hot[houseID] == 0 {
+		ids = append(ids, houseID)
+	}
+	users, err := lockUsers(tx, "transfer", ids...)
+	if err != nil {
+		return nil, err
 	}
 
-	calculatedFromUser := fromUser
-	calculatedToUser := toUser
-	calculatedFromUser.Balance = fromUser.Balance.Sub(amount)
-	calculatedToUser.Balance = toUser.Balance.Add(amount)
+	fromUser := users[fromUserID]
+	if fromUser == nil {
+		return nil, ErrUserNotFound
+	}
+	// a hot account pays from its buckets too
+	if fromUser.BalanceBuckets > 0 {
+		if _, err := bucket.Drain(tx, fromUser); err != nil {
+			return nil, err
+		}
+	}
+	if fromUser.Balance.LessThan(amount.Add(feeTotal(fee))) {
+		return nil, ErrInsufficientBalance
+	} else if hot[toUserID] == 0 && users[toUserID] == nil {
+		return nil, ErrUserNotFound
+	} else if houseID != 0 && hot[houseID] == 0 && users[houseID] == nil {
+		return nil, ErrHouseAccountNotFound
+	}
 
 	// Update the fromUser balance
-	result = tx.Save(fromUser)
+	fromUser.Balance = fromUser.Balance.Sub(amount).Sub(feeTotal(fee))
+	fromBalance := fromUser.Balance
+	result := tx.Save(fromUser)
 	if err := result.Error; err != nil {
 		return nil, err
 	}
 
 	// Update the toUser balance
-	result = tx.Save(toUser)
-	if err := result.Error; err != nil {
-		return nil, err
+	var toBalance decimal.Decimal
+	var toPartial bool
+	if toUser := users[toUserID]; toUser != nil {
+		toUser.Balance = toUser.Balance.Add(amount)
+		toBalance = toUser.Balance
+		result = tx.Save(toUser)
+		if err := result.Error; err != nil {
+			return nil, err
+		}
+	} else {
+		var found bool
+		if toBalance, toPartial, found, err = credit(tx, toUserID, hot[toUserID], amount); err != nil {
+			return nil, err
+		} else if !found {
+			return nil, ErrUserNotFound
+		}
 	}
 
 	transaction = &mysqlModel.Transaction{
-		FromUserID:      fromUserID,
-		ToUserID:        toUserID,
-		Amount:          amount,
-		FromUserBalance: calculatedFromUser.Balance,
-		ToUserBalance:   calculatedToUser.Balance,
-		TransactionType: mysqlModel.Transfer,
+		FromUserID:           fromUserID,
+		ToUserID:             toUserID,
+		Amount:               amount,
+		FromUserBalance:      fromBalance,
+		ToUserBalance:        toBalance,
+		ToUserBalancePartial: toPartial,
+		Fee:                  feeTotal(fee),
+		TransactionType:      mysqlModel.Transfer,
+		FeeBreakdown:         fee,
+		RequestID:            utils.RequestIDFromContext(ctx),
 	}
+	reference.ApplyTo(transaction)
 
 	result = tx.Create(transaction)
 	if err := result.Error; err != nil {
 		return nil, err
 	}
+	if err := eventstore.AppendTransaction(tx, transaction); err != nil {
+		return nil, err
+	}
+	// the transfer of an approved fraud review commits only together with the link to the review
+	if reviewID := utils.ApprovedReviewFromContext(ctx); reviewID != 0 {
+		if err := fraudRepo.LinkTransaction(tx, reviewID, transaction.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := chargeFee(tx, transaction, fromUserID, fromBalance, fee, hot[houseID]); err != nil {
+		return nil, err
+	}
+
+	// the audit entry goes last, the chain head it locks is held until the commit only
+	if err := auditRepo.AppendEntry(tx, utils.AuditEntryFromContext(ctx), transaction); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
@@ -167,18 +248,35 @@ func (r *transactionCommandRepo) Transfer(ctx context.Context, fromUserID, toUse
 	return transaction, nil
 }
 
-func (r *transactionCommandRepo) Deposit(ctx context.Context, userID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error) {
-	span, ctx := apm.StartSpan(ctx, "userCommandRepo.Deposit", "repo")
+func (r *transactionCommandRepo) Deposit(ctx context.Context, userID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "userCommandRepo.Deposit", "repo")
 	defer span.End()
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	err = driver.RetryOnConflict(ctx, "deposit", func() error {
+		transaction, err = r.deposit(ctx, userID, amount, reference)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func (r *transactionCommandRepo) deposit(ctx context.Context, userID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	start := time.Now()
 	tx := r.db.WithContext(ctx).Begin()
 	if err = tx.Error; err != nil {
 		return nil, err
 	}
 
+	defer func() {
+		metrics.ObserveDBTransaction("deposit", start, err)
+	}()
+
 	defer func() {
 		if r := recover(); r != nil {
 			global.Logger.Errorf("panic: %v", r)
@@ -188,32 +286,63 @@ func (r *transactionCommandRepo) Deposit(ctx context.Context, userID uint, amoun
 		}
 	}()
 
-	// Lock the user row for update to prevent concurrent updates
-	user := &mysqlModel.User{}
-	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).Take(user)
-	if result.Error != nil {
-		return nil, result.Error
+	hot, err := hotAccounts(tx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update the user balance
-	calculatedBalance := user.Balance.Add(amount)
-	result = tx.Model(user).Update("balance", calculatedBalance)
-	if err = result.Error; err != nil {
-		return nil, err
+	var calculatedBalance decimal.Decimal
+	var partial bool
+	if buckets := hot[userID]; buckets > 0 {
+		// a hot account is credited through one of its buckets, its user row is not locked
+		var found bool
+		if calculatedBalance, partial, found, err = credit(tx, userID, buckets, amount); err != nil {
+			return nil, err
+		} else if !found {
+			return nil, ErrUserNotFound
+		}
+	} else {
+		// Lock the user row for update to prevent concurrent updates
+		users, err := lockUsers(tx, "deposit", userID)
+		if err != nil {
+			return nil, err
+		}
+		user := users[userID]
+		if user == nil {
+			return nil, ErrUserNotFound
+		}
+
+		// Update the user balance
+		calculatedBalance = user.Balance.Add(amount)
+		result := tx.Model(user).Update("balance", calculatedBalance)
+		if err = result.Error; err != nil {
+			return nil, err
+		}
 	}
 
 	transaction = &mysqlModel.Transaction{
-		FromUserID:      userID,
-		ToUserID:        userID,
-		Amount:          amount,
-		FromUserBalance: calculatedBalance,
-		ToUserBalance:   calculatedBalance,
-		TransactionType: mysqlModel.Deposit,
+		FromUserID:           userID,
+		ToUserID:             userID,
+		Amount:               amount,
+		FromUserBalance:      calculatedBalance,
+		ToUserBalance:        calculatedBalance,
+		ToUserBalancePartial: partial,
+		TransactionType:      mysqlModel.Deposit,
+		RequestID:            utils.RequestIDFromContext(ctx),
 	}
+	reference.ApplyTo(transaction)
 
 	if err := tx.Create(transaction).Error; err != nil {
 		return nil, err
 	}
+	if err := eventstore.AppendTransaction(tx, transaction); err != nil {
+		return nil, err
+	}
+
+	// the audit entry goes last, the chain head it locks is held until the commit only
+	if err := auditRepo.AppendEntry(tx, utils.AuditEntryFromContext(ctx), transaction); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
@@ -222,15 +351,35 @@ func (r *transactionCommandRepo) Deposit(ctx context.Context, userID uint, amoun
 	return transaction, nil
 }
 
-func (r *transactionCommandRepo) Withdraw(ctx context.Context, userID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error) {
-	span, ctx := apm.StartSpan(ctx, "userCommandRepo.Withdraw", "repo")
+func (r *transactionCommandRepo) Withdraw(ctx context.Context, userID uint, amount decimal.Decimal, fee *mysqlModel.FeeBreakdown, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "userCommandRepo.Withdraw", "repo")
 	defer span.End()
 
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err = driver.RetryOnConflict(ctx, "withdraw", func() error {
+		transaction, err = r.withdraw(ctx, userID, amount, fee, reference)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func (r *transactionCommandRepo) withdraw(ctx context.Context, userID uint, amount decimal.Decimal, fee *mysqlModel.FeeBreakdown, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	start := time.Now()
 	tx := r.db.WithContext(ctx).Begin()
 	if err = tx.Error; err != nil {
 		return nil, err
 	}
 
+	defer func() {
+		metrics.ObserveDBTransaction("withdraw", start, err)
+	}()
+
 	defer func() {
 		if r := recover(); r != nil {
 			global.Logger.Errorf("panic: %v", r)
@@ -240,19 +389,43 @@ func (r *transactionCommandRepo) Withdraw(ctx context.Context, userID uint, amou
 		}
 	}()
 
-	user := &mysqlModel.User{}
-	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).Take(user)
-	if err := result.Error; err != nil {
+	var houseID uint
+	if feeTotal(fee).IsPositive() {
+		houseID = fee.HouseAccountID
+	}
+	hot, err := hotAccounts(tx, houseID)
+	if err != nil {
 		return nil, err
-	} else if result.RowsAffected == 0 {
-		return nil, ErrInsufficientBalance
-	} else if user.Balance.LessThan(amount) {
+	}
+
+	ids := []uint{userID}
+	if houseID != 0 && hot[houseID] == 0 {
+		ids = append(ids, houseID)
+	}
+	users, err := lockUsers(tx, "withdraw", ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	user := users[userID]
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	// a hot account pays from its buckets too
+	if user.BalanceBuckets > 0 {
+		if _, err := bucket.Drain(tx, user); err != nil {
+			return nil, err
+		}
+	}
+	if user.Balance.LessThan(amount.Add(feeTotal(fee))) {
 		return nil, ErrInsufficientBalance
+	} else if houseID != 0 && hot[houseID] == 0 && users[houseID] == nil {
+		return nil, ErrHouseAccountNotFound
 	}
 
 	// Update the user balance
-	calculatedBalance := user.Balance.Sub(amount)
-	result = tx.Model(user).Update("balance", calculatedBalance)
+	calculatedBalance := user.Balance.Sub(amount).Sub(feeTotal(fee))
+	result := tx.Model(user).Update("balance", calculatedBalance)
 	if err := result.Error; err != nil {
 		return nil, err
 	}
@@ -263,12 +436,28 @@ func (r *transactionCommandRepo) Withdraw(ctx context.Context, userID uint, amou
 		Amount:          amount,
 		FromUserBalance: calculatedBalance,
 		ToUserBalance:   calculatedBalance,
+		Fee:             feeTotal(fee),
 		TransactionType: mysqlModel.Withdraw,
+		FeeBreakdown:    fee,
+		RequestID:       utils.RequestIDFromContext(ctx),
 	}
+	reference.ApplyTo(transaction)
 
 	if err := tx.Create(transaction).Error; err != nil {
 		return nil, err
 	}
+	if err := eventstore.AppendTransaction(tx, transaction); err != nil {
+		return nil, err
+	}
+
+	if err := chargeFee(tx, transaction, userID, calculatedBalance, fee, hot[houseID]); err != nil {
+		return nil, err
+	}
+
+	// the audit entry goes last, the chain head it locks is held until the commit only
+	if err := auditRepo.AppendEntry(tx, utils.AuditEntryFromContext(ctx), transaction); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
@@ -276,3 +465,127 @@ func (r *transactionCommandRepo) Withdraw(ctx context.Context, userID uint, amou
 
 	return transaction, nil
 }
+
+// lockUsers locks the rows of ids inside tx in ascending id order, whatever order the caller names
+// them in, so two transactions on the same users wait for each other instead of deadlocking. Missing
+// users are left out of the result
+func lockUsers(tx *gorm.DB, operation string, ids ...uint) (map[uint]*mysqlModel.User, error) {
+	sorted := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !slices.Contains(sorted, id) {
+			sorted = append(sorted, id)
+		}
+	}
+	slices.Sort(sorted)
+
+	lockStart := time.Now()
+	defer metrics.ObserveLockWait(operation, lockStart)
+
+	// one statement per row, the order a single IN query locks in is up to the query plan
+	users := make(map[uint]*mysqlModel.User, len(sorted))
+	for _, id := range sorted {
+		user := &mysqlModel.User{}
+		result := driver.LockForUpdate(tx).Where("id = ?", id).Limit(1).Find(user)
+		if result.Error != nil {
+			return nil, result.Error
+		} else if result.RowsAffected == 0 {
+			continue
+		}
+		users[id] = user
+	}
+
+	return users, nil
+}
+
+// chargeFee credits the fee to the house account inside tx and records it as a fee transaction
+// of the payer, payerBalance is the payer balance after amount and fee were debited. houseBuckets are
+// the buckets of a hot house account, whose row was not locked
+func chargeFee(tx *gorm.DB, charged *mysqlModel.Transaction, payerID uint, payerBalance decimal.Decimal, fee *mysqlModel.FeeBreakdown, houseBuckets int) error {
+	if !feeTotal(fee).IsPositive() {
+		return nil
+	}
+
+	// unless it is a hot account the house account row was locked with the payer and recipient, it
+	// may also be one of them
+	houseBalance, housePartial, found, err := credit(tx, fee.HouseAccountID, houseBuckets, fee.Total)
+	if err != nil {
+		return err
+	} else if !found {
+		return ErrHouseAccountNotFound
+	}
+
+	transaction := &mysqlModel.Transaction{
+		FromUserID:           payerID,
+		ToUserID:             fee.HouseAccountID,
+		Amount:               fee.Total,
+		FromUserBalance:      payerBalance,
+		ToUserBalance:        houseBalance,
+		ToUserBalancePartial: housePartial,
+		TransactionType:      mysqlModel.FeeCharge,
+		Details:              fmt.Sprintf("%s fee for transaction %d", charged.TransactionType, charged.ID),
+		RequestID:            charged.RequestID,
+	}
+	if err := tx.Create(transaction).Error; err != nil {
+		return err
+	}
+
+	return eventstore.AppendTransaction(tx, transaction)
+}
+
+// hotAccounts reads the bucket counts of the hot accounts among ids without locking their rows,
+// accounts which are not hot are left out
+func hotAccounts(tx *gorm.DB, ids ...uint) (map[uint]int, error) {
+	ids = slices.DeleteFunc(ids, func(id uint) bool { return id == 0 })
+	if len(ids) == 0 {
+		return map[uint]int{}, nil
+	}
+
+	users := []*mysqlModel.User{}
+	if err := tx.Select("id", "balance_buckets").Where("id IN ? AND balance_buckets > 0", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	hot := make(map[uint]int, len(users))
+	for _, user := range users {
+		hot[user.ID] = user.BalanceBuckets
+	}
+
+	return hot, nil
+}
+
+// credit adds amount to the balance of the user inside tx, through one of its buckets when it is a hot
+// account, and returns the new balance. found is false when the user does not exist. partial is true
+// when a bucket was credited: only that bucket is locked, so the balance misses the credits of
+// concurrent transactions to the other buckets and is not recorded as the balance of the user
+func credit(tx *gorm.DB, userID uint, buckets int, amount decimal.Decimal) (balance decimal.Decimal, partial, found bool, err error) {
+	credited := false
+	if buckets > 0 {
+		if credited, err = bucket.Credit(tx, userID, buckets, amount); err != nil {
+			return decimal.Zero, false, false, err
+		}
+	}
+
+	if !credited {
+		result := tx.Model(&mysqlModel.User{}).Where("id = ?", userID).Update("balance", gorm.Expr("balance + ?", amount))
+		if result.Error != nil {
+			return decimal.Zero, false, false, result.Error
+		} else if result.RowsAffected == 0 {
+			return decimal.Zero, false, false, nil
+		}
+	}
+
+	balance, err = bucket.Balance(tx, userID)
+	if err != nil {
+		return decimal.Zero, false, false, err
+	}
+
+	return balance, credited, true, nil
+}
+
+func feeTotal(fee *mysqlModel.FeeBreakdown) decimal.Decimal {
+	if fee == nil {
+		return decimal.Zero
+	}
+
+	return fee.Total
+}
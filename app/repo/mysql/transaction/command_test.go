@@ -16,12 +16,14 @@ func Test_Transfer(t *testing.T) {
 	if err := mysqlTestDB.Migrator().DropTable(
 		&mysqlModel.User{},
 		&mysqlModel.Transaction{},
+		&mysqlModel.LedgerEvent{},
 	); err != nil {
 		t.Fatal(err)
 	}
 	if err := mysqlTestDB.AutoMigrate(
 		&mysqlModel.User{},
 		&mysqlModel.Transaction{},
+		&mysqlModel.LedgerEvent{},
 	); err != nil {
 		t.Fatal(err)
 	}
@@ -48,7 +50,7 @@ func Test_Transfer(t *testing.T) {
 	}
 
 	transactionCommandRepo := transactionRepo.NewTransactionCommandRepo(mysqlTestDB)
-	transaction, err := transactionCommandRepo.Transfer(context.Background(), user1.Model.ID, user2.Model.ID, decimal.NewFromFloat(50))
+	transaction, err := transactionCommandRepo.Transfer(context.Background(), user1.Model.ID, user2.Model.ID, decimal.NewFromFloat(50), nil, nil)
 
 	assert.Nil(t, err)
 	assert.NotNil(t, transaction)
@@ -64,12 +66,14 @@ func Test_Deposit(t *testing.T) {
 	if err := mysqlTestDB.Migrator().DropTable(
 		&mysqlModel.User{},
 		&mysqlModel.Transaction{},
+		&mysqlModel.LedgerEvent{},
 	); err != nil {
 		t.Fatal(err)
 	}
 	if err := mysqlTestDB.AutoMigrate(
 		&mysqlModel.User{},
 		&mysqlModel.Transaction{},
+		&mysqlModel.LedgerEvent{},
 	); err != nil {
 		t.Fatal(err)
 	}
@@ -86,7 +90,7 @@ func Test_Deposit(t *testing.T) {
 	}
 
 	transactionCommandRepo := transactionRepo.NewTransactionCommandRepo(mysqlTestDB)
-	transaction, err := transactionCommandRepo.Deposit(context.Background(), 1, decimal.NewFromFloat(50))
+	transaction, err := transactionCommandRepo.Deposit(context.Background(), 1, decimal.NewFromFloat(50), nil)
 
 	assert.Nil(t, err)
 	assert.NotNil(t, transaction)
@@ -102,12 +106,14 @@ func Test_Withdraw(t *testing.T) {
 	if err := mysqlTestDB.Migrator().DropTable(
 		&mysqlModel.User{},
 		&mysqlModel.Transaction{},
+		&mysqlModel.LedgerEvent{},
 	); err != nil {
 		t.Fatal(err)
 	}
 	if err := mysqlTestDB.AutoMigrate(
 		&mysqlModel.User{},
 		&mysqlModel.Transaction{},
+		&mysqlModel.LedgerEvent{},
 	); err != nil {
 		t.Fatal(err)
 	}
@@ -123,7 +129,7 @@ func Test_Withdraw(t *testing.T) {
 	}
 
 	transactionCommandRepo := transactionRepo.NewTransactionCommandRepo(mysqlTestDB)
-	transaction, err := transactionCommandRepo.Withdraw(context.Background(), 1, decimal.NewFromFloat(50))
+	transaction, err := transactionCommandRepo.Withdraw(context.Background(), 1, decimal.NewFromFloat(50), nil, nil)
 
 	assert.Nil(t, err)
 	assert.NotNil(t, transaction)
@@ -134,3 +140,59 @@ func Test_Withdraw(t *testing.T) {
 	assert.True(t, decimal.NewFromFloat(50).Equal(transaction.Amount))
 	assert.Equal(t, mysqlModel.Withdraw, transaction.TransactionType)
 }
+
+func Test_Withdraw_Fee(t *testing.T) {
+	if err := mysqlTestDB.Migrator().DropTable(
+		&mysqlModel.User{},
+		&mysqlModel.Transaction{},
+		&mysqlModel.LedgerEvent{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := mysqlTestDB.AutoMigrate(
+		&mysqlModel.User{},
+		&mysqlModel.Transaction{},
+		&mysqlModel.LedgerEvent{},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	user1 := &mysqlModel.User{
+		Model:   gorm.Model{ID: 1},
+		Name:    "user1",
+		Email:   "user1@yopmail",
+		Balance: decimal.NewFromFloat(100),
+	}
+	house := &mysqlModel.User{
+		Model:   gorm.Model{ID: 2},
+		Name:    "house",
+		Email:   "house@yopmail",
+		Balance: decimal.NewFromFloat(0),
+	}
+
+	if err := mysqlTestDB.Create(user1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := mysqlTestDB.Create(house).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	fee := &mysqlModel.FeeBreakdown{Total: decimal.NewFromFloat(1.5), HouseAccountID: house.Model.ID}
+	transactionCommandRepo := transactionRepo.NewTransactionCommandRepo(mysqlTestDB)
+	transaction, err := transactionCommandRepo.Withdraw(context.Background(), 1, decimal.NewFromFloat(50), fee, nil)
+
+	assert.Nil(t, err)
+	assert.True(t, decimal.NewFromFloat(48.5).Equal(transaction.FromUserBalance))
+	assert.True(t, decimal.NewFromFloat(1.5).Equal(transaction.Fee))
+
+	feeTransaction := &mysqlModel.Transaction{}
+	if err := mysqlTestDB.Where("transaction_type = ?", mysqlModel.FeeCharge).Take(feeTransaction).Error; err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, house.Model.ID, feeTransaction.ToUserID)
+	assert.True(t, decimal.NewFromFloat(1.5).Equal(feeTransaction.ToUserBalance))
+
+	// amount plus fee must be covered
+	_, err = transactionCommandRepo.Withdraw(context.Background(), 1, decimal.NewFromFloat(48), fee, nil)
+	assert.ErrorIs(t, err, transactionRepo.ErrInsufficientBalance)
+}
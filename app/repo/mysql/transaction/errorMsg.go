@@ -1,8 +1,13 @@
 package transaction
 
-import "errors"
+import (
+	"errors"
+
+	"banking/domain"
+)
 
 var (
-	ErrInsufficientBalance = errors.New("insufficient balance")
-	ErrUserNotFound		 = errors.New("user not found")
+	ErrInsufficientBalance  = domain.NewError(domain.CodeInsufficientBalance, "insufficient balance")
+	ErrUserNotFound         = domain.NewError(domain.CodeUserNotFound, "user not found")
+	ErrHouseAccountNotFound = errors.New("fee house account not found")
 )
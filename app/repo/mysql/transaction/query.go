@@ -5,26 +5,39 @@ import (
 
 	"banking/domain"
 	mysqlModel "banking/model/mysql"
-
-	"go.elastic.co/apm/v2"
-	"gorm.io/gorm"
+	"banking/tracing"
 )
 
+// transactionQueryRepo reads the replica through router, unless the read has to see recent writes
 type transactionQueryRepo struct {
-	db *gorm.DB
+	router domain.IReadRouter
 }
 
-func NewTransactionQueryRepo(db *gorm.DB) domain.ITransactionQueryRepo {
+func NewTransactionQueryRepo(router domain.IReadRouter) domain.ITransactionQueryRepo {
 	return &transactionQueryRepo{
-		db: db,
+		router: router,
 	}
 }
 
 func (r *transactionQueryRepo) GetTransactions(ctx context.Context, userID uint) (transactions []*mysqlModel.Transaction, err error) {
-	span, ctx := apm.StartSpan(ctx, "transactionQueryRepo.GetTransactions", "repo")
+	span, ctx := tracing.StartSpan(ctx, "transactionQueryRepo.GetTransactions", "repo")
+	defer span.End()
+
+	result := r.router.Reader(ctx, userID).WithContext(ctx).Where("from_user_id = ?", userID).Find(&transactions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return transactions, nil
+}
+
+func (r *transactionQueryRepo) GetTransactionsByReference(ctx context.Context, userID uint, reference string) (transactions []*mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "transactionQueryRepo.GetTransactionsByReference", "repo")
 	defer span.End()
 
-	result := r.db.WithContext(ctx).Where("from_user_id = ?", userID).Find(&transactions)
+	result := r.router.Reader(ctx, userID).WithContext(ctx).
+		Where("from_user_id = ? AND (external_reference = ? OR details = ?)", userID, reference, reference).
+		Find(&transactions)
 	if result.Error != nil {
 		return nil, result.Error
 	}
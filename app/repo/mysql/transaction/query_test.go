@@ -5,8 +5,10 @@ import (
 	"testing"
 
 	transactionRepo "banking/app/repo/mysql/transaction"
+	domainMock "banking/domain/mock"
 	mysqlModel "banking/model/mysql"
 
+	"github.com/golang/mock/gomock"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -51,7 +53,10 @@ func Test_GetTransactions(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	transactionQueryRepo := transactionRepo.NewTransactionQueryRepo(mysqlTestDB)
+	mockRouter := domainMock.NewMockIReadRouter(gomock.NewController(t))
+	mockRouter.EXPECT().Reader(gomock.Any(), user.Model.ID).Return(mysqlTestDB)
+
+	transactionQueryRepo := transactionRepo.NewTransactionQueryRepo(mockRouter)
 	transactions, err := transactionQueryRepo.GetTransactions(context.Background(), user.Model.ID)
 	if err != nil {
 		t.Fatal(err)
@@ -0,0 +1,145 @@
+package transferqueue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type transferQueueCommandRepo struct {
+	db *gorm.DB
+}
+
+func NewTransferQueueCommandRepo(db *gorm.DB) domain.ITransferQueueCommandRepo {
+	return &transferQueueCommandRepo{
+		db: db,
+	}
+}
+
+func (r *transferQueueCommandRepo) Enqueue(ctx context.Context, request *mysqlModel.TransferRequest) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "transferQueueCommandRepo.Enqueue", "repo")
+	defer span.End()
+
+	request.Status = mysqlModel.TransferQueued
+	if err := r.db.WithContext(ctx).Create(request).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Claim takes the oldest queued request with a status condition instead of a row lock, so it works
+// the same on every driver. A worker which loses the race to another one tries the next request
+func (r *transferQueueCommandRepo) Claim(ctx context.Context) (request *mysqlModel.TransferRequest, err error) {
+	span, ctx := tracing.StartSpan(ctx, "transferQueueCommandRepo.Claim", "repo")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	for ctx.Err() == nil {
+		request = &mysqlModel.TransferRequest{}
+		if err := db.Where("status = ?", mysqlModel.TransferQueued).Order("id").Take(request).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		claimedAt := time.Now()
+		result := db.Model(&mysqlModel.TransferRequest{}).
+			Where("id = ? AND status = ?", request.ID, mysqlModel.TransferQueued).
+			Updates(map[string]interface{}{"status": mysqlModel.TransferProcessing, "claimed_at": claimedAt})
+		if result.Error != nil {
+			return nil, result.Error
+		} else if result.RowsAffected == 1 {
+			request.Status, request.ClaimedAt = mysqlModel.TransferProcessing, &claimedAt
+			return request, nil
+		}
+	}
+
+	return nil, ctx.Err()
+}
+
+func (r *transferQueueCommandRepo) Finish(ctx context.Context, request *mysqlModel.TransferRequest) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "transferQueueCommandRepo.Finish", "repo")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&mysqlModel.TransferRequest{}).
+		Where("id = ? AND status = ?", request.ID, mysqlModel.TransferProcessing).
+		Updates(map[string]interface{}{
+			"status":         request.Status,
+			"transaction_id": request.TransactionID,
+			"review_id":      request.ReviewID,
+			"error_code":     request.ErrorCode,
+			"error_message":  request.ErrorMessage,
+		})
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrTransferRequestNotProcessing
+	}
+
+	return nil
+}
+
+func (r *transferQueueCommandRepo) FailStale(ctx context.Context, claimedBefore time.Time, code domain.ErrorCode, msg string) (completed, failed int64, err error) {
+	span, ctx := tracing.StartSpan(ctx, "transferQueueCommandRepo.FailStale", "repo")
+	defer span.End()
+
+	var stale []*mysqlModel.TransferRequest
+	if err := r.db.WithContext(ctx).Where("status = ? AND claimed_at < ?", mysqlModel.TransferProcessing, claimedBefore).Find(&stale).Error; err != nil {
+		return 0, 0, err
+	}
+
+	for _, request := range stale {
+		transactionID, err := committedTransfer(r.db.WithContext(ctx), request)
+		if err != nil {
+			return completed, failed, err
+		}
+
+		updates := map[string]interface{}{"status": mysqlModel.TransferFailed, "error_code": code, "error_message": msg}
+		if transactionID != 0 {
+			updates = map[string]interface{}{"status": mysqlModel.TransferCompleted, "transaction_id": transactionID}
+		}
+
+		// a request its worker finished meanwhile is not changed
+		result := r.db.WithContext(ctx).Model(&mysqlModel.TransferRequest{}).
+			Where("id = ? AND status = ?", request.ID, mysqlModel.TransferProcessing).
+			Updates(updates)
+		if result.Error != nil {
+			return completed, failed, result.Error
+		} else if result.RowsAffected == 0 {
+			continue
+		}
+
+		if transactionID != 0 {
+			completed++
+		} else {
+			failed++
+		}
+	}
+
+	return completed, failed, nil
+}
+
+// committedTransfer returns the id of the transfer the worker of request committed, 0 when there is none.
+// The worker ran the transfer with the request id of the request, the index of the column finds it
+func committedTransfer(db *gorm.DB, request *mysqlModel.TransferRequest) (transactionID uint, err error) {
+	if request.RequestID == "" {
+		return 0, nil
+	}
+
+	var transactions []*mysqlModel.Transaction
+	err = db.Where("request_id = ? AND transaction_type = ? AND from_user_id = ? AND to_user_id = ?", request.RequestID, mysqlModel.Transfer, request.FromUserID, request.ToUserID).
+		Order("id").Limit(1).Find(&transactions).Error
+	if err != nil || len(transactions) == 0 {
+		return 0, err
+	}
+
+	return transactions[0].ID, nil
+}
@@ -0,0 +1,12 @@
+package transferqueue
+
+import (
+	"errors"
+
+	"banking/domain"
+)
+
+var (
+	ErrTransferRequestNotFound      = domain.NewError(domain.CodeTransferRequestNotFound, "transfer request not found")
+	ErrTransferRequestNotProcessing = errors.New("transfer request is not processing")
+)
@@ -0,0 +1,37 @@
+package transferqueue
+
+import (
+	"context"
+	"errors"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type transferQueueQueryRepo struct {
+	db *gorm.DB
+}
+
+func NewTransferQueueQueryRepo(db *gorm.DB) domain.ITransferQueueQueryRepo {
+	return &transferQueueQueryRepo{
+		db: db,
+	}
+}
+
+func (r *transferQueueQueryRepo) GetTransferRequest(ctx context.Context, requestID uint) (request *mysqlModel.TransferRequest, err error) {
+	span, ctx := tracing.StartSpan(ctx, "transferQueueQueryRepo.GetTransferRequest", "repo")
+	defer span.End()
+
+	request = &mysqlModel.TransferRequest{}
+	if err := r.db.WithContext(ctx).Where("id = ?", requestID).Take(request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransferRequestNotFound
+		}
+		return nil, err
+	}
+
+	return request, nil
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 
+	"banking/app/repo/mysql/eventstore"
 	"banking/domain"
 	mysqlModel "banking/model/mysql"
+	"banking/tracing"
 
-	"go.elastic.co/apm/v2"
 	"gorm.io/gorm"
 )
 
@@ -22,12 +23,21 @@ func NewUserCommandRepo(db *gorm.DB) domain.IUserCommandRepo {
 }
 
 func (r *userCommandRepo) CreateUser(ctx context.Context, user *mysqlModel.User) (err error) {
-	span, ctx := apm.StartSpan(ctx, "userCommandRepo.CreateUser", "repo")
+	span, ctx := tracing.StartSpan(ctx, "userCommandRepo.CreateUser", "repo")
 	defer span.End()
 
-	result := r.db.WithContext(ctx).Create(user)
-	if err := result.Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	// a user created with a balance opens its account in the event store
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if user.Balance.IsZero() {
+			return nil
+		}
+		return eventstore.Append(tx, mysqlModel.NewAccountOpened(user))
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			return ErrUserExisted
 		}
 		return err
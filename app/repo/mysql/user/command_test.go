@@ -12,10 +12,10 @@ import (
 )
 
 func Test_CreateUser(t *testing.T) {
-	if err := mysqlTestDB.Migrator().DropTable(&mysqlModel.User{}); err != nil {
+	if err := mysqlTestDB.Migrator().DropTable(&mysqlModel.User{}, &mysqlModel.LedgerEvent{}); err != nil {
 		t.Fatal(err)
 	}
-	if err := mysqlTestDB.AutoMigrate(&mysqlModel.User{}); err != nil {
+	if err := mysqlTestDB.AutoMigrate(&mysqlModel.User{}, &mysqlModel.LedgerEvent{}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1,8 +1,8 @@
 package user
 
-import "errors"
+import "banking/domain"
 
 var (
-	ErrUserExisted         = errors.New("user existed")
-	ErrUserNotFound        = errors.New("user not found")
+	ErrUserExisted  = domain.NewError(domain.CodeUserExists, "user existed")
+	ErrUserNotFound = domain.NewError(domain.CodeUserNotFound, "user not found")
 )
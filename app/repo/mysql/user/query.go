@@ -2,45 +2,62 @@ package user
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"banking/app/repo/mysql/bucket"
 	domain "banking/domain"
 	mysqlModel "banking/model/mysql"
+	"banking/tracing"
 
-	"go.elastic.co/apm/v2"
 	"gorm.io/gorm"
 )
 
+// userQueryRepo reads the replica through router, unless the read has to see recent writes
 type userQueryRepo struct {
-	db *gorm.DB
+	router domain.IReadRouter
 }
 
-func NewUserQueryRepo(db *gorm.DB) domain.IUserQueryRepo {
+func NewUserQueryRepo(router domain.IReadRouter) domain.IUserQueryRepo {
 	return &userQueryRepo{
-		db: db,
+		router: router,
 	}
 }
 
 func (r *userQueryRepo) GetUsers(ctx context.Context, userID uint) (users []*mysqlModel.User, err error) {
-	span, ctx := apm.StartSpan(ctx, "userQueryRepo.GetUsers", "repo")
+	span, ctx := tracing.StartSpan(ctx, "userQueryRepo.GetUsers", "repo")
 	defer span.End()
 
 	if userID != 0 {
 		user := &mysqlModel.User{}
-		result := r.db.WithContext(ctx).Where("id = ?", userID).Take(&user)
+		db := r.router.Reader(ctx, userID).WithContext(ctx)
+		result := db.Where("id = ?", userID).Take(&user)
 		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return nil, ErrUserNotFound
+			}
 			return nil, result.Error
 		}
 
+		// hot accounts keep part of their balance in buckets
+		if err := bucket.AddBalances(db, user); err != nil {
+			return nil, err
+		}
+
 		users = append(users, user)
 		return users, nil
 	}
 
-	result := r.db.WithContext(ctx).Find(&users)
+	db := r.router.Reader(ctx, 0).WithContext(ctx)
+	result := db.Find(&users)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 
+	if err := bucket.AddBalances(db, users...); err != nil {
+		return nil, err
+	}
+
 	// Simulate slow query for demo
 	time.Sleep(2 * time.Millisecond)
 
@@ -48,13 +65,39 @@ func (r *userQueryRepo) GetUsers(ctx context.Context, userID uint) (users []*mys
 }
 
 func (r *userQueryRepo) GetUserByEmail(ctx context.Context, email string) (user *mysqlModel.User, err error) {
-	span, ctx := apm.StartSpan(ctx, "userQueryRepo.GetUserByEmail", "repo")
+	span, ctx := tracing.StartSpan(ctx, "userQueryRepo.GetUserByEmail", "repo")
 	defer span.End()
 
-	result := r.db.WithContext(ctx).Where("email = ?", email).Take(&user)
+	db := r.router.Reader(ctx, 0).WithContext(ctx)
+	result := db.Where("email = ?", email).Take(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 
+	if err := bucket.AddBalances(db, user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
+
+func (r *userQueryRepo) GetUserNames(ctx context.Context, userIDs []uint) (names map[uint]string, err error) {
+	span, ctx := tracing.StartSpan(ctx, "userQueryRepo.GetUserNames", "repo")
+	defer span.End()
+
+	names = make(map[uint]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return names, nil
+	}
+
+	users := []*mysqlModel.User{}
+	result := r.router.Reader(ctx, 0).WithContext(ctx).Select("id", "name").Where("id IN ?", userIDs).Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, user := range users {
+		names[user.ID] = user.Name
+	}
+	return names, nil
+}
@@ -5,8 +5,10 @@ import (
 	"testing"
 
 	userRepo "banking/app/repo/mysql/user"
+	domainMock "banking/domain/mock"
 	userModel "banking/model/mysql"
 
+	"github.com/golang/mock/gomock"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -43,7 +45,10 @@ func Test_GetUsers(t *testing.T) {
 		t.Fatal("Error creating user2:", err)
 	}
 
-	userQueryRepo := userRepo.NewUserQueryRepo(mysqlTestDB)
+	mockRouter := domainMock.NewMockIReadRouter(gomock.NewController(t))
+	mockRouter.EXPECT().Reader(gomock.Any(), uint(0)).Return(mysqlTestDB)
+
+	userQueryRepo := userRepo.NewUserQueryRepo(mockRouter)
 	users, err := userQueryRepo.GetUsers(context.Background(), 0)
 
 	assert.Nil(t, err)
@@ -0,0 +1,32 @@
+package watchlist
+
+import (
+	"context"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"gorm.io/gorm"
+)
+
+type watchlistCommandRepo struct {
+	db *gorm.DB
+}
+
+func NewWatchlistCommandRepo(db *gorm.DB) domain.IWatchlistCommandRepo {
+	return &watchlistCommandRepo{
+		db: db,
+	}
+}
+
+func (r *watchlistCommandRepo) CreateScreeningResult(ctx context.Context, result *mysqlModel.ScreeningResult) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "watchlistCommandRepo.CreateScreeningResult", "repo")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(result).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
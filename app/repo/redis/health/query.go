@@ -0,0 +1,26 @@
+package health
+
+import (
+	"context"
+
+	"banking/domain"
+	"banking/tracing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type healthRedisQueryRepo struct {
+	redisClient *redis.Client
+}
+
+func NewRedisHealthQueryRepo(redisClient *redis.Client) domain.IRedisHealthQueryRepo {
+	return &healthRedisQueryRepo{redisClient: redisClient}
+}
+
+// Ping is a round trip to the redis server
+func (r *healthRedisQueryRepo) Ping(ctx context.Context) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "healthRedisQueryRepo.Ping", "repo")
+	defer span.End()
+
+	return r.redisClient.Ping(ctx).Err()
+}
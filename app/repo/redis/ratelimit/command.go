@@ -0,0 +1,24 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"banking/domain"
+	"banking/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type rateLimitRedisCommandRepo struct {
+	redisClient *redis.Client
+}
+
+func NewRedisRateLimitCommandRepo(redisClient *redis.Client) domain.IRedisRateLimitCommandRepo {
+	return &rateLimitRedisCommandRepo{redisClient: redisClient}
+}
+
+// TakeRateLimit runs the script of utils.RateLimit, so every apiserver replica shares the budget of a key
+func (r *rateLimitRedisCommandRepo) TakeRateLimit(ctx context.Context, key string, limit int64, duration time.Duration) (remaining, reset int64, err error) {
+	return utils.RateLimit(ctx, r.redisClient, key, limit, duration)
+}
@@ -0,0 +1,32 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"banking/domain"
+	"banking/tracing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type routingRedisCommandRepo struct {
+	redisClient *redis.Client
+}
+
+func NewRedisRoutingCommandRepo(redisClient *redis.Client) domain.IRedisRoutingCommandRepo {
+	return &routingRedisCommandRepo{redisClient: redisClient}
+}
+
+// SetPin starts or extends the pin of the user, it expires after window
+func (r *routingRedisCommandRepo) SetPin(ctx context.Context, userID uint, window time.Duration) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "routingRedisCommandRepo.SetPin", "repo")
+	defer span.End()
+
+	return r.redisClient.Set(ctx, pinKey(userID), 1, window).Err()
+}
+
+func pinKey(userID uint) string {
+	return fmt.Sprintf("read_pin:%d", userID)
+}
@@ -0,0 +1,30 @@
+package routing
+
+import (
+	"context"
+
+	"banking/domain"
+	"banking/tracing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type routingRedisQueryRepo struct {
+	redisClient *redis.Client
+}
+
+func NewRedisRoutingQueryRepo(redisClient *redis.Client) domain.IRedisRoutingQueryRepo {
+	return &routingRedisQueryRepo{redisClient: redisClient}
+}
+
+func (r *routingRedisQueryRepo) IsPinned(ctx context.Context, userID uint) (pinned bool, err error) {
+	span, ctx := tracing.StartSpan(ctx, "routingRedisQueryRepo.IsPinned", "repo")
+	defer span.End()
+
+	count, err := r.redisClient.Exists(ctx, pinKey(userID)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
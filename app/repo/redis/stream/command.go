@@ -0,0 +1,56 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"banking/domain"
+	redisModel "banking/model/redis"
+	"banking/tracing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+)
+
+// eventChannel fans out the events of all users to every apiserver replica
+const eventChannel = "balanceStream"
+
+type streamCommandRepo struct {
+	redisClient *redis.Client
+}
+
+func NewRedisStreamCommandRepo(redisClient *redis.Client) domain.IRedisStreamCommandRepo {
+	return &streamCommandRepo{redisClient: redisClient}
+}
+
+// AppendEvent adds the event to the stream of the user, which keeps the last stream.maxLen events
+// for resuming clients, then publishes it with the assigned id
+func (r *streamCommandRepo) AppendEvent(ctx context.Context, event *redisModel.BalanceEvent) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "streamCommandRepo.AppendEvent", "repo")
+	defer span.End()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	event.ID, err = r.redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream:       streamKey(event.UserID),
+		MaxLenApprox: viper.GetInt64("stream.maxLen"),
+		Values:       map[string]interface{}{"event": payload},
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	if payload, err = json.Marshal(event); err != nil {
+		return err
+	}
+
+	return r.redisClient.Publish(ctx, eventChannel, payload).Err()
+}
+
+func streamKey(userID uint) string {
+	return fmt.Sprintf("balanceStream:%d", userID)
+}
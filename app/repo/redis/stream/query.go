@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"banking/domain"
+	"banking/global"
+	redisModel "banking/model/redis"
+	"banking/tracing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type streamQueryRepo struct {
+	redisClient *redis.Client
+}
+
+func NewRedisStreamQueryRepo(redisClient *redis.Client) domain.IRedisStreamQueryRepo {
+	return &streamQueryRepo{redisClient: redisClient}
+}
+
+// GetEventsAfter returns the events of the user stream newer than lastEventID, events trimmed
+// from the stream are no longer returned
+func (r *streamQueryRepo) GetEventsAfter(ctx context.Context, userID uint, lastEventID string) (events []*redisModel.BalanceEvent, err error) {
+	span, ctx := tracing.StartSpan(ctx, "streamQueryRepo.GetEventsAfter", "repo")
+	defer span.End()
+
+	messages, err := r.redisClient.XRange(ctx, streamKey(userID), lastEventID, "+").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events = make([]*redisModel.BalanceEvent, 0, len(messages))
+	for _, message := range messages {
+		// the range is inclusive, the client already has lastEventID
+		if message.ID == lastEventID {
+			continue
+		}
+
+		event, err := decodeEvent(message.Values["event"])
+		if err != nil {
+			return nil, err
+		}
+		event.ID = message.ID
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// SubscribeEvents receives the events of all users published by any replica until ctx is done
+func (r *streamQueryRepo) SubscribeEvents(ctx context.Context) (events <-chan *redisModel.BalanceEvent, err error) {
+	pubsub := r.redisClient.Subscribe(ctx, eventChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	out := make(chan *redisModel.BalanceEvent)
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				event, err := decodeEvent(message.Payload)
+				if err != nil {
+					global.Logger.Errorf("decode balance event error: %s", err)
+					continue
+				}
+
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func decodeEvent(value interface{}) (*redisModel.BalanceEvent, error) {
+	payload, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected balance event payload %T", value)
+	}
+
+	event := &redisModel.BalanceEvent{}
+	if err := json.Unmarshal([]byte(payload), event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
@@ -0,0 +1,290 @@
+package alias
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	aliasRepo "banking/app/repo/mysql/alias"
+	"banking/domain"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrAliasTypeInvalid = domain.NewError(domain.CodeInvalidRequest, "alias type must be email, phone or handle")
+	ErrEmailInvalid     = domain.NewError(domain.CodeInvalidRequest, "email is invalid")
+	ErrPhoneInvalid     = domain.NewError(domain.CodeInvalidRequest, "phone must be an international number, e.g. +33612345678")
+	ErrHandleInvalid    = domain.NewError(domain.CodeInvalidRequest, "handle must be 3 to 30 lowercase letters, digits, dots or underscores")
+	ErrAliasVerified    = domain.NewError(domain.CodeInvalidRequest, "alias is verified already")
+	ErrCodeInvalid      = domain.NewError(domain.CodeAliasCodeInvalid, "verification code is invalid")
+	ErrCodeExpired      = domain.NewError(domain.CodeAliasCodeInvalid, "verification code expired or was entered wrong too often, add the alias again for a new code")
+)
+
+var (
+	phonePattern  = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+	handlePattern = regexp.MustCompile(`^[a-z0-9_.]{3,30}$`)
+)
+
+const maxEmailLength = 254
+
+type aliasService struct {
+	aliasCmdRepo   domain.IAliasCommandRepo
+	aliasQueryRepo domain.IAliasQueryRepo
+	userQryRepo    domain.IUserQueryRepo
+	codeSender     domain.ICodeSender
+	auditService   domain.IAuditService
+	codeTTL        time.Duration
+	maxAttempts    int
+}
+
+func NewAliasService(AliasCmdRepo domain.IAliasCommandRepo, AliasQueryRepo domain.IAliasQueryRepo, UserQryRepo domain.IUserQueryRepo, CodeSender domain.ICodeSender, AuditService domain.IAuditService) domain.IAliasService {
+	return &aliasService{
+		aliasCmdRepo:   AliasCmdRepo,
+		aliasQueryRepo: AliasQueryRepo,
+		userQryRepo:    UserQryRepo,
+		codeSender:     CodeSender,
+		auditService:   AuditService,
+		codeTTL:        max(time.Duration(viper.GetInt("alias.codeTTL"))*time.Second, time.Minute),
+		maxAttempts:    max(viper.GetInt("alias.maxAttempts"), 1),
+	}
+}
+
+func (s *aliasService) CreateAlias(ctx context.Context, userID uint, aliasType mysqlModel.AliasType, value string) (alias *mysqlModel.PayeeAlias, err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasService.CreateAlias", "service")
+	defer span.End()
+
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditAliasCreate, fmt.Sprintf("user:%d", userID), nil, alias, err)
+	}()
+
+	value, err = NormalizeAlias(aliasType, value)
+	if err != nil {
+		return nil, err
+	}
+
+	// verified by anyone, the user included
+	if _, err := s.aliasQueryRepo.GetVerifiedAlias(ctx, value); err == nil {
+		return nil, aliasRepo.ErrAliasTaken
+	} else if !errors.Is(err, aliasRepo.ErrAliasNotFound) {
+		return nil, err
+	}
+
+	alias = &mysqlModel.PayeeAlias{UserID: userID, Type: aliasType, Value: value}
+
+	// a handle is only chosen, there is nothing to prove
+	if aliasType == mysqlModel.AliasHandle {
+		now := time.Now()
+		alias.VerifiedValue, alias.VerifiedAt = &value, &now
+		if err := s.aliasCmdRepo.CreateAlias(ctx, alias); err != nil {
+			return nil, err
+		}
+		return alias, nil
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		return nil, err
+	}
+	alias.CodeHash, err = utils.GenerateHashedSecretKey(code)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.codeTTL)
+	alias.CodeExpiresAt = &expiresAt
+
+	err = s.aliasCmdRepo.CreateAlias(ctx, alias)
+	if errors.Is(err, aliasRepo.ErrAliasTaken) {
+		// the user added the alias before, it gets a new code unless they verified it
+		err = s.aliasCmdRepo.RenewCode(ctx, alias)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.codeSender.SendCode(ctx, alias, code); err != nil {
+		// the user is left without a code, a pending alias would block no one but confuse them
+		if err := s.aliasCmdRepo.DeleteAlias(ctx, userID, alias.ID); err != nil {
+			global.LoggerFromContext(ctx).Errorf("delete alias %d after its code was not sent error: %s", alias.ID, err)
+		}
+		return nil, err
+	}
+
+	return alias, nil
+}
+
+// VerifyAlias checks the code sent for the alias, a code is valid for alias.codeTTL seconds and
+// alias.maxAttempts tries
+func (s *aliasService) VerifyAlias(ctx context.Context, userID, aliasID uint, code string) (alias *mysqlModel.PayeeAlias, err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasService.VerifyAlias", "service")
+	defer span.End()
+
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditAliasVerify, fmt.Sprintf("user:%d", userID), map[string]uint{"aliasId": aliasID}, alias, err)
+	}()
+
+	alias, err = s.getAlias(ctx, userID, aliasID)
+	if err != nil {
+		return nil, err
+	}
+
+	if alias.Verified() {
+		return nil, ErrAliasVerified
+	}
+
+	if alias.CodeExpiresAt == nil || time.Now().After(*alias.CodeExpiresAt) || alias.VerificationAttempts >= s.maxAttempts {
+		return nil, ErrCodeExpired
+	}
+
+	// the attempt is counted before the code is checked, concurrent requests can not try more codes
+	// than the attempts left
+	if err := s.aliasCmdRepo.CountAttempt(ctx, alias.ID, s.maxAttempts); err != nil {
+		if errors.Is(err, aliasRepo.ErrAttemptsExhausted) {
+			return nil, ErrCodeExpired
+		}
+		return nil, err
+	}
+
+	if !utils.VerifySecretKey(alias.CodeHash, code) {
+		return nil, ErrCodeInvalid
+	}
+
+	now := time.Now()
+	alias.VerifiedValue, alias.VerifiedAt = &alias.Value, &now
+	if err := s.aliasCmdRepo.VerifyAlias(ctx, alias); err != nil {
+		return nil, err
+	}
+
+	return alias, nil
+}
+
+func (s *aliasService) GetAliases(ctx context.Context, userID uint) (aliases []*mysqlModel.PayeeAlias, err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasService.GetAliases", "service")
+	defer span.End()
+
+	return s.aliasQueryRepo.GetAliases(ctx, userID)
+}
+
+func (s *aliasService) DeleteAlias(ctx context.Context, userID, aliasID uint) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasService.DeleteAlias", "service")
+	defer span.End()
+
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditAliasDelete, fmt.Sprintf("user:%d", userID), map[string]uint{"aliasId": aliasID}, nil, err)
+	}()
+
+	return s.aliasCmdRepo.DeleteAlias(ctx, userID, aliasID)
+}
+
+// Resolve tells the type of the alias by its form: a phone number starts with +, an email has an @
+// after its first character, anything else is a handle
+func (s *aliasService) Resolve(ctx context.Context, alias string) (payee *mysqlModel.Payee, err error) {
+	span, ctx := tracing.StartSpan(ctx, "aliasService.Resolve", "service")
+	defer span.End()
+
+	aliasType := AliasTypeOf(alias)
+	value, err := NormalizeAlias(aliasType, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	verified, err := s.aliasQueryRepo.GetVerifiedAlias(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := s.userQryRepo.GetUserNames(ctx, []uint{verified.UserID})
+	if err != nil {
+		return nil, err
+	}
+	name, ok := names[verified.UserID]
+	if !ok {
+		return nil, aliasRepo.ErrAliasNotFound
+	}
+
+	return &mysqlModel.Payee{UserID: verified.UserID, AliasType: aliasType, MaskedName: MaskName(name)}, nil
+}
+
+// getAlias returns an alias of userID, the aliases of other users are not found
+func (s *aliasService) getAlias(ctx context.Context, userID, aliasID uint) (*mysqlModel.PayeeAlias, error) {
+	alias, err := s.aliasQueryRepo.GetAlias(ctx, aliasID)
+	if err != nil {
+		return nil, err
+	}
+
+	if alias.UserID != userID {
+		return nil, aliasRepo.ErrAliasNotFound
+	}
+
+	return alias, nil
+}
+
+// AliasTypeOf guesses the type of an alias entered by a payer
+func AliasTypeOf(alias string) mysqlModel.AliasType {
+	alias = strings.TrimSpace(alias)
+	switch {
+	case strings.HasPrefix(alias, "+"):
+		return mysqlModel.AliasPhone
+	case strings.Index(alias, "@") > 0:
+		return mysqlModel.AliasEmail
+	}
+	return mysqlModel.AliasHandle
+}
+
+// NormalizeAlias returns the stored form of an alias: lowercase emails and handles without a leading @,
+// phone numbers without spaces, dashes, dots or parentheses
+func NormalizeAlias(aliasType mysqlModel.AliasType, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch aliasType {
+	case mysqlModel.AliasEmail:
+		value = strings.ToLower(value)
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value || len(value) > maxEmailLength {
+			return "", ErrEmailInvalid
+		}
+		return value, nil
+	case mysqlModel.AliasPhone:
+		value = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(value)
+		if !phonePattern.MatchString(value) {
+			return "", ErrPhoneInvalid
+		}
+		return value, nil
+	case mysqlModel.AliasHandle:
+		value = strings.TrimPrefix(strings.ToLower(value), "@")
+		if !handlePattern.MatchString(value) {
+			return "", ErrHandleInvalid
+		}
+		return value, nil
+	}
+	return "", ErrAliasTypeInvalid
+}
+
+// MaskName keeps the first letter of each word of a name, a payer recognizes the payee without
+// learning their full name
+func MaskName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		runes := []rune(word)
+		words[i] = string(runes[0]) + strings.Repeat("*", len(runes)-1)
+	}
+	return strings.Join(words, " ")
+}
+
+// generateCode returns a random code of 6 digits
+func generateCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
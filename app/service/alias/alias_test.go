@@ -0,0 +1,255 @@
+package alias_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	aliasRepo "banking/app/repo/mysql/alias"
+	aliasSrv "banking/app/service/alias"
+	"banking/domain"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/utils"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type aliasMocks struct {
+	cmdRepo   *domainMock.MockIAliasCommandRepo
+	queryRepo *domainMock.MockIAliasQueryRepo
+	userRepo  *domainMock.MockIUserQueryRepo
+	sender    *domainMock.MockICodeSender
+}
+
+func initialAliasService(t *testing.T) (domain.IAliasService, *aliasMocks) {
+	global.Logger = zap.NewNop().Sugar()
+
+	viper.Set("alias.codeTTL", 600)
+	viper.Set("alias.maxAttempts", 3)
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	mocks := &aliasMocks{
+		cmdRepo:   domainMock.NewMockIAliasCommandRepo(ctrl),
+		queryRepo: domainMock.NewMockIAliasQueryRepo(ctrl),
+		userRepo:  domainMock.NewMockIUserQueryRepo(ctrl),
+		sender:    domainMock.NewMockICodeSender(ctrl),
+	}
+	audit := domainMock.NewMockIAuditService(ctrl)
+	audit.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	return aliasSrv.NewAliasService(mocks.cmdRepo, mocks.queryRepo, mocks.userRepo, mocks.sender, audit), mocks
+}
+
+func Test_NormalizeAlias(t *testing.T) {
+	tests := []struct {
+		aliasType mysqlModel.AliasType
+		value     string
+		want      string
+		err       error
+	}{
+		{aliasType: mysqlModel.AliasEmail, value: " Alice@YopMail.com ", want: "alice@yopmail.com"},
+		{aliasType: mysqlModel.AliasEmail, value: "Alice <alice@yopmail.com>", err: aliasSrv.ErrEmailInvalid},
+		{aliasType: mysqlModel.AliasEmail, value: "alice", err: aliasSrv.ErrEmailInvalid},
+		{aliasType: mysqlModel.AliasPhone, value: "+33 6 12-34.56 (78)", want: "+33612345678"},
+		{aliasType: mysqlModel.AliasPhone, value: "0612345678", err: aliasSrv.ErrPhoneInvalid},
+		{aliasType: mysqlModel.AliasPhone, value: "+0612345678", err: aliasSrv.ErrPhoneInvalid},
+		{aliasType: mysqlModel.AliasHandle, value: "@Alice.M", want: "alice.m"},
+		{aliasType: mysqlModel.AliasHandle, value: "al", err: aliasSrv.ErrHandleInvalid},
+		{aliasType: mysqlModel.AliasHandle, value: "alice@yopmail.com", err: aliasSrv.ErrHandleInvalid},
+		{aliasType: "iban", value: "FR76", err: aliasSrv.ErrAliasTypeInvalid},
+	}
+
+	for _, tt := range tests {
+		got, err := aliasSrv.NormalizeAlias(tt.aliasType, tt.value)
+		assert.Equal(t, tt.err, err, tt.value)
+		assert.Equal(t, tt.want, got, tt.value)
+	}
+}
+
+func Test_AliasTypeOf(t *testing.T) {
+	assert.Equal(t, mysqlModel.AliasPhone, aliasSrv.AliasTypeOf("+33612345678"))
+	assert.Equal(t, mysqlModel.AliasEmail, aliasSrv.AliasTypeOf("alice@yopmail.com"))
+	assert.Equal(t, mysqlModel.AliasHandle, aliasSrv.AliasTypeOf("@alice"))
+	assert.Equal(t, mysqlModel.AliasHandle, aliasSrv.AliasTypeOf("alice"))
+}
+
+func Test_MaskName(t *testing.T) {
+	assert.Equal(t, "J*** S****", aliasSrv.MaskName("John Smith"))
+	assert.Equal(t, "É*****", aliasSrv.MaskName("Élodie"))
+	assert.Equal(t, "a", aliasSrv.MaskName("a"))
+}
+
+func Test_CreateAlias(t *testing.T) {
+	t.Run("email is sent a code", func(t *testing.T) {
+		service, mocks := initialAliasService(t)
+
+		mocks.queryRepo.EXPECT().GetVerifiedAlias(gomock.Any(), "alice@yopmail.com").Return(nil, aliasRepo.ErrAliasNotFound)
+		mocks.cmdRepo.EXPECT().CreateAlias(gomock.Any(), gomock.Any()).Return(nil)
+		mocks.sender.EXPECT().SendCode(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, alias *mysqlModel.PayeeAlias, code string) error {
+			assert.Len(t, code, 6)
+			assert.True(t, utils.VerifySecretKey(alias.CodeHash, code), "only the hash is stored")
+			return nil
+		})
+
+		alias, err := service.CreateAlias(context.Background(), 1, mysqlModel.AliasEmail, "Alice@yopmail.com")
+		require.NoError(t, err)
+		assert.Equal(t, "alice@yopmail.com", alias.Value)
+		assert.False(t, alias.Verified())
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), *alias.CodeExpiresAt, time.Minute)
+	})
+
+	t.Run("added again is sent a new code", func(t *testing.T) {
+		service, mocks := initialAliasService(t)
+
+		mocks.queryRepo.EXPECT().GetVerifiedAlias(gomock.Any(), "alice@yopmail.com").Return(nil, aliasRepo.ErrAliasNotFound)
+		mocks.cmdRepo.EXPECT().CreateAlias(gomock.Any(), gomock.Any()).Return(aliasRepo.ErrAliasTaken)
+		var renewed string
+		mocks.cmdRepo.EXPECT().RenewCode(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, alias *mysqlModel.PayeeAlias) error {
+			renewed, alias.ID = alias.CodeHash, 3
+			return nil
+		})
+		mocks.sender.EXPECT().SendCode(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, alias *mysqlModel.PayeeAlias, code string) error {
+			assert.True(t, utils.VerifySecretKey(renewed, code))
+			return nil
+		})
+
+		alias, err := service.CreateAlias(context.Background(), 1, mysqlModel.AliasEmail, "alice@yopmail.com")
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), alias.ID)
+	})
+
+	t.Run("code not sent", func(t *testing.T) {
+		service, mocks := initialAliasService(t)
+
+		mocks.queryRepo.EXPECT().GetVerifiedAlias(gomock.Any(), "alice@yopmail.com").Return(nil, aliasRepo.ErrAliasNotFound)
+		mocks.cmdRepo.EXPECT().CreateAlias(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, alias *mysqlModel.PayeeAlias) error {
+			alias.ID = 3
+			return nil
+		})
+		mocks.sender.EXPECT().SendCode(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+		// the alias is removed, adding it again sends a code
+		mocks.cmdRepo.EXPECT().DeleteAlias(gomock.Any(), uint(1), uint(3)).Return(nil)
+
+		_, err := service.CreateAlias(context.Background(), 1, mysqlModel.AliasEmail, "alice@yopmail.com")
+		assert.EqualError(t, err, "smtp down")
+	})
+
+	t.Run("handle is verified at once", func(t *testing.T) {
+		service, mocks := initialAliasService(t)
+
+		mocks.queryRepo.EXPECT().GetVerifiedAlias(gomock.Any(), "alice").Return(nil, aliasRepo.ErrAliasNotFound)
+		mocks.cmdRepo.EXPECT().CreateAlias(gomock.Any(), gomock.Any()).Return(nil)
+
+		alias, err := service.CreateAlias(context.Background(), 1, mysqlModel.AliasHandle, "@alice")
+		require.NoError(t, err)
+		assert.True(t, alias.Verified())
+		assert.Equal(t, "alice", *alias.VerifiedValue)
+	})
+
+	t.Run("taken", func(t *testing.T) {
+		service, mocks := initialAliasService(t)
+
+		mocks.queryRepo.EXPECT().GetVerifiedAlias(gomock.Any(), "alice").Return(&mysqlModel.PayeeAlias{UserID: 2}, nil)
+
+		_, err := service.CreateAlias(context.Background(), 1, mysqlModel.AliasHandle, "alice")
+		assert.ErrorIs(t, err, aliasRepo.ErrAliasTaken)
+	})
+}
+
+func Test_VerifyAlias(t *testing.T) {
+	hash, err := utils.GenerateHashedSecretKey("123456")
+	require.NoError(t, err)
+	pending := func(attempts int, expiresIn time.Duration) *mysqlModel.PayeeAlias {
+		expiresAt := time.Now().Add(expiresIn)
+		return &mysqlModel.PayeeAlias{ID: 3, UserID: 1, Type: mysqlModel.AliasPhone, Value: "+33612345678", CodeHash: hash, CodeExpiresAt: &expiresAt, VerificationAttempts: attempts}
+	}
+
+	t.Run("verified", func(t *testing.T) {
+		service, mocks := initialAliasService(t)
+
+		mocks.queryRepo.EXPECT().GetAlias(gomock.Any(), uint(3)).Return(pending(0, time.Minute), nil)
+		mocks.cmdRepo.EXPECT().CountAttempt(gomock.Any(), uint(3), 3).Return(nil)
+		mocks.cmdRepo.EXPECT().VerifyAlias(gomock.Any(), gomock.Any()).Return(nil)
+
+		alias, err := service.VerifyAlias(context.Background(), 1, 3, "123456")
+		require.NoError(t, err)
+		assert.True(t, alias.Verified())
+		assert.Equal(t, "+33612345678", *alias.VerifiedValue)
+	})
+
+	t.Run("wrong code is counted", func(t *testing.T) {
+		service, mocks := initialAliasService(t)
+
+		mocks.queryRepo.EXPECT().GetAlias(gomock.Any(), uint(3)).Return(pending(0, time.Minute), nil)
+		mocks.cmdRepo.EXPECT().CountAttempt(gomock.Any(), uint(3), 3).Return(nil)
+
+		_, err := service.VerifyAlias(context.Background(), 1, 3, "654321")
+		assert.ErrorIs(t, err, aliasSrv.ErrCodeInvalid)
+	})
+
+	t.Run("last attempt taken by a concurrent request", func(t *testing.T) {
+		service, mocks := initialAliasService(t)
+
+		mocks.queryRepo.EXPECT().GetAlias(gomock.Any(), uint(3)).Return(pending(2, time.Minute), nil)
+		mocks.cmdRepo.EXPECT().CountAttempt(gomock.Any(), uint(3), 3).Return(aliasRepo.ErrAttemptsExhausted)
+
+		// even the right code
+		_, err := service.VerifyAlias(context.Background(), 1, 3, "123456")
+		assert.ErrorIs(t, err, aliasSrv.ErrCodeExpired)
+	})
+
+	tests := []struct {
+		name  string
+		alias *mysqlModel.PayeeAlias
+		want  error
+	}{
+		{name: "expired", alias: pending(0, -time.Second), want: aliasSrv.ErrCodeExpired},
+		{name: "too many attempts", alias: pending(3, time.Minute), want: aliasSrv.ErrCodeExpired},
+		{name: "of another user", alias: &mysqlModel.PayeeAlias{ID: 3, UserID: 2}, want: aliasRepo.ErrAliasNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mocks := initialAliasService(t)
+
+			mocks.queryRepo.EXPECT().GetAlias(gomock.Any(), uint(3)).Return(tt.alias, nil)
+
+			_, err := service.VerifyAlias(context.Background(), 1, 3, "123456")
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func Test_Resolve(t *testing.T) {
+	t.Run("masked name", func(t *testing.T) {
+		service, mocks := initialAliasService(t)
+
+		mocks.queryRepo.EXPECT().GetVerifiedAlias(gomock.Any(), "+33612345678").Return(&mysqlModel.PayeeAlias{UserID: 2}, nil)
+		mocks.userRepo.EXPECT().GetUserNames(gomock.Any(), []uint{2}).Return(map[uint]string{2: "John Smith"}, nil)
+
+		payee, err := service.Resolve(context.Background(), "+33 6 12 34 56 78")
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), payee.UserID)
+		assert.Equal(t, mysqlModel.AliasPhone, payee.AliasType)
+		assert.Equal(t, "J*** S****", payee.MaskedName)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		service, mocks := initialAliasService(t)
+
+		mocks.queryRepo.EXPECT().GetVerifiedAlias(gomock.Any(), "bob").Return(nil, aliasRepo.ErrAliasNotFound)
+
+		_, err := service.Resolve(context.Background(), "@Bob")
+		assert.ErrorIs(t, err, aliasRepo.ErrAliasNotFound)
+	})
+}
@@ -0,0 +1,87 @@
+package alias
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"banking/domain"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/spf13/viper"
+)
+
+// NewCodeSender returns the sender of alias.sender: webhook posts the codes to the email and SMS
+// gateway at alias.webhook.url, log writes them to the log for development. Any other sender, unset
+// included, is a configuration error, codes would never reach the users
+func NewCodeSender() domain.ICodeSender {
+	switch sender := viper.GetString("alias.sender"); sender {
+	case "log":
+		return &logSender{}
+	case "webhook":
+		url := viper.GetString("alias.webhook.url")
+		if url == "" {
+			panic("alias.webhook.url is required by the webhook sender")
+		}
+		return &webhookSender{
+			url:    url,
+			client: &http.Client{Timeout: time.Duration(viper.GetInt("alias.webhook.timeout")) * time.Second},
+		}
+	default:
+		panic(fmt.Sprintf("alias.sender must be log or webhook, got %q", sender))
+	}
+}
+
+type logSender struct{}
+
+func (s *logSender) SendCode(ctx context.Context, alias *mysqlModel.PayeeAlias, code string) error {
+	global.LoggerFromContext(ctx).Infof("verification code of %s alias %d of user %d: %s", alias.Type, alias.ID, alias.UserID, code)
+	return nil
+}
+
+// CodeMessage is the body posted to the gateway, it sends Code to Value
+type CodeMessage struct {
+	Type      mysqlModel.AliasType `json:"type"`
+	Value     string               `json:"value"`
+	Code      string               `json:"code"`
+	ExpiresAt *time.Time           `json:"expiresAt"`
+}
+
+type webhookSender struct {
+	url    string
+	client *http.Client
+}
+
+func (s *webhookSender) SendCode(ctx context.Context, alias *mysqlModel.PayeeAlias, code string) error {
+	span, ctx := tracing.StartSpan(ctx, "webhookSender.SendCode", "service")
+	defer span.End()
+
+	body, err := json.Marshal(&CodeMessage{Type: alias.Type, Value: alias.Value, Code: code, ExpiresAt: alias.CodeExpiresAt})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(utils.RequestIDHeader, utils.RequestIDFromContext(ctx))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("send verification code: status %d", resp.StatusCode)
+	}
+	return nil
+}
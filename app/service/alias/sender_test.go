@@ -0,0 +1,42 @@
+package alias_test
+
+import (
+	"testing"
+
+	aliasSrv "banking/app/service/alias"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewCodeSender(t *testing.T) {
+	tests := []struct {
+		name   string
+		sender string
+		url    string
+		panics bool
+	}{
+		{name: "log", sender: "log"},
+		{name: "webhook", sender: "webhook", url: "http://localhost:8090/codes"},
+		{name: "webhook without url", sender: "webhook", panics: true},
+		{name: "unset", panics: true},
+		{name: "unknown", sender: "sms", panics: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("alias.sender", tt.sender)
+			viper.Set("alias.webhook.url", tt.url)
+			t.Cleanup(func() {
+				viper.Set("alias.sender", nil)
+				viper.Set("alias.webhook.url", nil)
+			})
+
+			if tt.panics {
+				assert.Panics(t, func() { aliasSrv.NewCodeSender() })
+				return
+			}
+			assert.NotNil(t, aliasSrv.NewCodeSender())
+		})
+	}
+}
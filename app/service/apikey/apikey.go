@@ -2,6 +2,7 @@ package apikey
 
 import (
 	"context"
+	"fmt"
 
 	"banking/domain"
 	mysqlModel "banking/model/mysql"
@@ -15,18 +16,25 @@ type apikeyService struct {
 	apikeyRedisQueryRepo domain.IRedisAPIKeyQueryRepo
 	apikeyCmdRepo        domain.IAPIKeyCommandRepo
 	apikeyQueryRepo      domain.IAPIKeyQueryRepo
+	auditService         domain.IAuditService
 }
 
-func NewAPIKeyService(APIKeyRedisCmdRepo domain.IRedisAPIKeyCommandRepo, APIKeyRedisQueryRepo domain.IRedisAPIKeyQueryRepo, APIKeyCmdRepo domain.IAPIKeyCommandRepo, APIKeyQueryRepo domain.IAPIKeyQueryRepo) domain.IAPIKeyService {
+func NewAPIKeyService(APIKeyRedisCmdRepo domain.IRedisAPIKeyCommandRepo, APIKeyRedisQueryRepo domain.IRedisAPIKeyQueryRepo, APIKeyCmdRepo domain.IAPIKeyCommandRepo, APIKeyQueryRepo domain.IAPIKeyQueryRepo, AuditService domain.IAuditService) domain.IAPIKeyService {
 	return &apikeyService{
 		apikeyRedisCmdRepo:   APIKeyRedisCmdRepo,
 		apikeyRedisQueryRepo: APIKeyRedisQueryRepo,
 		apikeyCmdRepo:        APIKeyCmdRepo,
 		apikeyQueryRepo:      APIKeyQueryRepo,
+		auditService:         AuditService,
 	}
 }
 
 func (s *apikeyService) CreateAPIKey(ctx context.Context, userID uint) (key string, secret string, err error) {
+	// the secret is never written to the audit log
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditAPIKeyCreate, fmt.Sprintf("user:%d", userID), nil, map[string]string{"key": key}, err)
+	}()
+
 	// Generate key and secret
 	key = utils.GenerateRandomAPIKey()
 	secret = utils.GenerateRandomSecretKey()
@@ -115,6 +123,10 @@ func (s *apikeyService) GetAPIKeys(ctx context.Context, userID uint, key string)
 }
 
 func (s *apikeyService) DeleteAPIKey(ctx context.Context, userID uint, key string) (err error) {
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditAPIKeyDelete, fmt.Sprintf("user:%d", userID), map[string]string{"key": key}, nil, err)
+	}()
+
 	// delete api key from redis
 	err = s.apikeyRedisCmdRepo.DeleteRedisAPIKey(ctx, userID, key)
 	if err != nil {
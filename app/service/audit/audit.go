@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"banking/domain"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
+)
+
+// verifyBatchSize is the number of entries loaded per query while verifying the chain
+const verifyBatchSize = 1000
+
+type auditService struct {
+	auditCmdRepo   domain.IAuditCommandRepo
+	auditQueryRepo domain.IAuditQueryRepo
+}
+
+func NewAuditService(AuditCmdRepo domain.IAuditCommandRepo, AuditQueryRepo domain.IAuditQueryRepo) domain.IAuditService {
+	return &auditService{
+		auditCmdRepo:   AuditCmdRepo,
+		auditQueryRepo: AuditQueryRepo,
+	}
+}
+
+func (s *auditService) Prepare(ctx context.Context, action, target string, before interface{}) context.Context {
+	return utils.ContextWithAuditEntry(ctx, newEntry(ctx, action, target, before, nil))
+}
+
+func (s *auditService) Record(ctx context.Context, action, target string, before, after interface{}, actionErr error) {
+	span, ctx := tracing.StartSpan(ctx, "auditService.Record", "service")
+	defer span.End()
+
+	// a successful action appended its prepared entry when it committed
+	if prepared := utils.AuditEntryFromContext(ctx); actionErr == nil && prepared != nil && prepared.ID != 0 &&
+		prepared.Action == action && prepared.Target == target {
+		return
+	}
+
+	entry := newEntry(ctx, action, target, before, after)
+	if actionErr != nil {
+		entry.Outcome = mysqlModel.AuditFailure
+	}
+
+	if err := s.auditCmdRepo.AppendAuditLog(ctx, entry); err != nil {
+		tracing.CaptureError(ctx, err)
+		global.LoggerFromContext(ctx).Errorf("append audit log %s on %s error: %s", action, target, err)
+	}
+}
+
+func (s *auditService) GetAuditLogs(ctx context.Context, actorID uint, action string, from, to time.Time, afterID uint, limit int) (logs []*mysqlModel.AuditLog, err error) {
+	span, ctx := tracing.StartSpan(ctx, "auditService.GetAuditLogs", "service")
+	defer span.End()
+
+	return s.auditQueryRepo.GetAuditLogs(ctx, actorID, action, from, to, afterID, limit)
+}
+
+// VerifyAuditChain recomputes every hash in order, brokenID is the first entry which does not
+// link to its predecessor or whose content changed, 0 when the chain is intact up to the head
+func (s *auditService) VerifyAuditChain(ctx context.Context) (checked int, brokenID uint, err error) {
+	span, ctx := tracing.StartSpan(ctx, "auditService.VerifyAuditChain", "service")
+	defer span.End()
+
+	// the head is read first, entries appended while verifying are checked up to it only
+	head, err := s.auditQueryRepo.GetAuditChainHead(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	prevHash := mysqlModel.AuditGenesisHash
+	var lastID uint
+	for lastID < head.LastID {
+		logs, err := s.auditQueryRepo.GetAuditLogs(ctx, 0, "", time.Time{}, time.Time{}, lastID, verifyBatchSize)
+		if err != nil {
+			return checked, 0, err
+		}
+		if len(logs) == 0 {
+			break
+		}
+
+		for _, log := range logs {
+			if log.ID > head.LastID {
+				return checked, 0, nil
+			}
+			if log.PrevHash != prevHash || log.ComputeHash() != log.Hash {
+				return checked, log.ID, nil
+			}
+
+			prevHash = log.Hash
+			lastID = log.ID
+			checked++
+		}
+	}
+
+	// entries removed from the end of the chain leave the head pointing past the last row
+	if lastID != head.LastID || prevHash != head.Hash {
+		return checked, head.LastID, nil
+	}
+
+	return checked, 0, nil
+}
+
+// newEntry returns a successful entry of the actor in ctx
+func newEntry(ctx context.Context, action, target string, before, after interface{}) *mysqlModel.AuditLog {
+	// millisecond precision matches the datetime(3) column, so the stored entry hashes the same
+	entry := &mysqlModel.AuditLog{
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+		IP:        utils.ClientIPFromContext(ctx),
+		Action:    action,
+		Target:    target,
+		Before:    marshalAuditValue(before),
+		After:     marshalAuditValue(after),
+		RequestID: utils.RequestIDFromContext(ctx),
+		Outcome:   mysqlModel.AuditSuccess,
+	}
+	if actor := utils.ActorFromContext(ctx); actor != nil {
+		entry.ActorID = &actor.UserID
+		entry.ActorEmail = actor.Email
+		entry.AuthMethod = actor.AuthMethod
+	}
+
+	return entry
+}
+
+func marshalAuditValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
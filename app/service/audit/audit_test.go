@@ -0,0 +1,117 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	auditSrv "banking/app/service/audit"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/utils"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func initialAuditService(t *testing.T) (*domainMock.MockIAuditCommandRepo, *domainMock.MockIAuditQueryRepo) {
+	global.Logger = zap.NewNop().Sugar()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	return domainMock.NewMockIAuditCommandRepo(ctrl), domainMock.NewMockIAuditQueryRepo(ctrl)
+}
+
+// buildChain links the entries the same way the command repo does
+func buildChain(actions ...string) []*mysqlModel.AuditLog {
+	logs := make([]*mysqlModel.AuditLog, 0, len(actions))
+	prevHash := mysqlModel.AuditGenesisHash
+	for i, action := range actions {
+		log := &mysqlModel.AuditLog{
+			ID:        uint(i + 1),
+			CreatedAt: time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC),
+			Action:    action,
+			Outcome:   mysqlModel.AuditSuccess,
+			PrevHash:  prevHash,
+		}
+		log.Hash = log.ComputeHash()
+		prevHash = log.Hash
+		logs = append(logs, log)
+	}
+
+	return logs
+}
+
+func Test_Record(t *testing.T) {
+	mockCmdRepo, mockQueryRepo := initialAuditService(t)
+
+	ctx := utils.ContextWithActor(context.Background(), &utils.Actor{UserID: 2, Email: "user2@yopmail.com", AuthMethod: utils.AuthMethodAPIKey})
+	ctx = utils.ContextWithClientIP(ctx, "10.0.0.1")
+	ctx = utils.ContextWithRequestID(ctx, "req-1")
+
+	mockCmdRepo.EXPECT().AppendAuditLog(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, log *mysqlModel.AuditLog) error {
+		assert.Equal(t, uint(2), *log.ActorID)
+		assert.Equal(t, utils.AuthMethodAPIKey, log.AuthMethod)
+		assert.Equal(t, "10.0.0.1", log.IP)
+		assert.Equal(t, "req-1", log.RequestID)
+		assert.Equal(t, `{"amount":"10"}`, log.Before)
+		assert.Equal(t, "", log.After)
+		assert.Equal(t, mysqlModel.AuditFailure, log.Outcome)
+		return nil
+	})
+
+	srv := auditSrv.NewAuditService(mockCmdRepo, mockQueryRepo)
+	srv.Record(ctx, mysqlModel.AuditWithdraw, "user:2", map[string]string{"amount": "10"}, nil, assert.AnError)
+}
+
+func Test_VerifyAuditChain_Valid(t *testing.T) {
+	mockCmdRepo, mockQueryRepo := initialAuditService(t)
+
+	logs := buildChain("user.login", "transaction.transfer", "apikey.create")
+	mockQueryRepo.EXPECT().GetAuditChainHead(gomock.Any()).Return(&mysqlModel.AuditChainHead{ID: 1, LastID: 3, Hash: logs[2].Hash}, nil)
+	mockQueryRepo.EXPECT().GetAuditLogs(gomock.Any(), uint(0), "", gomock.Any(), gomock.Any(), uint(0), gomock.Any()).Return(logs, nil)
+
+	srv := auditSrv.NewAuditService(mockCmdRepo, mockQueryRepo)
+	checked, brokenID, err := srv.VerifyAuditChain(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, checked)
+	assert.Equal(t, uint(0), brokenID)
+}
+
+func Test_VerifyAuditChain_Modified(t *testing.T) {
+	mockCmdRepo, mockQueryRepo := initialAuditService(t)
+
+	logs := buildChain("user.login", "transaction.transfer", "apikey.create")
+	logs[1].Target = "user:9"
+	mockQueryRepo.EXPECT().GetAuditChainHead(gomock.Any()).Return(&mysqlModel.AuditChainHead{ID: 1, LastID: 3, Hash: logs[2].Hash}, nil)
+	mockQueryRepo.EXPECT().GetAuditLogs(gomock.Any(), uint(0), "", gomock.Any(), gomock.Any(), uint(0), gomock.Any()).Return(logs, nil)
+
+	srv := auditSrv.NewAuditService(mockCmdRepo, mockQueryRepo)
+	checked, brokenID, err := srv.VerifyAuditChain(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, checked)
+	assert.Equal(t, uint(2), brokenID)
+}
+
+func Test_VerifyAuditChain_TailDeleted(t *testing.T) {
+	mockCmdRepo, mockQueryRepo := initialAuditService(t)
+
+	logs := buildChain("user.login", "transaction.transfer", "apikey.create")
+	mockQueryRepo.EXPECT().GetAuditChainHead(gomock.Any()).Return(&mysqlModel.AuditChainHead{ID: 1, LastID: 3, Hash: logs[2].Hash}, nil)
+	mockQueryRepo.EXPECT().GetAuditLogs(gomock.Any(), uint(0), "", gomock.Any(), gomock.Any(), uint(0), gomock.Any()).Return(logs[:2], nil)
+	mockQueryRepo.EXPECT().GetAuditLogs(gomock.Any(), uint(0), "", gomock.Any(), gomock.Any(), uint(2), gomock.Any()).Return(nil, nil)
+
+	srv := auditSrv.NewAuditService(mockCmdRepo, mockQueryRepo)
+	checked, brokenID, err := srv.VerifyAuditChain(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, checked)
+	assert.Equal(t, uint(3), brokenID)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"banking/domain"
+	"banking/metrics"
 	"banking/utils"
 
 	"github.com/go-redis/redis/v8"
@@ -48,6 +49,8 @@ func (s *authService) APIKeyConfirmation(ctx context.Context, userID uint, key s
 		return err
 	}
 
+	metrics.ObserveAPIKeyCache(hashedSecret != "")
+
 	// If the API key is not found in Redis, fall back to the database
 	if hashedSecret == "" {
 		// Query the database for the API key and secret key
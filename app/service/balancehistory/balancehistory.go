@@ -0,0 +1,160 @@
+package balancehistory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+var ErrRangeInvalid = domain.NewError(domain.CodeInvalidRequest, "to must not be before from")
+
+type balanceHistoryService struct {
+	balanceHistoryQryRepo domain.IBalanceHistoryQueryRepo
+	userQryRepo           domain.IUserQueryRepo
+	maxDays               int
+}
+
+func NewBalanceHistoryService(BalanceHistoryQryRepo domain.IBalanceHistoryQueryRepo, UserQryRepo domain.IUserQueryRepo) domain.IBalanceHistoryService {
+	return &balanceHistoryService{
+		balanceHistoryQryRepo: BalanceHistoryQryRepo,
+		userQryRepo:           UserQryRepo,
+		maxDays:               max(viper.GetInt("balanceHistory.maxDays"), 1),
+	}
+}
+
+func (s *balanceHistoryService) GetBalanceAt(ctx context.Context, userID uint, at time.Time) (balance decimal.Decimal, err error) {
+	span, ctx := tracing.StartSpan(ctx, "balanceHistoryService.GetBalanceAt", "service")
+	defer span.End()
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if !at.After(user.CreatedAt) {
+		return decimal.Zero, nil
+	}
+
+	return s.balanceBefore(ctx, user, at)
+}
+
+// GetDailyBalances reads the balance before the first day and the transactions of the range, one
+// query per side of the transactions whatever the length of the history
+func (s *balanceHistoryService) GetDailyBalances(ctx context.Context, userID uint, from, to time.Time) (balances []*mysqlModel.DailyBalance, err error) {
+	span, ctx := tracing.StartSpan(ctx, "balanceHistoryService.GetDailyBalances", "service")
+	defer span.End()
+
+	start := startOfDay(from)
+	end := startOfDay(to).AddDate(0, 0, 1)
+	if !end.After(start) {
+		return nil, ErrRangeInvalid
+	}
+
+	days := 0
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		days++
+	}
+	if days > s.maxDays {
+		return nil, domain.NewError(domain.CodeInvalidRequest, fmt.Sprintf("at most %d days per request", s.maxDays))
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := s.balanceBefore(ctx, user, start)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.balanceHistoryQryRepo.GetTransactionsBetween(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	balances = make([]*mysqlModel.DailyBalance, 0, days)
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		for len(transactions) > 0 && transactions[0].CreatedAt.Before(next) {
+			balance = transactions[0].BalanceAfter(userID, balance)
+			transactions = transactions[1:]
+		}
+
+		dayBalance := balance
+		if !next.After(user.CreatedAt) {
+			dayBalance = decimal.Zero
+		}
+		balances = append(balances, &mysqlModel.DailyBalance{Date: day.Format(time.DateOnly), Balance: dayBalance})
+	}
+
+	return balances, nil
+}
+
+// balanceBefore starts from the exact balance recorded by the last transaction before at and adds
+// the credits through buckets since. Without one it starts from the balance before the next exact
+// transaction, or the current balance without any, and takes off the credits through buckets before
+func (s *balanceHistoryService) balanceBefore(ctx context.Context, user *mysqlModel.User, at time.Time) (balance decimal.Decimal, err error) {
+	last, err := s.balanceHistoryQryRepo.GetLastTransaction(ctx, user.ID, at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if last != nil {
+		credits, err := s.balanceHistoryQryRepo.GetTransactionsBetween(ctx, user.ID, last.CreatedAt, at)
+		if err != nil {
+			return decimal.Zero, err
+		}
+
+		balance = last.BalanceOf(user.ID)
+		for _, credit := range credits {
+			if last.RecordedBefore(credit) {
+				balance = balance.Add(credit.PostingOf(user.ID))
+			}
+		}
+		return balance, nil
+	}
+
+	next, err := s.balanceHistoryQryRepo.GetNextTransaction(ctx, user.ID, at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	balance, until := user.Balance, time.Now()
+	if next != nil {
+		balance, until = next.BalanceBefore(user.ID), next.CreatedAt.Add(time.Millisecond)
+	}
+
+	credits, err := s.balanceHistoryQryRepo.GetTransactionsBetween(ctx, user.ID, at, until)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	for _, credit := range credits {
+		if next == nil || credit.RecordedBefore(next) {
+			balance = balance.Sub(credit.PostingOf(user.ID))
+		}
+	}
+
+	return balance, nil
+}
+
+func (s *balanceHistoryService) getUser(ctx context.Context, userID uint) (*mysqlModel.User, error) {
+	users, err := s.userQryRepo.GetUsers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return users[0], nil
+}
+
+// startOfDay is midnight of the day of t in the location of t
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
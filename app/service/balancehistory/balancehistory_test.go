@@ -0,0 +1,217 @@
+package balancehistory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	balanceHistorySrv "banking/app/service/balancehistory"
+	"banking/domain"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func initialBalanceHistoryService(t *testing.T) (domain.IBalanceHistoryService, *domainMock.MockIBalanceHistoryQueryRepo, *domainMock.MockIUserQueryRepo) {
+	global.Logger = zap.NewNop().Sugar()
+
+	viper.Set("balanceHistory.maxDays", 31)
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	mockQueryRepo := domainMock.NewMockIBalanceHistoryQueryRepo(ctrl)
+	mockUserQryRepo := domainMock.NewMockIUserQueryRepo(ctrl)
+	return balanceHistorySrv.NewBalanceHistoryService(mockQueryRepo, mockUserQryRepo), mockQueryRepo, mockUserQryRepo
+}
+
+var created = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func user() *mysqlModel.User {
+	return &mysqlModel.User{Model: gorm.Model{ID: 1, CreatedAt: created}, Balance: decimal.NewFromFloat(80)}
+}
+
+// transfer is a transfer of 10 with a fee of 1 from user 1 to user 2, which leaves user 1 with balance
+func transfer(id uint, at time.Time, balance float64) *mysqlModel.Transaction {
+	return &mysqlModel.Transaction{
+		Model:           gorm.Model{ID: id, CreatedAt: at},
+		FromUserID:      1,
+		ToUserID:        2,
+		Amount:          decimal.NewFromFloat(10),
+		Fee:             decimal.NewFromFloat(1),
+		FromUserBalance: decimal.NewFromFloat(balance),
+		ToUserBalance:   decimal.NewFromFloat(500),
+		TransactionType: mysqlModel.Transfer,
+	}
+}
+
+// credit is a deposit of 5 through a bucket of user 1, the balance recorded misses the uncommitted
+// credits to its other buckets
+func credit(id uint, at time.Time) *mysqlModel.Transaction {
+	return &mysqlModel.Transaction{
+		Model:                gorm.Model{ID: id, CreatedAt: at},
+		FromUserID:           1,
+		ToUserID:             1,
+		Amount:               decimal.NewFromFloat(5),
+		FromUserBalance:      decimal.NewFromFloat(1000),
+		ToUserBalance:        decimal.NewFromFloat(1000),
+		ToUserBalancePartial: true,
+		TransactionType:      mysqlModel.Deposit,
+	}
+}
+
+func Test_GetBalanceAt(t *testing.T) {
+	at := created.Add(48 * time.Hour)
+
+	t.Run("balance of the last transaction before", func(t *testing.T) {
+		service, mockQueryRepo, mockUserQryRepo := initialBalanceHistoryService(t)
+
+		mockUserQryRepo.EXPECT().GetUsers(gomock.Any(), uint(1)).Return([]*mysqlModel.User{user()}, nil)
+		mockQueryRepo.EXPECT().GetLastTransaction(gomock.Any(), uint(1), at).Return(transfer(3, at.Add(-time.Hour), 89), nil)
+		mockQueryRepo.EXPECT().GetTransactionsBetween(gomock.Any(), uint(1), at.Add(-time.Hour), at).Return([]*mysqlModel.Transaction{transfer(3, at.Add(-time.Hour), 89)}, nil)
+
+		balance, err := service.GetBalanceAt(context.Background(), 1, at)
+		require.NoError(t, err)
+		assert.Equal(t, "89", balance.String())
+	})
+
+	t.Run("credits through buckets after the last transaction", func(t *testing.T) {
+		service, mockQueryRepo, mockUserQryRepo := initialBalanceHistoryService(t)
+
+		mockUserQryRepo.EXPECT().GetUsers(gomock.Any(), uint(1)).Return([]*mysqlModel.User{user()}, nil)
+		mockQueryRepo.EXPECT().GetLastTransaction(gomock.Any(), uint(1), at).Return(transfer(3, at.Add(-time.Hour), 89), nil)
+		mockQueryRepo.EXPECT().GetTransactionsBetween(gomock.Any(), uint(1), at.Add(-time.Hour), at).Return([]*mysqlModel.Transaction{
+			credit(2, at.Add(-time.Hour)), // recorded before the transfer in the same millisecond
+			transfer(3, at.Add(-time.Hour), 89),
+			credit(4, at.Add(-time.Minute)),
+			credit(5, at.Add(-time.Second)),
+		}, nil)
+
+		balance, err := service.GetBalanceAt(context.Background(), 1, at)
+		require.NoError(t, err)
+		assert.Equal(t, "99", balance.String())
+	})
+
+	t.Run("balance before the next transaction", func(t *testing.T) {
+		service, mockQueryRepo, mockUserQryRepo := initialBalanceHistoryService(t)
+
+		mockUserQryRepo.EXPECT().GetUsers(gomock.Any(), uint(1)).Return([]*mysqlModel.User{user()}, nil)
+		mockQueryRepo.EXPECT().GetLastTransaction(gomock.Any(), uint(1), at).Return(nil, nil)
+		mockQueryRepo.EXPECT().GetNextTransaction(gomock.Any(), uint(1), at).Return(transfer(3, at.Add(time.Hour), 89), nil)
+		mockQueryRepo.EXPECT().GetTransactionsBetween(gomock.Any(), uint(1), at, at.Add(time.Hour+time.Millisecond)).Return([]*mysqlModel.Transaction{
+			credit(2, at.Add(time.Minute)),
+			transfer(3, at.Add(time.Hour), 89),
+		}, nil)
+
+		balance, err := service.GetBalanceAt(context.Background(), 1, at)
+		require.NoError(t, err)
+		assert.Equal(t, "95", balance.String(), "the transfer debited the amount and the fee, the credit before it is taken off")
+	})
+
+	t.Run("current balance without transactions", func(t *testing.T) {
+		service, mockQueryRepo, mockUserQryRepo := initialBalanceHistoryService(t)
+
+		mockUserQryRepo.EXPECT().GetUsers(gomock.Any(), uint(1)).Return([]*mysqlModel.User{user()}, nil)
+		mockQueryRepo.EXPECT().GetLastTransaction(gomock.Any(), uint(1), at).Return(nil, nil)
+		mockQueryRepo.EXPECT().GetNextTransaction(gomock.Any(), uint(1), at).Return(nil, nil)
+		mockQueryRepo.EXPECT().GetTransactionsBetween(gomock.Any(), uint(1), at, gomock.Any()).Return(nil, nil)
+
+		balance, err := service.GetBalanceAt(context.Background(), 1, at)
+		require.NoError(t, err)
+		assert.Equal(t, "80", balance.String())
+	})
+
+	t.Run("zero before the user was created", func(t *testing.T) {
+		service, _, mockUserQryRepo := initialBalanceHistoryService(t)
+
+		mockUserQryRepo.EXPECT().GetUsers(gomock.Any(), uint(1)).Return([]*mysqlModel.User{user()}, nil)
+
+		balance, err := service.GetBalanceAt(context.Background(), 1, created.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.True(t, balance.IsZero())
+	})
+}
+
+func Test_GetDailyBalances(t *testing.T) {
+	t.Run("end of day balances", func(t *testing.T) {
+		service, mockQueryRepo, mockUserQryRepo := initialBalanceHistoryService(t)
+
+		from := time.Date(2026, 2, 28, 15, 0, 0, 0, time.UTC)
+		to := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+		start := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+
+		mockUserQryRepo.EXPECT().GetUsers(gomock.Any(), uint(1)).Return([]*mysqlModel.User{user()}, nil)
+		mockQueryRepo.EXPECT().GetLastTransaction(gomock.Any(), uint(1), start).Return(nil, nil)
+		mockQueryRepo.EXPECT().GetNextTransaction(gomock.Any(), uint(1), start).Return(transfer(1, created.Add(time.Hour), 89), nil)
+		mockQueryRepo.EXPECT().GetTransactionsBetween(gomock.Any(), uint(1), start, created.Add(time.Hour+time.Millisecond)).Return([]*mysqlModel.Transaction{
+			transfer(1, created.Add(time.Hour), 89),
+		}, nil)
+		mockQueryRepo.EXPECT().GetTransactionsBetween(gomock.Any(), uint(1), start, end).Return([]*mysqlModel.Transaction{
+			transfer(1, created.Add(time.Hour), 89),
+			transfer(2, created.Add(2*time.Hour), 78),
+			credit(3, created.Add(26*time.Hour)),
+			transfer(4, created.Add(49*time.Hour), 67),
+			credit(5, created.Add(50*time.Hour)),
+		}, nil)
+
+		balances, err := service.GetDailyBalances(context.Background(), 1, from, to)
+		require.NoError(t, err)
+
+		want := map[string]string{
+			"2026-02-28": "0", // before the user was created
+			"2026-03-01": "78",
+			"2026-03-02": "83", // the recorded balance of a credit through a bucket is not used
+			"2026-03-03": "72",
+			"2026-03-04": "72",
+		}
+		require.Len(t, balances, len(want))
+		for _, balance := range balances {
+			assert.Equal(t, want[balance.Date], balance.Balance.String(), balance.Date)
+		}
+		assert.Equal(t, "2026-02-28", balances[0].Date)
+	})
+
+	t.Run("days of the location of from", func(t *testing.T) {
+		service, mockQueryRepo, mockUserQryRepo := initialBalanceHistoryService(t)
+
+		tokyo := time.FixedZone("JST", 9*60*60)
+		day := time.Date(2026, 3, 2, 0, 0, 0, 0, tokyo)
+
+		mockUserQryRepo.EXPECT().GetUsers(gomock.Any(), uint(1)).Return([]*mysqlModel.User{user()}, nil)
+		mockQueryRepo.EXPECT().GetLastTransaction(gomock.Any(), uint(1), day).Return(transfer(1, created, 100), nil)
+		mockQueryRepo.EXPECT().GetTransactionsBetween(gomock.Any(), uint(1), created, day).Return([]*mysqlModel.Transaction{transfer(1, created, 100)}, nil)
+		// 11:00 UTC is 20:00 of the same day in Tokyo
+		mockQueryRepo.EXPECT().GetTransactionsBetween(gomock.Any(), uint(1), day, day.AddDate(0, 0, 1)).Return([]*mysqlModel.Transaction{
+			transfer(2, time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC), 89),
+		}, nil)
+
+		balances, err := service.GetDailyBalances(context.Background(), 1, day, day.Add(12*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, balances, 1)
+		assert.Equal(t, "2026-03-02", balances[0].Date)
+		assert.Equal(t, "89", balances[0].Balance.String())
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		service, _, _ := initialBalanceHistoryService(t)
+
+		_, err := service.GetDailyBalances(context.Background(), 1, created, created.AddDate(0, 0, -1))
+		assert.ErrorIs(t, err, balanceHistorySrv.ErrRangeInvalid)
+
+		_, err = service.GetDailyBalances(context.Background(), 1, created, created.AddDate(0, 0, 31))
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.CodeInvalidRequest, domainErr.Code)
+	})
+}
@@ -0,0 +1,75 @@
+package bucket
+
+import (
+	"context"
+	"fmt"
+
+	"banking/domain"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/spf13/viper"
+)
+
+type bucketService struct {
+	bucketCmdRepo   domain.IBucketCommandRepo
+	bucketQueryRepo domain.IBucketQueryRepo
+	auditService    domain.IAuditService
+	maxBuckets      int
+}
+
+func NewBucketService(BucketCmdRepo domain.IBucketCommandRepo, BucketQueryRepo domain.IBucketQueryRepo, AuditService domain.IAuditService) domain.IBucketService {
+	return &bucketService{
+		bucketCmdRepo:   BucketCmdRepo,
+		bucketQueryRepo: BucketQueryRepo,
+		auditService:    AuditService,
+		maxBuckets:      viper.GetInt("bucket.maxBuckets"),
+	}
+}
+
+func (s *bucketService) SetBalanceBuckets(ctx context.Context, userID uint, buckets int) (user *mysqlModel.User, err error) {
+	span, ctx := tracing.StartSpan(ctx, "bucketService.SetBalanceBuckets", "service")
+	defer span.End()
+
+	if buckets < 0 || buckets > s.maxBuckets {
+		return nil, domain.NewError(domain.CodeInvalidRequest, fmt.Sprintf("buckets must be between 0 and %d", s.maxBuckets))
+	}
+
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditBalanceBuckets, fmt.Sprintf("user:%d", userID), nil, map[string]int{"balanceBuckets": buckets}, err)
+	}()
+
+	return s.bucketCmdRepo.SetBalanceBuckets(ctx, userID, buckets)
+}
+
+// Consolidate goes on with the next account when one fails, the buckets of an account are consolidated
+// again by the next run
+func (s *bucketService) Consolidate(ctx context.Context) (consolidated int, err error) {
+	span, ctx := tracing.StartSpan(ctx, "bucketService.Consolidate", "service")
+	defer span.End()
+
+	userIDs, err := s.bucketQueryRepo.GetHotAccountIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var failed int
+	for _, userID := range userIDs {
+		moved, err := s.bucketCmdRepo.Consolidate(ctx, userID)
+		if err != nil {
+			global.LoggerFromContext(ctx).Errorf("consolidate balance buckets of user %d error: %s", userID, err)
+			failed++
+			continue
+		}
+
+		global.LoggerFromContext(ctx).Infof("consolidated %s from the balance buckets of user %d", moved, userID)
+		consolidated++
+	}
+
+	if failed > 0 {
+		return consolidated, fmt.Errorf("consolidate balance buckets of %d users failed", failed)
+	}
+
+	return consolidated, nil
+}
@@ -0,0 +1,99 @@
+package bucket_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	bucketRepo "banking/app/repo/mysql/bucket"
+	bucketSrv "banking/app/service/bucket"
+	"banking/domain"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func initialBucketService(t *testing.T) (domain.IBucketService, *domainMock.MockIBucketCommandRepo, *domainMock.MockIBucketQueryRepo, *domainMock.MockIAuditService) {
+	global.Logger = zap.NewNop().Sugar()
+
+	viper.Set("bucket.maxBuckets", 16)
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	mockCmdRepo := domainMock.NewMockIBucketCommandRepo(ctrl)
+	mockQueryRepo := domainMock.NewMockIBucketQueryRepo(ctrl)
+	mockAudit := domainMock.NewMockIAuditService(ctrl)
+	return bucketSrv.NewBucketService(mockCmdRepo, mockQueryRepo, mockAudit), mockCmdRepo, mockQueryRepo, mockAudit
+}
+
+func Test_SetBalanceBuckets(t *testing.T) {
+	t.Run("audited", func(t *testing.T) {
+		service, mockCmdRepo, _, mockAudit := initialBucketService(t)
+
+		user := &mysqlModel.User{Model: gorm.Model{ID: 1}, BalanceBuckets: 8}
+		mockCmdRepo.EXPECT().SetBalanceBuckets(gomock.Any(), uint(1), 8).Return(user, nil)
+		mockAudit.EXPECT().Record(gomock.Any(), mysqlModel.AuditBalanceBuckets, "user:1", nil, map[string]int{"balanceBuckets": 8}, nil)
+
+		got, err := service.SetBalanceBuckets(context.Background(), 1, 8)
+		assert.NoError(t, err)
+		assert.Equal(t, user, got)
+	})
+
+	t.Run("missing user", func(t *testing.T) {
+		service, mockCmdRepo, _, mockAudit := initialBucketService(t)
+
+		mockCmdRepo.EXPECT().SetBalanceBuckets(gomock.Any(), uint(2), 0).Return(nil, bucketRepo.ErrUserNotFound)
+		mockAudit.EXPECT().Record(gomock.Any(), mysqlModel.AuditBalanceBuckets, "user:2", nil, gomock.Any(), bucketRepo.ErrUserNotFound)
+
+		_, err := service.SetBalanceBuckets(context.Background(), 2, 0)
+		assert.ErrorIs(t, err, bucketRepo.ErrUserNotFound)
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		service, _, _, _ := initialBucketService(t)
+
+		for _, buckets := range []int{-1, 17} {
+			_, err := service.SetBalanceBuckets(context.Background(), 1, buckets)
+
+			var domainErr *domain.Error
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, domain.CodeInvalidRequest, domainErr.Code)
+		}
+	})
+}
+
+func Test_Consolidate(t *testing.T) {
+	t.Run("every hot account", func(t *testing.T) {
+		service, mockCmdRepo, mockQueryRepo, _ := initialBucketService(t)
+
+		mockQueryRepo.EXPECT().GetHotAccountIDs(gomock.Any()).Return([]uint{1, 2}, nil)
+		mockCmdRepo.EXPECT().Consolidate(gomock.Any(), uint(1)).Return(decimal.NewFromInt(10), nil)
+		mockCmdRepo.EXPECT().Consolidate(gomock.Any(), uint(2)).Return(decimal.Zero, nil)
+
+		consolidated, err := service.Consolidate(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, consolidated)
+	})
+
+	t.Run("goes on after a failure", func(t *testing.T) {
+		service, mockCmdRepo, mockQueryRepo, _ := initialBucketService(t)
+
+		mockQueryRepo.EXPECT().GetHotAccountIDs(gomock.Any()).Return([]uint{1, 2}, nil)
+		mockCmdRepo.EXPECT().Consolidate(gomock.Any(), uint(1)).Return(decimal.Zero, errors.New("lock wait timeout"))
+		mockCmdRepo.EXPECT().Consolidate(gomock.Any(), uint(2)).Return(decimal.NewFromInt(10), nil)
+
+		consolidated, err := service.Consolidate(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, 1, consolidated)
+	})
+}
@@ -0,0 +1,71 @@
+package fee
+
+import (
+	"context"
+	"fmt"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+var ErrUnsupportedTransactionType = domain.NewError(domain.CodeUnsupportedTransactionType, "fees are quoted for transfer and withdraw only")
+
+type feeService struct {
+	userQryRepo  domain.IUserQueryRepo
+	schedule     Schedule
+	houseAccount string
+	enabled      bool
+}
+
+func NewFeeService(UserQryRepo domain.IUserQueryRepo) domain.IFeeService {
+	schedule := Schedule{}
+	if err := viper.UnmarshalKey("fee.schedules", &schedule); err != nil {
+		panic(fmt.Sprintf("Load fee schedules error: %s\n", err))
+	}
+
+	return &feeService{
+		userQryRepo:  UserQryRepo,
+		schedule:     schedule,
+		houseAccount: viper.GetString("fee.houseAccount"),
+		enabled:      viper.GetBool("fee.enabled"),
+	}
+}
+
+func (s *feeService) Quote(ctx context.Context, userID uint, transactionType mysqlModel.TransactionType, amount decimal.Decimal) (fee *mysqlModel.FeeBreakdown, err error) {
+	span, ctx := tracing.StartSpan(ctx, "feeService.Quote", "service")
+	defer span.End()
+
+	if transactionType != mysqlModel.Transfer && transactionType != mysqlModel.Withdraw {
+		return nil, ErrUnsupportedTransactionType
+	}
+
+	users, err := s.userQryRepo.GetUsers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tier := users[0].Tier
+
+	rule := s.schedule.Rule(transactionType, tier)
+	if !s.enabled || rule == nil {
+		return &mysqlModel.FeeBreakdown{TransactionType: transactionType, Tier: tier, Amount: amount}, nil
+	}
+
+	fee = rule.Calculate(amount)
+	fee.TransactionType = transactionType
+	fee.Tier = tier
+
+	if fee.Total.IsPositive() {
+		house, err := s.userQryRepo.GetUserByEmail(ctx, s.houseAccount)
+		if err != nil {
+			// a missing house account is a configuration error, not a user not found for the client
+			return nil, fmt.Errorf("fee house account %s: %v", s.houseAccount, err)
+		}
+		fee.HouseAccountID = house.ID
+	}
+
+	return fee, nil
+}
@@ -0,0 +1,119 @@
+package fee_test
+
+import (
+	"context"
+	"testing"
+
+	"banking/app/repo/memory"
+	userRepo "banking/app/repo/mysql/user"
+	feeSrv "banking/app/service/fee"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func initialFeeService(t *testing.T) *domainMock.MockIUserQueryRepo {
+	global.Logger = zap.NewNop().Sugar()
+
+	viper.Set("fee.enabled", true)
+	viper.Set("fee.houseAccount", "house@yopmail.com")
+	viper.Set("fee.schedules", map[string]interface{}{
+		"transfer": map[string]interface{}{
+			"default": map[string]interface{}{"flat": 0.5, "percent": 1, "min": 1, "max": 10},
+			"premium": map[string]interface{}{"percent": 0.25},
+		},
+		"withdraw": map[string]interface{}{
+			"default": map[string]interface{}{
+				"tiers": []interface{}{
+					map[string]interface{}{"upTo": 100, "percent": 2},
+					map[string]interface{}{"upTo": 1000, "percent": 1},
+					map[string]interface{}{"percent": 0.5},
+				},
+			},
+		},
+	})
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	return domainMock.NewMockIUserQueryRepo(ctrl)
+}
+
+func Test_Rule_Calculate(t *testing.T) {
+	rule := &feeSrv.Rule{Flat: 0.5, Percent: 1, Min: 1, Max: 10}
+
+	tests := []struct {
+		name       string
+		amount     float64
+		total      float64
+		adjustment float64
+	}{
+		{"raised to minimum", 20, 1, 0.3},
+		{"within caps", 200, 2.5, 0},
+		{"capped at maximum", 5000, 10, -40.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee := rule.Calculate(decimal.NewFromFloat(tt.amount))
+
+			assert.True(t, decimal.NewFromFloat(tt.total).Equal(fee.Total), fee.Total.String())
+			assert.True(t, decimal.NewFromFloat(tt.adjustment).Equal(fee.Adjustment), fee.Adjustment.String())
+		})
+	}
+}
+
+func Test_Rule_Calculate_Tiered(t *testing.T) {
+	rule := &feeSrv.Rule{Tiers: []feeSrv.Bracket{{UpTo: 100, Percent: 2}, {UpTo: 1000, Percent: 1}, {Percent: 0.5}}}
+
+	// 100 * 2% + 900 * 1% + 1000 * 0.5%
+	fee := rule.Calculate(decimal.NewFromFloat(2000))
+	assert.True(t, decimal.NewFromFloat(16).Equal(fee.Tiered), fee.Tiered.String())
+	assert.True(t, decimal.NewFromFloat(16).Equal(fee.Total))
+
+	// only the first bracket applies
+	fee = rule.Calculate(decimal.NewFromFloat(50))
+	assert.True(t, decimal.NewFromFloat(1).Equal(fee.Total), fee.Total.String())
+}
+
+func Test_Quote_Tier(t *testing.T) {
+	mockUserQryRepo := initialFeeService(t)
+
+	mockUserQryRepo.EXPECT().GetUsers(gomock.Any(), uint(1)).Return([]*mysqlModel.User{{Tier: "premium"}}, nil)
+	mockUserQryRepo.EXPECT().GetUserByEmail(gomock.Any(), "house@yopmail.com").Return(&mysqlModel.User{Model: gorm.Model{ID: 9}}, nil)
+
+	srv := feeSrv.NewFeeService(mockUserQryRepo)
+	fee, err := srv.Quote(context.Background(), 1, mysqlModel.Transfer, decimal.NewFromFloat(400))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "premium", fee.Tier)
+	assert.True(t, decimal.NewFromFloat(1).Equal(fee.Total), fee.Total.String())
+	assert.Equal(t, uint(9), fee.HouseAccountID)
+}
+
+func Test_Quote_Unsupported(t *testing.T) {
+	mockUserQryRepo := initialFeeService(t)
+
+	srv := feeSrv.NewFeeService(mockUserQryRepo)
+	_, err := srv.Quote(context.Background(), 1, mysqlModel.Deposit, decimal.NewFromFloat(400))
+
+	assert.ErrorIs(t, err, feeSrv.ErrUnsupportedTransactionType)
+}
+
+func Test_Quote_UnknownUser(t *testing.T) {
+	initialFeeService(t)
+
+	// the user repo reports the missing user, not the raw record error which would be a 500
+	srv := feeSrv.NewFeeService(memory.NewUserQueryRepo(memory.NewStore()))
+	_, err := srv.Quote(context.Background(), 99, mysqlModel.Withdraw, decimal.NewFromFloat(400))
+
+	assert.ErrorIs(t, err, userRepo.ErrUserNotFound)
+}
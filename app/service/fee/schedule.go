@@ -0,0 +1,95 @@
+package fee
+
+import (
+	"strings"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultTier is the schedule entry used for users whose tier has no rule of its own
+const defaultTier = "default"
+
+var hundred = decimal.NewFromInt(100)
+
+// Bracket is one band of a tiered fee, Percent applies to the part of the amount between the
+// previous bracket and UpTo, the last bracket leaves UpTo at 0 to cover the rest
+type Bracket struct {
+	UpTo    float64 `mapstructure:"upTo"`
+	Percent float64 `mapstructure:"percent"`
+}
+
+// Rule combines a flat fee, a percentage of the amount and tiered brackets, the sum is kept
+// between Min and Max, a Max of 0 leaves the fee uncapped
+type Rule struct {
+	Flat    float64   `mapstructure:"flat"`
+	Percent float64   `mapstructure:"percent"`
+	Tiers   []Bracket `mapstructure:"tiers"`
+	Min     float64   `mapstructure:"min"`
+	Max     float64   `mapstructure:"max"`
+}
+
+// Schedule holds the rules per transaction type and user tier
+type Schedule map[mysqlModel.TransactionType]map[string]*Rule
+
+// Rule returns the rule of the tier, falling back to the default tier, nil means no fee
+func (s Schedule) Rule(transactionType mysqlModel.TransactionType, tier string) *Rule {
+	rules, ok := s[transactionType]
+	if !ok {
+		return nil
+	}
+
+	// viper lower cases map keys
+	if rule, ok := rules[strings.ToLower(tier)]; ok {
+		return rule
+	}
+
+	return rules[defaultTier]
+}
+
+// Calculate itemizes the fee of amount, every component is rounded to cents
+func (r *Rule) Calculate(amount decimal.Decimal) *mysqlModel.FeeBreakdown {
+	fee := &mysqlModel.FeeBreakdown{
+		Amount:     amount,
+		Flat:       decimal.NewFromFloat(r.Flat).Round(2),
+		Percentage: amount.Mul(decimal.NewFromFloat(r.Percent)).Div(hundred).Round(2),
+		Tiered:     r.tiered(amount).Round(2),
+	}
+
+	total := fee.Flat.Add(fee.Percentage).Add(fee.Tiered)
+	capped := total
+	if min := decimal.NewFromFloat(r.Min).Round(2); capped.LessThan(min) {
+		capped = min
+	}
+	if max := decimal.NewFromFloat(r.Max).Round(2); max.IsPositive() && capped.GreaterThan(max) {
+		capped = max
+	}
+
+	fee.Adjustment = capped.Sub(total)
+	fee.Total = capped
+
+	return fee
+}
+
+func (r *Rule) tiered(amount decimal.Decimal) decimal.Decimal {
+	fee := decimal.Zero
+	lower := decimal.Zero
+	for _, bracket := range r.Tiers {
+		upper := decimal.NewFromFloat(bracket.UpTo)
+		if bracket.UpTo == 0 || upper.GreaterThan(amount) {
+			upper = amount
+		}
+
+		if upper.GreaterThan(lower) {
+			fee = fee.Add(upper.Sub(lower).Mul(decimal.NewFromFloat(bracket.Percent)).Div(hundred))
+			lower = upper
+		}
+
+		if lower.GreaterThanOrEqual(amount) {
+			break
+		}
+	}
+
+	return fee
+}
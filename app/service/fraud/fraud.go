@@ -0,0 +1,188 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"banking/domain"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrTransferUnderReview = domain.NewError(domain.CodeTransferUnderReview, "transfer held for fraud review")
+	ErrTransferBlocked     = domain.NewError(domain.CodeTransferBlocked, "transfer blocked by fraud screening")
+)
+
+// ScreeningError is returned for transfers which must not be executed right away,
+// it unwraps to ErrTransferUnderReview or ErrTransferBlocked
+type ScreeningError struct {
+	Review *mysqlModel.FraudReview
+}
+
+func (e *ScreeningError) Error() string {
+	return e.Unwrap().Error()
+}
+
+func (e *ScreeningError) Unwrap() error {
+	if e.Review.Decision == mysqlModel.Block {
+		return ErrTransferBlocked
+	}
+
+	return ErrTransferUnderReview
+}
+
+type fraudService struct {
+	fraudCmdRepo   domain.IFraudCommandRepo
+	fraudQueryRepo domain.IFraudQueryRepo
+	engine         *Engine
+	enabled        bool
+	auditService   domain.IAuditService
+}
+
+func NewFraudService(FraudCmdRepo domain.IFraudCommandRepo, FraudQueryRepo domain.IFraudQueryRepo, AuditService domain.IAuditService) domain.IFraudService {
+	lookback := time.Duration(viper.GetInt("fraud.lookbackDays")) * 24 * time.Hour
+
+	engine := NewEngine(
+		viper.GetInt("fraud.reviewScore"),
+		viper.GetInt("fraud.blockScore"),
+		&newRecipientRule{
+			repo:     FraudQueryRepo,
+			score:    viper.GetInt("fraud.rules.newRecipient.score"),
+			lookback: lookback,
+		},
+		&amountSpikeRule{
+			repo:       FraudQueryRepo,
+			score:      viper.GetInt("fraud.rules.amountSpike.score"),
+			factor:     decimal.NewFromFloat(viper.GetFloat64("fraud.rules.amountSpike.factor")),
+			minHistory: viper.GetInt64("fraud.rules.amountSpike.minHistory"),
+			lookback:   lookback,
+		},
+		&rapidTransferRule{
+			repo:     FraudQueryRepo,
+			score:    viper.GetInt("fraud.rules.rapidTransfers.score"),
+			maxCount: viper.GetInt64("fraud.rules.rapidTransfers.count"),
+			window:   time.Duration(viper.GetInt("fraud.rules.rapidTransfers.window")) * time.Second,
+		},
+		&newAPIKeyRule{
+			repo:   FraudQueryRepo,
+			score:  viper.GetInt("fraud.rules.newAPIKey.score"),
+			minAge: time.Duration(viper.GetInt("fraud.rules.newAPIKey.minAge")) * time.Hour,
+		},
+		&unusualHourRule{
+			score: viper.GetInt("fraud.rules.unusualHour.score"),
+			start: viper.GetInt("fraud.rules.unusualHour.start"),
+			end:   viper.GetInt("fraud.rules.unusualHour.end"),
+		},
+	)
+
+	return &fraudService{
+		fraudCmdRepo:   FraudCmdRepo,
+		fraudQueryRepo: FraudQueryRepo,
+		engine:         engine,
+		enabled:        viper.GetBool("fraud.enabled"),
+		auditService:   AuditService,
+	}
+}
+
+// ScreenTransfer evaluates the transfer before money moves, transfers which are not allowed are
+// recorded with the reference they are executed with once approved and returned as a *ScreeningError
+func (s *fraudService) ScreenTransfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (review *mysqlModel.FraudReview, err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudService.ScreenTransfer", "service")
+	defer span.End()
+
+	if !s.enabled {
+		return nil, nil
+	}
+
+	apiKey := utils.APIKeyFromContext(ctx)
+	assessment, err := s.engine.Evaluate(ctx, &Input{
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		Amount:     amount,
+		APIKey:     apiKey,
+		At:         time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if assessment.Decision == mysqlModel.Allow {
+		return nil, nil
+	}
+
+	review = &mysqlModel.FraudReview{
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		Amount:     amount,
+		APIKey:     apiKey,
+		Score:      assessment.Score,
+		Decision:   assessment.Decision,
+		Reasons:    strings.Join(assessment.Reasons, ","),
+		Status:     mysqlModel.ReviewPending,
+	}
+	if reference != nil {
+		review.Memo, review.ExternalReference = reference.Memo, reference.ExternalReference
+	}
+	if assessment.Decision == mysqlModel.Block {
+		review.Status = mysqlModel.ReviewBlocked
+	}
+
+	if err := s.fraudCmdRepo.CreateReview(ctx, review); err != nil {
+		return nil, err
+	}
+
+	global.LoggerFromContext(ctx).Warnf("transfer from user %d to user %d screened as %s, score %d, reasons %s", fromUserID, toUserID, review.Decision, review.Score, review.Reasons)
+
+	return review, &ScreeningError{Review: review}
+}
+
+func (s *fraudService) GetReviews(ctx context.Context, status mysqlModel.FraudReviewStatus) (reviews []*mysqlModel.FraudReview, err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudService.GetReviews", "service")
+	defer span.End()
+
+	return s.fraudQueryRepo.GetReviews(ctx, status)
+}
+
+// ClaimReview approves a pending review, the status condition lets only one of concurrent approvals
+// execute the transfer
+func (s *fraudService) ClaimReview(ctx context.Context, reviewID, adminID uint) (review *mysqlModel.FraudReview, err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudService.ClaimReview", "service")
+	defer span.End()
+
+	review, err = s.fraudQueryRepo.GetReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fraudCmdRepo.UpdateReviewStatus(ctx, reviewID, mysqlModel.ReviewPending, mysqlModel.ReviewApproved, &adminID); err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (s *fraudService) ReleaseReview(ctx context.Context, reviewID uint) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudService.ReleaseReview", "service")
+	defer span.End()
+
+	return s.fraudCmdRepo.UpdateReviewStatus(ctx, reviewID, mysqlModel.ReviewApproved, mysqlModel.ReviewPending, nil)
+}
+
+func (s *fraudService) RejectReview(ctx context.Context, reviewID, adminID uint) (err error) {
+	span, ctx := tracing.StartSpan(ctx, "fraudService.RejectReview", "service")
+	defer span.End()
+
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditFraudReviewReject, fmt.Sprintf("fraudReview:%d", reviewID), mysqlModel.ReviewPending, mysqlModel.ReviewRejected, err)
+	}()
+
+	return s.fraudCmdRepo.UpdateReviewStatus(ctx, reviewID, mysqlModel.ReviewPending, mysqlModel.ReviewRejected, &adminID)
+}
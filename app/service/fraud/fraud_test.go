@@ -0,0 +1,142 @@
+package fraud_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	fraudRepo "banking/app/repo/mysql/fraud"
+	fraudSrv "banking/app/service/fraud"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/utils"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func initialFraudService(t *testing.T) (*domainMock.MockIFraudCommandRepo, *domainMock.MockIFraudQueryRepo, *domainMock.MockIAuditService) {
+	global.Logger = zap.NewNop().Sugar()
+
+	viper.Set("fraud.enabled", true)
+	viper.Set("fraud.reviewScore", 50)
+	viper.Set("fraud.blockScore", 80)
+	viper.Set("fraud.lookbackDays", 90)
+	viper.Set("fraud.rules.newRecipient.score", 20)
+	viper.Set("fraud.rules.amountSpike.score", 40)
+	viper.Set("fraud.rules.amountSpike.factor", 5)
+	viper.Set("fraud.rules.amountSpike.minHistory", 3)
+	viper.Set("fraud.rules.rapidTransfers.score", 30)
+	viper.Set("fraud.rules.rapidTransfers.count", 5)
+	viper.Set("fraud.rules.rapidTransfers.window", 60)
+	viper.Set("fraud.rules.newAPIKey.score", 15)
+	viper.Set("fraud.rules.newAPIKey.minAge", 24)
+	// an empty hour range never triggers, keeps the tests independent of the clock
+	viper.Set("fraud.rules.unusualHour.score", 15)
+	viper.Set("fraud.rules.unusualHour.start", 0)
+	viper.Set("fraud.rules.unusualHour.end", 0)
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	return domainMock.NewMockIFraudCommandRepo(ctrl), domainMock.NewMockIFraudQueryRepo(ctrl), domainMock.NewMockIAuditService(ctrl)
+}
+
+func Test_ScreenTransfer_Allow(t *testing.T) {
+	mockCmdRepo, mockQueryRepo, mockAuditService := initialFraudService(t)
+
+	// known recipient, normal amount, no recent burst
+	mockQueryRepo.EXPECT().CountTransfers(gomock.Any(), uint(1), uint(2), gomock.Any()).Return(int64(3), nil)
+	mockQueryRepo.EXPECT().GetAverageTransferAmount(gomock.Any(), uint(1), gomock.Any()).Return(decimal.NewFromFloat(50), int64(10), nil)
+	mockQueryRepo.EXPECT().CountTransfers(gomock.Any(), uint(1), uint(0), gomock.Any()).Return(int64(1), nil)
+
+	srv := fraudSrv.NewFraudService(mockCmdRepo, mockQueryRepo, mockAuditService)
+	review, err := srv.ScreenTransfer(context.Background(), 1, 2, decimal.NewFromFloat(60), nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, review)
+}
+
+func Test_ScreenTransfer_Review(t *testing.T) {
+	mockCmdRepo, mockQueryRepo, mockAuditService := initialFraudService(t)
+
+	// new recipient (20) + fresh api key (15) + rapid transfers (30) = 65
+	mockQueryRepo.EXPECT().CountTransfers(gomock.Any(), uint(1), uint(2), gomock.Any()).Return(int64(0), nil)
+	mockQueryRepo.EXPECT().GetAverageTransferAmount(gomock.Any(), uint(1), gomock.Any()).Return(decimal.Zero, int64(0), nil)
+	mockQueryRepo.EXPECT().CountTransfers(gomock.Any(), uint(1), uint(0), gomock.Any()).Return(int64(5), nil)
+	mockQueryRepo.EXPECT().GetAPIKeyCreatedAt(gomock.Any(), "key").Return(time.Now().Add(-time.Hour), nil)
+	mockCmdRepo.EXPECT().CreateReview(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, review *mysqlModel.FraudReview) error {
+		review.ID = 7
+		return nil
+	})
+
+	srv := fraudSrv.NewFraudService(mockCmdRepo, mockQueryRepo, mockAuditService)
+	review, err := srv.ScreenTransfer(utils.ContextWithAPIKey(context.Background(), "key"), 1, 2, decimal.NewFromFloat(60), &mysqlModel.TransactionReference{Memo: "rent", ExternalReference: "INV-1"})
+
+	assert.ErrorIs(t, err, fraudSrv.ErrTransferUnderReview)
+	var screeningErr *fraudSrv.ScreeningError
+	assert.True(t, errors.As(err, &screeningErr))
+	assert.Equal(t, uint(7), screeningErr.Review.ID)
+	assert.Equal(t, 65, review.Score)
+	assert.Equal(t, mysqlModel.Review, review.Decision)
+	assert.Equal(t, mysqlModel.ReviewPending, review.Status)
+	assert.Equal(t, "newRecipient,rapidTransfers,newAPIKey", review.Reasons)
+	// kept for the transfer once approved
+	assert.Equal(t, "rent", review.Memo)
+	assert.Equal(t, "INV-1", review.ExternalReference)
+}
+
+func Test_ScreenTransfer_Block(t *testing.T) {
+	mockCmdRepo, mockQueryRepo, mockAuditService := initialFraudService(t)
+
+	// new recipient (20) + amount spike (40) + rapid transfers (30) = 90
+	mockQueryRepo.EXPECT().CountTransfers(gomock.Any(), uint(1), uint(2), gomock.Any()).Return(int64(0), nil)
+	mockQueryRepo.EXPECT().GetAverageTransferAmount(gomock.Any(), uint(1), gomock.Any()).Return(decimal.NewFromFloat(10), int64(5), nil)
+	mockQueryRepo.EXPECT().CountTransfers(gomock.Any(), uint(1), uint(0), gomock.Any()).Return(int64(9), nil)
+	mockCmdRepo.EXPECT().CreateReview(gomock.Any(), gomock.Any()).Return(nil)
+
+	srv := fraudSrv.NewFraudService(mockCmdRepo, mockQueryRepo, mockAuditService)
+	review, err := srv.ScreenTransfer(context.Background(), 1, 2, decimal.NewFromFloat(500), nil)
+
+	assert.ErrorIs(t, err, fraudSrv.ErrTransferBlocked)
+	assert.Equal(t, mysqlModel.Block, review.Decision)
+	assert.Equal(t, mysqlModel.ReviewBlocked, review.Status)
+}
+
+func Test_ClaimReview_NotPending(t *testing.T) {
+	mockCmdRepo, mockQueryRepo, mockAuditService := initialFraudService(t)
+
+	review := &mysqlModel.FraudReview{FromUserID: 1, ToUserID: 2, Amount: decimal.NewFromFloat(10), Status: mysqlModel.ReviewApproved}
+
+	mockQueryRepo.EXPECT().GetReview(gomock.Any(), uint(3)).Return(review, nil)
+	mockCmdRepo.EXPECT().UpdateReviewStatus(gomock.Any(), uint(3), mysqlModel.ReviewPending, mysqlModel.ReviewApproved, gomock.Any()).Return(fraudRepo.ErrReviewNotPending)
+
+	srv := fraudSrv.NewFraudService(mockCmdRepo, mockQueryRepo, mockAuditService)
+	claimed, err := srv.ClaimReview(context.Background(), 3, 1)
+
+	assert.ErrorIs(t, err, fraudRepo.ErrReviewNotPending)
+	assert.Nil(t, claimed)
+}
+
+func Test_ClaimReview(t *testing.T) {
+	mockCmdRepo, mockQueryRepo, mockAuditService := initialFraudService(t)
+
+	review := &mysqlModel.FraudReview{FromUserID: 1, ToUserID: 2, Amount: decimal.NewFromFloat(10), Memo: "rent", Status: mysqlModel.ReviewPending}
+	adminID := uint(1)
+
+	mockQueryRepo.EXPECT().GetReview(gomock.Any(), uint(3)).Return(review, nil)
+	mockCmdRepo.EXPECT().UpdateReviewStatus(gomock.Any(), uint(3), mysqlModel.ReviewPending, mysqlModel.ReviewApproved, &adminID).Return(nil)
+
+	srv := fraudSrv.NewFraudService(mockCmdRepo, mockQueryRepo, mockAuditService)
+	claimed, err := srv.ClaimReview(context.Background(), 3, adminID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, review, claimed)
+}
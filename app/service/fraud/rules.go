@@ -0,0 +1,183 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	fraudRepo "banking/app/repo/mysql/fraud"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+// Input is the transfer under evaluation
+type Input struct {
+	FromUserID uint
+	ToUserID   uint
+	Amount     decimal.Decimal
+	APIKey     string
+	At         time.Time
+}
+
+// Rule detects one risk signal, the rule score is added to the assessment when the signal is present
+type Rule interface {
+	Name() string
+	Score() int
+	Evaluate(ctx context.Context, in *Input) (hit bool, err error)
+}
+
+type Assessment struct {
+	Score    int
+	Decision mysqlModel.FraudDecision
+	Reasons  []string
+}
+
+// Engine sums the scores of all triggered rules and maps the total to a decision
+type Engine struct {
+	rules       []Rule
+	reviewScore int
+	blockScore  int
+}
+
+func NewEngine(reviewScore, blockScore int, rules ...Rule) *Engine {
+	return &Engine{
+		rules:       rules,
+		reviewScore: reviewScore,
+		blockScore:  blockScore,
+	}
+}
+
+func (e *Engine) Evaluate(ctx context.Context, in *Input) (*Assessment, error) {
+	assessment := &Assessment{Decision: mysqlModel.Allow}
+	for _, rule := range e.rules {
+		hit, err := rule.Evaluate(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		if hit {
+			assessment.Score += rule.Score()
+			assessment.Reasons = append(assessment.Reasons, rule.Name())
+		}
+	}
+
+	switch {
+	case assessment.Score >= e.blockScore:
+		assessment.Decision = mysqlModel.Block
+	case assessment.Score >= e.reviewScore:
+		assessment.Decision = mysqlModel.Review
+	}
+
+	return assessment, nil
+}
+
+// newRecipientRule triggers when the sender never transferred to the recipient within the lookback period
+type newRecipientRule struct {
+	repo     domain.IFraudQueryRepo
+	score    int
+	lookback time.Duration
+}
+
+func (r *newRecipientRule) Name() string { return "newRecipient" }
+func (r *newRecipientRule) Score() int   { return r.score }
+
+func (r *newRecipientRule) Evaluate(ctx context.Context, in *Input) (bool, error) {
+	count, err := r.repo.CountTransfers(ctx, in.FromUserID, in.ToUserID, in.At.Add(-r.lookback))
+	if err != nil {
+		return false, err
+	}
+
+	return count == 0, nil
+}
+
+// amountSpikeRule triggers when the amount exceeds the sender's average transfer by the configured factor,
+// senders with less than minHistory transfers have no meaningful average and are skipped
+type amountSpikeRule struct {
+	repo       domain.IFraudQueryRepo
+	score      int
+	factor     decimal.Decimal
+	minHistory int64
+	lookback   time.Duration
+}
+
+func (r *amountSpikeRule) Name() string { return "amountSpike" }
+func (r *amountSpikeRule) Score() int   { return r.score }
+
+func (r *amountSpikeRule) Evaluate(ctx context.Context, in *Input) (bool, error) {
+	average, count, err := r.repo.GetAverageTransferAmount(ctx, in.FromUserID, in.At.Add(-r.lookback))
+	if err != nil {
+		return false, err
+	}
+
+	if count < r.minHistory {
+		return false, nil
+	}
+
+	return in.Amount.GreaterThan(average.Mul(r.factor)), nil
+}
+
+// rapidTransferRule triggers when the sender already made maxCount transfers inside the window
+type rapidTransferRule struct {
+	repo     domain.IFraudQueryRepo
+	score    int
+	maxCount int64
+	window   time.Duration
+}
+
+func (r *rapidTransferRule) Name() string { return "rapidTransfers" }
+func (r *rapidTransferRule) Score() int   { return r.score }
+
+func (r *rapidTransferRule) Evaluate(ctx context.Context, in *Input) (bool, error) {
+	count, err := r.repo.CountTransfers(ctx, in.FromUserID, 0, in.At.Add(-r.window))
+	if err != nil {
+		return false, err
+	}
+
+	return count >= r.maxCount, nil
+}
+
+// newAPIKeyRule triggers when the request is authenticated by an API key created less than minAge ago
+type newAPIKeyRule struct {
+	repo   domain.IFraudQueryRepo
+	score  int
+	minAge time.Duration
+}
+
+func (r *newAPIKeyRule) Name() string { return "newAPIKey" }
+func (r *newAPIKeyRule) Score() int   { return r.score }
+
+func (r *newAPIKeyRule) Evaluate(ctx context.Context, in *Input) (bool, error) {
+	if in.APIKey == "" {
+		return false, nil
+	}
+
+	createdAt, err := r.repo.GetAPIKeyCreatedAt(ctx, in.APIKey)
+	if errors.Is(err, fraudRepo.ErrAPIKeyNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return in.At.Sub(createdAt) < r.minAge, nil
+}
+
+// unusualHourRule triggers inside [start, end) hours of the server clock, start > end wraps past midnight
+type unusualHourRule struct {
+	score int
+	start int
+	end   int
+}
+
+func (r *unusualHourRule) Name() string { return "unusualHour" }
+func (r *unusualHourRule) Score() int   { return r.score }
+
+func (r *unusualHourRule) Evaluate(_ context.Context, in *Input) (bool, error) {
+	hour := in.At.Hour()
+	if r.start <= r.end {
+		return hour >= r.start && hour < r.end, nil
+	}
+
+	return hour >= r.start || hour < r.end, nil
+}
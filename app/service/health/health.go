@@ -0,0 +1,177 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	healthRepo "banking/app/repo/mysql/health"
+	"banking/domain"
+	"banking/global"
+	"banking/tracing"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	CheckMySQLMaster = "mysqlMaster"
+	CheckMySQLSlave  = "mysqlSlave"
+	CheckRedis       = "redis"
+)
+
+// healthService checks the dependencies for the readiness probe. Results are cached, so frequent
+// probes of several orchestrators do not hammer MySQL and redis
+type healthService struct {
+	masterQueryRepo domain.IHealthQueryRepo
+	slaveQueryRepo  domain.IHealthQueryRepo
+	redisQueryRepo  domain.IRedisHealthQueryRepo
+	timeout         time.Duration
+	cacheTTL        time.Duration
+	maxReplicaLag   time.Duration
+
+	shuttingDown atomic.Bool
+	mu           sync.Mutex
+	report       *domain.HealthReport
+}
+
+func NewHealthService(MasterQueryRepo domain.IHealthQueryRepo, SlaveQueryRepo domain.IHealthQueryRepo, RedisQueryRepo domain.IRedisHealthQueryRepo) domain.IHealthService {
+	timeout := time.Duration(viper.GetInt("health.timeout")) * time.Second
+	if timeout <= 0 {
+		panic("health.timeout must be positive")
+	}
+	cacheTTL := time.Duration(viper.GetInt("health.cacheTTL")) * time.Second
+	if cacheTTL < 0 {
+		panic("health.cacheTTL must not be negative")
+	}
+	maxReplicaLag := time.Duration(viper.GetInt("health.maxReplicaLag")) * time.Second
+	if maxReplicaLag <= 0 {
+		panic("health.maxReplicaLag must be positive")
+	}
+
+	return &healthService{
+		masterQueryRepo: MasterQueryRepo,
+		slaveQueryRepo:  SlaveQueryRepo,
+		redisQueryRepo:  RedisQueryRepo,
+		timeout:         timeout,
+		cacheTTL:        cacheTTL,
+		maxReplicaLag:   maxReplicaLag,
+	}
+}
+
+// Readiness returns the cached report while it is fresh, concurrent probes wait for a single check
+func (s *healthService) Readiness(ctx context.Context) (report *domain.HealthReport) {
+	span, ctx := tracing.StartSpan(ctx, "healthService.Readiness", "service")
+	defer span.End()
+
+	if s.shuttingDown.Load() {
+		return &domain.HealthReport{Status: domain.HealthStatusShuttingDown, CheckedAt: time.Now()}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.report != nil && time.Since(s.report.CheckedAt) < s.cacheTTL {
+		return s.report
+	}
+
+	s.report = s.check(ctx)
+	return s.report
+}
+
+func (s *healthService) ShutDown() {
+	s.shuttingDown.Store(true)
+}
+
+// check runs the dependency checks in parallel, each bounded by health.timeout
+func (s *healthService) check(ctx context.Context) *domain.HealthReport {
+	checks := map[string]func(ctx context.Context) *domain.DependencyHealth{
+		CheckMySQLMaster: func(ctx context.Context) *domain.DependencyHealth {
+			return s.ping(ctx, CheckMySQLMaster, s.masterQueryRepo.Ping)
+		},
+		CheckMySQLSlave: s.checkSlave,
+		CheckRedis: func(ctx context.Context) *domain.DependencyHealth {
+			return s.ping(ctx, CheckRedis, s.redisQueryRepo.Ping)
+		},
+	}
+
+	report := &domain.HealthReport{
+		Status:    domain.HealthStatusOK,
+		CheckedAt: time.Now(),
+		Checks:    make(map[string]*domain.DependencyHealth, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(ctx context.Context) *domain.DependencyHealth) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+			result := check(checkCtx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != domain.HealthStatusOK {
+				report.Status = domain.HealthStatusFail
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (s *healthService) ping(ctx context.Context, name string, ping func(ctx context.Context) error) *domain.DependencyHealth {
+	start := time.Now()
+	err := ping(ctx)
+	result := &domain.DependencyHealth{Status: domain.HealthStatusOK, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		s.fail(ctx, name, result, err)
+	}
+
+	return result
+}
+
+// checkSlave pings the slave and fails when it lags more than health.maxReplicaLag behind the master
+func (s *healthService) checkSlave(ctx context.Context) *domain.DependencyHealth {
+	result := s.ping(ctx, CheckMySQLSlave, s.slaveQueryRepo.Ping)
+	if result.Status != domain.HealthStatusOK {
+		return result
+	}
+
+	lag, err := s.slaveQueryRepo.ReplicaLag(ctx)
+	if err != nil {
+		s.fail(ctx, CheckMySQLSlave, result, err)
+		return result
+	}
+
+	seconds := lag.Seconds()
+	result.LagSeconds = &seconds
+	if lag > s.maxReplicaLag {
+		result.Status = domain.HealthStatusFail
+		result.Error = fmt.Sprintf("replica lag of %s exceeds %s", lag, s.maxReplicaLag)
+	}
+
+	return result
+}
+
+// fail logs err and reports a short reason, the error may hold hosts and addresses
+func (s *healthService) fail(ctx context.Context, name string, result *domain.DependencyHealth, err error) {
+	result.Status = domain.HealthStatusFail
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		result.Error = "timeout"
+	case errors.Is(err, healthRepo.ErrReplicationStopped):
+		result.Error = "replication stopped"
+	default:
+		result.Error = "unreachable"
+	}
+
+	global.LoggerFromContext(ctx).Warnf("health check %s failed: %s", name, err)
+}
@@ -0,0 +1,113 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	healthRepo "banking/app/repo/mysql/health"
+	healthSrv "banking/app/service/health"
+	"banking/domain"
+	domainMock "banking/domain/mock"
+	"banking/global"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func initialHealthService(t *testing.T) (*domainMock.MockIHealthQueryRepo, *domainMock.MockIHealthQueryRepo, *domainMock.MockIRedisHealthQueryRepo) {
+	global.Logger = zap.NewNop().Sugar()
+	viper.Set("health.timeout", 1)
+	viper.Set("health.cacheTTL", 60)
+	viper.Set("health.maxReplicaLag", 30)
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	return domainMock.NewMockIHealthQueryRepo(ctrl), domainMock.NewMockIHealthQueryRepo(ctrl), domainMock.NewMockIRedisHealthQueryRepo(ctrl)
+}
+
+func Test_Readiness_OK(t *testing.T) {
+	mockMasterRepo, mockSlaveRepo, mockRedisRepo := initialHealthService(t)
+
+	// the second call is served from the cache
+	mockMasterRepo.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+	mockSlaveRepo.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+	mockSlaveRepo.EXPECT().ReplicaLag(gomock.Any()).Return(2*time.Second, nil).Times(1)
+	mockRedisRepo.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+
+	service := healthSrv.NewHealthService(mockMasterRepo, mockSlaveRepo, mockRedisRepo)
+	report := service.Readiness(context.Background())
+	assert.Equal(t, domain.HealthStatusOK, report.Status)
+	assert.Len(t, report.Checks, 3)
+	assert.Equal(t, float64(2), *report.Checks[healthSrv.CheckMySQLSlave].LagSeconds)
+
+	assert.Same(t, report, service.Readiness(context.Background()))
+}
+
+func Test_Readiness_Fail(t *testing.T) {
+	tests := []struct {
+		name      string
+		pingErr   error
+		lag       time.Duration
+		lagErr    error
+		wantError string
+	}{
+		{name: "slave unreachable", pingErr: errors.New("dial tcp 10.0.0.3:3306: connection refused"), wantError: "unreachable"},
+		{name: "replication stopped", lagErr: healthRepo.ErrReplicationStopped, wantError: "replication stopped"},
+		{name: "replica lag", lag: 45 * time.Second, wantError: "replica lag of 45s exceeds 30s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMasterRepo, mockSlaveRepo, mockRedisRepo := initialHealthService(t)
+
+			mockMasterRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+			mockSlaveRepo.EXPECT().Ping(gomock.Any()).Return(tt.pingErr)
+			if tt.pingErr == nil {
+				mockSlaveRepo.EXPECT().ReplicaLag(gomock.Any()).Return(tt.lag, tt.lagErr)
+			}
+			mockRedisRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+
+			service := healthSrv.NewHealthService(mockMasterRepo, mockSlaveRepo, mockRedisRepo)
+			report := service.Readiness(context.Background())
+			assert.Equal(t, domain.HealthStatusFail, report.Status)
+			assert.Equal(t, domain.HealthStatusOK, report.Checks[healthSrv.CheckMySQLMaster].Status)
+			assert.Equal(t, domain.HealthStatusFail, report.Checks[healthSrv.CheckMySQLSlave].Status)
+			assert.Equal(t, tt.wantError, report.Checks[healthSrv.CheckMySQLSlave].Error)
+		})
+	}
+}
+
+func Test_Readiness_Timeout(t *testing.T) {
+	mockMasterRepo, mockSlaveRepo, mockRedisRepo := initialHealthService(t)
+
+	mockMasterRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+	mockSlaveRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+	mockSlaveRepo.EXPECT().ReplicaLag(gomock.Any()).Return(time.Duration(0), nil)
+	mockRedisRepo.EXPECT().Ping(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	service := healthSrv.NewHealthService(mockMasterRepo, mockSlaveRepo, mockRedisRepo)
+	report := service.Readiness(context.Background())
+	assert.Equal(t, domain.HealthStatusFail, report.Status)
+	assert.Equal(t, "timeout", report.Checks[healthSrv.CheckRedis].Error)
+}
+
+func Test_Readiness_ShutDown(t *testing.T) {
+	mockMasterRepo, mockSlaveRepo, mockRedisRepo := initialHealthService(t)
+
+	service := healthSrv.NewHealthService(mockMasterRepo, mockSlaveRepo, mockRedisRepo)
+	service.ShutDown()
+
+	// no dependency is checked any more
+	report := service.Readiness(context.Background())
+	assert.Equal(t, domain.HealthStatusShuttingDown, report.Status)
+}
@@ -0,0 +1,162 @@
+package interest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	interestRepo "banking/app/repo/mysql/interest"
+	"banking/domain"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// batchSize is the number of users loaded per query by the accrual batch
+const batchSize = 500
+
+type interestService struct {
+	interestCmdRepo   domain.IInterestCommandRepo
+	interestQueryRepo domain.IInterestQueryRepo
+	plans             map[string]*Plan
+	enabled           bool
+}
+
+func NewInterestService(InterestCmdRepo domain.IInterestCommandRepo, InterestQueryRepo domain.IInterestQueryRepo) domain.IInterestService {
+	plans := map[string]*Plan{}
+	if err := viper.UnmarshalKey("interest.plans", &plans); err != nil {
+		panic(fmt.Sprintf("Load interest plans error: %s\n", err))
+	}
+	for name, plan := range plans {
+		if err := plan.Validate(); err != nil {
+			panic(fmt.Sprintf("Interest plan %s error: %s\n", name, err))
+		}
+	}
+
+	return &interestService{
+		interestCmdRepo:   InterestCmdRepo,
+		interestQueryRepo: InterestQueryRepo,
+		plans:             plans,
+		enabled:           viper.GetBool("interest.enabled"),
+	}
+}
+
+func (s *interestService) AccrueDaily(ctx context.Context, date time.Time) (accrued int, err error) {
+	span, ctx := tracing.StartSpan(ctx, "interestService.AccrueDaily", "service")
+	defer span.End()
+
+	if !s.enabled {
+		return 0, nil
+	}
+
+	date = startOfDay(date)
+
+	var afterID uint
+	for {
+		users, err := s.interestQueryRepo.GetInterestUsers(ctx, afterID, batchSize)
+		if err != nil {
+			return accrued, err
+		}
+		if len(users) == 0 {
+			return accrued, nil
+		}
+
+		for _, user := range users {
+			afterID = user.ID
+
+			// viper lower cases map keys
+			planName := strings.ToLower(user.InterestPlan)
+			plan, ok := s.plans[planName]
+			if !ok {
+				global.LoggerFromContext(ctx).Warnf("user %d has unknown interest plan %s", user.ID, user.InterestPlan)
+				continue
+			}
+
+			// compound plans earn on the interest accrued before date which is not capitalized yet and the carry
+			uncapitalized := decimal.Zero
+			if plan.Method == Compound {
+				sum, err := s.interestQueryRepo.SumUncapitalizedAccruals(ctx, user.ID, time.Time{}, date)
+				if err != nil {
+					return accrued, err
+				}
+				carry, err := s.interestQueryRepo.GetLastCarry(ctx, user.ID)
+				if err != nil {
+					return accrued, err
+				}
+				uncapitalized = sum.Add(carry)
+			}
+
+			principal, interest := plan.DailyInterest(user.Balance, uncapitalized, date)
+			created, err := s.interestCmdRepo.CreateAccrual(ctx, &mysqlModel.InterestAccrual{
+				UserID:    user.ID,
+				Date:      date,
+				Plan:      planName,
+				Rate:      decimal.NewFromFloat(plan.Rate),
+				Principal: principal,
+				Amount:    interest,
+			})
+			if err != nil {
+				return accrued, err
+			}
+			if created {
+				accrued++
+			}
+		}
+	}
+}
+
+func (s *interestService) Capitalize(ctx context.Context, month time.Time) (capitalized int, err error) {
+	span, ctx := tracing.StartSpan(ctx, "interestService.Capitalize", "service")
+	defer span.End()
+
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	to := from.AddDate(0, 1, 0)
+	period := from.Format("2006-01")
+
+	userIDs, err := s.interestQueryRepo.GetAccrualUserIDs(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, userID := range userIDs {
+		sum, err := s.interestQueryRepo.SumUncapitalizedAccruals(ctx, userID, from, to)
+		if err != nil {
+			return capitalized, err
+		}
+		carry, err := s.interestQueryRepo.GetLastCarry(ctx, userID)
+		if err != nil {
+			return capitalized, err
+		}
+
+		// banker's rounding keeps the carried remainders from drifting in one direction
+		total := sum.Add(carry)
+		posted := total.RoundBank(2)
+		err = s.interestCmdRepo.Capitalize(ctx, &mysqlModel.InterestCapitalization{
+			UserID:        userID,
+			Period:        period,
+			Accrued:       sum,
+			PreviousCarry: carry,
+			Posted:        posted,
+			Carry:         total.Sub(posted),
+		}, from, to)
+		if errors.Is(err, interestRepo.ErrAlreadyCapitalized) {
+			global.LoggerFromContext(ctx).Warnf("interest of user %d already capitalized for %s", userID, period)
+			continue
+		} else if err != nil {
+			return capitalized, err
+		}
+
+		capitalized++
+	}
+
+	return capitalized, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,112 @@
+package interest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	interestSrv "banking/app/service/interest"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func initialInterestService(t *testing.T) (*domainMock.MockIInterestCommandRepo, *domainMock.MockIInterestQueryRepo) {
+	global.Logger = zap.NewNop().Sugar()
+
+	viper.Set("interest.enabled", true)
+	viper.Set("interest.plans", map[string]interface{}{
+		"savings": map[string]interface{}{"rate": 3.65, "method": "compound", "dayCount": "ACT/365"},
+	})
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	return domainMock.NewMockIInterestCommandRepo(ctrl), domainMock.NewMockIInterestQueryRepo(ctrl)
+}
+
+func Test_Plan_DailyInterest(t *testing.T) {
+	balance := decimal.NewFromInt(1000)
+
+	tests := []struct {
+		name     string
+		plan     *interestSrv.Plan
+		date     time.Time
+		accrued  decimal.Decimal
+		interest string
+	}{
+		{"ACT/365", &interestSrv.Plan{Rate: 3.65, Method: interestSrv.Simple, DayCount: interestSrv.ACT365}, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), decimal.NewFromInt(5), "0.1"},
+		{"ACT/365 compound", &interestSrv.Plan{Rate: 3.65, Method: interestSrv.Compound, DayCount: interestSrv.ACT365}, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), decimal.NewFromInt(5), "0.1005"},
+		{"30/360 ordinary day", &interestSrv.Plan{Rate: 3.6, Method: interestSrv.Simple, DayCount: interestSrv.Thirty360}, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), decimal.Zero, "0.1"},
+		{"30/360 31st", &interestSrv.Plan{Rate: 3.6, Method: interestSrv.Simple, DayCount: interestSrv.Thirty360}, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), decimal.Zero, "0"},
+		{"30/360 end of February", &interestSrv.Plan{Rate: 3.6, Method: interestSrv.Simple, DayCount: interestSrv.Thirty360}, time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC), decimal.Zero, "0.3"},
+		{"30/360 leap February", &interestSrv.Plan{Rate: 3.6, Method: interestSrv.Simple, DayCount: interestSrv.Thirty360}, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), decimal.Zero, "0.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, interest := tt.plan.DailyInterest(balance, tt.accrued, tt.date)
+
+			assert.Equal(t, tt.interest, interest.String())
+		})
+	}
+}
+
+func Test_AccrueDaily(t *testing.T) {
+	mockCmdRepo, mockQueryRepo := initialInterestService(t)
+
+	date := time.Date(2024, 3, 10, 15, 4, 5, 0, time.UTC)
+	users := []*mysqlModel.User{
+		{Model: gorm.Model{ID: 1}, Balance: decimal.NewFromInt(1000), InterestPlan: "Savings"},
+		{Model: gorm.Model{ID: 2}, Balance: decimal.NewFromInt(1000), InterestPlan: "unknown"},
+	}
+
+	mockQueryRepo.EXPECT().GetInterestUsers(gomock.Any(), uint(0), gomock.Any()).Return(users, nil)
+	mockQueryRepo.EXPECT().GetInterestUsers(gomock.Any(), uint(2), gomock.Any()).Return(nil, nil)
+	mockQueryRepo.EXPECT().SumUncapitalizedAccruals(gomock.Any(), uint(1), time.Time{}, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)).Return(decimal.NewFromInt(4), nil)
+	mockQueryRepo.EXPECT().GetLastCarry(gomock.Any(), uint(1)).Return(decimal.NewFromInt(1), nil)
+	mockCmdRepo.EXPECT().CreateAccrual(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, accrual *mysqlModel.InterestAccrual) (bool, error) {
+		assert.Equal(t, "savings", accrual.Plan)
+		assert.Equal(t, "1005", accrual.Principal.String())
+		assert.Equal(t, "0.1005", accrual.Amount.String())
+		return true, nil
+	})
+
+	srv := interestSrv.NewInterestService(mockCmdRepo, mockQueryRepo)
+	accrued, err := srv.AccrueDaily(context.Background(), date)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, accrued)
+}
+
+func Test_Capitalize_BankersRounding(t *testing.T) {
+	mockCmdRepo, mockQueryRepo := initialInterestService(t)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	// 2.0 + 0.005 rounds half to even, down to 2.00, the half cent is carried
+	mockQueryRepo.EXPECT().GetAccrualUserIDs(gomock.Any(), from, to).Return([]uint{1}, nil)
+	mockQueryRepo.EXPECT().SumUncapitalizedAccruals(gomock.Any(), uint(1), from, to).Return(decimal.RequireFromString("2.0"), nil)
+	mockQueryRepo.EXPECT().GetLastCarry(gomock.Any(), uint(1)).Return(decimal.RequireFromString("0.005"), nil)
+	mockCmdRepo.EXPECT().Capitalize(gomock.Any(), gomock.Any(), from, to).DoAndReturn(func(_ context.Context, capitalization *mysqlModel.InterestCapitalization, _, _ time.Time) error {
+		assert.Equal(t, "2024-03", capitalization.Period)
+		assert.Equal(t, "2", capitalization.Posted.String())
+		assert.Equal(t, "0.005", capitalization.Carry.String())
+		return nil
+	})
+
+	srv := interestSrv.NewInterestService(mockCmdRepo, mockQueryRepo)
+	capitalized, err := srv.Capitalize(context.Background(), time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, capitalized)
+}
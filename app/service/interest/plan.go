@@ -0,0 +1,78 @@
+package interest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	Simple   = "simple"   // interest on the balance only
+	Compound = "compound" // interest on the balance plus interest accrued but not capitalized yet
+
+	ACT365    = "ACT/365" // actual days over a 365 day year
+	Thirty360 = "30/360"  // 30 day months over a 360 day year
+)
+
+// accrualPlaces is the precision of daily accruals, rounding to cents only happens at capitalization
+const accrualPlaces = 10
+
+var (
+	hundred     = decimal.NewFromInt(100)
+	daysPerYear = map[string]decimal.Decimal{ACT365: decimal.NewFromInt(365), Thirty360: decimal.NewFromInt(360)}
+)
+
+// Plan is an interest rate plan, Rate is the annual rate in percent
+type Plan struct {
+	Rate     float64 `mapstructure:"rate"`
+	Method   string  `mapstructure:"method"`
+	DayCount string  `mapstructure:"dayCount"`
+}
+
+func (p *Plan) Validate() error {
+	if p.Rate < 0 {
+		return fmt.Errorf("negative rate %v", p.Rate)
+	}
+	if p.Method != Simple && p.Method != Compound {
+		return fmt.Errorf("unknown method %q", p.Method)
+	}
+	if _, ok := daysPerYear[p.DayCount]; !ok {
+		return fmt.Errorf("unknown day count %q", p.DayCount)
+	}
+
+	return nil
+}
+
+// DailyInterest is the interest earned on date, accrued is only added to the principal by compound plans
+func (p *Plan) DailyInterest(balance, accrued decimal.Decimal, date time.Time) (principal, interest decimal.Decimal) {
+	principal = balance
+	if p.Method == Compound {
+		principal = principal.Add(accrued)
+	}
+
+	days := decimal.NewFromInt(int64(p.dayWeight(date)))
+	interest = principal.
+		Mul(decimal.NewFromFloat(p.Rate)).Div(hundred).
+		Mul(days).Div(daysPerYear[p.DayCount]).
+		RoundBank(accrualPlaces)
+
+	return principal, interest
+}
+
+// dayWeight is the number of days date counts for, 30/360 skips the 31st and lets the last
+// day of February make up the days to 30
+func (p *Plan) dayWeight(date time.Time) int {
+	if p.DayCount != Thirty360 {
+		return 1
+	}
+
+	switch {
+	case date.Day() == 31:
+		return 0
+	case date.Month() == time.February && date.AddDate(0, 0, 1).Month() == time.March:
+		return 30 - date.Day() + 1
+	default:
+		return 1
+	}
+}
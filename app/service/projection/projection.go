@@ -0,0 +1,112 @@
+package projection
+
+import (
+	"context"
+	"time"
+
+	"banking/domain"
+	"banking/global"
+	"banking/metrics"
+	"banking/tracing"
+
+	"github.com/spf13/viper"
+)
+
+type projectionService struct {
+	projectionCmdRepo   domain.IProjectionCommandRepo
+	projectionQueryRepo domain.IProjectionQueryRepo
+	enabled             bool
+	interval            time.Duration
+	batchSize           int
+	gapTimeout          time.Duration
+	gapExpiry           time.Duration
+	snapshotEvery       uint
+}
+
+func NewProjectionService(ProjectionCmdRepo domain.IProjectionCommandRepo, ProjectionQueryRepo domain.IProjectionQueryRepo) domain.IProjectionService {
+	return &projectionService{
+		projectionCmdRepo:   ProjectionCmdRepo,
+		projectionQueryRepo: ProjectionQueryRepo,
+		enabled:             viper.GetBool("projection.enabled"),
+		interval:            max(time.Duration(viper.GetInt("projection.interval"))*time.Second, time.Second),
+		batchSize:           max(viper.GetInt("projection.batchSize"), 1),
+		gapTimeout:          time.Duration(viper.GetInt("projection.gapTimeout")) * time.Second,
+		gapExpiry:           max(time.Duration(viper.GetInt("projection.gapExpiry"))*time.Second, time.Hour),
+		snapshotEvery:       viper.GetUint("projection.snapshotEvery"),
+	}
+}
+
+// Project applies batches until one comes back short, the projection then caught up with the event store
+func (s *projectionService) Project(ctx context.Context) (applied int, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projectionService.Project", "service")
+	defer span.End()
+
+	for {
+		last, batch, err := s.projectionCmdRepo.Project(ctx, s.batchSize, s.gapTimeout)
+		if err != nil {
+			return applied, err
+		}
+		if last != nil {
+			metrics.ObserveQueueLag(metrics.QueueProjection, last.CreatedAt)
+		}
+
+		applied += batch
+		if batch < s.batchSize {
+			break
+		}
+	}
+
+	// an id skipped longer ago than any ledger transaction stays open was rolled back, not committed late
+	expired, err := s.projectionCmdRepo.ExpireGaps(ctx, time.Now().Add(-s.gapExpiry))
+	if err != nil {
+		return applied, err
+	}
+	if expired > 0 {
+		global.LoggerFromContext(ctx).Infof("stopped re-checking %d skipped ledger event ids", expired)
+	}
+
+	if s.snapshotEvery == 0 {
+		return applied, nil
+	}
+
+	checkpoint, err := s.projectionQueryRepo.GetCheckpoint(ctx)
+	if err != nil {
+		return applied, err
+	}
+
+	if checkpoint.Position-checkpoint.SnapshotPosition >= s.snapshotEvery {
+		position, err := s.projectionCmdRepo.Snapshot(ctx)
+		if err != nil {
+			return applied, err
+		}
+		global.LoggerFromContext(ctx).Infof("snapshot the projected balances at event %d", position)
+	}
+
+	return applied, nil
+}
+
+func (s *projectionService) Rebuild(ctx context.Context, fromSnapshot bool) (applied int, err error) {
+	span, ctx := tracing.StartSpan(ctx, "projectionService.Rebuild", "service")
+	defer span.End()
+
+	return s.projectionCmdRepo.Rebuild(ctx, fromSnapshot, s.batchSize, s.gapTimeout)
+}
+
+func (s *projectionService) Run(ctx context.Context) {
+	if !s.enabled {
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Project(ctx); err != nil && ctx.Err() == nil {
+				global.Logger.Errorf("project ledger events error: %s", err)
+			}
+		}
+	}
+}
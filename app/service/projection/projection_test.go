@@ -0,0 +1,103 @@
+package projection_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	projectionSrv "banking/app/service/projection"
+	"banking/domain"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func initialProjectionService(t *testing.T) (domain.IProjectionService, *domainMock.MockIProjectionCommandRepo, *domainMock.MockIProjectionQueryRepo) {
+	global.Logger = zap.NewNop().Sugar()
+
+	viper.Set("projection.batchSize", 2)
+	viper.Set("projection.gapTimeout", 5)
+	viper.Set("projection.gapExpiry", 7200)
+	viper.Set("projection.snapshotEvery", 10)
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	mockCmdRepo := domainMock.NewMockIProjectionCommandRepo(ctrl)
+	mockQueryRepo := domainMock.NewMockIProjectionQueryRepo(ctrl)
+	return projectionSrv.NewProjectionService(mockCmdRepo, mockQueryRepo), mockCmdRepo, mockQueryRepo
+}
+
+func Test_Project(t *testing.T) {
+	event := &mysqlModel.LedgerEvent{ID: 3, CreatedAt: time.Now()}
+
+	t.Run("until a batch comes back short", func(t *testing.T) {
+		service, mockCmdRepo, mockQueryRepo := initialProjectionService(t)
+
+		gomock.InOrder(
+			mockCmdRepo.EXPECT().Project(gomock.Any(), 2, 5*time.Second).Return(event, 2, nil),
+			mockCmdRepo.EXPECT().Project(gomock.Any(), 2, 5*time.Second).Return(event, 1, nil),
+		)
+		mockCmdRepo.EXPECT().ExpireGaps(gomock.Any(), gomock.Any()).Return(0, nil)
+		mockQueryRepo.EXPECT().GetCheckpoint(gomock.Any()).Return(&mysqlModel.ProjectionCheckpoint{Position: 3}, nil)
+
+		applied, err := service.Project(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 3, applied)
+	})
+
+	t.Run("snapshots every snapshotEvery events", func(t *testing.T) {
+		service, mockCmdRepo, mockQueryRepo := initialProjectionService(t)
+
+		mockCmdRepo.EXPECT().Project(gomock.Any(), 2, 5*time.Second).Return(nil, 0, nil)
+		mockCmdRepo.EXPECT().ExpireGaps(gomock.Any(), gomock.Any()).Return(0, nil)
+		mockQueryRepo.EXPECT().GetCheckpoint(gomock.Any()).Return(&mysqlModel.ProjectionCheckpoint{Position: 25, SnapshotPosition: 15}, nil)
+		mockCmdRepo.EXPECT().Snapshot(gomock.Any()).Return(uint(25), nil)
+
+		_, err := service.Project(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("expires the gaps older than gapExpiry", func(t *testing.T) {
+		service, mockCmdRepo, mockQueryRepo := initialProjectionService(t)
+
+		mockCmdRepo.EXPECT().Project(gomock.Any(), 2, 5*time.Second).Return(nil, 0, nil)
+		mockCmdRepo.EXPECT().ExpireGaps(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, before time.Time) (int, error) {
+			assert.WithinDuration(t, time.Now().Add(-2*time.Hour), before, time.Minute)
+			return 1, nil
+		})
+		mockQueryRepo.EXPECT().GetCheckpoint(gomock.Any()).Return(&mysqlModel.ProjectionCheckpoint{}, nil)
+
+		_, err := service.Project(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		service, mockCmdRepo, _ := initialProjectionService(t)
+
+		mockCmdRepo.EXPECT().Project(gomock.Any(), gomock.Any(), gomock.Any()).Return(event, 2, nil)
+		mockCmdRepo.EXPECT().Project(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, errors.New("connection refused"))
+
+		applied, err := service.Project(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, 2, applied)
+	})
+}
+
+func Test_Rebuild(t *testing.T) {
+	service, mockCmdRepo, _ := initialProjectionService(t)
+
+	mockCmdRepo.EXPECT().Rebuild(gomock.Any(), true, 2, 5*time.Second).Return(7, nil)
+
+	applied, err := service.Rebuild(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, applied)
+}
@@ -0,0 +1,187 @@
+package reconciliation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+// camt053Document maps the subset of ISO 20022 camt.053 used for reconciliation,
+// element names are matched without namespace so any camt.053.001.xx version is accepted
+type camt053Document struct {
+	XMLName    xml.Name `xml:"Document"`
+	Statements []struct {
+		ID      string `xml:"Id"`
+		Account struct {
+			IBAN  string `xml:"Id>IBAN"`
+			Other string `xml:"Id>Othr>Id"`
+		} `xml:"Acct"`
+		Entries []camt053Entry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camt053Entry struct {
+	Amount        camt053Amount    `xml:"Amt"`
+	CreditDebit   string           `xml:"CdtDbtInd"`
+	Reversal      bool             `xml:"RvslInd"`
+	BookingDate   string           `xml:"BookgDt>Dt"`
+	ValueDate     string           `xml:"ValDt>Dt"`
+	ServicerRef   string           `xml:"AcctSvcrRef"`
+	Details       []camt053Details `xml:"NtryDtls>TxDtls"`
+	AdditionalInf string           `xml:"AddtlNtryInf"`
+}
+
+type camt053Amount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+// camt053Details is a transaction booked by an entry, a batch entry books several
+type camt053Details struct {
+	Amount       camt053Amount `xml:"Amt"`               // camt.053.001.04 and later
+	TxAmount     camt053Amount `xml:"AmtDtls>TxAmt>Amt"` // camt.053.001.02
+	CreditDebit  string        `xml:"CdtDbtInd"`
+	EndToEndID   string        `xml:"Refs>EndToEndId"`
+	Unstructured string        `xml:"RmtInf>Ustrd"`
+}
+
+// ParseCAMT053 parses an ISO 20022 camt.053 bank to customer statement
+func ParseCAMT053(data []byte) (*Statement, error) {
+	doc := &camt053Document{}
+	if err := xml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidStatement, err)
+	}
+
+	if len(doc.Statements) != 1 {
+		return nil, fmt.Errorf("%w: expected exactly one Stmt, got %d", ErrInvalidStatement, len(doc.Statements))
+	}
+
+	stmt := doc.Statements[0]
+	statement := &Statement{
+		Reference: stmt.ID,
+		Account:   stmt.Account.IBAN,
+	}
+	if statement.Account == "" {
+		statement.Account = stmt.Account.Other
+	}
+
+	for _, entry := range stmt.Entries {
+		lines, err := parseCAMT053Entry(entry)
+		if err != nil {
+			return nil, err
+		}
+		statement.Lines = append(statement.Lines, lines...)
+	}
+
+	return statement, nil
+}
+
+// parseCAMT053Entry returns a line per transaction of the entry, the lines of a batch entry share its
+// dates and bank reference
+func parseCAMT053Entry(entry camt053Entry) ([]*StatementLine, error) {
+	amount, err := decimal.NewFromString(strings.TrimSpace(entry.Amount.Value))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidStatement, err)
+	}
+
+	direction, err := parseCreditDebit(entry.CreditDebit)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Reversal {
+		if direction == mysqlModel.Credit {
+			direction = mysqlModel.Debit
+		} else {
+			direction = mysqlModel.Credit
+		}
+	}
+
+	date := entry.ValueDate
+	if date == "" {
+		date = entry.BookingDate
+	}
+	valueDate, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidStatement, err)
+	}
+
+	newLine := func(amount decimal.Decimal, currency string, details camt053Details) *StatementLine {
+		reference := strings.TrimSpace(details.EndToEndID)
+		if reference == "NOTPROVIDED" {
+			reference = ""
+		}
+
+		description := strings.TrimSpace(details.Unstructured)
+		if description == "" {
+			description = strings.TrimSpace(entry.AdditionalInf)
+		}
+
+		return &StatementLine{
+			ValueDate:     valueDate,
+			Direction:     direction,
+			Amount:        amount,
+			Currency:      currency,
+			Reference:     reference,
+			BankReference: strings.TrimSpace(entry.ServicerRef),
+			Description:   description,
+		}
+	}
+
+	if len(entry.Details) <= 1 {
+		details := camt053Details{}
+		if len(entry.Details) == 1 {
+			details = entry.Details[0]
+		}
+		return []*StatementLine{newLine(amount, entry.Amount.Currency, details)}, nil
+	}
+
+	// a batch entry is split when each of its transactions has an amount and they add up to the entry,
+	// a line of the entry total would match none of them
+	lines := make([]*StatementLine, 0, len(entry.Details))
+	total := decimal.Zero
+	for _, details := range entry.Details {
+		txAmount := details.Amount
+		if txAmount.Value == "" {
+			txAmount = details.TxAmount
+		}
+		if txAmount.Value == "" {
+			return nil, fmt.Errorf("%w: batch entry %s without the amount of each transaction", ErrInvalidStatement, entry.ServicerRef)
+		}
+		value, err := decimal.NewFromString(strings.TrimSpace(txAmount.Value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidStatement, err)
+		}
+
+		if details.CreditDebit != "" && details.CreditDebit != entry.CreditDebit {
+			return nil, fmt.Errorf("%w: batch entry %s mixes credits and debits", ErrInvalidStatement, entry.ServicerRef)
+		}
+
+		currency := txAmount.Currency
+		if currency == "" {
+			currency = entry.Amount.Currency
+		}
+		lines = append(lines, newLine(value, currency, details))
+		total = total.Add(value)
+	}
+
+	if !total.Equal(amount) {
+		return nil, fmt.Errorf("%w: transactions of batch entry %s add up to %s, not %s", ErrInvalidStatement, entry.ServicerRef, total, amount)
+	}
+
+	return lines, nil
+}
+
+func parseCreditDebit(indicator string) (mysqlModel.StatementDirection, error) {
+	switch indicator {
+	case "CRDT":
+		return mysqlModel.Credit, nil
+	case "DBIT":
+		return mysqlModel.Debit, nil
+	}
+	return "", fmt.Errorf("%w: unknown CdtDbtInd %q", ErrInvalidStatement, indicator)
+}
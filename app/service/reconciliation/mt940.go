@@ -0,0 +1,143 @@
+package reconciliation
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+// :61: YYMMDD[MMDD](C|D|RC|RD)[funds code]amount(N|F|S)XXX customer reference[//bank reference]
+var mt940EntryRegexp = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d{0,2})([NFS][A-Z0-9]{3})([^/\n]*)(?://([^\n]*))?`)
+
+// ParseMT940 parses a SWIFT MT940 customer statement message
+func ParseMT940(data []byte) (*Statement, error) {
+	fields, err := splitMT940Fields(data)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &Statement{}
+	var currency string
+	var last *StatementLine
+	for _, field := range fields {
+		switch field.tag {
+		case "20":
+			statement.Reference = field.value
+		case "25":
+			statement.Account = field.value
+		case "60F", "60M":
+			// D/C mark, date, currency and amount, e.g. C240301EUR1000,00
+			if len(field.value) >= 10 {
+				currency = field.value[7:10]
+			}
+		case "61":
+			line, err := parseMT940Entry(field.value)
+			if err != nil {
+				return nil, err
+			}
+			line.Currency = currency
+			statement.Lines = append(statement.Lines, line)
+			last = line
+		case "86":
+			// information to account owner belongs to the preceding :61: entry
+			if last != nil {
+				last.Description = strings.ReplaceAll(field.value, "\n", " ")
+			}
+		}
+	}
+
+	if statement.Reference == "" {
+		return nil, fmt.Errorf("%w: missing :20: transaction reference", ErrInvalidStatement)
+	}
+
+	return statement, nil
+}
+
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+func splitMT940Fields(data []byte) ([]*mt940Field, error) {
+	var fields []*mt940Field
+	var current *mt940Field
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r ")
+		if line == "" || line == "-" || line == "-}" || strings.HasPrefix(line, "{") {
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			end := strings.Index(line[1:], ":")
+			if end <= 0 {
+				return nil, fmt.Errorf("%w: malformed tag %q", ErrInvalidStatement, line)
+			}
+			current = &mt940Field{tag: line[1 : end+1], value: line[end+2:]}
+			fields = append(fields, current)
+			continue
+		}
+
+		// continuation line of a multi-line field
+		if current == nil {
+			return nil, fmt.Errorf("%w: content before first tag", ErrInvalidStatement)
+		}
+		current.value += "\n" + strings.TrimSpace(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func parseMT940Entry(value string) (*StatementLine, error) {
+	matches := mt940EntryRegexp.FindStringSubmatch(value)
+	if matches == nil {
+		return nil, fmt.Errorf("%w: malformed :61: entry %q", ErrInvalidStatement, value)
+	}
+
+	valueDate, err := time.Parse("060102", matches[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidStatement, err)
+	}
+
+	amount, err := decimal.NewFromString(strings.Replace(matches[5], ",", ".", 1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidStatement, err)
+	}
+
+	// RC and RD are reversals, booked in the opposite direction
+	direction := mysqlModel.Credit
+	if matches[3] == "D" || matches[3] == "RC" {
+		direction = mysqlModel.Debit
+	}
+
+	reference := strings.TrimSpace(matches[7])
+	if reference == "NONREF" {
+		reference = ""
+	}
+
+	line := &StatementLine{
+		ValueDate:     valueDate,
+		Direction:     direction,
+		Amount:        amount,
+		Reference:     reference,
+		BankReference: strings.TrimSpace(matches[8]),
+	}
+
+	// optional supplementary details on the second line, replaced by :86: when present
+	if idx := strings.Index(value, "\n"); idx >= 0 {
+		line.Description = strings.TrimSpace(value[idx+1:])
+	}
+
+	return line, nil
+}
@@ -0,0 +1,221 @@
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	reconciliationRepo "banking/app/repo/mysql/reconciliation"
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// maxCandidateIDs keeps the comma separated candidate list inside its varchar(255) column
+const maxCandidateIDs = 20
+
+var (
+	ErrLineAlreadyReconciled = domain.NewError(domain.CodeLineAlreadyReconciled, "statement line already reconciled")
+	ErrTransactionMismatch   = domain.NewError(domain.CodeTransactionMismatch, "transaction type does not match statement line direction")
+	ErrTransactionClaimed    = reconciliationRepo.ErrTransactionClaimed
+)
+
+type reconciliationService struct {
+	reconciliationCmdRepo   domain.IReconciliationCommandRepo
+	reconciliationQueryRepo domain.IReconciliationQueryRepo
+	amountTolerance         decimal.Decimal
+	dateTolerance           time.Duration
+	auditService            domain.IAuditService
+}
+
+func NewReconciliationService(ReconciliationCmdRepo domain.IReconciliationCommandRepo, ReconciliationQueryRepo domain.IReconciliationQueryRepo, AuditService domain.IAuditService) domain.IReconciliationService {
+	return &reconciliationService{
+		reconciliationCmdRepo:   ReconciliationCmdRepo,
+		reconciliationQueryRepo: ReconciliationQueryRepo,
+		amountTolerance:         decimal.NewFromFloat(viper.GetFloat64("reconciliation.amountTolerance")),
+		dateTolerance:           time.Duration(viper.GetInt("reconciliation.dateToleranceDays")) * 24 * time.Hour,
+		auditService:            AuditService,
+	}
+}
+
+func (s *reconciliationService) ImportStatement(ctx context.Context, format mysqlModel.StatementFormat, fileName string, data []byte, adminID uint) (statement *mysqlModel.BankStatement, err error) {
+	span, ctx := tracing.StartSpan(ctx, "reconciliationService.ImportStatement", "service")
+	defer span.End()
+
+	defer func() {
+		var after interface{}
+		if statement != nil {
+			after = map[string]interface{}{"statementId": statement.ID, "statementRef": statement.StatementRef, "lines": len(statement.Lines)}
+		}
+		s.auditService.Record(ctx, mysqlModel.AuditStatementImport, "statement:"+fileName, nil, after, err)
+	}()
+
+	parsed, err := ParseStatement(format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	statement = &mysqlModel.BankStatement{
+		Format:       format,
+		StatementRef: parsed.Reference,
+		Account:      parsed.Account,
+		FileName:     fileName,
+		ImportedBy:   adminID,
+		Lines:        make([]mysqlModel.BankStatementLine, 0, len(parsed.Lines)),
+	}
+
+	// transactions matched earlier in this file are not available to later lines
+	claimed := make(map[uint]bool)
+	for _, parsedLine := range parsed.Lines {
+		line := mysqlModel.BankStatementLine{
+			ValueDate:     parsedLine.ValueDate,
+			Direction:     parsedLine.Direction,
+			Amount:        parsedLine.Amount,
+			Currency:      parsedLine.Currency,
+			Reference:     parsedLine.Reference,
+			BankReference: parsedLine.BankReference,
+			Description:   parsedLine.Description,
+		}
+
+		if err := s.match(ctx, &line, claimed); err != nil {
+			return nil, err
+		}
+
+		statement.Lines = append(statement.Lines, line)
+	}
+
+	if err := s.reconciliationCmdRepo.CreateStatement(ctx, statement); err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+func (s *reconciliationService) GetStatementLines(ctx context.Context, statementID uint, status mysqlModel.ReconciliationStatus) (lines []*mysqlModel.BankStatementLine, err error) {
+	span, ctx := tracing.StartSpan(ctx, "reconciliationService.GetStatementLines", "service")
+	defer span.End()
+
+	return s.reconciliationQueryRepo.GetStatementLines(ctx, statementID, status)
+}
+
+// ResolveStatementLine assigns a transaction to an unmatched or ambiguous line, transactionID 0 marks the line as ignored
+func (s *reconciliationService) ResolveStatementLine(ctx context.Context, lineID, transactionID, adminID uint) (line *mysqlModel.BankStatementLine, err error) {
+	span, ctx := tracing.StartSpan(ctx, "reconciliationService.ResolveStatementLine", "service")
+	defer span.End()
+
+	var before interface{}
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditStatementLineResolve, fmt.Sprintf("statementLine:%d", lineID), before, line, err)
+	}()
+
+	line, err = s.reconciliationQueryRepo.GetStatementLine(ctx, lineID)
+	if err != nil {
+		return nil, err
+	}
+	before = map[string]interface{}{"status": line.Status, "transactionId": line.TransactionID}
+
+	if line.Status != mysqlModel.Unmatched && line.Status != mysqlModel.Ambiguous {
+		return nil, ErrLineAlreadyReconciled
+	}
+
+	line.ResolvedBy = &adminID
+	if transactionID == 0 {
+		line.Status = mysqlModel.Ignored
+	} else {
+		transaction, err := s.reconciliationQueryRepo.GetTransaction(ctx, transactionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if transaction.TransactionType != transactionTypeFor(line.Direction) {
+			return nil, ErrTransactionMismatch
+		}
+
+		// checked first for a clear error, the unique transaction id of the lines settles a race
+		claimed, err := s.reconciliationQueryRepo.IsTransactionClaimed(ctx, transactionID)
+		if err != nil {
+			return nil, err
+		} else if claimed {
+			return nil, ErrTransactionClaimed
+		}
+
+		line.Status = mysqlModel.Resolved
+		line.TransactionID = &transaction.ID
+	}
+
+	if err := s.reconciliationCmdRepo.UpdateStatementLine(ctx, line); err != nil {
+		return nil, err
+	}
+
+	return line, nil
+}
+
+// match looks up deposit or withdraw transactions within the configured amount and date tolerance,
+// a transaction whose external reference, memo or id equals the line reference wins over other candidates
+func (s *reconciliationService) match(ctx context.Context, line *mysqlModel.BankStatementLine, claimed map[uint]bool) error {
+	from := line.ValueDate.Add(-s.dateTolerance)
+	to := line.ValueDate.Add(s.dateTolerance + 24*time.Hour)
+
+	transactions, err := s.reconciliationQueryRepo.GetCandidateTransactions(
+		ctx,
+		transactionTypeFor(line.Direction),
+		line.Amount.Sub(s.amountTolerance),
+		line.Amount.Add(s.amountTolerance),
+		from,
+		to,
+	)
+	if err != nil {
+		return err
+	}
+
+	candidates := make([]*mysqlModel.Transaction, 0, len(transactions))
+	referenced := make([]*mysqlModel.Transaction, 0, 1)
+	for _, transaction := range transactions {
+		if claimed[transaction.ID] {
+			continue
+		}
+
+		candidates = append(candidates, transaction)
+		if transaction.MatchesReference(line.Reference) || strconv.FormatUint(uint64(transaction.ID), 10) == line.Reference {
+			referenced = append(referenced, transaction)
+		}
+	}
+
+	if len(referenced) == 1 {
+		candidates = referenced
+	}
+
+	switch len(candidates) {
+	case 0:
+		line.Status = mysqlModel.Unmatched
+	case 1:
+		line.Status = mysqlModel.Matched
+		line.TransactionID = &candidates[0].ID
+		claimed[candidates[0].ID] = true
+	default:
+		line.Status = mysqlModel.Ambiguous
+		ids := make([]string, 0, maxCandidateIDs)
+		for i, candidate := range candidates {
+			if i == maxCandidateIDs {
+				break
+			}
+			ids = append(ids, strconv.FormatUint(uint64(candidate.ID), 10))
+		}
+		line.CandidateIDs = strings.Join(ids, ",")
+	}
+
+	return nil
+}
+
+func transactionTypeFor(direction mysqlModel.StatementDirection) mysqlModel.TransactionType {
+	if direction == mysqlModel.Debit {
+		return mysqlModel.Withdraw
+	}
+
+	return mysqlModel.Deposit
+}
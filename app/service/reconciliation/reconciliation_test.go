@@ -0,0 +1,58 @@
+package reconciliation_test
+
+import (
+	"context"
+	"testing"
+
+	reconciliationSrv "banking/app/service/reconciliation"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func Test_ImportStatement_MatchesReference(t *testing.T) {
+	global.Logger = zap.NewNop().Sugar()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cmdRepo := domainMock.NewMockIReconciliationCommandRepo(ctrl)
+	queryRepo := domainMock.NewMockIReconciliationQueryRepo(ctrl)
+	audit := domainMock.NewMockIAuditService(ctrl)
+	audit.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	service := reconciliationSrv.NewReconciliationService(cmdRepo, queryRepo, audit)
+
+	deposit := func(id uint, reference *mysqlModel.TransactionReference) *mysqlModel.Transaction {
+		transaction := &mysqlModel.Transaction{Model: gorm.Model{ID: id}, Amount: decimal.NewFromFloat(150.25), TransactionType: mysqlModel.Deposit}
+		reference.ApplyTo(transaction)
+		return transaction
+	}
+	// the same amount on the same day, only the external reference tells them apart
+	queryRepo.EXPECT().GetCandidateTransactions(gomock.Any(), mysqlModel.Deposit, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*mysqlModel.Transaction{
+		deposit(1, &mysqlModel.TransactionReference{Memo: "invoice"}),
+		deposit(2, &mysqlModel.TransactionReference{Memo: "invoice", ExternalReference: "REF-001"}),
+	}, nil)
+	cmdRepo.EXPECT().CreateStatement(gomock.Any(), gomock.Any()).Return(nil)
+
+	data := []byte(`{1:F01BANKBEBBAXXX0000000000}{2:I940BANKBEBBXXXXN}{4:
+:20:STMT20240301
+:25:BE68539007547034
+:28C:00001/001
+:60F:C240229EUR1000,00
+:61:2403010301C150,25NTRFREF-001//BANKREF1
+:62F:C240301EUR1150,25
+-}`)
+	statement, err := service.ImportStatement(context.Background(), mysqlModel.MT940, "statement.sta", data, 1)
+	require.NoError(t, err)
+	require.Len(t, statement.Lines, 1)
+	assert.Equal(t, mysqlModel.Matched, statement.Lines[0].Status)
+	require.NotNil(t, statement.Lines[0].TransactionID)
+	assert.Equal(t, uint(2), *statement.Lines[0].TransactionID)
+}
@@ -0,0 +1,44 @@
+package reconciliation
+
+import (
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrUnsupportedFormat = domain.NewError(domain.CodeUnsupportedFormat, "unsupported statement format")
+	ErrInvalidStatement  = domain.NewError(domain.CodeInvalidStatement, "invalid statement file")
+)
+
+// Statement is the format independent result of parsing a bank statement file
+type Statement struct {
+	Reference string
+	Account   string
+	Lines     []*StatementLine
+}
+
+type StatementLine struct {
+	ValueDate     time.Time
+	Direction     mysqlModel.StatementDirection
+	Amount        decimal.Decimal
+	Currency      string
+	Reference     string
+	BankReference string
+	Description   string
+}
+
+// ParseStatement parses the raw file content according to the given format
+func ParseStatement(format mysqlModel.StatementFormat, data []byte) (*Statement, error) {
+	switch format {
+	case mysqlModel.MT940:
+		return ParseMT940(data)
+	case mysqlModel.CAMT053:
+		return ParseCAMT053(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
@@ -0,0 +1,156 @@
+package reconciliation_test
+
+import (
+	"testing"
+	"time"
+
+	reconciliationSrv "banking/app/service/reconciliation"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseMT940(t *testing.T) {
+	data := []byte(`{1:F01BANKBEBBAXXX0000000000}{2:I940BANKBEBBXXXXN}{4:
+:20:STMT20240301
+:25:BE68539007547034
+:28C:00001/001
+:60F:C240229EUR1000,00
+:61:2403010301C150,25NTRFREF-001//BANKREF1
+:86:Deposit from customer
+ second line
+:61:240302DR20,00NCHGNONREF
+:61:240302RC5,00NTRFREF-003
+:62F:C240302EUR1125,25
+-}`)
+
+	statement, err := reconciliationSrv.ParseMT940(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "STMT20240301", statement.Reference)
+	assert.Equal(t, "BE68539007547034", statement.Account)
+	assert.Len(t, statement.Lines, 3)
+
+	line := statement.Lines[0]
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), line.ValueDate)
+	assert.Equal(t, mysqlModel.Credit, line.Direction)
+	assert.True(t, decimal.NewFromFloat(150.25).Equal(line.Amount))
+	assert.Equal(t, "EUR", line.Currency)
+	assert.Equal(t, "REF-001", line.Reference)
+	assert.Equal(t, "BANKREF1", line.BankReference)
+	assert.Equal(t, "Deposit from customer second line", line.Description)
+
+	// D mark with funds code, NONREF means no customer reference
+	assert.Equal(t, mysqlModel.Debit, statement.Lines[1].Direction)
+	assert.True(t, decimal.NewFromFloat(20).Equal(statement.Lines[1].Amount))
+	assert.Equal(t, "", statement.Lines[1].Reference)
+
+	// reversal of credit is booked as debit
+	assert.Equal(t, mysqlModel.Debit, statement.Lines[2].Direction)
+}
+
+func Test_ParseMT940_Invalid(t *testing.T) {
+	_, err := reconciliationSrv.ParseMT940([]byte(":20:STMT\n:61:notanentry\n"))
+	assert.ErrorIs(t, err, reconciliationSrv.ErrInvalidStatement)
+}
+
+func Test_ParseCAMT053(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Id>CAMT-20240301</Id>
+      <Acct><Id><IBAN>DE89370400440532013000</IBAN></Id></Acct>
+      <Ntry>
+        <Amt Ccy="EUR">99.90</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><Dt>2024-03-01</Dt></BookgDt>
+        <ValDt><Dt>2024-03-02</Dt></ValDt>
+        <AcctSvcrRef>SVC-1</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>E2E-1</EndToEndId></Refs>
+          <RmtInf><Ustrd>invoice 42</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">10.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <BookgDt><Dt>2024-03-03</Dt></BookgDt>
+        <NtryDtls><TxDtls><Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs></TxDtls></NtryDtls>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`)
+
+	statement, err := reconciliationSrv.ParseCAMT053(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "CAMT-20240301", statement.Reference)
+	assert.Equal(t, "DE89370400440532013000", statement.Account)
+	assert.Len(t, statement.Lines, 2)
+
+	line := statement.Lines[0]
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), line.ValueDate)
+	assert.Equal(t, mysqlModel.Credit, line.Direction)
+	assert.True(t, decimal.NewFromFloat(99.9).Equal(line.Amount))
+	assert.Equal(t, "EUR", line.Currency)
+	assert.Equal(t, "E2E-1", line.Reference)
+	assert.Equal(t, "SVC-1", line.BankReference)
+	assert.Equal(t, "invoice 42", line.Description)
+
+	// booking date is used when value date is missing
+	assert.Equal(t, time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), statement.Lines[1].ValueDate)
+	assert.Equal(t, mysqlModel.Debit, statement.Lines[1].Direction)
+	assert.Equal(t, "", statement.Lines[1].Reference)
+}
+
+func Test_ParseCAMT053_Batch(t *testing.T) {
+	document := func(entry string) []byte {
+		return []byte(`<Document><BkToCstmrStmt><Stmt><Id>CAMT-BATCH</Id><Acct><Id><IBAN>DE89370400440532013000</IBAN></Id></Acct>` +
+			entry + `</Stmt></BkToCstmrStmt></Document>`)
+	}
+
+	t.Run("split into a line per transaction", func(t *testing.T) {
+		statement, err := reconciliationSrv.ParseCAMT053(document(`<Ntry>
+  <Amt Ccy="EUR">30.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><BookgDt><Dt>2024-03-01</Dt></BookgDt><AcctSvcrRef>SVC-B</AcctSvcrRef>
+  <NtryDtls>
+    <TxDtls><Amt Ccy="EUR">10.00</Amt><Refs><EndToEndId>E2E-1</EndToEndId></Refs></TxDtls>
+    <TxDtls><AmtDtls><TxAmt><Amt Ccy="EUR">20.00</Amt></TxAmt></AmtDtls><Refs><EndToEndId>E2E-2</EndToEndId></Refs><RmtInf><Ustrd>invoice 43</Ustrd></RmtInf></TxDtls>
+  </NtryDtls>
+</Ntry>`))
+		require.NoError(t, err)
+		require.Len(t, statement.Lines, 2)
+
+		assert.True(t, decimal.NewFromFloat(10).Equal(statement.Lines[0].Amount))
+		assert.Equal(t, "E2E-1", statement.Lines[0].Reference)
+		assert.True(t, decimal.NewFromFloat(20).Equal(statement.Lines[1].Amount))
+		assert.Equal(t, "E2E-2", statement.Lines[1].Reference)
+		assert.Equal(t, "invoice 43", statement.Lines[1].Description)
+		for _, line := range statement.Lines {
+			assert.Equal(t, mysqlModel.Credit, line.Direction)
+			assert.Equal(t, "SVC-B", line.BankReference)
+			assert.Equal(t, "EUR", line.Currency)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		details string
+	}{
+		{name: "amount missing", details: `<TxDtls><Amt Ccy="EUR">10.00</Amt></TxDtls><TxDtls><Refs><EndToEndId>E2E-2</EndToEndId></Refs></TxDtls>`},
+		{name: "amounts do not add up", details: `<TxDtls><Amt Ccy="EUR">10.00</Amt></TxDtls><TxDtls><Amt Ccy="EUR">10.00</Amt></TxDtls>`},
+		{name: "credits and debits", details: `<TxDtls><Amt Ccy="EUR">10.00</Amt></TxDtls><TxDtls><Amt Ccy="EUR">20.00</Amt><CdtDbtInd>DBIT</CdtDbtInd></TxDtls>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := reconciliationSrv.ParseCAMT053(document(`<Ntry><Amt Ccy="EUR">30.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><BookgDt><Dt>2024-03-01</Dt></BookgDt><NtryDtls>` +
+				tt.details + `</NtryDtls></Ntry>`))
+			assert.ErrorIs(t, err, reconciliationSrv.ErrInvalidStatement)
+		})
+	}
+}
+
+func Test_ParseStatement_UnsupportedFormat(t *testing.T) {
+	_, err := reconciliationSrv.ParseStatement("csv", []byte("a,b"))
+	assert.ErrorIs(t, err, reconciliationSrv.ErrUnsupportedFormat)
+}
@@ -0,0 +1,266 @@
+package stream
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"banking/domain"
+	"banking/global"
+	"banking/metrics"
+	mysqlModel "banking/model/mysql"
+	redisModel "banking/model/redis"
+	"banking/tracing"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+var ErrInvalidEventID = domain.NewError(domain.CodeInvalidEventID, "invalid last event id")
+
+// streamService fans out the events received from redis pub/sub to the subscribers connected to
+// this replica. A subscriber which does not keep up is closed, the client resumes from its last event id
+type streamService struct {
+	streamCmdRepo   domain.IRedisStreamCommandRepo
+	streamQueryRepo domain.IRedisStreamQueryRepo
+	bufferSize      int
+
+	mu          sync.Mutex
+	listening   bool
+	subscribers map[uint]map[chan *redisModel.BalanceEvent]struct{}
+}
+
+func NewStreamService(StreamCmdRepo domain.IRedisStreamCommandRepo, StreamQueryRepo domain.IRedisStreamQueryRepo) domain.IStreamService {
+	bufferSize := viper.GetInt("stream.bufferSize")
+	if bufferSize <= 0 {
+		panic("stream.bufferSize must be positive")
+	}
+
+	return &streamService{
+		streamCmdRepo:   StreamCmdRepo,
+		streamQueryRepo: StreamQueryRepo,
+		bufferSize:      bufferSize,
+		subscribers:     make(map[uint]map[chan *redisModel.BalanceEvent]struct{}),
+	}
+}
+
+// Publish sends an event to every user whose balance was changed by the transaction. Failures are
+// logged only, the transaction is committed already
+func (s *streamService) Publish(ctx context.Context, transaction *mysqlModel.Transaction) {
+	span, ctx := tracing.StartSpan(ctx, "streamService.Publish", "service")
+	defer span.End()
+
+	events := []*redisModel.BalanceEvent{newBalanceEvent(transaction, transaction.FromUserID, transaction.FromUserBalance)}
+	if transaction.ToUserID != transaction.FromUserID {
+		events = append(events, newBalanceEvent(transaction, transaction.ToUserID, transaction.ToUserBalance))
+	}
+
+	for _, event := range events {
+		if err := s.streamCmdRepo.AppendEvent(ctx, event); err != nil {
+			tracing.CaptureError(ctx, err)
+			global.LoggerFromContext(ctx).Errorf("publish balance event of user %d error: %s", event.UserID, err)
+		}
+	}
+}
+
+// Subscribe streams the events of the user until ctx is done, starting with the events after
+// lastEventID when it is set. The channel is closed when the subscriber falls behind
+func (s *streamService) Subscribe(ctx context.Context, userID uint, lastEventID string) (events <-chan *redisModel.BalanceEvent, err error) {
+	span, ctx := tracing.StartSpan(ctx, "streamService.Subscribe", "service")
+	defer span.End()
+
+	if lastEventID != "" {
+		if _, _, ok := parseEventID(lastEventID); !ok {
+			return nil, ErrInvalidEventID
+		}
+	}
+
+	if err := s.listen(); err != nil {
+		return nil, err
+	}
+
+	// register before reading the backlog, so no event falls between replay and live events
+	live := s.register(userID)
+
+	var replay []*redisModel.BalanceEvent
+	if lastEventID != "" {
+		if replay, err = s.streamQueryRepo.GetEventsAfter(ctx, userID, lastEventID); err != nil {
+			s.unregister(userID, live)
+			return nil, err
+		}
+	}
+
+	out := make(chan *redisModel.BalanceEvent)
+	go func() {
+		defer close(out)
+		defer s.unregister(userID, live)
+
+		last := lastEventID
+		for _, event := range replay {
+			select {
+			case out <- event:
+				last = event.ID
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-live:
+				if !ok {
+					return
+				}
+				// skip live events which were replayed already
+				if last != "" && !eventAfter(event.ID, last) {
+					continue
+				}
+
+				select {
+				case out <- event:
+					last = event.ID
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// listen subscribes this replica to redis pub/sub once, the subscription lives as long as the process
+func (s *streamService) listen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listening {
+		return nil
+	}
+
+	events, err := s.streamQueryRepo.SubscribeEvents(context.Background())
+	if err != nil {
+		return err
+	}
+	s.listening = true
+
+	go func() {
+		for event := range events {
+			s.dispatch(event)
+		}
+
+		// the subscription ended, close every subscriber so clients reconnect and resume
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.listening = false
+		for userID, channels := range s.subscribers {
+			for ch := range channels {
+				close(ch)
+			}
+			delete(s.subscribers, userID)
+		}
+	}()
+
+	return nil
+}
+
+func (s *streamService) dispatch(event *redisModel.BalanceEvent) {
+	// the stream entry id starts with the time the event was appended
+	if ms, _, ok := parseEventID(event.ID); ok {
+		metrics.ObserveQueueLag(metrics.QueueBalanceStream, time.UnixMilli(int64(ms)))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subscribers[event.UserID] {
+		select {
+		case ch <- event:
+		default:
+			global.Logger.Warnf("balance stream subscriber of user %d fell behind, closing", event.UserID)
+			delete(s.subscribers[event.UserID], ch)
+			close(ch)
+		}
+	}
+}
+
+func (s *streamService) register(userID uint) chan *redisModel.BalanceEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan *redisModel.BalanceEvent, s.bufferSize)
+	if s.subscribers[userID] == nil {
+		s.subscribers[userID] = make(map[chan *redisModel.BalanceEvent]struct{})
+	}
+	s.subscribers[userID][ch] = struct{}{}
+
+	return ch
+}
+
+func (s *streamService) unregister(userID uint, ch chan *redisModel.BalanceEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the channel is closed already when dispatch dropped the subscriber
+	if _, ok := s.subscribers[userID][ch]; !ok {
+		return
+	}
+
+	delete(s.subscribers[userID], ch)
+	if len(s.subscribers[userID]) == 0 {
+		delete(s.subscribers, userID)
+	}
+	close(ch)
+}
+
+func newBalanceEvent(transaction *mysqlModel.Transaction, userID uint, balance decimal.Decimal) *redisModel.BalanceEvent {
+	return &redisModel.BalanceEvent{
+		UserID:  userID,
+		Type:    redisModel.TransactionEvent,
+		Balance: balance,
+		Transaction: &redisModel.StreamTransaction{
+			ID:              transaction.ID,
+			FromUserID:      transaction.FromUserID,
+			ToUserID:        transaction.ToUserID,
+			Amount:          transaction.Amount,
+			Fee:             transaction.Fee,
+			TransactionType: string(transaction.TransactionType),
+			Details:         transaction.Details,
+			CreatedAt:       transaction.CreatedAt,
+		},
+	}
+}
+
+// parseEventID splits a redis stream id "<milliseconds>-<sequence>"
+func parseEventID(id string) (ms, seq uint64, ok bool) {
+	parts := strings.SplitN(id, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	ms, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err = strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return ms, seq, true
+}
+
+// eventAfter reports whether stream id a is newer than b
+func eventAfter(a, b string) bool {
+	aMs, aSeq, _ := parseEventID(a)
+	bMs, bSeq, _ := parseEventID(b)
+	if aMs != bMs {
+		return aMs > bMs
+	}
+
+	return aSeq > bSeq
+}
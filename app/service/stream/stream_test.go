@@ -0,0 +1,166 @@
+package stream_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	streamSrv "banking/app/service/stream"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	redisModel "banking/model/redis"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func initialStreamService(t *testing.T) (*domainMock.MockIRedisStreamCommandRepo, *domainMock.MockIRedisStreamQueryRepo) {
+	global.Logger = zap.NewNop().Sugar()
+	viper.Set("stream.bufferSize", 2)
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	return domainMock.NewMockIRedisStreamCommandRepo(ctrl), domainMock.NewMockIRedisStreamQueryRepo(ctrl)
+}
+
+func receive(t *testing.T, events <-chan *redisModel.BalanceEvent) *redisModel.BalanceEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return nil
+	}
+}
+
+func Test_Publish_Transfer(t *testing.T) {
+	mockCmdRepo, mockQueryRepo := initialStreamService(t)
+
+	transaction := &mysqlModel.Transaction{
+		FromUserID:      1,
+		FromUserBalance: decimal.NewFromFloat(89.5),
+		ToUserID:        2,
+		ToUserBalance:   decimal.NewFromFloat(110),
+		Amount:          decimal.NewFromFloat(10),
+		Fee:             decimal.NewFromFloat(0.5),
+		TransactionType: mysqlModel.Transfer,
+	}
+
+	var published []*redisModel.BalanceEvent
+	mockCmdRepo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *redisModel.BalanceEvent) error {
+		published = append(published, event)
+		return nil
+	}).Times(2)
+
+	srv := streamSrv.NewStreamService(mockCmdRepo, mockQueryRepo)
+	srv.Publish(context.Background(), transaction)
+
+	require.Len(t, published, 2)
+	// each user only sees the own balance
+	assert.Equal(t, uint(1), published[0].UserID)
+	assert.True(t, published[0].Balance.Equal(transaction.FromUserBalance))
+	assert.Equal(t, uint(2), published[1].UserID)
+	assert.True(t, published[1].Balance.Equal(transaction.ToUserBalance))
+	assert.Equal(t, redisModel.TransactionEvent, published[1].Type)
+	assert.Equal(t, string(mysqlModel.Transfer), published[1].Transaction.TransactionType)
+}
+
+func Test_Publish_Deposit(t *testing.T) {
+	mockCmdRepo, mockQueryRepo := initialStreamService(t)
+
+	transaction := &mysqlModel.Transaction{
+		FromUserID:      1,
+		FromUserBalance: decimal.NewFromFloat(100),
+		ToUserID:        1,
+		ToUserBalance:   decimal.NewFromFloat(100),
+		Amount:          decimal.NewFromFloat(100),
+		TransactionType: mysqlModel.Deposit,
+	}
+	mockCmdRepo.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	srv := streamSrv.NewStreamService(mockCmdRepo, mockQueryRepo)
+	srv.Publish(context.Background(), transaction)
+}
+
+func Test_Subscribe_Resume(t *testing.T) {
+	mockCmdRepo, mockQueryRepo := initialStreamService(t)
+
+	live := make(chan *redisModel.BalanceEvent)
+	mockQueryRepo.EXPECT().SubscribeEvents(gomock.Any()).Return((<-chan *redisModel.BalanceEvent)(live), nil)
+	mockQueryRepo.EXPECT().GetEventsAfter(gomock.Any(), uint(1), "100-0").Return([]*redisModel.BalanceEvent{
+		{ID: "100-1", UserID: 1},
+		{ID: "101-0", UserID: 1},
+	}, nil)
+
+	srv := streamSrv.NewStreamService(mockCmdRepo, mockQueryRepo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := srv.Subscribe(ctx, 1, "100-0")
+	require.NoError(t, err)
+
+	assert.Equal(t, "100-1", receive(t, events).ID)
+	assert.Equal(t, "101-0", receive(t, events).ID)
+
+	// the replayed event arriving live again is skipped, other users are not delivered
+	live <- &redisModel.BalanceEvent{ID: "101-0", UserID: 1}
+	live <- &redisModel.BalanceEvent{ID: "101-1", UserID: 2}
+	live <- &redisModel.BalanceEvent{ID: "102-0", UserID: 1}
+	assert.Equal(t, "102-0", receive(t, events).ID)
+
+	cancel()
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func Test_Subscribe_InvalidEventID(t *testing.T) {
+	mockCmdRepo, mockQueryRepo := initialStreamService(t)
+
+	srv := streamSrv.NewStreamService(mockCmdRepo, mockQueryRepo)
+	_, err := srv.Subscribe(context.Background(), 1, "latest")
+
+	assert.ErrorIs(t, err, streamSrv.ErrInvalidEventID)
+}
+
+func Test_Subscribe_SlowSubscriber(t *testing.T) {
+	mockCmdRepo, mockQueryRepo := initialStreamService(t)
+
+	live := make(chan *redisModel.BalanceEvent)
+	mockQueryRepo.EXPECT().SubscribeEvents(gomock.Any()).Return((<-chan *redisModel.BalanceEvent)(live), nil)
+
+	srv := streamSrv.NewStreamService(mockCmdRepo, mockQueryRepo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := srv.Subscribe(ctx, 1, "")
+	require.NoError(t, err)
+
+	// nobody reads, the forwarder holds one event and the buffer of 2 fills up
+	for i := 1; i <= 5; i++ {
+		live <- &redisModel.BalanceEvent{ID: fmt.Sprintf("200-%d", i), UserID: 1}
+	}
+
+	// the subscriber was dropped, the queued events are drained then the channel closes
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("slow subscriber was not closed")
+		}
+	}
+}
@@ -2,50 +2,283 @@ package transaction
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
 
 	"banking/domain"
+	"banking/global"
+	"banking/metrics"
 	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
 
 	"github.com/shopspring/decimal"
-	"go.elastic.co/apm/v2"
 )
 
+var (
+	ErrMemoInvalid              = domain.NewError(domain.CodeInvalidRequest, fmt.Sprintf("memo must be at most %d letters, digits, spaces or .,:;!?'()&/#@+%%_- characters", mysqlModel.MaxMemoLength))
+	ErrExternalReferenceInvalid = domain.NewError(domain.CodeInvalidRequest, fmt.Sprintf("externalReference must be 1 to %d letters, digits or ._:/- characters", mysqlModel.MaxExternalReferenceLength))
+)
+
+var (
+	memoPattern              = regexp.MustCompile(`^[\p{L}\p{M}\p{N} .,:;!?'()&/#@+%_-]*$`)
+	externalReferencePattern = regexp.MustCompile(fmt.Sprintf(`^[A-Za-z0-9._:/-]{1,%d}$`, mysqlModel.MaxExternalReferenceLength))
+)
+
+// transferAuditRequest is the requested change recorded as the before value of an audit entry
+type transferAuditRequest struct {
+	ToUserID          uint            `json:"toUserId,omitempty"`
+	Amount            decimal.Decimal `json:"amount"`
+	ExternalReference string          `json:"externalReference,omitempty"`
+}
+
 type transactionService struct {
 	transactionCmdRepo   domain.ITransactionCommandRepo
 	transactionQueryRepo domain.ITransactionQueryRepo
+	userQryRepo          domain.IUserQueryRepo
+	fraudService         domain.IFraudService
+	watchlistService     domain.IWatchlistService
+	auditService         domain.IAuditService
+	feeService           domain.IFeeService
+	streamService        domain.IStreamService
+	readRouter           domain.IReadRouter
 }
 
-func NewTransactionService(TransactionCmdRepo domain.ITransactionCommandRepo, TransactionQueryRepo domain.ITransactionQueryRepo) domain.ITransactionService {
+func NewTransactionService(TransactionCmdRepo domain.ITransactionCommandRepo, TransactionQueryRepo domain.ITransactionQueryRepo, UserQryRepo domain.IUserQueryRepo, FraudService domain.IFraudService, WatchlistService domain.IWatchlistService, AuditService domain.IAuditService, FeeService domain.IFeeService, StreamService domain.IStreamService, ReadRouter domain.IReadRouter) domain.ITransactionService {
 	return &transactionService{
 		transactionCmdRepo:   TransactionCmdRepo,
 		transactionQueryRepo: TransactionQueryRepo,
+		userQryRepo:          UserQryRepo,
+		fraudService:         FraudService,
+		watchlistService:     WatchlistService,
+		auditService:         AuditService,
+		feeService:           FeeService,
+		streamService:        StreamService,
+		readRouter:           ReadRouter,
 	}
 }
 
-func (s *transactionService) Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error) {
-	span, ctx := apm.StartSpan(ctx, "userService.Transfer", "service")
+func (s *transactionService) Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "userService.Transfer", "service")
 	defer span.End()
+	defer func() {
+		metrics.ObserveTransaction(mysqlModel.Transfer, amount, err)
+	}()
+
+	// the repo appends the audit entry in the database transaction of the transfer
+	target, request := fmt.Sprintf("user:%d", fromUserID), transferAuditRequest{ToUserID: toUserID, Amount: amount, ExternalReference: externalReference(reference)}
+	ctx = s.auditService.Prepare(ctx, mysqlModel.AuditTransfer, target, request)
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditTransfer, target, request, transaction, err)
+	}()
 
-	return s.transactionCmdRepo.Transfer(ctx, fromUserID, toUserID, amount)
+	if err := ValidateReference(reference); err != nil {
+		return nil, err
+	}
+
+	// sanctions screening of both counterparties
+	for _, userID := range []uint{fromUserID, toUserID} {
+		if _, err := s.watchlistService.ScreenUser(ctx, userID, mysqlModel.ScreeningTransfer); err != nil {
+			return nil, err
+		}
+	}
+
+	// held or blocked transfers are returned as fraud screening errors, an approved review was screened already
+	if utils.ApprovedReviewFromContext(ctx) == 0 {
+		if _, err := s.fraudService.ScreenTransfer(ctx, fromUserID, toUserID, amount, reference); err != nil {
+			return nil, err
+		}
+	}
+
+	fee, err := s.feeService.Quote(ctx, fromUserID, mysqlModel.Transfer, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction, err = s.transactionCmdRepo.Transfer(ctx, fromUserID, toUserID, amount, fee, reference); err != nil {
+		return nil, err
+	}
+	// both balances changed, the next reads of either user must see the transfer
+	s.readRouter.Pin(ctx, fromUserID, toUserID)
+	s.streamService.Publish(ctx, transaction)
+
+	return transaction, nil
 }
 
-func (s *transactionService) Deposit(ctx context.Context, userID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error) {
-	span, ctx := apm.StartSpan(ctx, "userService.Deposit", "service")
+// ApproveReview executes a held transfer as a regular transfer without fraud screening, the repo links
+// the review to the transaction in the database transaction of the transfer
+func (s *transactionService) ApproveReview(ctx context.Context, reviewID, adminID uint) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "userService.ApproveReview", "service")
 	defer span.End()
 
-	return s.transactionCmdRepo.Deposit(ctx, userID, amount)
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditFraudReviewApprove, fmt.Sprintf("fraudReview:%d", reviewID), mysqlModel.ReviewPending, transaction, err)
+	}()
+
+	review, err := s.fraudService.ClaimReview(ctx, reviewID, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the fee is quoted at approval, a held transfer pays the schedule in force when it executes
+	transaction, err = s.Transfer(utils.ContextWithApprovedReview(ctx, reviewID), review.FromUserID, review.ToUserID, review.Amount, review.Reference())
+	if err != nil {
+		if releaseErr := s.fraudService.ReleaseReview(ctx, reviewID); releaseErr != nil {
+			global.LoggerFromContext(ctx).Errorf("revert fraud review %d to pending error: %s", reviewID, releaseErr)
+		}
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func (s *transactionService) Deposit(ctx context.Context, userID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "userService.Deposit", "service")
+	defer span.End()
+	defer func() {
+		metrics.ObserveTransaction(mysqlModel.Deposit, amount, err)
+	}()
+
+	target, request := fmt.Sprintf("user:%d", userID), transferAuditRequest{Amount: amount, ExternalReference: externalReference(reference)}
+	ctx = s.auditService.Prepare(ctx, mysqlModel.AuditDeposit, target, request)
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditDeposit, target, request, transaction, err)
+	}()
+
+	if err := ValidateReference(reference); err != nil {
+		return nil, err
+	}
+
+	if transaction, err = s.transactionCmdRepo.Deposit(ctx, userID, amount, reference); err != nil {
+		return nil, err
+	}
+	s.readRouter.Pin(ctx, userID)
+	s.streamService.Publish(ctx, transaction)
+
+	return transaction, nil
 }
 
-func (s *transactionService) Withdraw(ctx context.Context, userID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error) {
-	span, ctx := apm.StartSpan(ctx, "userService.Withdraw", "service")
+func (s *transactionService) Withdraw(ctx context.Context, userID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "userService.Withdraw", "service")
 	defer span.End()
+	defer func() {
+		metrics.ObserveTransaction(mysqlModel.Withdraw, amount, err)
+	}()
+
+	target, request := fmt.Sprintf("user:%d", userID), transferAuditRequest{Amount: amount, ExternalReference: externalReference(reference)}
+	ctx = s.auditService.Prepare(ctx, mysqlModel.AuditWithdraw, target, request)
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditWithdraw, target, request, transaction, err)
+	}()
+
+	if err := ValidateReference(reference); err != nil {
+		return nil, err
+	}
+
+	fee, err := s.feeService.Quote(ctx, userID, mysqlModel.Withdraw, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction, err = s.transactionCmdRepo.Withdraw(ctx, userID, amount, fee, reference); err != nil {
+		return nil, err
+	}
+	s.readRouter.Pin(ctx, userID)
+	s.streamService.Publish(ctx, transaction)
 
-	return s.transactionCmdRepo.Withdraw(ctx, userID, amount)
+	return transaction, nil
 }
 
 func (s *transactionService) GetTransactions(ctx context.Context, userID uint) (transactions []*mysqlModel.Transaction, err error) {
-	span, ctx := apm.StartSpan(ctx, "userService.GetTransactions", "service")
+	span, ctx := tracing.StartSpan(ctx, "userService.GetTransactions", "service")
+	defer span.End()
+
+	transactions, err = s.transactionQueryRepo.GetTransactions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.resolveNames(ctx, transactions); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+func (s *transactionService) GetTransactionsByReference(ctx context.Context, userID uint, reference string) (transactions []*mysqlModel.Transaction, err error) {
+	span, ctx := tracing.StartSpan(ctx, "userService.GetTransactionsByReference", "service")
 	defer span.End()
 
-	return s.transactionQueryRepo.GetTransactions(ctx, userID)
+	transactions, err = s.transactionQueryRepo.GetTransactionsByReference(ctx, userID, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.resolveNames(ctx, transactions); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+func (s *transactionService) QuoteFee(ctx context.Context, userID uint, transactionType mysqlModel.TransactionType, amount decimal.Decimal) (fee *mysqlModel.FeeBreakdown, err error) {
+	span, ctx := tracing.StartSpan(ctx, "userService.QuoteFee", "service")
+	defer span.End()
+
+	return s.feeService.Quote(ctx, userID, transactionType, amount)
+}
+
+// resolveNames sets the names of the users of transactions, with one read for all of them
+func (s *transactionService) resolveNames(ctx context.Context, transactions []*mysqlModel.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	userIDs := []uint{}
+	seen := make(map[uint]bool)
+	for _, transaction := range transactions {
+		for _, userID := range []uint{transaction.FromUserID, transaction.ToUserID} {
+			if !seen[userID] {
+				seen[userID] = true
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+
+	names, err := s.userQryRepo.GetUserNames(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, transaction := range transactions {
+		transaction.FromUserName = names[transaction.FromUserID]
+		transaction.ToUserName = names[transaction.ToUserID]
+	}
+	return nil
+}
+
+// ValidateReference checks the memo and external reference a client wrote, a nil reference is valid.
+// The memo is free text shown to both users, so it is limited to printable characters
+func ValidateReference(reference *mysqlModel.TransactionReference) error {
+	if reference == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(reference.Memo) > mysqlModel.MaxMemoLength || !memoPattern.MatchString(reference.Memo) {
+		return ErrMemoInvalid
+	}
+
+	if reference.ExternalReference != "" && !externalReferencePattern.MatchString(reference.ExternalReference) {
+		return ErrExternalReferenceInvalid
+	}
+
+	return nil
+}
+
+func externalReference(reference *mysqlModel.TransactionReference) string {
+	if reference == nil {
+		return ""
+	}
+	return reference.ExternalReference
 }
@@ -0,0 +1,274 @@
+package transaction_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"banking/app/repo/memory"
+	fraudRepo "banking/app/repo/mysql/fraud"
+	transactionRepo "banking/app/repo/mysql/transaction"
+	userRepo "banking/app/repo/mysql/user"
+	auditSrv "banking/app/service/audit"
+	fraudSrv "banking/app/service/fraud"
+	transactionSrv "banking/app/service/transaction"
+	watchlistSrv "banking/app/service/watchlist"
+	"banking/domain"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type transactionMocks struct {
+	queryRepo *domainMock.MockITransactionQueryRepo
+	userRepo  *domainMock.MockIUserQueryRepo
+	audit     *domainMock.MockIAuditService
+}
+
+func initialTransactionService(t *testing.T) (domain.ITransactionService, *transactionMocks) {
+	global.Logger = zap.NewNop().Sugar()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	mocks := &transactionMocks{
+		queryRepo: domainMock.NewMockITransactionQueryRepo(ctrl),
+		userRepo:  domainMock.NewMockIUserQueryRepo(ctrl),
+		audit:     domainMock.NewMockIAuditService(ctrl),
+	}
+	service := transactionSrv.NewTransactionService(
+		domainMock.NewMockITransactionCommandRepo(ctrl),
+		mocks.queryRepo,
+		mocks.userRepo,
+		domainMock.NewMockIFraudService(ctrl),
+		domainMock.NewMockIWatchlistService(ctrl),
+		mocks.audit,
+		domainMock.NewMockIFeeService(ctrl),
+		domainMock.NewMockIStreamService(ctrl),
+		domainMock.NewMockIReadRouter(ctrl),
+	)
+	return service, mocks
+}
+
+func Test_ValidateReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference *mysqlModel.TransactionReference
+		want      error
+	}{
+		{name: "none"},
+		{name: "memo and external reference", reference: &mysqlModel.TransactionReference{Memo: "Loyer mars, appt #4 (50%)", ExternalReference: "INV-2024/03_1"}},
+		{name: "memo of 140 characters", reference: &mysqlModel.TransactionReference{Memo: strings.Repeat("é", mysqlModel.MaxMemoLength)}},
+		{name: "memo too long", reference: &mysqlModel.TransactionReference{Memo: strings.Repeat("a", mysqlModel.MaxMemoLength+1)}, want: transactionSrv.ErrMemoInvalid},
+		{name: "memo with markup", reference: &mysqlModel.TransactionReference{Memo: "<script>"}, want: transactionSrv.ErrMemoInvalid},
+		{name: "memo with a newline", reference: &mysqlModel.TransactionReference{Memo: "rent\nmarch"}, want: transactionSrv.ErrMemoInvalid},
+		{name: "external reference with a space", reference: &mysqlModel.TransactionReference{ExternalReference: "INV 1"}, want: transactionSrv.ErrExternalReferenceInvalid},
+		{name: "external reference too long", reference: &mysqlModel.TransactionReference{ExternalReference: strings.Repeat("1", mysqlModel.MaxExternalReferenceLength+1)}, want: transactionSrv.ErrExternalReferenceInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, transactionSrv.ValidateReference(tt.reference))
+		})
+	}
+}
+
+func Test_Transfer_InvalidReference(t *testing.T) {
+	service, mocks := initialTransactionService(t)
+
+	// rejected before any screening, and audited
+	mocks.audit.EXPECT().Prepare(gomock.Any(), mysqlModel.AuditTransfer, "user:1", gomock.Any()).Return(context.Background())
+	mocks.audit.EXPECT().Record(gomock.Any(), mysqlModel.AuditTransfer, "user:1", gomock.Any(), gomock.Any(), transactionSrv.ErrMemoInvalid)
+
+	_, err := service.Transfer(context.Background(), 1, 2, decimal.NewFromFloat(10), &mysqlModel.TransactionReference{Memo: "<b>"})
+	assert.ErrorIs(t, err, transactionSrv.ErrMemoInvalid)
+}
+
+func Test_Transfer_UnknownUser(t *testing.T) {
+	global.Logger = zap.NewNop().Sugar()
+	viper.Set("watchlist.enabled", true)
+	viper.Set("watchlist.files", []string{})
+	t.Cleanup(func() {
+		viper.Set("watchlist.enabled", false)
+	})
+
+	ctrl := gomock.NewController(t)
+	store := memory.NewStore()
+	sender := &mysqlModel.User{Name: "alice", Email: "alice@yopmail.com", Password: "password", Balance: decimal.NewFromFloat(100)}
+	require.NoError(t, memory.NewUserCommandRepo(store).CreateUser(context.Background(), sender))
+
+	// screening is on by default, it is the first to read the missing counterparty
+	userQryRepo := memory.NewUserQueryRepo(store)
+	audit := domainMock.NewMockIAuditService(ctrl)
+	service := transactionSrv.NewTransactionService(
+		domainMock.NewMockITransactionCommandRepo(ctrl),
+		domainMock.NewMockITransactionQueryRepo(ctrl),
+		userQryRepo,
+		domainMock.NewMockIFraudService(ctrl),
+		watchlistSrv.NewWatchlistService(memory.NewWatchlistCommandRepo(store), userQryRepo),
+		audit,
+		domainMock.NewMockIFeeService(ctrl),
+		domainMock.NewMockIStreamService(ctrl),
+		domainMock.NewMockIReadRouter(ctrl),
+	)
+
+	audit.EXPECT().Prepare(gomock.Any(), mysqlModel.AuditTransfer, fmt.Sprintf("user:%d", sender.ID), gomock.Any()).Return(context.Background())
+	audit.EXPECT().Record(gomock.Any(), mysqlModel.AuditTransfer, fmt.Sprintf("user:%d", sender.ID), gomock.Any(), gomock.Any(), userRepo.ErrUserNotFound)
+
+	_, err := service.Transfer(context.Background(), sender.ID, sender.ID+1, decimal.NewFromFloat(10), nil)
+	assert.ErrorIs(t, err, userRepo.ErrUserNotFound)
+}
+
+// initialApproveService runs the approval on the memory store with fraud screening on and holding
+// every transfer, so an approval only succeeds without screening, and with the audit log of the store
+func initialApproveService(t *testing.T) (domain.ITransactionService, *memory.Store, []*mysqlModel.User, *mysqlModel.FraudReview) {
+	global.Logger = zap.NewNop().Sugar()
+	viper.Set("fraud.enabled", true)
+	t.Cleanup(func() {
+		viper.Set("fraud.enabled", false)
+	})
+
+	ctrl := gomock.NewController(t)
+	store := memory.NewStore()
+	users := []*mysqlModel.User{
+		{Name: "alice", Email: "alice@yopmail.com", Password: "password", Balance: decimal.NewFromFloat(100)},
+		{Name: "bob", Email: "bob@yopmail.com", Password: "password"},
+	}
+	for _, user := range users {
+		require.NoError(t, memory.NewUserCommandRepo(store).CreateUser(context.Background(), user))
+	}
+	review := &mysqlModel.FraudReview{FromUserID: users[0].ID, ToUserID: users[1].ID, Amount: decimal.NewFromFloat(30), Memo: "rent", Score: 60, Decision: mysqlModel.Review, Status: mysqlModel.ReviewPending}
+	require.NoError(t, memory.NewFraudCommandRepo(store).CreateReview(context.Background(), review))
+
+	audit := auditSrv.NewAuditService(memory.NewAuditCommandRepo(store), memory.NewAuditQueryRepo(store))
+	watchlist := domainMock.NewMockIWatchlistService(ctrl)
+	watchlist.EXPECT().ScreenUser(gomock.Any(), gomock.Any(), mysqlModel.ScreeningTransfer).Return(nil, nil).AnyTimes()
+	fee := domainMock.NewMockIFeeService(ctrl)
+	fee.EXPECT().Quote(gomock.Any(), gomock.Any(), mysqlModel.Transfer, gomock.Any()).Return(nil, nil).AnyTimes()
+	stream := domainMock.NewMockIStreamService(ctrl)
+	stream.EXPECT().Publish(gomock.Any(), gomock.Any()).AnyTimes()
+	readRouter := domainMock.NewMockIReadRouter(ctrl)
+	readRouter.EXPECT().Pin(gomock.Any(), gomock.Any()).AnyTimes()
+
+	service := transactionSrv.NewTransactionService(
+		memory.NewTransactionCommandRepo(store),
+		memory.NewTransactionQueryRepo(store),
+		memory.NewUserQueryRepo(store),
+		fraudSrv.NewFraudService(memory.NewFraudCommandRepo(store), memory.NewFraudQueryRepo(store), audit),
+		watchlist,
+		audit,
+		fee,
+		stream,
+		readRouter,
+	)
+	return service, store, users, review
+}
+
+func Test_ApproveReview(t *testing.T) {
+	service, store, users, review := initialApproveService(t)
+
+	// without approval the transfer is held
+	_, err := service.Transfer(context.Background(), users[0].ID, users[1].ID, review.Amount, nil)
+	require.ErrorIs(t, err, fraudSrv.ErrTransferBlocked)
+
+	transaction, err := service.ApproveReview(context.Background(), review.ID, 9)
+	require.NoError(t, err)
+	assert.Equal(t, "rent", transaction.Details)
+	assert.True(t, transaction.FromUserBalance.Equal(decimal.NewFromFloat(70)))
+
+	got, err := memory.NewFraudQueryRepo(store).GetReview(context.Background(), review.ID)
+	require.NoError(t, err)
+	assert.Equal(t, mysqlModel.ReviewApproved, got.Status)
+	require.NotNil(t, got.TransactionID)
+	assert.Equal(t, transaction.ID, *got.TransactionID)
+
+	// a second approval does not execute the transfer again
+	_, err = service.ApproveReview(context.Background(), review.ID, 9)
+	assert.ErrorIs(t, err, fraudRepo.ErrReviewNotPending)
+
+	// the transfer appended its entry with the transaction, once
+	logs, err := memory.NewAuditQueryRepo(store).GetAuditLogs(context.Background(), 0, mysqlModel.AuditTransfer, time.Time{}, time.Time{}, 0, 0)
+	require.NoError(t, err)
+	var succeeded []*mysqlModel.AuditLog
+	for _, log := range logs {
+		if log.Outcome == mysqlModel.AuditSuccess {
+			succeeded = append(succeeded, log)
+		}
+	}
+	require.Len(t, succeeded, 1)
+	assert.Contains(t, succeeded[0].After, fmt.Sprintf(`"ID":%d`, transaction.ID))
+}
+
+func Test_ApproveReview_TransferFailed(t *testing.T) {
+	service, store, users, review := initialApproveService(t)
+
+	// the sender spent the money while the transfer was held
+	_, err := memory.NewTransactionCommandRepo(store).Withdraw(context.Background(), users[0].ID, decimal.NewFromFloat(90), nil, nil)
+	require.NoError(t, err)
+
+	_, err = service.ApproveReview(context.Background(), review.ID, 9)
+	assert.ErrorIs(t, err, transactionRepo.ErrInsufficientBalance)
+
+	got, err := memory.NewFraudQueryRepo(store).GetReview(context.Background(), review.ID)
+	require.NoError(t, err)
+	assert.Equal(t, mysqlModel.ReviewPending, got.Status)
+	assert.Nil(t, got.TransactionID)
+}
+
+func Test_GetTransactions(t *testing.T) {
+	transactions := func() []*mysqlModel.Transaction {
+		return []*mysqlModel.Transaction{
+			{Model: gorm.Model{ID: 1}, FromUserID: 1, ToUserID: 2, Details: "rent", ExternalReference: "INV-1"},
+			{Model: gorm.Model{ID: 2}, FromUserID: 1, ToUserID: 3},
+		}
+	}
+
+	t.Run("names of the counterparties", func(t *testing.T) {
+		service, mocks := initialTransactionService(t)
+
+		mocks.queryRepo.EXPECT().GetTransactions(gomock.Any(), uint(1)).Return(transactions(), nil)
+		// one read for all users, a deleted user has no name
+		mocks.userRepo.EXPECT().GetUserNames(gomock.Any(), []uint{1, 2, 3}).Return(map[uint]string{1: "alice", 2: "bob"}, nil)
+
+		got, err := service.GetTransactions(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "alice", got[0].FromUserName)
+		assert.Equal(t, "bob", got[0].ToUserName)
+		assert.Equal(t, "", got[1].ToUserName)
+	})
+
+	t.Run("by reference", func(t *testing.T) {
+		service, mocks := initialTransactionService(t)
+
+		mocks.queryRepo.EXPECT().GetTransactionsByReference(gomock.Any(), uint(1), "INV-1").Return(transactions()[:1], nil)
+		mocks.userRepo.EXPECT().GetUserNames(gomock.Any(), []uint{1, 2}).Return(map[uint]string{1: "alice", 2: "bob"}, nil)
+
+		got, err := service.GetTransactionsByReference(context.Background(), 1, "INV-1")
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, "bob", got[0].ToUserName)
+	})
+
+	t.Run("no transactions", func(t *testing.T) {
+		service, mocks := initialTransactionService(t)
+
+		mocks.queryRepo.EXPECT().GetTransactionsByReference(gomock.Any(), uint(1), "missing").Return([]*mysqlModel.Transaction{}, nil)
+
+		got, err := service.GetTransactionsByReference(context.Background(), 1, "missing")
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
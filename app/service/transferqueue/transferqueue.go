@@ -0,0 +1,300 @@
+package transferqueue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	transferQueueRepo "banking/app/repo/mysql/transferqueue"
+	fraudSrv "banking/app/service/fraud"
+	transactionSrv "banking/app/service/transaction"
+	"banking/domain"
+	"banking/global"
+	"banking/metrics"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrAsyncDisabled          = domain.NewError(domain.CodeInvalidRequest, "async transfers are disabled")
+	ErrCallbackURLInvalid     = domain.NewError(domain.CodeInvalidRequest, "callbackUrl must be an absolute http or https url")
+	ErrCallbackHostNotAllowed = domain.NewError(domain.CodeInvalidRequest, "callbackUrl host is not allowed")
+)
+
+// errInterrupted is recorded for requests whose worker stopped between claim and finish and whose
+// transfer was not found, a worker which is only slow may still commit it
+const errInterrupted = "transfer was interrupted, look for a transaction with the request id before retrying"
+
+// Callback is the body posted to the callbackUrl of a request once it left the queue
+type Callback struct {
+	TransferID    uint                             `json:"transferId"`
+	Status        mysqlModel.TransferRequestStatus `json:"status"`
+	TransactionID *uint                            `json:"transactionId,omitempty"`
+	ReviewID      *uint                            `json:"reviewId,omitempty"`
+	ErrorCode     string                           `json:"errorCode,omitempty"`
+	Error         string                           `json:"error,omitempty"`
+	RequestID     string                           `json:"requestId"`
+}
+
+type transferQueueService struct {
+	transferQueueCmdRepo   domain.ITransferQueueCommandRepo
+	transferQueueQueryRepo domain.ITransferQueueQueryRepo
+	transactionService     domain.ITransactionService
+	enabled                bool
+	workers                int
+	pollInterval           time.Duration
+	staleAfter             time.Duration
+	callbackHosts          map[string]bool
+	client                 *http.Client
+}
+
+func NewTransferQueueService(TransferQueueCmdRepo domain.ITransferQueueCommandRepo, TransferQueueQueryRepo domain.ITransferQueueQueryRepo, TransactionService domain.ITransactionService) domain.ITransferQueueService {
+	callbackHosts := make(map[string]bool)
+	for _, host := range viper.GetStringSlice("transfer.async.callbackHosts") {
+		callbackHosts[host] = true
+	}
+
+	return &transferQueueService{
+		transferQueueCmdRepo:   TransferQueueCmdRepo,
+		transferQueueQueryRepo: TransferQueueQueryRepo,
+		transactionService:     TransactionService,
+		enabled:                viper.GetBool("transfer.async.enabled"),
+		workers:                max(viper.GetInt("transfer.async.workers"), 1),
+		pollInterval:           max(time.Duration(viper.GetInt("transfer.async.pollInterval"))*time.Millisecond, 10*time.Millisecond),
+		staleAfter:             max(time.Duration(viper.GetInt("transfer.async.staleAfter"))*time.Second, time.Second),
+		callbackHosts:          callbackHosts,
+		client: &http.Client{
+			Timeout: time.Duration(viper.GetInt("transfer.async.callbackTimeout")) * time.Second,
+			// a redirect could lead anywhere, only the allowed host of the callback url is called
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Enqueue accepts the transfer for a worker, it is screened and executed later as the request of ctx.
+// The reference is checked now, a client learns of an invalid one from the response
+func (s *transferQueueService) Enqueue(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference, callbackURL string) (request *mysqlModel.TransferRequest, err error) {
+	span, ctx := tracing.StartSpan(ctx, "transferQueueService.Enqueue", "service")
+	defer span.End()
+
+	if !s.enabled {
+		return nil, ErrAsyncDisabled
+	}
+
+	if callbackURL != "" {
+		if err := s.checkCallbackURL(callbackURL); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := transactionSrv.ValidateReference(reference); err != nil {
+		return nil, err
+	}
+
+	request = &mysqlModel.TransferRequest{
+		FromUserID:  fromUserID,
+		ToUserID:    toUserID,
+		Amount:      amount,
+		APIKey:      utils.APIKeyFromContext(ctx),
+		ClientIP:    utils.ClientIPFromContext(ctx),
+		RequestID:   utils.RequestIDFromContext(ctx),
+		CallbackURL: callbackURL,
+	}
+	if reference != nil {
+		request.Memo, request.ExternalReference = reference.Memo, reference.ExternalReference
+	}
+	if err := s.transferQueueCmdRepo.Enqueue(ctx, request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// GetTransferRequest returns a request of userID, the requests of other users are not found
+func (s *transferQueueService) GetTransferRequest(ctx context.Context, userID, requestID uint) (request *mysqlModel.TransferRequest, err error) {
+	span, ctx := tracing.StartSpan(ctx, "transferQueueService.GetTransferRequest", "service")
+	defer span.End()
+
+	request, err = s.transferQueueQueryRepo.GetTransferRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.FromUserID != userID {
+		return nil, transferQueueRepo.ErrTransferRequestNotFound
+	}
+
+	return request, nil
+}
+
+func (s *transferQueueService) ProcessNext(ctx context.Context) (request *mysqlModel.TransferRequest, err error) {
+	span, ctx := tracing.StartSpan(ctx, "transferQueueService.ProcessNext", "service")
+	defer span.End()
+
+	request, err = s.transferQueueCmdRepo.Claim(ctx)
+	if err != nil || request == nil {
+		return nil, err
+	}
+	metrics.ObserveQueueLag(metrics.QueueTransfer, request.CreatedAt)
+
+	// the transfer runs as the request which enqueued it, a shutdown does not cut it short
+	ctx = utils.ContextWithRequestID(context.WithoutCancel(ctx), request.RequestID)
+	ctx = utils.ContextWithAPIKey(ctx, request.APIKey)
+	ctx = utils.ContextWithClientIP(ctx, request.ClientIP)
+	ctx = utils.ContextWithActor(ctx, &utils.Actor{UserID: request.FromUserID, AuthMethod: utils.AuthMethodAPIKey})
+
+	transaction, err := s.transactionService.Transfer(ctx, request.FromUserID, request.ToUserID, request.Amount, request.Reference())
+	settle(ctx, request, transaction, err)
+
+	if err := s.transferQueueCmdRepo.Finish(ctx, request); err != nil {
+		return nil, err
+	}
+
+	if request.CallbackURL != "" {
+		s.callback(ctx, request)
+	}
+
+	return request, nil
+}
+
+func (s *transferQueueService) Run(ctx context.Context) {
+	if !s.enabled {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(s.staleAfter)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+			completed, failed, err := s.transferQueueCmdRepo.FailStale(ctx, time.Now().Add(-s.staleAfter), domain.CodeTransferInterrupted, errInterrupted)
+			if err != nil {
+				global.Logger.Errorf("fail stale transfer requests error: %s", err)
+			}
+			if completed > 0 {
+				global.Logger.Infof("completed %d transfer requests claimed more than %s ago, their transfer was committed", completed, s.staleAfter)
+			}
+			if failed > 0 {
+				global.Logger.Warnf("failed %d transfer requests claimed more than %s ago", failed, s.staleAfter)
+			}
+		}
+	}
+}
+
+// work processes requests until ctx is done and waits pollInterval whenever the queue is empty
+func (s *transferQueueService) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		request, err := s.ProcessNext(ctx)
+		if err != nil && ctx.Err() == nil {
+			global.Logger.Errorf("process transfer request error: %s", err)
+		}
+		if request != nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// settle records the outcome of the transfer on the request. Only coded errors are shown to clients,
+// the others are logged and recorded as internal errors
+func settle(ctx context.Context, request *mysqlModel.TransferRequest, transaction *mysqlModel.Transaction, err error) {
+	var screeningErr *fraudSrv.ScreeningError
+	var domainErr *domain.Error
+	switch {
+	case err == nil:
+		request.Status, request.TransactionID = mysqlModel.TransferCompleted, &transaction.ID
+	case errors.As(err, &screeningErr) && !errors.Is(err, fraudSrv.ErrTransferBlocked):
+		request.Status, request.ReviewID = mysqlModel.TransferHeld, &screeningErr.Review.ID
+	case errors.As(err, &domainErr):
+		request.Status, request.ErrorCode, request.ErrorMessage = mysqlModel.TransferFailed, string(domainErr.Code), domainErr.Msg
+	default:
+		global.LoggerFromContext(ctx).Errorf("transfer request %d error: %s", request.ID, err)
+		request.Status, request.ErrorCode, request.ErrorMessage = mysqlModel.TransferFailed, string(domain.CodeInternal), http.StatusText(http.StatusInternalServerError)
+	}
+}
+
+// checkCallbackURL only accepts the hosts of transfer.async.callbackHosts, so the workers cannot be
+// made to call internal services
+func (s *transferQueueService) checkCallbackURL(callbackURL string) error {
+	parsed, err := url.Parse(callbackURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrCallbackURLInvalid
+	}
+
+	if !s.callbackHosts[parsed.Hostname()] {
+		return ErrCallbackHostNotAllowed
+	}
+
+	return nil
+}
+
+// callback posts the outcome once, clients which miss it poll the request
+func (s *transferQueueService) callback(ctx context.Context, request *mysqlModel.TransferRequest) {
+	span, ctx := tracing.StartSpan(ctx, "transferQueueService.callback", "service")
+	defer span.End()
+
+	body, err := json.Marshal(&Callback{
+		TransferID:    request.ID,
+		Status:        request.Status,
+		TransactionID: request.TransactionID,
+		ReviewID:      request.ReviewID,
+		ErrorCode:     request.ErrorCode,
+		Error:         request.ErrorMessage,
+		RequestID:     request.RequestID,
+	})
+	if err != nil {
+		global.LoggerFromContext(ctx).Errorf("transfer request %d callback error: %s", request.ID, err)
+		return
+	}
+
+	err = func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.CallbackURL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(utils.RequestIDHeader, request.RequestID)
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	}()
+	if err != nil {
+		global.LoggerFromContext(ctx).Warnf("transfer request %d callback error: %s", request.ID, err)
+	}
+}
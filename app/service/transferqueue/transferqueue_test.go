@@ -0,0 +1,250 @@
+package transferqueue_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	transferQueueRepo "banking/app/repo/mysql/transferqueue"
+	fraudSrv "banking/app/service/fraud"
+	transactionSrv "banking/app/service/transaction"
+	transferQueueSrv "banking/app/service/transferqueue"
+	"banking/domain"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/utils"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func initialTransferQueueService(t *testing.T, enabled bool) (domain.ITransferQueueService, *domainMock.MockITransferQueueCommandRepo, *domainMock.MockITransferQueueQueryRepo, *domainMock.MockITransactionService) {
+	global.Logger = zap.NewNop().Sugar()
+
+	viper.Set("transfer.async.enabled", enabled)
+	viper.Set("transfer.async.callbackHosts", []string{"127.0.0.1"})
+	viper.Set("transfer.async.callbackTimeout", 1)
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	mockCmdRepo := domainMock.NewMockITransferQueueCommandRepo(ctrl)
+	mockQueryRepo := domainMock.NewMockITransferQueueQueryRepo(ctrl)
+	mockTransaction := domainMock.NewMockITransactionService(ctrl)
+	return transferQueueSrv.NewTransferQueueService(mockCmdRepo, mockQueryRepo, mockTransaction), mockCmdRepo, mockQueryRepo, mockTransaction
+}
+
+func Test_Enqueue(t *testing.T) {
+	amount := decimal.NewFromFloat(10)
+
+	t.Run("keeps the request of ctx", func(t *testing.T) {
+		service, mockCmdRepo, _, _ := initialTransferQueueService(t, true)
+
+		ctx := utils.ContextWithRequestID(context.Background(), "req-1")
+		ctx = utils.ContextWithAPIKey(ctx, "key")
+		ctx = utils.ContextWithClientIP(ctx, "10.0.0.1")
+		mockCmdRepo.EXPECT().Enqueue(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, request *mysqlModel.TransferRequest) error {
+			assert.Equal(t, "req-1", request.RequestID)
+			assert.Equal(t, "rent", request.Memo)
+			assert.Equal(t, "key", request.APIKey)
+			assert.Equal(t, "10.0.0.1", request.ClientIP)
+			request.ID = 7
+			return nil
+		})
+
+		request, err := service.Enqueue(ctx, 1, 2, amount, &mysqlModel.TransactionReference{Memo: "rent"}, "https://127.0.0.1/hook")
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), request.ID)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		service, _, _, _ := initialTransferQueueService(t, false)
+
+		_, err := service.Enqueue(context.Background(), 1, 2, amount, nil, "")
+		assert.ErrorIs(t, err, transferQueueSrv.ErrAsyncDisabled)
+	})
+
+	t.Run("invalid reference", func(t *testing.T) {
+		service, _, _, _ := initialTransferQueueService(t, true)
+
+		_, err := service.Enqueue(context.Background(), 1, 2, amount, &mysqlModel.TransactionReference{ExternalReference: "INV 1"}, "")
+		assert.ErrorIs(t, err, transactionSrv.ErrExternalReferenceInvalid)
+	})
+
+	t.Run("callback url", func(t *testing.T) {
+		tests := []struct {
+			url  string
+			want error
+		}{
+			{url: "ftp://127.0.0.1/hook", want: transferQueueSrv.ErrCallbackURLInvalid},
+			{url: "/hook", want: transferQueueSrv.ErrCallbackURLInvalid},
+			{url: "http://169.254.169.254/latest", want: transferQueueSrv.ErrCallbackHostNotAllowed},
+		}
+
+		service, _, _, _ := initialTransferQueueService(t, true)
+		for _, tt := range tests {
+			_, err := service.Enqueue(context.Background(), 1, 2, amount, nil, tt.url)
+			assert.ErrorIs(t, err, tt.want, tt.url)
+		}
+	})
+}
+
+func Test_GetTransferRequest(t *testing.T) {
+	service, _, mockQueryRepo, _ := initialTransferQueueService(t, true)
+
+	request := &mysqlModel.TransferRequest{Model: gorm.Model{ID: 3}, FromUserID: 1}
+	mockQueryRepo.EXPECT().GetTransferRequest(gomock.Any(), uint(3)).Return(request, nil).Times(2)
+
+	got, err := service.GetTransferRequest(context.Background(), 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, request, got)
+
+	// the requests of other users are not found
+	_, err = service.GetTransferRequest(context.Background(), 2, 3)
+	assert.ErrorIs(t, err, transferQueueRepo.ErrTransferRequestNotFound)
+}
+
+func Test_ProcessNext(t *testing.T) {
+	claimed := func() *mysqlModel.TransferRequest {
+		return &mysqlModel.TransferRequest{
+			Model:             gorm.Model{ID: 5},
+			FromUserID:        1,
+			ToUserID:          2,
+			Amount:            decimal.NewFromFloat(10),
+			Status:            mysqlModel.TransferProcessing,
+			RequestID:         "req-5",
+			ExternalReference: "INV-5",
+		}
+	}
+
+	t.Run("empty queue", func(t *testing.T) {
+		service, mockCmdRepo, _, _ := initialTransferQueueService(t, true)
+
+		mockCmdRepo.EXPECT().Claim(gomock.Any()).Return(nil, nil)
+
+		request, err := service.ProcessNext(context.Background())
+		assert.NoError(t, err)
+		assert.Nil(t, request)
+	})
+
+	tests := []struct {
+		name          string
+		transaction   *mysqlModel.Transaction
+		err           error
+		wantStatus    mysqlModel.TransferRequestStatus
+		wantErrorCode string
+	}{
+		{name: "completed", transaction: &mysqlModel.Transaction{Model: gorm.Model{ID: 9}}, wantStatus: mysqlModel.TransferCompleted},
+		{
+			name:       "held for review",
+			err:        &fraudSrv.ScreeningError{Review: &mysqlModel.FraudReview{Model: gorm.Model{ID: 4}, Decision: mysqlModel.Review}},
+			wantStatus: mysqlModel.TransferHeld,
+		},
+		{
+			name:          "blocked",
+			err:           &fraudSrv.ScreeningError{Review: &mysqlModel.FraudReview{Model: gorm.Model{ID: 4}, Decision: mysqlModel.Block}},
+			wantStatus:    mysqlModel.TransferFailed,
+			wantErrorCode: string(domain.CodeTransferBlocked),
+		},
+		{
+			name:          "insufficient balance",
+			err:           domain.NewError(domain.CodeInsufficientBalance, "insufficient balance"),
+			wantStatus:    mysqlModel.TransferFailed,
+			wantErrorCode: string(domain.CodeInsufficientBalance),
+		},
+		{name: "internal error", err: errors.New("connection refused"), wantStatus: mysqlModel.TransferFailed, wantErrorCode: string(domain.CodeInternal)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockCmdRepo, _, mockTransaction := initialTransferQueueService(t, true)
+
+			mockCmdRepo.EXPECT().Claim(gomock.Any()).Return(claimed(), nil)
+			mockTransaction.EXPECT().Transfer(gomock.Any(), uint(1), uint(2), decimal.NewFromFloat(10), gomock.Any()).DoAndReturn(func(ctx context.Context, _, _ uint, _ decimal.Decimal, reference *mysqlModel.TransactionReference) (*mysqlModel.Transaction, error) {
+				assert.Equal(t, "INV-5", reference.ExternalReference)
+				// executed as the request which enqueued it
+				assert.Equal(t, "req-5", utils.RequestIDFromContext(ctx))
+				assert.Equal(t, uint(1), utils.ActorFromContext(ctx).UserID)
+				return tt.transaction, tt.err
+			})
+			mockCmdRepo.EXPECT().Finish(gomock.Any(), gomock.Any()).Return(nil)
+
+			request, err := service.ProcessNext(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, request.Status)
+			assert.Equal(t, tt.wantErrorCode, request.ErrorCode)
+			assert.NotContains(t, request.ErrorMessage, "connection refused")
+		})
+	}
+
+	t.Run("posts the callback", func(t *testing.T) {
+		service, mockCmdRepo, _, mockTransaction := initialTransferQueueService(t, true)
+
+		received := make(chan *transferQueueSrv.Callback, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			callback := &transferQueueSrv.Callback{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(callback))
+			assert.Equal(t, "req-5", r.Header.Get(utils.RequestIDHeader))
+			received <- callback
+		}))
+		defer server.Close()
+
+		request := claimed()
+		request.CallbackURL = server.URL
+		mockCmdRepo.EXPECT().Claim(gomock.Any()).Return(request, nil)
+		mockTransaction.EXPECT().Transfer(gomock.Any(), uint(1), uint(2), gomock.Any(), gomock.Any()).Return(&mysqlModel.Transaction{Model: gorm.Model{ID: 9}}, nil)
+		mockCmdRepo.EXPECT().Finish(gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := service.ProcessNext(context.Background())
+		require.NoError(t, err)
+
+		callback := <-received
+		assert.Equal(t, uint(5), callback.TransferID)
+		assert.Equal(t, mysqlModel.TransferCompleted, callback.Status)
+		assert.Equal(t, uint(9), *callback.TransactionID)
+	})
+
+	t.Run("does not follow redirects of the callback", func(t *testing.T) {
+		service, mockCmdRepo, _, mockTransaction := initialTransferQueueService(t, true)
+
+		redirected := false
+		internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			redirected = true
+		}))
+		defer internal.Close()
+		server := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusTemporaryRedirect))
+		defer server.Close()
+
+		request := claimed()
+		request.CallbackURL = server.URL
+		mockCmdRepo.EXPECT().Claim(gomock.Any()).Return(request, nil)
+		mockTransaction.EXPECT().Transfer(gomock.Any(), uint(1), uint(2), gomock.Any(), gomock.Any()).Return(&mysqlModel.Transaction{Model: gorm.Model{ID: 9}}, nil)
+		mockCmdRepo.EXPECT().Finish(gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := service.ProcessNext(context.Background())
+		require.NoError(t, err)
+		assert.False(t, redirected)
+	})
+
+	t.Run("request failed as stale meanwhile", func(t *testing.T) {
+		service, mockCmdRepo, _, mockTransaction := initialTransferQueueService(t, true)
+
+		mockCmdRepo.EXPECT().Claim(gomock.Any()).Return(claimed(), nil)
+		mockTransaction.EXPECT().Transfer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&mysqlModel.Transaction{}, nil)
+		mockCmdRepo.EXPECT().Finish(gomock.Any(), gomock.Any()).Return(transferQueueRepo.ErrTransferRequestNotProcessing)
+
+		_, err := service.ProcessNext(context.Background())
+		assert.ErrorIs(t, err, transferQueueRepo.ErrTransferRequestNotProcessing)
+	})
+}
@@ -2,25 +2,27 @@ package user
 
 import (
 	"context"
-	"errors"
 	"time"
 
 	"banking/domain"
+	"banking/metrics"
 	mysqlModel "banking/model/mysql"
+	"banking/tracing"
 	"banking/utils"
 
 	"github.com/go-redis/redis/v8"
-	"go.elastic.co/apm/v2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var ErrPasswordIncorrect = errors.New("password incorrect")
+var ErrPasswordIncorrect = domain.NewError(domain.CodeInvalidCredentials, "password incorrect")
 
 type userService struct {
 	userQryRepo       domain.IUserQueryRepo
 	userCmdRepo       domain.IUserCommandRepo
 	jwtRedisCmdRepo   domain.IRedisJWTCommandRepo
 	jwtRedisQueryRepo domain.IRedisJWTQueryRepo
+	watchlistService  domain.IWatchlistService
+	auditService      domain.IAuditService
 }
 
 // add database repo here
@@ -29,19 +31,28 @@ func NewUserService(
 	UserQryRepo domain.IUserQueryRepo,
 	JWTRedisCmdRepo domain.IRedisJWTCommandRepo,
 	JWTRedisQueryRepo domain.IRedisJWTQueryRepo,
+	WatchlistService domain.IWatchlistService,
+	AuditService domain.IAuditService,
 ) domain.IUserService {
 	return &userService{
 		userQryRepo:       UserQryRepo,
 		userCmdRepo:       UserCmdRepo,
 		jwtRedisCmdRepo:   JWTRedisCmdRepo,
 		jwtRedisQueryRepo: JWTRedisQueryRepo,
+		watchlistService:  WatchlistService,
+		auditService:      AuditService,
 	}
 }
 
 func (s *userService) CreateUser(ctx context.Context, user *mysqlModel.User) (err error) {
-	span, ctx := apm.StartSpan(ctx, "userService.CreateUser", "service")
+	span, ctx := tracing.StartSpan(ctx, "userService.CreateUser", "service")
 	defer span.End()
 
+	// sanctions screening, flagged names are recorded but may still register
+	if _, err := s.watchlistService.ScreenName(ctx, user.Name, nil, mysqlModel.ScreeningRegistration); err != nil {
+		return err
+	}
+
 	// hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -54,9 +65,18 @@ func (s *userService) CreateUser(ctx context.Context, user *mysqlModel.User) (er
 }
 
 func (s *userService) Login(ctx context.Context, email, password string) (token string, err error) {
-	span, ctx := apm.StartSpan(ctx, "userService.Login", "service")
+	span, ctx := tracing.StartSpan(ctx, "userService.Login", "service")
 	defer span.End()
 
+	// failed attempts are audited as well, the caller is not authenticated yet so the email is the target
+	defer func() {
+		s.auditService.Record(ctx, mysqlModel.AuditUserLogin, "user:"+email, nil, nil, err)
+		// unknown emails and wrong passwords are the only coded errors of a login
+		if metrics.Outcome(err) == metrics.OutcomeRejected {
+			metrics.ObserveAuthFailure(metrics.AuthMethodPassword)
+		}
+	}()
+
 	// Check if token exists in redis
 	tokenRedis, err := s.jwtRedisQueryRepo.GetRedisJWT(ctx, email)
 	if err != redis.Nil && err != nil {
@@ -78,7 +98,7 @@ func (s *userService) Login(ctx context.Context, email, password string) (token
 	}
 
 	// Generate token
-	token, err = utils.GenerateJWT(user.ID, email, user.IsAdmin)
+	token, err = utils.GenerateJWT(user.ID, email, user.IsAdmin, user.IsAuditor)
 	if err != nil {
 		return "", err
 	}
@@ -92,7 +112,7 @@ func (s *userService) Login(ctx context.Context, email, password string) (token
 }
 
 func (s *userService) GetUsers(ctx context.Context, userID uint) (users []*mysqlModel.User, err error) {
-	span, ctx := apm.StartSpan(ctx, "userService.GetUsers", "service")
+	span, ctx := tracing.StartSpan(ctx, "userService.GetUsers", "service")
 	defer span.End()
 
 	// Simulate slow query for demo
@@ -0,0 +1,219 @@
+package watchlist
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidWatchlist = errors.New("invalid watchlist file")
+
+// Entry is one sanctioned or watched party
+type Entry struct {
+	ID      string
+	Name    string
+	Aliases []string
+	Source  string
+}
+
+type indexedName struct {
+	entry    *Entry
+	name     string
+	variants []string
+}
+
+// List is an immutable in-memory watchlist, safe for concurrent use
+type List struct {
+	names []*indexedName
+}
+
+// Match is the best scoring watchlist name for a screened name
+type Match struct {
+	Entry *Entry
+	Name  string
+	Score float64
+}
+
+// LoadList reads all watchlist files, the format is chosen by the .csv or .xml extension
+func LoadList(paths []string) (*List, error) {
+	list := &List{}
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		source := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		var entries []*Entry
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			entries, err = parseCSV(file, source)
+		case ".xml":
+			entries, err = parseXML(file, source)
+		default:
+			err = fmt.Errorf("%w: unsupported extension %s", ErrInvalidWatchlist, filepath.Ext(path))
+		}
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		for _, entry := range entries {
+			list.add(entry)
+		}
+	}
+
+	return list, nil
+}
+
+// NewList builds a list from entries already in memory
+func NewList(entries ...*Entry) *List {
+	list := &List{}
+	for _, entry := range entries {
+		list.add(entry)
+	}
+
+	return list
+}
+
+func (l *List) add(entry *Entry) {
+	for _, name := range append([]string{entry.Name}, entry.Aliases...) {
+		normalized := NormalizeName(name)
+		if normalized == "" {
+			continue
+		}
+
+		l.names = append(l.names, &indexedName{
+			entry:    entry,
+			name:     name,
+			variants: nameVariants(normalized),
+		})
+	}
+}
+
+// Size returns the number of indexed names including aliases
+func (l *List) Size() int {
+	return len(l.names)
+}
+
+// BestMatch returns the highest scoring name, nil for an empty list or name
+func (l *List) BestMatch(name string) *Match {
+	normalized := NormalizeName(name)
+	if normalized == "" {
+		return nil
+	}
+	variants := nameVariants(normalized)
+
+	var best *Match
+	for _, indexed := range l.names {
+		score := 0.0
+		for i, variant := range variants {
+			score = max(score, JaroWinkler(variant, indexed.variants[i]))
+		}
+
+		if best == nil || score > best.Score {
+			best = &Match{Entry: indexed.entry, Name: indexed.name, Score: score}
+		}
+	}
+
+	return best
+}
+
+// parseCSV reads the header "id,name,aliases,source", aliases are separated by ";" and source is optional
+func parseCSV(r io.Reader, defaultSource string) ([]*Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidWatchlist, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := columns["id"]; !ok {
+		return nil, fmt.Errorf("%w: missing id column", ErrInvalidWatchlist)
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("%w: missing name column", ErrInvalidWatchlist)
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []*Entry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidWatchlist, err)
+		}
+
+		entry := &Entry{
+			ID:     field(record, "id"),
+			Name:   field(record, "name"),
+			Source: field(record, "source"),
+		}
+		if entry.Source == "" {
+			entry.Source = defaultSource
+		}
+		for _, alias := range strings.Split(field(record, "aliases"), ";") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				entry.Aliases = append(entry.Aliases, alias)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+type xmlWatchlist struct {
+	XMLName xml.Name `xml:"watchlist"`
+	Source  string   `xml:"source,attr"`
+	Entries []struct {
+		ID      string   `xml:"id,attr"`
+		Name    string   `xml:"name"`
+		Aliases []string `xml:"alias"`
+	} `xml:"entry"`
+}
+
+// parseXML reads <watchlist source=".."><entry id=".."><name/><alias/>...</entry></watchlist>
+func parseXML(r io.Reader, defaultSource string) ([]*Entry, error) {
+	doc := &xmlWatchlist{}
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidWatchlist, err)
+	}
+
+	source := doc.Source
+	if source == "" {
+		source = defaultSource
+	}
+
+	entries := make([]*Entry, 0, len(doc.Entries))
+	for _, e := range doc.Entries {
+		entries = append(entries, &Entry{
+			ID:      e.ID,
+			Name:    strings.TrimSpace(e.Name),
+			Aliases: e.Aliases,
+			Source:  source,
+		})
+	}
+
+	return entries, nil
+}
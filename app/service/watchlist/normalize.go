@@ -0,0 +1,40 @@
+package watchlist
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeName lowercases the name, strips diacritics and punctuation and collapses whitespace,
+// so "  José  O'Brien " becomes "jose o brien"
+func NormalizeName(name string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		stripped = name
+	}
+
+	fields := strings.FieldsFunc(strings.ToLower(stripped), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(fields, " ")
+}
+
+// nameVariants returns the forms of a normalized name compared during matching: as written,
+// with tokens sorted to ignore word order and without spaces since registration names are single words
+func nameVariants(normalized string) []string {
+	tokens := strings.Fields(normalized)
+	sorted := append([]string(nil), tokens...)
+	sort.Strings(sorted)
+
+	return []string{
+		normalized,
+		strings.Join(sorted, " "),
+		strings.Join(tokens, ""),
+	}
+}
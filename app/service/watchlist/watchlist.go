@@ -0,0 +1,102 @@
+package watchlist
+
+import (
+	"context"
+	"fmt"
+
+	"banking/domain"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
+
+	"github.com/spf13/viper"
+)
+
+var ErrWatchlistMatch = domain.NewError(domain.CodeComplianceRejected, "rejected by compliance screening")
+
+type watchlistService struct {
+	watchlistCmdRepo domain.IWatchlistCommandRepo
+	userQryRepo      domain.IUserQueryRepo
+	list             *List
+	enabled          bool
+	flagThreshold    float64
+	blockThreshold   float64
+}
+
+// NewWatchlistService loads the configured watchlist files once, a broken file stops the server from starting
+func NewWatchlistService(WatchlistCmdRepo domain.IWatchlistCommandRepo, UserQryRepo domain.IUserQueryRepo) domain.IWatchlistService {
+	list, err := LoadList(viper.GetStringSlice("watchlist.files"))
+	if err != nil {
+		panic(fmt.Sprintf("Load watchlist error: %s\n", err))
+	}
+
+	return &watchlistService{
+		watchlistCmdRepo: WatchlistCmdRepo,
+		userQryRepo:      UserQryRepo,
+		list:             list,
+		enabled:          viper.GetBool("watchlist.enabled"),
+		flagThreshold:    viper.GetFloat64("watchlist.flagThreshold"),
+		blockThreshold:   viper.GetFloat64("watchlist.blockThreshold"),
+	}
+}
+
+// ScreenName fuzzy matches the name against the watchlist and records the result,
+// names scoring at least the block threshold return ErrWatchlistMatch
+func (s *watchlistService) ScreenName(ctx context.Context, name string, userID *uint, screeningContext mysqlModel.ScreeningContext) (result *mysqlModel.ScreeningResult, err error) {
+	span, ctx := tracing.StartSpan(ctx, "watchlistService.ScreenName", "service")
+	defer span.End()
+
+	if !s.enabled {
+		return nil, nil
+	}
+
+	result = &mysqlModel.ScreeningResult{
+		UserID:   userID,
+		Name:     name,
+		Context:  screeningContext,
+		Decision: mysqlModel.ScreeningClear,
+	}
+
+	if match := s.list.BestMatch(name); match != nil {
+		result.Score = match.Score
+		if match.Score >= s.flagThreshold {
+			result.EntryID = match.Entry.ID
+			result.EntryName = match.Name
+			result.EntrySource = match.Entry.Source
+			result.Decision = mysqlModel.ScreeningFlag
+		}
+		if match.Score >= s.blockThreshold {
+			result.Decision = mysqlModel.ScreeningBlock
+		}
+	}
+
+	if err := s.watchlistCmdRepo.CreateScreeningResult(ctx, result); err != nil {
+		return nil, err
+	}
+
+	if result.Decision != mysqlModel.ScreeningClear {
+		global.LoggerFromContext(ctx).Warnf("watchlist %s on %s for %q, entry %s/%s score %.4f", result.Decision, screeningContext, name, result.EntrySource, result.EntryID, result.Score)
+	}
+
+	if result.Decision == mysqlModel.ScreeningBlock {
+		return result, ErrWatchlistMatch
+	}
+
+	return result, nil
+}
+
+func (s *watchlistService) ScreenUser(ctx context.Context, userID uint, screeningContext mysqlModel.ScreeningContext) (result *mysqlModel.ScreeningResult, err error) {
+	span, ctx := tracing.StartSpan(ctx, "watchlistService.ScreenUser", "service")
+	defer span.End()
+
+	if !s.enabled {
+		return nil, nil
+	}
+
+	users, err := s.userQryRepo.GetUsers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ScreenName(ctx, users[0].Name, &userID, screeningContext)
+}
@@ -0,0 +1,91 @@
+package watchlist_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	watchlistSrv "banking/app/service/watchlist"
+	domainMock "banking/domain/mock"
+	"banking/global"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func Test_JaroWinkler(t *testing.T) {
+	assert.Equal(t, 1.0, watchlistSrv.JaroWinkler("martha", "martha"))
+	assert.InDelta(t, 0.961, watchlistSrv.JaroWinkler("martha", "marhta"), 0.001)
+	assert.InDelta(t, 0.840, watchlistSrv.JaroWinkler("dwayne", "duane"), 0.001)
+	assert.Equal(t, 0.0, watchlistSrv.JaroWinkler("abc", ""))
+}
+
+func Test_NormalizeName(t *testing.T) {
+	assert.Equal(t, "jose o brien", watchlistSrv.NormalizeName("  José  O'Brien "))
+	assert.Equal(t, "muller", watchlistSrv.NormalizeName("MÜLLER"))
+}
+
+func Test_LoadList(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "sdn.csv")
+	xmlPath := filepath.Join(dir, "pep.xml")
+
+	assert.NoError(t, os.WriteFile(csvPath, []byte("id,name,aliases\nS-1,Ivan Petrov,Ivan Petroff;I. Petrov\n"), 0o600))
+	assert.NoError(t, os.WriteFile(xmlPath, []byte(`<watchlist source="PEP"><entry id="P-1"><name>Maria Gonzalez</name><alias>Maria Gonzales</alias></entry></watchlist>`), 0o600))
+
+	list, err := watchlistSrv.LoadList([]string{csvPath, xmlPath})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, list.Size())
+
+	// word order and missing spaces do not hide a match
+	match := list.BestMatch("IvanPetrov")
+	assert.Equal(t, "S-1", match.Entry.ID)
+	assert.Equal(t, "sdn", match.Entry.Source)
+	assert.Equal(t, 1.0, match.Score)
+
+	match = list.BestMatch("Gonzalez Maria")
+	assert.Equal(t, "P-1", match.Entry.ID)
+	assert.Equal(t, "PEP", match.Entry.Source)
+	assert.Equal(t, 1.0, match.Score)
+
+	_, err = watchlistSrv.LoadList([]string{filepath.Join(dir, "list.json")})
+	assert.Error(t, err)
+}
+
+func Test_ScreenName(t *testing.T) {
+	global.Logger = zap.NewNop().Sugar()
+
+	path := filepath.Join(t.TempDir(), "sdn.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("id,name\nS-1,Ivan Petrov\n"), 0o600))
+
+	viper.Set("watchlist.enabled", true)
+	viper.Set("watchlist.flagThreshold", 0.85)
+	viper.Set("watchlist.blockThreshold", 0.93)
+	viper.Set("watchlist.files", []string{path})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCmdRepo := domainMock.NewMockIWatchlistCommandRepo(ctrl)
+	mockUserQryRepo := domainMock.NewMockIUserQueryRepo(ctrl)
+	mockCmdRepo.EXPECT().CreateScreeningResult(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	srv := watchlistSrv.NewWatchlistService(mockCmdRepo, mockUserQryRepo)
+
+	result, err := srv.ScreenName(context.Background(), "IvanPetrov", nil, mysqlModel.ScreeningRegistration)
+	assert.ErrorIs(t, err, watchlistSrv.ErrWatchlistMatch)
+	assert.Equal(t, mysqlModel.ScreeningBlock, result.Decision)
+	assert.Equal(t, "S-1", result.EntryID)
+
+	result, err = srv.ScreenName(context.Background(), "Ivo Petrovic", nil, mysqlModel.ScreeningRegistration)
+	assert.NoError(t, err)
+	assert.Equal(t, mysqlModel.ScreeningFlag, result.Decision)
+
+	result, err = srv.ScreenName(context.Background(), "alice", nil, mysqlModel.ScreeningRegistration)
+	assert.NoError(t, err)
+	assert.Equal(t, mysqlModel.ScreeningClear, result.Decision)
+	assert.Equal(t, "", result.EntryID)
+}
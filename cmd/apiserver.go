@@ -6,23 +6,40 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	router "banking/app/api"
+	"banking/app/api/restful/v1/middleware"
+	"banking/app/api/rpc"
+	"banking/app/repo/memory"
 	"banking/database/mysql"
 	"banking/database/redis"
+	"banking/domain"
 	"banking/global"
 	logger "banking/log"
+	mysqlModel "banking/model/mysql"
+	"banking/tracing"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"go.elastic.co/apm/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	storageSQL    = "sql"    // master and slave of database.driver with redis
+	storageMemory = "memory" // in-memory store of one process
+	storageMySQL  = "mysql"  // former name of storageSQL
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -34,10 +51,10 @@ var apiserverCmd = &cobra.Command{
 }
 
 func RunApiserver(cmd *cobra.Command, _ []string) {
-	// apm tracer
-	tracer, err := apm.NewTracer(viper.GetString("apm.serviceName"), "")
+	// tracer of tracing.provider, also used by the spans of handlers, services and repos
+	tracer, err := tracing.Init(cmd.Context())
 	if err != nil {
-		panic(fmt.Sprintf("Init apm error: %s\n", err))
+		panic(fmt.Sprintf("Init tracing error: %s\n", err))
 	}
 
 	// init logger
@@ -59,25 +76,47 @@ func RunApiserver(cmd *cobra.Command, _ []string) {
 		panic(errMsg)
 	}
 
-	// Init MySQL
-	mysql, err := mysql.InitMySQL(cmd.Context())
-	if err != nil {
-		errMsg := fmt.Sprintf("Init MySQL error: %s\n", err)
+	// storage of the services, memory serves the whole API without a database and redis
+	var repos *router.Repos
+	switch storage, _ := cmd.Flags().GetString("storage"); storage {
+	case storageSQL, storageMySQL:
+		repos = initSQLRepos(cmd)
+	case storageMemory:
+		repos = initMemoryRepos(cmd)
+	default:
+		errMsg := fmt.Sprintf("Unknown storage: %s\n", storage)
 		global.Logger.Error(errMsg)
 		panic(errMsg)
 	}
 
-	// Init Redis
-	redis, err := redis.InitRedis(cmd.Context())
-	if err != nil {
-		errMsg := fmt.Sprintf("Init Redis error: %s\n", err)
-		global.Logger.Error(errMsg)
-		panic(errMsg)
+	// services shared by the REST and gRPC servers
+	services := router.InitServices(repos)
+
+	// audit config file changes
+	watchConfig(cmd.Context(), services.Audit)
+
+	// workers of async transfers, every replica claims from the same queue, and the projector of the
+	// ledger events, whose checkpoint lock lets one replica project at a time
+	workerCtx, stopWorkers := context.WithCancel(cmd.Context())
+	workersDone := make(chan struct{})
+	var workers sync.WaitGroup
+	for _, run := range []func(context.Context){services.TransferQueue.Run, services.Projection.Run} {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workerCtx)
+		}()
 	}
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
 
 	// init router
-	engine := gin.Default()
-	r := router.InitRouter(engine, mysql.Master.DB, mysql.Slave.DB, redis.Client, tracer)
+	// the access token of stream clients is taken out of the URL before the access log
+	engine := gin.New()
+	engine.Use(middleware.StripQueryTokenMiddleware(), gin.Logger(), gin.Recovery())
+	r := router.InitRouter(engine, services, tracer)
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", viper.GetInt("server.httpPort")),
 		Handler: r,
@@ -100,18 +139,48 @@ func RunApiserver(cmd *cobra.Command, _ []string) {
 		}
 	}()
 
+	// start gRPC server in goroutine
+	grpcServer := rpc.NewServer(services, tracer)
+	go func() {
+		grpcAddr := fmt.Sprintf(":%d", viper.GetInt("server.grpcPort"))
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			global.Logger.Fatalf("gRPC listen error: %s\n", err)
+		}
+
+		global.Logger.Infof("Start gRPC server %s\n", grpcAddr)
+		if err := grpcServer.Serve(listener); err != nil {
+			global.Logger.Fatalf("gRPC server error: %s\n", err)
+		}
+	}()
+
 	// graceful shutdown server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	global.Logger.Info("Shutdown server ...")
 
+	// fail readiness first and keep serving, so the orchestrator stops routing new requests here
+	services.Health.ShutDown()
+	time.Sleep(time.Duration(viper.GetInt("health.drainDelay")) * time.Second)
+
 	// waiting max 5 seconds, then force shutdown
 	ctx, cancel := context.WithTimeout(cmd.Context(), time.Duration(viper.GetInt("server.shutdownTimeout"))*time.Second)
 	defer cancel()
 	if err := server.Shutdown(ctx); err != nil {
 		global.Logger.Fatalf("Server shutdown error: %s\n", err)
 	}
+	grpcServer.GracefulStop()
+
+	// workers finish the transfers they claimed and take no new ones, a projection in progress rolls back
+	stopWorkers()
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+	}
+	if err := tracer.Shutdown(ctx); err != nil {
+		global.Logger.Errorf("Tracing shutdown error: %s\n", err)
+	}
 
 	// catching ctx.Done()
 	<-ctx.Done()
@@ -119,7 +188,118 @@ func RunApiserver(cmd *cobra.Command, _ []string) {
 	global.Logger.Info("Server exiting")
 }
 
+// initSQLRepos connects to the database of database.driver and redis, the schema has to be migrated already
+func initSQLRepos(cmd *cobra.Command) *router.Repos {
+	master, slave, err := openDatabases(cmd.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Init database error: %s\n", err)
+		global.Logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	// the schema is migrated by the migrate command before a deploy, never on startup
+	if err := checkSchema(cmd.Context(), master); err != nil {
+		errMsg := fmt.Sprintf("Database schema error: %s\n", err)
+		global.Logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	redis, err := redis.InitRedis(cmd.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Init Redis error: %s\n", err)
+		global.Logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	return router.NewSQLRepos(master, slave, redis.Client)
+}
+
+// initMemoryRepos keeps all data in the process until it exits, seeded with the development users.
+// A single process owns the data, so it is refused in release mode
+func initMemoryRepos(cmd *cobra.Command) *router.Repos {
+	if viper.GetString("server.runMode") == gin.ReleaseMode {
+		panic("Storage memory is refused with server.runMode release\n")
+	}
+
+	repos := router.NewMemoryRepos(memory.NewStore())
+
+	password, _ := cmd.Flags().GetString("seed-password")
+	if password == "" {
+		password = randomPassword()
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		panic(err)
+	}
+
+	var created []string
+	for _, user := range mysql.DevelopmentUsers() {
+		user.Password = string(hashedPassword)
+		if err := repos.UserCmd.CreateUser(cmd.Context(), user); err != nil {
+			panic(fmt.Sprintf("Seed users error: %s\n", err))
+		}
+		created = append(created, user.Email)
+	}
+
+	global.Logger.Warn("Storage memory keeps all data in this process, it is lost on exit\n")
+	fmt.Fprintf(cmd.OutOrStdout(), "Created %v with password %s\n", created, password)
+
+	return repos
+}
+
+// watchConfig records every change of the config file in the audit log, only the changed keys
+// are recorded and values of keys holding credentials are redacted
+func watchConfig(ctx context.Context, auditService domain.IAuditService) {
+	settings := configSnapshot()
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		current := configSnapshot()
+
+		before, after := make(map[string]interface{}), make(map[string]interface{})
+		for key, value := range settings {
+			if !reflect.DeepEqual(value, current[key]) {
+				before[key], after[key] = redactConfigValue(key, value), redactConfigValue(key, current[key])
+			}
+		}
+		for key, value := range current {
+			if _, ok := settings[key]; !ok {
+				before[key], after[key] = nil, redactConfigValue(key, value)
+			}
+		}
+		settings = current
+
+		if len(after) == 0 {
+			return
+		}
+
+		global.Logger.Infof("config file %s changed, %d keys updated", e.Name, len(after))
+		auditService.Record(ctx, mysqlModel.AuditConfigChange, "config:"+e.Name, before, after, nil)
+	})
+	viper.WatchConfig()
+}
+
+func configSnapshot() map[string]interface{} {
+	settings := make(map[string]interface{})
+	for _, key := range viper.AllKeys() {
+		settings[key] = viper.Get(key)
+	}
+
+	return settings
+}
+
+func redactConfigValue(key string, value interface{}) interface{} {
+	key = strings.ToLower(key)
+	for _, sensitive := range []string{"password", "secret", "token"} {
+		if strings.Contains(key, sensitive) && value != nil {
+			return "[REDACTED]"
+		}
+	}
+
+	return value
+}
+
 func init() {
 	// Add apiserverCmd to rootCmd, start on terminal: go run main.go apiserver
+	apiserverCmd.Flags().String("storage", storageSQL, "storage of the services, sql or memory")
+	apiserverCmd.Flags().String("seed-password", "", "password of the development users of storage memory (default random, printed once)")
 	rootCmd.AddCommand(apiserverCmd)
 }
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	auditRepo "banking/app/repo/mysql/audit"
+	bucketRepo "banking/app/repo/mysql/bucket"
+	auditSrv "banking/app/service/audit"
+	bucketSrv "banking/app/service/bucket"
+	"banking/global"
+	logger "banking/log"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var bucketCmd = &cobra.Command{
+	Use:   "bucket",
+	Short: "balance buckets of hot accounts",
+	Long:  `balance buckets of hot accounts, the credits of a hot account are spread over its buckets`,
+}
+
+var bucketConsolidateCmd = &cobra.Command{
+	Use:   "consolidate",
+	Short: "consolidate balance buckets",
+	Long:  `move the balances of the buckets of every hot account to its user row, run it periodically`,
+	Run:   RunBucketConsolidate,
+}
+
+func RunBucketConsolidate(cmd *cobra.Command, _ []string) {
+	tracer, err := tracing.Init(cmd.Context())
+	if err != nil {
+		panic(fmt.Sprintf("Init tracing error: %s\n", err))
+	}
+	defer tracing.Default().Shutdown(context.Background()) // flush the spans of the run
+
+	if global.Logger, err = logger.InitLogger(tracer); err != nil {
+		panic(fmt.Sprintf("Init logger error: %s\n", err))
+	}
+
+	master, err := openMaster(cmd.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Init database error: %s\n", err)
+		global.Logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	bucketService := bucketSrv.NewBucketService(
+		bucketRepo.NewBucketCommandRepo(master), // Write operations
+		bucketRepo.NewBucketQueryRepo(master),   // Read operations
+		auditSrv.NewAuditService(auditRepo.NewAuditCommandRepo(master), auditRepo.NewAuditQueryRepo(master)),
+	)
+
+	// one request id per run, so its log entries can be found together
+	ctx := utils.ContextWithRequestID(cmd.Context(), utils.NewRequestID())
+	consolidated, err := bucketService.Consolidate(ctx)
+	if err != nil {
+		global.LoggerFromContext(ctx).Fatalf("Consolidate balance buckets error: %s\n", err)
+	}
+
+	global.LoggerFromContext(ctx).Infof("Consolidated the balance buckets of %d hot accounts\n", consolidated)
+}
+
+func init() {
+	// Add bucketCmd to rootCmd, run on terminal: go run main.go bucket consolidate
+	bucketCmd.AddCommand(bucketConsolidateCmd)
+	rootCmd.AddCommand(bucketCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"banking/database/driver"
+	"banking/database/mysql"
+	"banking/database/postgres"
+	"banking/database/sqlite"
+
+	"gorm.io/gorm"
+)
+
+// openDatabases connects to the master and slave of database.driver, SQLite serves both from one file
+func openDatabases(ctx context.Context) (master, slave *gorm.DB, err error) {
+	switch name := driver.Name(); name {
+	case driver.MySQL:
+		mysql, err := mysql.InitMySQL(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return mysql.Master.DB, mysql.Slave.DB, nil
+	case driver.Postgres:
+		postgres, err := postgres.InitPostgres(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return postgres.Master.DB, postgres.Slave.DB, nil
+	case driver.SQLite:
+		sqlite, err := sqlite.NewSQLite(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sqlite.DB, sqlite.DB, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown database driver %s", name)
+	}
+}
+
+// openMaster connects to the master of database.driver only, for commands which never read the slave
+func openMaster(ctx context.Context) (*gorm.DB, error) {
+	switch name := driver.Name(); name {
+	case driver.MySQL:
+		master, err := mysql.NewMasterDB(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return master.DB, nil
+	case driver.Postgres:
+		master, err := postgres.NewMasterDB(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return master.DB, nil
+	case driver.SQLite:
+		sqlite, err := sqlite.NewSQLite(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return sqlite.DB, nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %s", name)
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	interestRepo "banking/app/repo/mysql/interest"
+	interestSrv "banking/app/service/interest"
+	"banking/domain"
+	"banking/global"
+	logger "banking/log"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var interestCmd = &cobra.Command{
+	Use:   "interest",
+	Short: "interest accrual batch jobs",
+	Long:  `interest accrual batch jobs, run accrue daily and capitalize once a month`,
+}
+
+var interestAccrueCmd = &cobra.Command{
+	Use:   "accrue",
+	Short: "accrue daily interest",
+	Long:  `accrue the interest of one day for every user on a rate plan, re-running a day is a no-op`,
+	Run:   RunInterestAccrue,
+}
+
+var interestCapitalizeCmd = &cobra.Command{
+	Use:   "capitalize",
+	Short: "capitalize monthly interest",
+	Long:  `post the interest accrued in one month to the balances as interest transactions`,
+	Run:   RunInterestCapitalize,
+}
+
+func RunInterestAccrue(cmd *cobra.Command, _ []string) {
+	date := time.Now().AddDate(0, 0, -1)
+	if value, _ := cmd.Flags().GetString("date"); value != "" {
+		var err error
+		if date, err = time.ParseInLocation(time.DateOnly, value, time.Local); err != nil {
+			panic(fmt.Sprintf("Invalid date %s: %s\n", value, err))
+		}
+	}
+
+	interestService := initInterestService(cmd)
+	defer tracing.Default().Shutdown(context.Background()) // flush the spans of the run
+	// one request id per run, so its log entries can be found together
+	ctx := utils.ContextWithRequestID(cmd.Context(), utils.NewRequestID())
+	accrued, err := interestService.AccrueDaily(ctx, date)
+	if err != nil {
+		global.LoggerFromContext(ctx).Fatalf("Accrue interest for %s error: %s\n", date.Format(time.DateOnly), err)
+	}
+
+	global.LoggerFromContext(ctx).Infof("Accrued interest for %s, %d users\n", date.Format(time.DateOnly), accrued)
+}
+
+func RunInterestCapitalize(cmd *cobra.Command, _ []string) {
+	month := time.Now().AddDate(0, -1, 0)
+	if value, _ := cmd.Flags().GetString("month"); value != "" {
+		var err error
+		if month, err = time.ParseInLocation("2006-01", value, time.Local); err != nil {
+			panic(fmt.Sprintf("Invalid month %s: %s\n", value, err))
+		}
+	}
+
+	interestService := initInterestService(cmd)
+	defer tracing.Default().Shutdown(context.Background()) // flush the spans of the run
+	// one request id per run, it is recorded on the interest transactions
+	ctx := utils.ContextWithRequestID(cmd.Context(), utils.NewRequestID())
+	capitalized, err := interestService.Capitalize(ctx, month)
+	if err != nil {
+		global.LoggerFromContext(ctx).Fatalf("Capitalize interest for %s error: %s\n", month.Format("2006-01"), err)
+	}
+
+	global.LoggerFromContext(ctx).Infof("Capitalized interest for %s, %d users\n", month.Format("2006-01"), capitalized)
+}
+
+// initInterestService reads and writes on master so a batch sees its own accruals
+func initInterestService(cmd *cobra.Command) domain.IInterestService {
+	tracer, err := tracing.Init(cmd.Context())
+	if err != nil {
+		panic(fmt.Sprintf("Init tracing error: %s\n", err))
+	}
+
+	if global.Logger, err = logger.InitLogger(tracer); err != nil {
+		panic(fmt.Sprintf("Init logger error: %s\n", err))
+	}
+
+	master, err := openMaster(cmd.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Init database error: %s\n", err)
+		global.Logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	return interestSrv.NewInterestService(
+		interestRepo.NewInterestCommandRepo(master), // Write operations
+		interestRepo.NewInterestQueryRepo(master),   // Read operations
+	)
+}
+
+func init() {
+	// Add interestCmd to rootCmd, run on terminal: go run main.go interest accrue --date 2024-01-31
+	interestAccrueCmd.Flags().String("date", "", "day to accrue, yyyy-mm-dd (default yesterday)")
+	interestCapitalizeCmd.Flags().String("month", "", "month to capitalize, yyyy-mm (default last month)")
+	interestCmd.AddCommand(interestAccrueCmd, interestCapitalizeCmd)
+	rootCmd.AddCommand(interestCmd)
+}
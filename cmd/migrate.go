@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"banking/database/driver"
+	"banking/database/migration"
+	"banking/global"
+	logger "banking/log"
+	"banking/tracing"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "database schema migrations",
+	Long:  `apply and revert the versioned SQL migrations of the master of database.driver, the apiserver does not start while migrations are pending`,
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up [N]",
+	Short: "apply pending migrations",
+	Long:  `apply the next N pending migrations, all of them without N`,
+	Args:  cobra.MaximumNArgs(1),
+	Run:   RunMigrateUp,
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down [N]",
+	Short: "revert applied migrations",
+	Long:  `revert the last N applied migrations, the last one without N`,
+	Args:  cobra.MaximumNArgs(1),
+	Run:   RunMigrateDown,
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "list migrations",
+	Long:  `list every migration and when it was applied`,
+	Args:  cobra.NoArgs,
+	Run:   RunMigrateStatus,
+}
+
+var migrateCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "create a migration",
+	Long:  `create empty up and down files of the next version for database.driver, they are embedded in the binary on the next build. Add the same version for the other drivers too`,
+	Args:  cobra.ExactArgs(1),
+	Run:   RunMigrateCreate,
+}
+
+func RunMigrateUp(cmd *cobra.Command, args []string) {
+	steps := migrateSteps(args, 0)
+	migrator := initMigrator(cmd)
+
+	done, err := migrator.Up(cmd.Context(), steps)
+	for _, m := range done {
+		global.Logger.Infof("Applied migration %04d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		global.Logger.Fatalf("Migrate up error: %s\n", err)
+	}
+
+	global.Logger.Infof("Applied %d migrations\n", len(done))
+}
+
+func RunMigrateDown(cmd *cobra.Command, args []string) {
+	steps := migrateSteps(args, 1)
+	migrator := initMigrator(cmd)
+
+	done, err := migrator.Down(cmd.Context(), steps)
+	for _, m := range done {
+		global.Logger.Infof("Reverted migration %04d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		global.Logger.Fatalf("Migrate down error: %s\n", err)
+	}
+
+	global.Logger.Infof("Reverted %d migrations\n", len(done))
+}
+
+func RunMigrateStatus(cmd *cobra.Command, _ []string) {
+	migrator := initMigrator(cmd)
+
+	statuses, err := migrator.Status(cmd.Context())
+	if err != nil {
+		global.Logger.Fatalf("Migrate status error: %s\n", err)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		switch {
+		case status.Dirty:
+			state = "dirty"
+		case status.Applied:
+			state = "applied"
+		}
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	w.Flush()
+}
+
+func RunMigrateCreate(cmd *cobra.Command, args []string) {
+	dir, _ := cmd.Flags().GetString("dir")
+	if dir == "" {
+		dir = filepath.Join(migration.Dir, driver.Name())
+	}
+
+	upPath, downPath, err := migration.Create(dir, args[0])
+	if err != nil {
+		panic(fmt.Sprintf("Create migration error: %s\n", err))
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Created %s\nCreated %s\n", upPath, downPath)
+}
+
+func migrateSteps(args []string, defaultSteps int) int {
+	if len(args) == 0 {
+		return defaultSteps
+	}
+
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps <= 0 {
+		panic(fmt.Sprintf("Invalid number of migrations %s\n", args[0]))
+	}
+	return steps
+}
+
+// initMigrator connects to the master only, migrations reach the slave by replication
+func initMigrator(cmd *cobra.Command) *migration.Migrator {
+	tracer, err := tracing.Init(cmd.Context())
+	if err != nil {
+		panic(fmt.Sprintf("Init tracing error: %s\n", err))
+	}
+
+	if global.Logger, err = logger.InitLogger(tracer); err != nil {
+		panic(fmt.Sprintf("Init logger error: %s\n", err))
+	}
+
+	master, err := openMaster(cmd.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Init database error: %s\n", err)
+		global.Logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	return newMigrator(master)
+}
+
+func newMigrator(db *gorm.DB) *migration.Migrator {
+	migrations, err := migration.Embedded(driver.Name())
+	if err != nil {
+		panic(fmt.Sprintf("Load migrations error: %s\n", err))
+	}
+
+	return migration.NewMigrator(db, migrations)
+}
+
+// checkSchema fails when the schema of db is behind the migrations of this binary
+func checkSchema(ctx context.Context, db *gorm.DB) error {
+	pending, err := newMigrator(db).Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d migrations are pending, starting with %04d_%s, run migrate up", len(pending), pending[0].Version, pending[0].Name)
+	}
+
+	return nil
+}
+
+func init() {
+	// Add migrateCmd to rootCmd, run on terminal: go run main.go migrate up
+	migrateCreateCmd.Flags().String("dir", "", "directory of the migration files (default "+migration.Dir+"/<database.driver>)")
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd, migrateCreateCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	projectionRepo "banking/app/repo/mysql/projection"
+	projectionSrv "banking/app/service/projection"
+	"banking/global"
+	logger "banking/log"
+	"banking/tracing"
+	"banking/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var projectionCmd = &cobra.Command{
+	Use:   "projections",
+	Short: "balance and transaction history read models",
+	Long:  `balance and transaction history read models, projected from the ledger events by the apiserver`,
+}
+
+var projectionRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "rebuild the read models",
+	Long:  `empty the read models and replay the ledger events into them, readers see the old read models until it is done`,
+	Run:   RunProjectionRebuild,
+}
+
+func RunProjectionRebuild(cmd *cobra.Command, _ []string) {
+	tracer, err := tracing.Init(cmd.Context())
+	if err != nil {
+		panic(fmt.Sprintf("Init tracing error: %s\n", err))
+	}
+	defer tracing.Default().Shutdown(context.Background()) // flush the spans of the run
+
+	if global.Logger, err = logger.InitLogger(tracer); err != nil {
+		panic(fmt.Sprintf("Init logger error: %s\n", err))
+	}
+
+	master, err := openMaster(cmd.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Init database error: %s\n", err)
+		global.Logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	projectionService := projectionSrv.NewProjectionService(
+		projectionRepo.NewProjectionCommandRepo(master), // Write operations
+		projectionRepo.NewProjectionQueryRepo(master),   // Read operations
+	)
+
+	fromSnapshot, _ := cmd.Flags().GetBool("from-snapshot")
+
+	// one request id per run, so its log entries can be found together
+	ctx := utils.ContextWithRequestID(cmd.Context(), utils.NewRequestID())
+	applied, err := projectionService.Rebuild(ctx, fromSnapshot)
+	if err != nil {
+		global.LoggerFromContext(ctx).Fatalf("Rebuild projections error: %s\n", err)
+	}
+
+	global.LoggerFromContext(ctx).Infof("Rebuilt the projections from %d ledger events\n", applied)
+}
+
+func init() {
+	projectionRebuildCmd.Flags().Bool("from-snapshot", false, "start from the latest balance snapshot instead of the first event")
+
+	// Add projectionCmd to rootCmd, run on terminal: go run main.go projections rebuild
+	projectionCmd.AddCommand(projectionRebuildCmd)
+	rootCmd.AddCommand(projectionCmd)
+}
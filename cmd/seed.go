@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"banking/database/mysql"
+	"banking/global"
+	logger "banking/log"
+	"banking/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "create development users",
+	Long:  `create the users of README "Test Data" which do not exist yet, for development only. Refused in release mode`,
+	Args:  cobra.NoArgs,
+	Run:   RunSeed,
+}
+
+func RunSeed(cmd *cobra.Command, _ []string) {
+	if viper.GetString("server.runMode") == gin.ReleaseMode {
+		panic("Seed is refused with server.runMode release\n")
+	}
+
+	tracer, err := tracing.Init(cmd.Context())
+	if err != nil {
+		panic(fmt.Sprintf("Init tracing error: %s\n", err))
+	}
+	if global.Logger, err = logger.InitLogger(tracer); err != nil {
+		panic(fmt.Sprintf("Init logger error: %s\n", err))
+	}
+
+	master, err := openMaster(cmd.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Init database error: %s\n", err)
+		global.Logger.Error(errMsg)
+		panic(errMsg)
+	}
+	if err := checkSchema(cmd.Context(), master); err != nil {
+		global.Logger.Fatalf("Schema error: %s\n", err)
+	}
+
+	// a random password unless one is given, so seeded users never share a known password by accident
+	password, _ := cmd.Flags().GetString("password")
+	if password == "" {
+		password = randomPassword()
+	}
+
+	created, err := mysql.SeedUsers(master, password)
+	if err != nil {
+		global.Logger.Fatalf("Seed users error: %s\n", err)
+	}
+	if len(created) == 0 {
+		global.Logger.Info("All seed users exist already\n")
+		return
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Created %v with password %s\n", created, password)
+}
+
+// randomPassword has 16 hex digits, inside the 8 to 20 characters accepted by login
+func randomPassword() string {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(bytes)
+}
+
+func init() {
+	// Add seedCmd to rootCmd, run on terminal: go run main.go seed --password password
+	seedCmd.Flags().String("password", "", "password of the created users (default random, printed once)")
+	rootCmd.AddCommand(seedCmd)
+}
@@ -0,0 +1,112 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"banking/tracing"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// Names of the SQL backends, they match the Name of their gorm dialector
+const (
+	MySQL    = "mysql"
+	Postgres = "postgres"
+	SQLite   = "sqlite"
+)
+
+// Name returns the backend selected by database.driver, MySQL when unset
+func Name() string {
+	name := viper.GetString("database.driver")
+	if name == "" {
+		return MySQL
+	}
+	return name
+}
+
+// TablePrefix returns database.tablePrefix, configs written before the drivers were added set mysql.tablePrefix
+func TablePrefix() string {
+	if viper.IsSet("database.tablePrefix") {
+		return viper.GetString("database.tablePrefix")
+	}
+	return viper.GetString("mysql.tablePrefix")
+}
+
+// Open connects with dialector until a ping succeeds, shared by every backend so the table names,
+// timestamps and tracing do not depend on the driver. dsn is only logged
+func Open(ctx context.Context, dialector gorm.Dialector, dsn string) (*gorm.DB, error) {
+	location, err := time.LoadLocation("UTC")
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Connecting to %s: %s\n", dialector.Name(), dsn)
+
+	var db *gorm.DB
+	err = retry(ctx, func() error {
+		db, err = gorm.Open(dialector, &gorm.Config{
+			NamingStrategy: schema.NamingStrategy{
+				SingularTable: true,
+				TablePrefix:   TablePrefix(),
+			},
+			Logger: logger.Default.LogMode(logger.Info),
+			// errors of every driver come back as gorm.ErrDuplicatedKey and friends
+			TranslateError: true,
+			NowFunc: func() time.Time {
+				return time.Now().In(location)
+			},
+		})
+		if err != nil {
+			return err
+		}
+		sqlDB, errDB := db.DB()
+		if errDB != nil {
+			return errDB
+		}
+
+		// Use the context for the ping operation
+		ctxWT, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		return sqlDB.PingContext(ctxWT)
+	}, 5, 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tracing.Default().InstrumentGORM(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// LockForUpdate locks the rows read by tx until it commits. SQLite has no row locks, its
+// transactions take the database write lock when they begin, see sqlite.NewDB
+func LockForUpdate(tx *gorm.DB) *gorm.DB {
+	if tx.Dialector.Name() == SQLite {
+		return tx
+	}
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+}
+
+func retry(ctx context.Context, action func() error, attempts int, sleep time.Duration) error {
+	for i := 0; i < attempts; i++ {
+		err := action()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sleep):
+		}
+	}
+	return fmt.Errorf("failed after %d attempts", attempts)
+}
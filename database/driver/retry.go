@@ -0,0 +1,74 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"banking/metrics"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Retries of a transaction which lost a lock conflict, the backoff doubles from conflictBackoff and
+// is jittered so the transactions which collided do not collide again
+const (
+	conflictAttempts = 5
+	conflictBackoff  = 10 * time.Millisecond
+)
+
+// MySQL error numbers and PostgreSQL SQLSTATEs of lock conflicts, the transaction was rolled back or
+// its statement gave up waiting and running it again may succeed
+const (
+	mysqlDeadlock        = 1213
+	mysqlLockWaitTimeout = 1205
+
+	postgresDeadlock             = "40P01"
+	postgresSerializationFailure = "40001"
+	postgresLockNotAvailable     = "55P03"
+)
+
+// IsConflict reports whether err is a deadlock or lock timeout of any driver
+func IsConflict(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDeadlock || mysqlErr.Number == mysqlLockWaitTimeout
+	}
+
+	// pgx and lib/pq errors both expose their SQLSTATE
+	var postgresErr interface{ SQLState() string }
+	if errors.As(err, &postgresErr) {
+		switch postgresErr.SQLState() {
+		case postgresDeadlock, postgresSerializationFailure, postgresLockNotAvailable:
+			return true
+		}
+		return false
+	}
+
+	// the sqlite3 error type only exists with cgo, SQLITE_BUSY and SQLITE_LOCKED by their messages
+	return err != nil && (strings.Contains(err.Error(), "database is locked") || strings.Contains(err.Error(), "database table is locked"))
+}
+
+// RetryOnConflict runs the transaction fn again while it fails with a lock conflict, at most
+// conflictAttempts times and until ctx is done. fn has to begin and roll back its own transaction
+func RetryOnConflict(ctx context.Context, operation string, fn func() error) error {
+	backoff := conflictBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt == conflictAttempts || !IsConflict(err) {
+			return err
+		}
+		metrics.ObserveDBRetry(operation)
+
+		// full jitter between half and the whole backoff
+		sleep := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(sleep):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,93 @@
+package driver_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"banking/database/driver"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+// postgresError is a driver error with a SQLSTATE, like the errors of pgx and lib/pq
+type postgresError string
+
+func (e postgresError) Error() string    { return "postgres: " + string(e) }
+func (e postgresError) SQLState() string { return string(e) }
+
+func Test_IsConflict(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "mysql deadlock", err: &mysql.MySQLError{Number: 1213}, want: true},
+		{name: "mysql lock wait timeout", err: fmt.Errorf("transfer: %w", &mysql.MySQLError{Number: 1205}), want: true},
+		{name: "mysql duplicate entry", err: &mysql.MySQLError{Number: 1062}, want: false},
+		{name: "postgres deadlock", err: postgresError("40P01"), want: true},
+		{name: "postgres serialization failure", err: postgresError("40001"), want: true},
+		{name: "postgres unique violation", err: postgresError("23505"), want: false},
+		{name: "sqlite busy", err: errors.New("database is locked"), want: true},
+		{name: "other", err: errors.New("connection refused"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, driver.IsConflict(tt.err))
+		})
+	}
+}
+
+func Test_RetryOnConflict(t *testing.T) {
+	deadlock := &mysql.MySQLError{Number: 1213}
+
+	t.Run("retries until it succeeds", func(t *testing.T) {
+		calls := 0
+		err := driver.RetryOnConflict(context.Background(), "transfer", func() error {
+			calls++
+			if calls < 3 {
+				return deadlock
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		calls := 0
+		other := errors.New("insufficient balance")
+		err := driver.RetryOnConflict(context.Background(), "transfer", func() error {
+			calls++
+			return other
+		})
+		assert.ErrorIs(t, err, other)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		calls := 0
+		err := driver.RetryOnConflict(context.Background(), "transfer", func() error {
+			calls++
+			return deadlock
+		})
+		assert.ErrorIs(t, err, deadlock)
+		assert.Equal(t, 5, calls)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := driver.RetryOnConflict(ctx, "transfer", func() error {
+			calls++
+			cancel()
+			return deadlock
+		})
+		assert.ErrorIs(t, err, deadlock)
+		assert.Equal(t, 1, calls)
+	})
+}
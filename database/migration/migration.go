@@ -0,0 +1,411 @@
+package migration
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"banking/database/driver"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql
+var files embed.FS
+
+// Dir is the source directory of the migrations embedded in the binary, relative to the repository
+// root, with a subdirectory of the same migrations per driver
+const Dir = "database/migration/sql"
+
+// PrefixPlaceholder is replaced by database.tablePrefix in the migration SQL
+const PrefixPlaceholder = "{{prefix}}"
+
+// lockName serializes migrations of several deploys starting together
+const lockName = "banking_migration"
+
+const lockTimeout = 30 // seconds
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	ErrDirty       = errors.New("a migration failed half way, fix the schema by hand and delete its row from the version table")
+	ErrLockTimeout = errors.New("another migration holds the lock")
+)
+
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration is a row of the version table, a dirty row is a migration which started and did not finish
+type SchemaMigration struct {
+	Version   uint64    `gorm:"primarykey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	Dirty     bool      `gorm:"not null"`
+	AppliedAt time.Time `gorm:"precision:3;not null"`
+}
+
+type Status struct {
+	Version   uint64
+	Name      string
+	Applied   bool
+	Dirty     bool
+	AppliedAt *time.Time
+}
+
+// Embedded returns the migrations of driverName shipped with the binary
+func Embedded(driverName string) ([]*Migration, error) {
+	if _, err := fs.Stat(files, path.Join("sql", driverName)); err != nil {
+		return nil, fmt.Errorf("no migrations for driver %s", driverName)
+	}
+
+	fsys, err := fs.Sub(files, path.Join("sql", driverName))
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(fsys)
+}
+
+// Load reads the pairs of NNNN_name.up.sql and NNNN_name.down.sql files of fsys, ordered by version
+func Load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s, expected NNNN_name.up.sql or NNNN_name.down.sql", entry.Name())
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of migration file %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", migration.Name, match[2], version)
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Create writes empty up and down files of the next version in dir
+func Create(dir, name string) (upPath, downPath string, err error) {
+	name = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "_"))
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return "", "", fmt.Errorf("invalid migration name %q, use letters, digits and underscores", name)
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	var version uint64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", version, name))
+	upPath, downPath = base+".up.sql", base+".down.sql"
+	if err := os.WriteFile(upPath, []byte("-- "+name+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(downPath, []byte("-- revert "+name+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+
+	return upPath, downPath, nil
+}
+
+// Migrator applies migrations to the master and records them in the version table. MySQL commits
+// DDL implicitly, so on every driver a migration failing half way is left dirty instead of being
+// rolled back
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+	prefix     string
+}
+
+func NewMigrator(db *gorm.DB, migrations []*Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		prefix:     driver.TablePrefix(),
+	}
+}
+
+// Status returns every known migration and whether it is applied, versions applied by a newer
+// release are included too
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := &Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied, status.Dirty, status.AppliedAt = true, row.Dirty, &row.AppliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range applied {
+		statuses = append(statuses, &Status{Version: row.Version, Name: row.Name, Applied: true, Dirty: row.Dirty, AppliedAt: &row.AppliedAt})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Pending returns the migrations not applied yet, ErrDirty when a migration failed half way
+func (m *Migrator) Pending(ctx context.Context) ([]*Migration, error) {
+	return m.pending(ctx, m.db)
+}
+
+func (m *Migrator) pending(ctx context.Context, db *gorm.DB) ([]*Migration, error) {
+	applied, err := m.applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkDirty(applied); err != nil {
+		return nil, err
+	}
+
+	pending := make([]*Migration, 0)
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies at most steps pending migrations in version order, all of them when steps is 0
+func (m *Migrator) Up(ctx context.Context, steps int) (done []*Migration, err error) {
+	err = m.withLock(ctx, func(db *gorm.DB) error {
+		pending, err := m.pending(ctx, db)
+		if err != nil {
+			return err
+		}
+		if steps > 0 && steps < len(pending) {
+			pending = pending[:steps]
+		}
+
+		for _, migration := range pending {
+			if err := m.run(ctx, db, migration, migration.Up, true); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts the last steps applied migrations, newest first
+func (m *Migrator) Down(ctx context.Context, steps int) (done []*Migration, err error) {
+	err = m.withLock(ctx, func(db *gorm.DB) error {
+		applied, err := m.applied(ctx, db)
+		if err != nil {
+			return err
+		}
+		if err := checkDirty(applied); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.run(ctx, db, migration, migration.Down, false); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// run executes the statements of a migration between marking its version dirty and clean, up
+// inserts the version and down deletes it
+func (m *Migrator) run(ctx context.Context, db *gorm.DB, migration *Migration, script string, up bool) error {
+	versions := db.WithContext(ctx).Table(m.versionTable())
+	row := &SchemaMigration{Version: migration.Version, Name: migration.Name, Dirty: true, AppliedAt: time.Now()}
+	if up {
+		if err := versions.Create(row).Error; err != nil {
+			return err
+		}
+	} else if err := versions.Where("version = ?", migration.Version).Update("dirty", true).Error; err != nil {
+		return err
+	}
+
+	for _, statement := range SplitStatements(strings.ReplaceAll(script, PrefixPlaceholder, m.prefix)) {
+		if err := db.WithContext(ctx).Exec(statement).Error; err != nil {
+			return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	versions = db.WithContext(ctx).Table(m.versionTable()).Where("version = ?", migration.Version)
+	if up {
+		return versions.Updates(map[string]interface{}{"dirty": false, "applied_at": time.Now()}).Error
+	}
+	return versions.Delete(&SchemaMigration{}).Error
+}
+
+// applied reads the version table, which is missing until the first migration runs. Under the lock
+// db is the connection holding it, SQLite has no other connection to read with
+func (m *Migrator) applied(ctx context.Context, db *gorm.DB) (map[uint64]*SchemaMigration, error) {
+	if !db.WithContext(ctx).Migrator().HasTable(m.versionTable()) {
+		return map[uint64]*SchemaMigration{}, nil
+	}
+
+	var rows []*SchemaMigration
+	if err := db.WithContext(ctx).Table(m.versionTable()).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint64]*SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func checkDirty(applied map[uint64]*SchemaMigration) error {
+	for _, row := range applied {
+		if row.Dirty {
+			return fmt.Errorf("migration %d_%s: %w", row.Version, row.Name, ErrDirty)
+		}
+	}
+	return nil
+}
+
+// withLock runs action on one connection holding a lock of the database, the lock belongs to the
+// connection. SQLite has a single writer and no named locks, its migrations are not serialized
+func (m *Migrator) withLock(ctx context.Context, action func(db *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(db *gorm.DB) error {
+		switch db.Dialector.Name() {
+		case driver.MySQL:
+			var locked int
+			if err := db.Raw("SELECT GET_LOCK(?, ?)", lockName, lockTimeout).Scan(&locked).Error; err != nil {
+				return err
+			}
+			if locked != 1 {
+				return ErrLockTimeout
+			}
+			defer db.Exec("SELECT RELEASE_LOCK(?)", lockName)
+		case driver.Postgres:
+			if err := lockPostgres(ctx, db); err != nil {
+				return err
+			}
+			defer db.Exec("SELECT pg_advisory_unlock(hashtext(?))", lockName)
+		}
+
+		if err := db.Table(m.versionTable()).AutoMigrate(&SchemaMigration{}); err != nil {
+			return err
+		}
+		return action(db)
+	})
+}
+
+// lockPostgres polls for the advisory lock of lockName, pg_advisory_lock would wait without a timeout
+func lockPostgres(ctx context.Context, db *gorm.DB) error {
+	deadline := time.Now().Add(lockTimeout * time.Second)
+	for {
+		var locked bool
+		if err := db.Raw("SELECT pg_try_advisory_lock(hashtext(?))", lockName).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if locked {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrLockTimeout
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (m *Migrator) versionTable() string {
+	return m.prefix + "schema_migration"
+}
+
+// SplitStatements splits a script on semicolons ending a line, comment lines are dropped
+func SplitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
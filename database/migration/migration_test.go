@@ -0,0 +1,116 @@
+package migration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"banking/database/driver"
+	"banking/database/migration"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Embedded(t *testing.T) {
+	mysqlMigrations, err := migration.Embedded(driver.MySQL)
+	require.NoError(t, err)
+	require.NotEmpty(t, mysqlMigrations)
+
+	assert.Equal(t, uint64(1), mysqlMigrations[0].Version)
+	assert.Equal(t, "initial_schema", mysqlMigrations[0].Name)
+
+	// every driver ships the same versions, so a schema is at the same version on any backend
+	for _, name := range []string{driver.MySQL, driver.Postgres, driver.SQLite} {
+		t.Run(name, func(t *testing.T) {
+			migrations, err := migration.Embedded(name)
+			require.NoError(t, err)
+			require.Len(t, migrations, len(mysqlMigrations))
+			for i, m := range migrations {
+				assert.Equal(t, mysqlMigrations[i].Version, m.Version)
+				assert.Equal(t, mysqlMigrations[i].Name, m.Name)
+				assert.Contains(t, m.Up, migration.PrefixPlaceholder)
+			}
+		})
+	}
+
+	_, err = migration.Embedded("oracle")
+	assert.Error(t, err)
+}
+
+func Test_Load(t *testing.T) {
+	t.Run("ordered by version", func(t *testing.T) {
+		migrations, err := migration.Load(fstest.MapFS{
+			"0002_add_memo.up.sql":   {Data: []byte("ALTER TABLE t ADD memo text;")},
+			"0002_add_memo.down.sql": {Data: []byte("ALTER TABLE t DROP memo;")},
+			"0001_create_t.up.sql":   {Data: []byte("CREATE TABLE t (id int);")},
+			"0001_create_t.down.sql": {Data: []byte("DROP TABLE t;")},
+		})
+		require.NoError(t, err)
+		require.Len(t, migrations, 2)
+		assert.Equal(t, "create_t", migrations[0].Name)
+		assert.Equal(t, "add_memo", migrations[1].Name)
+		assert.Equal(t, "ALTER TABLE t DROP memo;", migrations[1].Down)
+	})
+
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{name: "missing down", fsys: fstest.MapFS{"0001_create_t.up.sql": {Data: []byte("CREATE TABLE t (id int);")}}},
+		{name: "duplicate version", fsys: fstest.MapFS{
+			"0001_create_t.up.sql":   {Data: []byte("CREATE TABLE t (id int);")},
+			"0001_create_t.down.sql": {Data: []byte("DROP TABLE t;")},
+			"0001_create_u.up.sql":   {Data: []byte("CREATE TABLE u (id int);")},
+			"0001_create_u.down.sql": {Data: []byte("DROP TABLE u;")},
+		}},
+		{name: "unexpected file", fsys: fstest.MapFS{"create_t.sql": {Data: []byte("CREATE TABLE t (id int);")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := migration.Load(tt.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_Create(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001_create_t.up.sql"), []byte("CREATE TABLE t (id int);"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001_create_t.down.sql"), []byte("DROP TABLE t;"), 0o644))
+
+	upPath, downPath, err := migration.Create(dir, "Add Memo")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0002_add_memo.up.sql"), upPath)
+	assert.Equal(t, filepath.Join(dir, "0002_add_memo.down.sql"), downPath)
+
+	// the new pair loads, its comments are no statements
+	migrations, err := migration.Load(os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Len(t, migrations, 2)
+	assert.Empty(t, migration.SplitStatements(migrations[1].Up))
+
+	_, _, err = migration.Create(dir, "drop;table")
+	assert.Error(t, err)
+}
+
+func Test_SplitStatements(t *testing.T) {
+	script := strings.Join([]string{
+		"-- two tables",
+		"CREATE TABLE t (",
+		"    id int",
+		");",
+		"",
+		"INSERT INTO t VALUES (1);",
+		"DROP TABLE u",
+	}, "\n")
+
+	assert.Equal(t, []string{
+		"CREATE TABLE t (\n    id int\n)",
+		"INSERT INTO t VALUES (1)",
+		"DROP TABLE u",
+	}, migration.SplitStatements(script))
+}
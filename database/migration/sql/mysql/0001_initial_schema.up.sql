@@ -0,0 +1,203 @@
+-- Tables as created by gorm AutoMigrate before versioned migrations, IF NOT EXISTS adopts existing databases
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}user` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `name` varchar(20) NOT NULL,
+    `email` varchar(100) NOT NULL,
+    `password` varchar(255) NOT NULL,
+    `balance` decimal(10,2) NOT NULL DEFAULT '0',
+    `is_admin` tinyint(1) DEFAULT false,
+    `is_auditor` tinyint(1) DEFAULT false,
+    `tier` varchar(20) NOT NULL DEFAULT 'standard',
+    `interest_plan` varchar(20),
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}user_deleted_at` (`deleted_at`),
+    INDEX `idx_{{prefix}}user_email` (`email`),
+    CONSTRAINT `uni_{{prefix}}user_email` UNIQUE (`email`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}transaction` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `from_user_id` bigint unsigned NOT NULL,
+    `from_user_balance` decimal(10,2) NOT NULL,
+    `to_user_id` bigint unsigned NOT NULL,
+    `to_user_balance` decimal(10,2) NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    `fee` decimal(10,2) NOT NULL DEFAULT '0',
+    `transaction_type` enum('deposit','withdraw','transfer','fee','interest') NOT NULL,
+    `details` text,
+    `request_id` varchar(64),
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}transaction_deleted_at` (`deleted_at`),
+    INDEX `idx_{{prefix}}transaction_from_user_id` (`from_user_id`),
+    INDEX `idx_{{prefix}}transaction_to_user_id` (`to_user_id`),
+    INDEX `idx_{{prefix}}transaction_request_id` (`request_id`),
+    CONSTRAINT `fk_{{prefix}}transaction_from_user` FOREIGN KEY (`from_user_id`) REFERENCES `{{prefix}}user`(`id`),
+    CONSTRAINT `fk_{{prefix}}transaction_to_user` FOREIGN KEY (`to_user_id`) REFERENCES `{{prefix}}user`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}api_key` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `api_key` varchar(255) NOT NULL,
+    `secret` varchar(255) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}api_key_deleted_at` (`deleted_at`),
+    CONSTRAINT `fk_{{prefix}}api_key_user` FOREIGN KEY (`user_id`) REFERENCES `{{prefix}}user`(`id`),
+    CONSTRAINT `uni_{{prefix}}api_key_api_key` UNIQUE (`api_key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}bank_statement` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `format` enum('mt940','camt053') NOT NULL,
+    `statement_ref` varchar(64) NOT NULL,
+    `account` varchar(64) NOT NULL,
+    `file_name` varchar(255),
+    `imported_by` bigint NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}bank_statement_statement_ref` (`statement_ref`),
+    INDEX `idx_{{prefix}}bank_statement_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}bank_statement_line` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `bank_statement_id` bigint unsigned NOT NULL,
+    `value_date` date NOT NULL,
+    `direction` enum('credit','debit') NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    `currency` varchar(3),
+    `reference` varchar(64),
+    `bank_reference` varchar(64),
+    `description` text,
+    `status` enum('matched','unmatched','ambiguous','resolved','ignored') NOT NULL,
+    `transaction_id` bigint,
+    `candidate_ids` varchar(255),
+    `resolved_by` bigint,
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}bank_statement_line_deleted_at` (`deleted_at`),
+    INDEX `idx_{{prefix}}bank_statement_line_bank_statement_id` (`bank_statement_id`),
+    INDEX `idx_{{prefix}}bank_statement_line_reference` (`reference`),
+    INDEX `idx_{{prefix}}bank_statement_line_status` (`status`),
+    INDEX `idx_{{prefix}}bank_statement_line_transaction_id` (`transaction_id`),
+    CONSTRAINT `fk_{{prefix}}bank_statement_lines` FOREIGN KEY (`bank_statement_id`) REFERENCES `{{prefix}}bank_statement`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}fraud_review` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `from_user_id` bigint NOT NULL,
+    `to_user_id` bigint NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    `api_key` varchar(255),
+    `score` bigint NOT NULL,
+    `decision` enum('allow','review','block') NOT NULL,
+    `reasons` varchar(255),
+    `status` enum('pending','approved','rejected','blocked') NOT NULL,
+    `reviewed_by` bigint,
+    `transaction_id` bigint,
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}fraud_review_deleted_at` (`deleted_at`),
+    INDEX `idx_{{prefix}}fraud_review_from_user_id` (`from_user_id`),
+    INDEX `idx_{{prefix}}fraud_review_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}screening_result` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `user_id` bigint,
+    `name` varchar(100) NOT NULL,
+    `context` enum('registration','transfer') NOT NULL,
+    `decision` enum('clear','flag','block') NOT NULL,
+    `score` decimal(5,4) NOT NULL,
+    `entry_id` varchar(64),
+    `entry_name` varchar(255),
+    `entry_source` varchar(64),
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}screening_result_decision` (`decision`),
+    INDEX `idx_{{prefix}}screening_result_deleted_at` (`deleted_at`),
+    INDEX `idx_{{prefix}}screening_result_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}audit_log` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NOT NULL,
+    `actor_id` bigint,
+    `actor_email` varchar(100),
+    `auth_method` varchar(20),
+    `ip` varchar(45),
+    `action` varchar(64) NOT NULL,
+    `target` varchar(255),
+    `before` text,
+    `after` text,
+    `request_id` varchar(64),
+    `outcome` enum('success','failure') NOT NULL,
+    `prev_hash` char(64) NOT NULL,
+    `hash` char(64) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}audit_log_actor_id` (`actor_id`),
+    INDEX `idx_{{prefix}}audit_log_action` (`action`),
+    INDEX `idx_{{prefix}}audit_log_request_id` (`request_id`),
+    INDEX `idx_{{prefix}}audit_log_created_at` (`created_at`),
+    CONSTRAINT `uni_{{prefix}}audit_log_hash` UNIQUE (`hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}audit_chain_head` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `last_id` bigint NOT NULL,
+    `hash` char(64) NOT NULL,
+    PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}interest_accrual` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `user_id` bigint NOT NULL,
+    `date` date NOT NULL,
+    `plan` varchar(20) NOT NULL,
+    `rate` decimal(8,4) NOT NULL,
+    `principal` decimal(20,10) NOT NULL,
+    `amount` decimal(20,10) NOT NULL,
+    `capitalization_id` bigint,
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}interest_accrual_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_interest_accrual_user_date` (`user_id`,`date`),
+    INDEX `idx_{{prefix}}interest_accrual_capitalization_id` (`capitalization_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}interest_capitalization` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `user_id` bigint NOT NULL,
+    `period` varchar(7) NOT NULL,
+    `accrued` decimal(20,10) NOT NULL,
+    `previous_carry` decimal(20,10) NOT NULL,
+    `posted` decimal(10,2) NOT NULL,
+    `carry` decimal(20,10) NOT NULL,
+    `transaction_id` bigint,
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}interest_capitalization_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_interest_capitalization_user_period` (`user_id`,`period`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
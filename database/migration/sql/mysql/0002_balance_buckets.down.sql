@@ -0,0 +1,3 @@
+-- the buckets are folded into the user rows first, see the bucket consolidate command
+DROP TABLE IF EXISTS `{{prefix}}balance_bucket`;
+ALTER TABLE `{{prefix}}user` DROP COLUMN `balance_buckets`;
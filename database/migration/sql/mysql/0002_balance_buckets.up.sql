@@ -0,0 +1,11 @@
+-- Hot accounts spread their credits over balance buckets, see model BalanceBucket
+
+ALTER TABLE `{{prefix}}user` ADD COLUMN `balance_buckets` bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}balance_bucket` (
+    `user_id` bigint unsigned NOT NULL,
+    `bucket` bigint NOT NULL,
+    `balance` decimal(10,2) NOT NULL DEFAULT '0',
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`user_id`, `bucket`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
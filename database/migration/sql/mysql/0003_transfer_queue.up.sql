@@ -0,0 +1,25 @@
+-- Transfers accepted in async mode wait here for a worker, see model TransferRequest
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}transfer_request` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `from_user_id` bigint unsigned NOT NULL,
+    `to_user_id` bigint unsigned NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    `status` enum('queued','processing','completed','held','failed') NOT NULL,
+    `api_key` varchar(255),
+    `client_ip` varchar(64),
+    `request_id` varchar(64),
+    `callback_url` varchar(2048),
+    `claimed_at` datetime(3) NULL,
+    `transaction_id` bigint unsigned,
+    `review_id` bigint unsigned,
+    `error_code` varchar(64),
+    `error_message` varchar(255),
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}transfer_request_deleted_at` (`deleted_at`),
+    INDEX `idx_{{prefix}}transfer_request_from_user_id` (`from_user_id`),
+    INDEX `idx_{{prefix}}transfer_request_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
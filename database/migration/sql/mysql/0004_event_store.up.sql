@@ -0,0 +1,93 @@
+-- Balances are projected from an append-only event store, see models LedgerEvent and BalanceProjection.
+-- The existing balances are opened by one account_opened event per user, followed by the events of
+-- the existing transactions
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}ledger_event` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `type` varchar(30) NOT NULL,
+    `transaction_id` bigint unsigned,
+    `from_user_id` bigint unsigned NOT NULL,
+    `to_user_id` bigint unsigned NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    `fee` decimal(10,2) NOT NULL DEFAULT '0',
+    `details` text,
+    `request_id` varchar(64),
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}ledger_event_transaction_id` (`transaction_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}balance_projection` (
+    `user_id` bigint unsigned NOT NULL,
+    `balance` decimal(10,2) NOT NULL,
+    `event_id` bigint unsigned NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}transaction_history` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `event_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `type` varchar(30) NOT NULL,
+    `transaction_id` bigint unsigned,
+    `from_user_id` bigint unsigned NOT NULL,
+    `to_user_id` bigint unsigned NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    `fee` decimal(10,2) NOT NULL DEFAULT '0',
+    `balance` decimal(10,2) NOT NULL,
+    `details` text,
+    `request_id` varchar(64),
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}transaction_history_event_id` (`event_id`),
+    INDEX `idx_{{prefix}}transaction_history_user_id` (`user_id`),
+    INDEX `idx_{{prefix}}transaction_history_from_user_id` (`from_user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}projection_checkpoint` (
+    `name` varchar(64) NOT NULL,
+    `position` bigint unsigned NOT NULL,
+    `snapshot_position` bigint unsigned NOT NULL DEFAULT 0,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}balance_snapshot` (
+    `position` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `balance` decimal(10,2) NOT NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`position`, `user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- the opening balance is the balance, buckets included, less what the existing transactions changed.
+-- A transfer or withdrawal debits its fee, the fee transaction credits the house account only
+INSERT INTO `{{prefix}}ledger_event` (`created_at`, `type`, `from_user_id`, `to_user_id`, `amount`, `fee`, `details`)
+SELECT `opened`.`created_at`, 'account_opened', `opened`.`id`, `opened`.`id`, `opened`.`amount`, 0, 'balance before the event store'
+FROM (
+    SELECT u.`id`, u.`created_at`, u.`balance`
+        + COALESCE((SELECT SUM(b.`balance`) FROM `{{prefix}}balance_bucket` b WHERE b.`user_id` = u.`id`), 0)
+        - COALESCE((SELECT SUM(CASE
+            WHEN t.`transaction_type` IN ('deposit', 'interest') THEN t.`amount`
+            WHEN t.`transaction_type` = 'withdraw' THEN -(t.`amount` + t.`fee`)
+            WHEN t.`transaction_type` = 'transfer' AND t.`from_user_id` = u.`id` THEN -(t.`amount` + t.`fee`)
+            WHEN t.`transaction_type` = 'transfer' THEN t.`amount`
+            WHEN t.`transaction_type` = 'fee' AND t.`to_user_id` = u.`id` THEN t.`amount`
+            ELSE 0
+        END) FROM `{{prefix}}transaction` t WHERE t.`from_user_id` = u.`id` OR t.`to_user_id` = u.`id`), 0) AS `amount`
+    FROM `{{prefix}}user` u
+) `opened`
+WHERE `opened`.`amount` <> 0
+ORDER BY `opened`.`id`;
+
+INSERT INTO `{{prefix}}ledger_event` (`created_at`, `type`, `transaction_id`, `from_user_id`, `to_user_id`, `amount`, `fee`, `details`, `request_id`)
+SELECT t.`created_at`, CASE t.`transaction_type`
+        WHEN 'deposit' THEN 'money_deposited'
+        WHEN 'withdraw' THEN 'money_withdrawn'
+        WHEN 'transfer' THEN 'transfer_completed'
+        WHEN 'fee' THEN 'fee_charged'
+        WHEN 'interest' THEN 'interest_credited'
+    END, t.`id`, t.`from_user_id`, t.`to_user_id`, t.`amount`, t.`fee`, t.`details`, t.`request_id`
+FROM `{{prefix}}transaction` t
+ORDER BY t.`id`;
@@ -0,0 +1,2 @@
+DROP INDEX `idx_{{prefix}}transaction_to_user_created` ON `{{prefix}}transaction`;
+DROP INDEX `idx_{{prefix}}transaction_from_user_created` ON `{{prefix}}transaction`;
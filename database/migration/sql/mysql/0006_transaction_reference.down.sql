@@ -0,0 +1,8 @@
+DROP INDEX `idx_{{prefix}}transaction_external_reference` ON `{{prefix}}transaction`;
+ALTER TABLE `{{prefix}}fraud_review` DROP COLUMN `external_reference`;
+ALTER TABLE `{{prefix}}fraud_review` DROP COLUMN `memo`;
+ALTER TABLE `{{prefix}}transfer_request` DROP COLUMN `external_reference`;
+ALTER TABLE `{{prefix}}transfer_request` DROP COLUMN `memo`;
+ALTER TABLE `{{prefix}}transaction_history` DROP COLUMN `external_reference`;
+ALTER TABLE `{{prefix}}ledger_event` DROP COLUMN `external_reference`;
+ALTER TABLE `{{prefix}}transaction` DROP COLUMN `external_reference`;
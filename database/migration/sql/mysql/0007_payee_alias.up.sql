@@ -0,0 +1,18 @@
+-- Users are paid by email, phone number or handle instead of their id, see model PayeeAlias
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}payee_alias` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `type` enum('email','phone','handle') NOT NULL,
+    `value` varchar(255) NOT NULL,
+    `verified_value` varchar(255),
+    `verified_at` datetime(3) NULL,
+    `code_hash` varchar(255),
+    `code_expires_at` datetime(3) NULL,
+    `verification_attempts` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_{{prefix}}payee_alias_user_value` (`user_id`,`value`),
+    UNIQUE INDEX `idx_{{prefix}}payee_alias_verified_value` (`verified_value`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
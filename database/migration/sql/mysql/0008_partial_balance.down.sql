@@ -0,0 +1 @@
+ALTER TABLE `{{prefix}}transaction` DROP COLUMN `to_user_balance_partial`;
@@ -0,0 +1,8 @@
+-- Event ids a projection passed while they were missing, see model ProjectionGap
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}projection_gap` (
+    `checkpoint` varchar(64) NOT NULL,
+    `event_id` bigint unsigned NOT NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`checkpoint`, `event_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
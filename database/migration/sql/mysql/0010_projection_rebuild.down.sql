@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `{{prefix}}transaction_history_rebuild`;
+DROP TABLE IF EXISTS `{{prefix}}balance_projection_rebuild`;
@@ -0,0 +1,29 @@
+-- A rebuild replays the event store into copies of the read models and swaps them in at the end,
+-- see projection.Rebuild
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}balance_projection_rebuild` (
+    `user_id` bigint unsigned NOT NULL,
+    `balance` decimal(10,2) NOT NULL,
+    `event_id` bigint unsigned NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `{{prefix}}transaction_history_rebuild` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `event_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `type` varchar(30) NOT NULL,
+    `transaction_id` bigint unsigned,
+    `from_user_id` bigint unsigned NOT NULL,
+    `to_user_id` bigint unsigned NOT NULL,
+    `amount` decimal(10,2) NOT NULL,
+    `fee` decimal(10,2) NOT NULL DEFAULT '0',
+    `balance` decimal(10,2) NOT NULL,
+    `details` text,
+    `external_reference` varchar(64),
+    `request_id` varchar(64),
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_{{prefix}}transaction_history_rebuild_event_id` (`event_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,2 @@
+DROP INDEX `idx_{{prefix}}bank_statement_line_transaction_id` ON `{{prefix}}bank_statement_line`;
+CREATE INDEX `idx_{{prefix}}bank_statement_line_transaction_id` ON `{{prefix}}bank_statement_line` (`transaction_id`);
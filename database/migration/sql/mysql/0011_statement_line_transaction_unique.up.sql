@@ -0,0 +1,5 @@
+-- A transaction reconciles one statement line, concurrent imports and resolutions can not both claim
+-- it. Lines claiming the same transaction have to be resolved before this migration
+
+DROP INDEX `idx_{{prefix}}bank_statement_line_transaction_id` ON `{{prefix}}bank_statement_line`;
+CREATE UNIQUE INDEX `idx_{{prefix}}bank_statement_line_transaction_id` ON `{{prefix}}bank_statement_line` (`transaction_id`);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS "{{prefix}}interest_capitalization";
+DROP TABLE IF EXISTS "{{prefix}}interest_accrual";
+DROP TABLE IF EXISTS "{{prefix}}audit_chain_head";
+DROP TABLE IF EXISTS "{{prefix}}audit_log";
+DROP TABLE IF EXISTS "{{prefix}}screening_result";
+DROP TABLE IF EXISTS "{{prefix}}fraud_review";
+DROP TABLE IF EXISTS "{{prefix}}bank_statement_line";
+DROP TABLE IF EXISTS "{{prefix}}bank_statement";
+DROP TABLE IF EXISTS "{{prefix}}api_key";
+DROP TABLE IF EXISTS "{{prefix}}transaction";
+DROP TABLE IF EXISTS "{{prefix}}user";
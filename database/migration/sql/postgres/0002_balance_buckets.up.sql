@@ -0,0 +1,11 @@
+-- Hot accounts spread their credits over balance buckets, see model BalanceBucket
+
+ALTER TABLE "{{prefix}}user" ADD COLUMN IF NOT EXISTS "balance_buckets" bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}balance_bucket" (
+    "user_id" bigint NOT NULL,
+    "bucket" bigint NOT NULL,
+    "balance" decimal(10,2) NOT NULL DEFAULT 0,
+    "updated_at" timestamptz(3),
+    PRIMARY KEY ("user_id", "bucket")
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "{{prefix}}transfer_request";
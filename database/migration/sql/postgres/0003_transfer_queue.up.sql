@@ -0,0 +1,25 @@
+-- Transfers accepted in async mode wait here for a worker, see model TransferRequest
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}transfer_request" (
+    "id" bigserial,
+    "created_at" timestamptz(3),
+    "updated_at" timestamptz(3),
+    "deleted_at" timestamptz(3),
+    "from_user_id" bigint NOT NULL,
+    "to_user_id" bigint NOT NULL,
+    "amount" decimal(10,2) NOT NULL,
+    "status" varchar(20) NOT NULL CHECK ("status" IN ('queued','processing','completed','held','failed')),
+    "api_key" varchar(255),
+    "client_ip" varchar(64),
+    "request_id" varchar(64),
+    "callback_url" varchar(2048),
+    "claimed_at" timestamptz(3),
+    "transaction_id" bigint,
+    "review_id" bigint,
+    "error_code" varchar(64),
+    "error_message" varchar(255),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transfer_request_deleted_at" ON "{{prefix}}transfer_request" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transfer_request_from_user_id" ON "{{prefix}}transfer_request" ("from_user_id");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transfer_request_status" ON "{{prefix}}transfer_request" ("status");
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "{{prefix}}balance_snapshot";
+DROP TABLE IF EXISTS "{{prefix}}projection_checkpoint";
+DROP TABLE IF EXISTS "{{prefix}}transaction_history";
+DROP TABLE IF EXISTS "{{prefix}}balance_projection";
+DROP TABLE IF EXISTS "{{prefix}}ledger_event";
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_{{prefix}}transaction_to_user_created";
+DROP INDEX IF EXISTS "idx_{{prefix}}transaction_from_user_created";
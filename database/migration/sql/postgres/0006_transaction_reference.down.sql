@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "idx_{{prefix}}transaction_external_reference";
+ALTER TABLE "{{prefix}}fraud_review" DROP COLUMN IF EXISTS "external_reference";
+ALTER TABLE "{{prefix}}fraud_review" DROP COLUMN IF EXISTS "memo";
+ALTER TABLE "{{prefix}}transfer_request" DROP COLUMN IF EXISTS "external_reference";
+ALTER TABLE "{{prefix}}transfer_request" DROP COLUMN IF EXISTS "memo";
+ALTER TABLE "{{prefix}}transaction_history" DROP COLUMN IF EXISTS "external_reference";
+ALTER TABLE "{{prefix}}ledger_event" DROP COLUMN IF EXISTS "external_reference";
+ALTER TABLE "{{prefix}}transaction" DROP COLUMN IF EXISTS "external_reference";
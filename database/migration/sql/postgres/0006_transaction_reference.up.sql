@@ -0,0 +1,11 @@
+-- Transfers carry a memo and an external reference of the client, see model TransactionReference
+
+ALTER TABLE "{{prefix}}transaction" ADD COLUMN IF NOT EXISTS "external_reference" varchar(64);
+ALTER TABLE "{{prefix}}ledger_event" ADD COLUMN IF NOT EXISTS "external_reference" varchar(64);
+ALTER TABLE "{{prefix}}transaction_history" ADD COLUMN IF NOT EXISTS "external_reference" varchar(64);
+ALTER TABLE "{{prefix}}transfer_request" ADD COLUMN IF NOT EXISTS "memo" varchar(140);
+ALTER TABLE "{{prefix}}transfer_request" ADD COLUMN IF NOT EXISTS "external_reference" varchar(64);
+ALTER TABLE "{{prefix}}fraud_review" ADD COLUMN IF NOT EXISTS "memo" varchar(140);
+ALTER TABLE "{{prefix}}fraud_review" ADD COLUMN IF NOT EXISTS "external_reference" varchar(64);
+
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transaction_external_reference" ON "{{prefix}}transaction" ("external_reference");
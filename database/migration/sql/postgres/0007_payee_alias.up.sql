@@ -0,0 +1,18 @@
+-- Users are paid by email, phone number or handle instead of their id, see model PayeeAlias
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}payee_alias" (
+    "id" bigserial,
+    "created_at" timestamptz(3),
+    "updated_at" timestamptz(3),
+    "user_id" bigint NOT NULL,
+    "type" varchar(10) NOT NULL CHECK ("type" IN ('email','phone','handle')),
+    "value" varchar(255) NOT NULL,
+    "verified_value" varchar(255),
+    "verified_at" timestamptz(3),
+    "code_hash" varchar(255),
+    "code_expires_at" timestamptz(3),
+    "verification_attempts" bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_{{prefix}}payee_alias_user_value" ON "{{prefix}}payee_alias" ("user_id","value");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_{{prefix}}payee_alias_verified_value" ON "{{prefix}}payee_alias" ("verified_value");
@@ -0,0 +1,8 @@
+-- Event ids a projection passed while they were missing, see model ProjectionGap
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}projection_gap" (
+    "checkpoint" varchar(64) NOT NULL,
+    "event_id" bigint NOT NULL,
+    "created_at" timestamptz(3),
+    PRIMARY KEY ("checkpoint", "event_id")
+);
@@ -0,0 +1,28 @@
+-- A rebuild replays the event store into copies of the read models and swaps them in at the end,
+-- see projection.Rebuild
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}balance_projection_rebuild" (
+    "user_id" bigint NOT NULL,
+    "balance" decimal(10,2) NOT NULL,
+    "event_id" bigint NOT NULL,
+    "updated_at" timestamptz(3),
+    PRIMARY KEY ("user_id")
+);
+CREATE TABLE IF NOT EXISTS "{{prefix}}transaction_history_rebuild" (
+    "id" bigserial,
+    "event_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "type" varchar(30) NOT NULL,
+    "transaction_id" bigint,
+    "from_user_id" bigint NOT NULL,
+    "to_user_id" bigint NOT NULL,
+    "amount" decimal(10,2) NOT NULL,
+    "fee" decimal(10,2) NOT NULL DEFAULT 0,
+    "balance" decimal(10,2) NOT NULL,
+    "details" text,
+    "external_reference" varchar(64),
+    "request_id" varchar(64),
+    "created_at" timestamptz(3),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transaction_history_rebuild_event_id" ON "{{prefix}}transaction_history_rebuild" ("event_id");
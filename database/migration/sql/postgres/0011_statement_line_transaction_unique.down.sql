@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_{{prefix}}bank_statement_line_transaction_id";
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}bank_statement_line_transaction_id" ON "{{prefix}}bank_statement_line" ("transaction_id");
@@ -0,0 +1,193 @@
+-- The MySQL initial schema in SQLite types, IF NOT EXISTS keeps re-running it harmless. SQLite stores
+-- decimals as numbers and checks no lengths, the development and test backend does not need either
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}user" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "deleted_at" datetime,
+    "name" varchar(20) NOT NULL,
+    "email" varchar(100) NOT NULL,
+    "password" varchar(255) NOT NULL,
+    "balance" decimal(10,2) NOT NULL DEFAULT 0,
+    "is_admin" numeric DEFAULT false,
+    "is_auditor" numeric DEFAULT false,
+    "tier" varchar(20) NOT NULL DEFAULT 'standard',
+    "interest_plan" varchar(20),
+    CONSTRAINT "uni_{{prefix}}user_email" UNIQUE ("email")
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}user_deleted_at" ON "{{prefix}}user" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}user_email" ON "{{prefix}}user" ("email");
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}transaction" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "deleted_at" datetime,
+    "from_user_id" bigint NOT NULL,
+    "from_user_balance" decimal(10,2) NOT NULL,
+    "to_user_id" bigint NOT NULL,
+    "to_user_balance" decimal(10,2) NOT NULL,
+    "amount" decimal(10,2) NOT NULL,
+    "fee" decimal(10,2) NOT NULL DEFAULT 0,
+    "transaction_type" varchar(20) NOT NULL CHECK ("transaction_type" IN ('deposit','withdraw','transfer','fee','interest')),
+    "details" text,
+    "request_id" varchar(64),
+    CONSTRAINT "fk_{{prefix}}transaction_from_user" FOREIGN KEY ("from_user_id") REFERENCES "{{prefix}}user"("id"),
+    CONSTRAINT "fk_{{prefix}}transaction_to_user" FOREIGN KEY ("to_user_id") REFERENCES "{{prefix}}user"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transaction_deleted_at" ON "{{prefix}}transaction" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transaction_from_user_id" ON "{{prefix}}transaction" ("from_user_id");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transaction_to_user_id" ON "{{prefix}}transaction" ("to_user_id");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transaction_request_id" ON "{{prefix}}transaction" ("request_id");
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}api_key" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "deleted_at" datetime,
+    "user_id" bigint NOT NULL,
+    "api_key" varchar(255) NOT NULL,
+    "secret" varchar(255) NOT NULL,
+    CONSTRAINT "fk_{{prefix}}api_key_user" FOREIGN KEY ("user_id") REFERENCES "{{prefix}}user"("id"),
+    CONSTRAINT "uni_{{prefix}}api_key_api_key" UNIQUE ("api_key")
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}api_key_deleted_at" ON "{{prefix}}api_key" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}bank_statement" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "deleted_at" datetime,
+    "format" varchar(20) NOT NULL CHECK ("format" IN ('mt940','camt053')),
+    "statement_ref" varchar(64) NOT NULL,
+    "account" varchar(64) NOT NULL,
+    "file_name" varchar(255),
+    "imported_by" bigint NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}bank_statement_statement_ref" ON "{{prefix}}bank_statement" ("statement_ref");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}bank_statement_deleted_at" ON "{{prefix}}bank_statement" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}bank_statement_line" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "deleted_at" datetime,
+    "bank_statement_id" bigint NOT NULL,
+    "value_date" date NOT NULL,
+    "direction" varchar(20) NOT NULL CHECK ("direction" IN ('credit','debit')),
+    "amount" decimal(10,2) NOT NULL,
+    "currency" varchar(3),
+    "reference" varchar(64),
+    "bank_reference" varchar(64),
+    "description" text,
+    "status" varchar(20) NOT NULL CHECK ("status" IN ('matched','unmatched','ambiguous','resolved','ignored')),
+    "transaction_id" bigint,
+    "candidate_ids" varchar(255),
+    "resolved_by" bigint,
+    CONSTRAINT "fk_{{prefix}}bank_statement_lines" FOREIGN KEY ("bank_statement_id") REFERENCES "{{prefix}}bank_statement"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}bank_statement_line_deleted_at" ON "{{prefix}}bank_statement_line" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}bank_statement_line_bank_statement_id" ON "{{prefix}}bank_statement_line" ("bank_statement_id");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}bank_statement_line_reference" ON "{{prefix}}bank_statement_line" ("reference");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}bank_statement_line_status" ON "{{prefix}}bank_statement_line" ("status");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}bank_statement_line_transaction_id" ON "{{prefix}}bank_statement_line" ("transaction_id");
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}fraud_review" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "deleted_at" datetime,
+    "from_user_id" bigint NOT NULL,
+    "to_user_id" bigint NOT NULL,
+    "amount" decimal(10,2) NOT NULL,
+    "api_key" varchar(255),
+    "score" bigint NOT NULL,
+    "decision" varchar(20) NOT NULL CHECK ("decision" IN ('allow','review','block')),
+    "reasons" varchar(255),
+    "status" varchar(20) NOT NULL CHECK ("status" IN ('pending','approved','rejected','blocked')),
+    "reviewed_by" bigint,
+    "transaction_id" bigint
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}fraud_review_deleted_at" ON "{{prefix}}fraud_review" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}fraud_review_from_user_id" ON "{{prefix}}fraud_review" ("from_user_id");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}fraud_review_status" ON "{{prefix}}fraud_review" ("status");
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}screening_result" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "deleted_at" datetime,
+    "user_id" bigint,
+    "name" varchar(100) NOT NULL,
+    "context" varchar(20) NOT NULL CHECK ("context" IN ('registration','transfer')),
+    "decision" varchar(20) NOT NULL CHECK ("decision" IN ('clear','flag','block')),
+    "score" decimal(5,4) NOT NULL,
+    "entry_id" varchar(64),
+    "entry_name" varchar(255),
+    "entry_source" varchar(64)
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}screening_result_decision" ON "{{prefix}}screening_result" ("decision");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}screening_result_deleted_at" ON "{{prefix}}screening_result" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}screening_result_user_id" ON "{{prefix}}screening_result" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}audit_log" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime NOT NULL,
+    "actor_id" bigint,
+    "actor_email" varchar(100),
+    "auth_method" varchar(20),
+    "ip" varchar(45),
+    "action" varchar(64) NOT NULL,
+    "target" varchar(255),
+    "before" text,
+    "after" text,
+    "request_id" varchar(64),
+    "outcome" varchar(20) NOT NULL CHECK ("outcome" IN ('success','failure')),
+    "prev_hash" varchar(64) NOT NULL,
+    "hash" varchar(64) NOT NULL,
+    CONSTRAINT "uni_{{prefix}}audit_log_hash" UNIQUE ("hash")
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}audit_log_actor_id" ON "{{prefix}}audit_log" ("actor_id");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}audit_log_action" ON "{{prefix}}audit_log" ("action");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}audit_log_request_id" ON "{{prefix}}audit_log" ("request_id");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}audit_log_created_at" ON "{{prefix}}audit_log" ("created_at");
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}audit_chain_head" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "last_id" bigint NOT NULL,
+    "hash" varchar(64) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}interest_accrual" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "deleted_at" datetime,
+    "user_id" bigint NOT NULL,
+    "date" date NOT NULL,
+    "plan" varchar(20) NOT NULL,
+    "rate" decimal(8,4) NOT NULL,
+    "principal" decimal(20,10) NOT NULL,
+    "amount" decimal(20,10) NOT NULL,
+    "capitalization_id" bigint
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}interest_accrual_deleted_at" ON "{{prefix}}interest_accrual" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_{{prefix}}interest_accrual_user_date" ON "{{prefix}}interest_accrual" ("user_id","date");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}interest_accrual_capitalization_id" ON "{{prefix}}interest_accrual" ("capitalization_id");
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}interest_capitalization" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "deleted_at" datetime,
+    "user_id" bigint NOT NULL,
+    "period" varchar(7) NOT NULL,
+    "accrued" decimal(20,10) NOT NULL,
+    "previous_carry" decimal(20,10) NOT NULL,
+    "posted" decimal(10,2) NOT NULL,
+    "carry" decimal(20,10) NOT NULL,
+    "transaction_id" bigint
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}interest_capitalization_deleted_at" ON "{{prefix}}interest_capitalization" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_{{prefix}}interest_capitalization_user_period" ON "{{prefix}}interest_capitalization" ("user_id","period");
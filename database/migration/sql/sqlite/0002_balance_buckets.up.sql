@@ -0,0 +1,11 @@
+-- Hot accounts spread their credits over balance buckets, see model BalanceBucket
+
+ALTER TABLE "{{prefix}}user" ADD COLUMN "balance_buckets" integer NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}balance_bucket" (
+    "user_id" integer NOT NULL,
+    "bucket" integer NOT NULL,
+    "balance" decimal(10,2) NOT NULL DEFAULT 0,
+    "updated_at" datetime,
+    PRIMARY KEY ("user_id", "bucket")
+);
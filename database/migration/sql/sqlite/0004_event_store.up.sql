@@ -0,0 +1,92 @@
+-- Balances are projected from an append-only event store, see models LedgerEvent and BalanceProjection.
+-- The existing balances are opened by one account_opened event per user, followed by the events of
+-- the existing transactions
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}ledger_event" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "type" varchar(30) NOT NULL,
+    "transaction_id" bigint,
+    "from_user_id" bigint NOT NULL,
+    "to_user_id" bigint NOT NULL,
+    "amount" decimal(10,2) NOT NULL,
+    "fee" decimal(10,2) NOT NULL DEFAULT 0,
+    "details" text,
+    "request_id" varchar(64)
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}ledger_event_transaction_id" ON "{{prefix}}ledger_event" ("transaction_id");
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}balance_projection" (
+    "user_id" bigint NOT NULL,
+    "balance" decimal(10,2) NOT NULL,
+    "event_id" bigint NOT NULL,
+    "updated_at" datetime,
+    PRIMARY KEY ("user_id")
+);
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}transaction_history" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "event_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "type" varchar(30) NOT NULL,
+    "transaction_id" bigint,
+    "from_user_id" bigint NOT NULL,
+    "to_user_id" bigint NOT NULL,
+    "amount" decimal(10,2) NOT NULL,
+    "fee" decimal(10,2) NOT NULL DEFAULT 0,
+    "balance" decimal(10,2) NOT NULL,
+    "details" text,
+    "request_id" varchar(64),
+    "created_at" datetime
+);
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transaction_history_event_id" ON "{{prefix}}transaction_history" ("event_id");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transaction_history_user_id" ON "{{prefix}}transaction_history" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transaction_history_from_user_id" ON "{{prefix}}transaction_history" ("from_user_id");
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}projection_checkpoint" (
+    "name" varchar(64) NOT NULL,
+    "position" bigint NOT NULL,
+    "snapshot_position" bigint NOT NULL DEFAULT 0,
+    "updated_at" datetime,
+    PRIMARY KEY ("name")
+);
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}balance_snapshot" (
+    "position" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "balance" decimal(10,2) NOT NULL,
+    "created_at" datetime,
+    PRIMARY KEY ("position", "user_id")
+);
+
+-- the opening balance is the balance, buckets included, less what the existing transactions changed.
+-- A transfer or withdrawal debits its fee, the fee transaction credits the house account only. SQLite
+-- sums decimals as floats, so the opening balance is rounded to cents
+INSERT INTO "{{prefix}}ledger_event" ("created_at", "type", "from_user_id", "to_user_id", "amount", "fee", "details")
+SELECT "opened"."created_at", 'account_opened', "opened"."id", "opened"."id", "opened"."amount", 0, 'balance before the event store'
+FROM (
+    SELECT u."id", u."created_at", ROUND(u."balance"
+        + COALESCE((SELECT SUM(b."balance") FROM "{{prefix}}balance_bucket" b WHERE b."user_id" = u."id"), 0)
+        - COALESCE((SELECT SUM(CASE
+            WHEN t."transaction_type" IN ('deposit', 'interest') THEN t."amount"
+            WHEN t."transaction_type" = 'withdraw' THEN -(t."amount" + t."fee")
+            WHEN t."transaction_type" = 'transfer' AND t."from_user_id" = u."id" THEN -(t."amount" + t."fee")
+            WHEN t."transaction_type" = 'transfer' THEN t."amount"
+            WHEN t."transaction_type" = 'fee' AND t."to_user_id" = u."id" THEN t."amount"
+            ELSE 0
+        END) FROM "{{prefix}}transaction" t WHERE t."from_user_id" = u."id" OR t."to_user_id" = u."id"), 0), 2) AS "amount"
+    FROM "{{prefix}}user" u
+) "opened"
+WHERE "opened"."amount" <> 0
+ORDER BY "opened"."id";
+
+INSERT INTO "{{prefix}}ledger_event" ("created_at", "type", "transaction_id", "from_user_id", "to_user_id", "amount", "fee", "details", "request_id")
+SELECT t."created_at", CASE t."transaction_type"
+        WHEN 'deposit' THEN 'money_deposited'
+        WHEN 'withdraw' THEN 'money_withdrawn'
+        WHEN 'transfer' THEN 'transfer_completed'
+        WHEN 'fee' THEN 'fee_charged'
+        WHEN 'interest' THEN 'interest_credited'
+    END, t."id", t."from_user_id", t."to_user_id", t."amount", t."fee", t."details", t."request_id"
+FROM "{{prefix}}transaction" t
+ORDER BY t."id";
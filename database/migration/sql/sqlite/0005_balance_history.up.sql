@@ -0,0 +1,4 @@
+-- Balances at a point in time read the last transaction of each side of a user before it, see the
+-- balancehistory repo. The single column indexes would scan the whole history of the user
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transaction_from_user_created" ON "{{prefix}}transaction" ("from_user_id", "created_at", "id");
+CREATE INDEX IF NOT EXISTS "idx_{{prefix}}transaction_to_user_created" ON "{{prefix}}transaction" ("to_user_id", "created_at", "id");
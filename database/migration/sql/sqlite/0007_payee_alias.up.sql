@@ -0,0 +1,17 @@
+-- Users are paid by email, phone number or handle instead of their id, see model PayeeAlias
+
+CREATE TABLE IF NOT EXISTS "{{prefix}}payee_alias" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "user_id" bigint NOT NULL,
+    "type" varchar(10) NOT NULL CHECK ("type" IN ('email','phone','handle')),
+    "value" varchar(255) NOT NULL,
+    "verified_value" varchar(255),
+    "verified_at" datetime,
+    "code_hash" varchar(255),
+    "code_expires_at" datetime,
+    "verification_attempts" integer NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_{{prefix}}payee_alias_user_value" ON "{{prefix}}payee_alias" ("user_id","value");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_{{prefix}}payee_alias_verified_value" ON "{{prefix}}payee_alias" ("verified_value");
@@ -0,0 +1,7 @@
+-- Credits of hot accounts through a balance bucket do not record an exact balance of the payee, see
+-- model Transaction. Past credits of the accounts with buckets are marked too
+
+ALTER TABLE "{{prefix}}transaction" ADD COLUMN "to_user_balance_partial" numeric NOT NULL DEFAULT false;
+
+UPDATE "{{prefix}}transaction" SET "to_user_balance_partial" = true
+WHERE "to_user_id" IN (SELECT DISTINCT "user_id" FROM "{{prefix}}balance_bucket");
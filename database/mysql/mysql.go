@@ -3,18 +3,12 @@ package mysql
 import (
 	"context"
 	"fmt"
-	"log"
-	"time"
 
-	mysqlModel "banking/model/mysql"
+	"banking/database/driver"
+	"banking/tracing"
 
-	"github.com/shopspring/decimal"
 	"github.com/spf13/viper"
-	mysql "go.elastic.co/apm/module/apmgormv2/v2/driver/mysql"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
-	"gorm.io/gorm/schema"
 )
 
 type MySQL struct {
@@ -30,6 +24,7 @@ type Slave struct {
 	DB *gorm.DB
 }
 
+// NewMasterDB connects to the master, its schema is managed by the migrate command
 func NewMasterDB(ctx context.Context) (*Master, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 		viper.GetString("mysql.master.username"),
@@ -37,23 +32,11 @@ func NewMasterDB(ctx context.Context) (*Master, error) {
 		viper.GetString("mysql.master.host"),
 		viper.GetString("mysql.master.dbName"))
 
-	db, err := initMySQL(ctx, dsn)
+	db, err := driver.Open(ctx, tracing.Default().MySQLDialector(dsn), dsn)
 	if err != nil {
 		return nil, err
 	}
 
-	// Auto migrate on master
-	if err := db.AutoMigrate(
-		&mysqlModel.User{},
-		&mysqlModel.Transaction{},
-		&mysqlModel.APIKey{},
-	); err != nil {
-		return nil, err
-	}
-
-	// Seed User data
-	seedUsers(db)
-
 	return &Master{DB: db}, nil
 }
 
@@ -64,111 +47,10 @@ func NewSlaveDB(ctx context.Context) (*Slave, error) {
 		viper.GetString("mysql.slave.host"),
 		viper.GetString("mysql.slave.dbName"))
 
-	db, err := initMySQL(ctx, dsn)
+	db, err := driver.Open(ctx, tracing.Default().MySQLDialector(dsn), dsn)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Slave{DB: db}, nil
 }
-
-func initMySQL(ctx context.Context, dsn string) (*gorm.DB, error) {
-	location, err := time.LoadLocation("UTC")
-	if err != nil {
-		return nil, err
-	}
-
-	log.Printf("Connecting to MySQL: %s\n", dsn)
-
-	var db *gorm.DB
-	err = retry(ctx, func() error {
-		db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-			NamingStrategy: schema.NamingStrategy{
-				SingularTable: true,
-				TablePrefix:   viper.GetString("mysql.tablePrefix"),
-			},
-			Logger: logger.Default.LogMode(logger.Info),
-			NowFunc: func() time.Time {
-				return time.Now().In(location)
-			},
-		})
-		if err != nil {
-			return err
-		}
-		sqlDB, errDB := db.DB()
-		if errDB != nil {
-			return errDB
-		}
-
-		// Use the context for the ping operation
-		ctxWT, cancel := context.WithTimeout(ctx, 5*time.Second)
-		defer cancel()
-		return sqlDB.PingContext(ctxWT)
-	}, 5, 5*time.Second)
-	if err != nil {
-		return nil, err
-	}
-
-	return db, nil
-}
-
-func retry(ctx context.Context, action func() error, attempts int, sleep time.Duration) error {
-	for i := 0; i < attempts; i++ {
-		err := action()
-		if err == nil {
-			return nil
-		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(sleep):
-		}
-	}
-	return fmt.Errorf("failed after %d attempts", attempts)
-}
-
-// Function to seed User data
-func seedUsers(db *gorm.DB) {
-	users := []*mysqlModel.User{
-		{
-			Name:    "user1",
-			Email:   "user1@yopmail.com",
-			Balance: decimal.NewFromFloat(100.00),
-			IsAdmin: true,
-		},
-		{
-			Name:    "user2",
-			Email:   "user2@yopmail.com",
-			Balance: decimal.NewFromFloat(200.00),
-			IsAdmin: false,
-		},
-		{
-			Name:    "user3",
-			Email:   "user3@yopmail.com",
-			Balance: decimal.NewFromFloat(300.00),
-			IsAdmin: false,
-		},
-	}
-
-	for _, user := range users {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
-		if err != nil {
-			panic(err)
-		}
-
-		var existingUser mysqlModel.User
-		result := db.Where("name = ?", user.Name).First(&existingUser)
-
-		// Only create the user if it does not already exist
-		if result.Error != nil && result.Error == gorm.ErrRecordNotFound {
-			newUser := mysqlModel.User{
-				Name:     user.Name,
-				Email:    user.Email,
-				Balance:  user.Balance,
-				Password: string(hashedPassword),
-				IsAdmin:  user.IsAdmin,
-			}
-			db.Create(&newUser)
-		}
-	}
-}
@@ -0,0 +1,89 @@
+package mysql
+
+import (
+	"errors"
+
+	"banking/app/repo/mysql/eventstore"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// DevelopmentUsers are the users of README "Test Data", without password
+func DevelopmentUsers() []*mysqlModel.User {
+	return []*mysqlModel.User{
+		{
+			Name:    "user1",
+			Email:   "user1@yopmail.com",
+			Balance: decimal.NewFromFloat(100.00),
+			IsAdmin: true,
+		},
+		{
+			Name:    "user2",
+			Email:   "user2@yopmail.com",
+			Balance: decimal.NewFromFloat(200.00),
+			IsAdmin: false,
+		},
+		{
+			Name:         "user3",
+			Email:        "user3@yopmail.com",
+			Balance:      decimal.NewFromFloat(300.00),
+			IsAdmin:      false,
+			InterestPlan: "savings",
+		},
+		{
+			Name:      "auditor1",
+			Email:     "auditor1@yopmail.com",
+			Balance:   decimal.NewFromFloat(0),
+			IsAuditor: true,
+		},
+		{
+			Name:    "house",
+			Email:   "house@yopmail.com",
+			Balance: decimal.NewFromFloat(0),
+		},
+	}
+}
+
+// SeedUsers creates the development users which do not exist yet, all with password
+func SeedUsers(db *gorm.DB, password string) (created []string, err error) {
+	users := DevelopmentUsers()
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		var existingUser mysqlModel.User
+		result := db.Where("email = ?", user.Email).First(&existingUser)
+
+		// Only create the user if it does not already exist
+		if result.Error == nil {
+			continue
+		}
+		if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return created, result.Error
+		}
+
+		user.Password = string(hashedPassword)
+		// as userCommandRepo.CreateUser, a balance opens the account in the event store
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(user).Error; err != nil {
+				return err
+			}
+			if user.Balance.IsZero() {
+				return nil
+			}
+			return eventstore.Append(tx, mysqlModel.NewAccountOpened(user))
+		})
+		if err != nil {
+			return created, err
+		}
+		created = append(created, user.Email)
+	}
+
+	return created, nil
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"net/url"
+
+	"banking/database/driver"
+	"banking/tracing"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type Postgres struct {
+	Master *Master
+	Slave  *Slave
+}
+
+type Master struct {
+	DB *gorm.DB
+}
+
+type Slave struct {
+	DB *gorm.DB
+}
+
+// NewMasterDB connects to the primary, its schema is managed by the migrate command
+func NewMasterDB(ctx context.Context) (*Master, error) {
+	db, err := open(ctx, "postgres.master")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Master{DB: db}, nil
+}
+
+// NewSlaveDB connects to the streaming replica
+func NewSlaveDB(ctx context.Context) (*Slave, error) {
+	db, err := open(ctx, "postgres.slave")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Slave{DB: db}, nil
+}
+
+func open(ctx context.Context, key string) (*gorm.DB, error) {
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(viper.GetString(key+".username"), viper.GetString(key+".password")),
+		Host:     viper.GetString(key + ".host"),
+		Path:     viper.GetString(key + ".dbName"),
+		RawQuery: url.Values{"sslmode": {sslMode()}, "TimeZone": {"UTC"}}.Encode(),
+	}
+
+	return driver.Open(ctx, tracing.Default().PostgresDialector(dsn.String()), dsn.Redacted())
+}
+
+func sslMode() string {
+	if mode := viper.GetString("postgres.sslMode"); mode != "" {
+		return mode
+	}
+	return "disable"
+}
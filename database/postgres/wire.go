@@ -0,0 +1,19 @@
+//go:build wireinject
+// +build wireinject
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/wire"
+)
+
+func InitPostgres(ctx context.Context) (*Postgres, error) {
+	wire.Build(
+		NewMasterDB,
+		NewSlaveDB,
+		wire.Struct(new(Postgres), "Master", "Slave"),
+	)
+	return nil, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"banking/tracing"
+
 	"github.com/go-redis/redis/v8"
 	"github.com/spf13/viper"
 )
@@ -93,6 +95,8 @@ func initRedisClient(ctx context.Context, addr, password string) (*redis.Client,
 		return nil, err
 	}
 
+	tracing.Default().InstrumentRedis(client)
+
 	return client, nil
 }
 
@@ -0,0 +1,46 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"banking/database/driver"
+	"banking/tracing"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// SQLite is a single file database for development and tests, it serves as both master and slave
+type SQLite struct {
+	DB *gorm.DB
+}
+
+// NewSQLite opens the file at sqlite.path, creating it when missing, ":memory:" keeps the database
+// in the process. Its schema is managed by the migrate command
+func NewSQLite(ctx context.Context) (*SQLite, error) {
+	path := viper.GetString("sqlite.path")
+	if path == "" {
+		return nil, fmt.Errorf("sqlite.path is not set")
+	}
+
+	// transactions take the write lock when they begin instead of on their first write, so two
+	// transfers never read the same balance, and wait for the lock held by another process
+	dsn := fmt.Sprintf("file:%s?_txlock=immediate&_busy_timeout=5000&_foreign_keys=1", path)
+	db, err := driver.Open(ctx, tracing.Default().SQLiteDialector(dsn), dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// one connection serializes the transactions of the process and keeps an in-memory database
+	// alive, every connection to ":memory:" opens an empty one
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+
+	return &SQLite{DB: db}, nil
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -destination ./mock/alias.go -source=./alias.go -package=mock
+
+type IAliasHandler interface {
+	CreateAlias() gin.HandlerFunc
+	VerifyAlias() gin.HandlerFunc
+	GetAliases() gin.HandlerFunc
+	DeleteAlias() gin.HandlerFunc
+	ResolveAlias() gin.HandlerFunc
+}
+
+type IAliasService interface {
+	// CreateAlias adds an alias of the user. A handle is verified at once, an email or phone number
+	// once the user enters the code sent to it. Adding an unverified alias again sends a new code
+	CreateAlias(ctx context.Context, userID uint, aliasType mysqlModel.AliasType, value string) (alias *mysqlModel.PayeeAlias, err error)
+	VerifyAlias(ctx context.Context, userID, aliasID uint, code string) (alias *mysqlModel.PayeeAlias, err error)
+	GetAliases(ctx context.Context, userID uint) (aliases []*mysqlModel.PayeeAlias, err error)
+	DeleteAlias(ctx context.Context, userID, aliasID uint) (err error)
+	// Resolve finds the user of a verified alias of any type
+	Resolve(ctx context.Context, alias string) (payee *mysqlModel.Payee, err error)
+}
+
+// ICodeSender delivers the verification code of an email or phone alias
+type ICodeSender interface {
+	SendCode(ctx context.Context, alias *mysqlModel.PayeeAlias, code string) (err error)
+}
+
+type IAliasQueryRepo interface {
+	GetAliases(ctx context.Context, userID uint) (aliases []*mysqlModel.PayeeAlias, err error)
+	GetAlias(ctx context.Context, aliasID uint) (alias *mysqlModel.PayeeAlias, err error)
+	GetVerifiedAlias(ctx context.Context, value string) (alias *mysqlModel.PayeeAlias, err error)
+}
+
+type IAliasCommandRepo interface {
+	CreateAlias(ctx context.Context, alias *mysqlModel.PayeeAlias) (err error)
+	// RenewCode replaces the code of the unverified alias of alias.UserID with the same value and
+	// resets its attempts, the stored alias is read into alias. It fails with ErrAliasTaken when the
+	// user verified the value
+	RenewCode(ctx context.Context, alias *mysqlModel.PayeeAlias) (err error)
+	// CountAttempt counts a code entered for the alias, it fails with ErrAttemptsExhausted once
+	// maxAttempts codes were counted
+	CountAttempt(ctx context.Context, aliasID uint, maxAttempts int) (err error)
+	// VerifyAlias sets VerifiedAt and VerifiedValue, it fails when another user verified the value first
+	VerifyAlias(ctx context.Context, alias *mysqlModel.PayeeAlias) (err error)
+	DeleteAlias(ctx context.Context, userID, aliasID uint) (err error)
+}
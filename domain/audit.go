@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -destination ./mock/audit.go -source=./audit.go -package=mock
+
+type IAuditHandler interface {
+	GetAuditLogs() gin.HandlerFunc
+	VerifyAuditChain() gin.HandlerFunc
+}
+
+type IAuditService interface {
+	// Prepare returns ctx with the entry of an action whose repo appends it in the database transaction
+	// of the action, so the action never commits without its entry
+	Prepare(ctx context.Context, action, target string, before interface{}) context.Context
+	// Record appends an audit entry for the actor in ctx, actionErr decides the outcome. The entry the
+	// action appended already is not written again, a failed write is logged and never fails the action
+	Record(ctx context.Context, action, target string, before, after interface{}, actionErr error)
+	GetAuditLogs(ctx context.Context, actorID uint, action string, from, to time.Time, afterID uint, limit int) (logs []*mysqlModel.AuditLog, err error)
+	VerifyAuditChain(ctx context.Context) (checked int, brokenID uint, err error)
+}
+
+type IAuditQueryRepo interface {
+	GetAuditLogs(ctx context.Context, actorID uint, action string, from, to time.Time, afterID uint, limit int) (logs []*mysqlModel.AuditLog, err error)
+	GetAuditChainHead(ctx context.Context) (head *mysqlModel.AuditChainHead, err error)
+}
+
+type IAuditCommandRepo interface {
+	AppendAuditLog(ctx context.Context, log *mysqlModel.AuditLog) (err error)
+}
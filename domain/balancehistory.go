@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+//go:generate mockgen -destination ./mock/balancehistory.go -source=./balancehistory.go -package=mock
+
+type IBalanceHistoryHandler interface {
+	GetBalanceAt() gin.HandlerFunc
+	GetDailyBalances() gin.HandlerFunc
+}
+
+type IBalanceHistoryService interface {
+	// GetBalanceAt returns the balance of the user after every transaction before at, zero before the
+	// user was created
+	GetBalanceAt(ctx context.Context, userID uint, at time.Time) (balance decimal.Decimal, err error)
+	// GetDailyBalances returns the end of day balance of every day from the day of from to the day of
+	// to, the days are those of the location of from
+	GetDailyBalances(ctx context.Context, userID uint, from, to time.Time) (balances []*mysqlModel.DailyBalance, err error)
+}
+
+type IBalanceHistoryQueryRepo interface {
+	// GetLastTransaction returns the last transaction of the user created before before which recorded
+	// an exact balance of the user, nil if there is none
+	GetLastTransaction(ctx context.Context, userID uint, before time.Time) (transaction *mysqlModel.Transaction, err error)
+	// GetNextTransaction returns the first transaction of the user created at or after from which
+	// recorded an exact balance of the user, nil if there is none
+	GetNextTransaction(ctx context.Context, userID uint, from time.Time) (transaction *mysqlModel.Transaction, err error)
+	// GetTransactionsBetween returns the transactions of the user created in [from, to), in the order
+	// their balances were recorded
+	GetTransactionsBetween(ctx context.Context, userID uint, from, to time.Time) (transactions []*mysqlModel.Transaction, err error)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+//go:generate mockgen -destination ./mock/bucket.go -source=./bucket.go -package=mock
+
+type IBucketHandler interface {
+	SetBalanceBuckets() gin.HandlerFunc
+}
+
+type IBucketService interface {
+	// SetBalanceBuckets makes the user a hot account whose credits are spread over buckets, 0 folds
+	// the buckets back into the user row
+	SetBalanceBuckets(ctx context.Context, userID uint, buckets int) (user *mysqlModel.User, err error)
+	// Consolidate folds the buckets of every hot account into its user row
+	Consolidate(ctx context.Context) (consolidated int, err error)
+}
+
+type IBucketQueryRepo interface {
+	GetHotAccountIDs(ctx context.Context) (userIDs []uint, err error)
+}
+
+type IBucketCommandRepo interface {
+	SetBalanceBuckets(ctx context.Context, userID uint, buckets int) (user *mysqlModel.User, err error)
+	// Consolidate moves the balances of the buckets of the user to its user row and returns the sum moved
+	Consolidate(ctx context.Context, userID uint) (moved decimal.Decimal, err error)
+}
@@ -0,0 +1,66 @@
+package domain
+
+// ErrorCode is the stable machine readable code of an error, clients may rely on it
+type ErrorCode string
+
+const (
+	// generic codes
+	CodeInvalidRequest ErrorCode = "invalid_request"
+	CodeUnauthorized   ErrorCode = "unauthorized"
+	CodeForbidden      ErrorCode = "forbidden"
+	CodeNotFound       ErrorCode = "not_found"
+	CodeRateLimited    ErrorCode = "rate_limited"
+	CodeInternal       ErrorCode = "internal_error"
+
+	// user
+	CodeUserExists         ErrorCode = "user_exists"
+	CodeUserNotFound       ErrorCode = "user_not_found"
+	CodeInvalidCredentials ErrorCode = "invalid_credentials"
+
+	// transaction
+	CodeInsufficientBalance        ErrorCode = "insufficient_balance"
+	CodeUnsupportedTransactionType ErrorCode = "unsupported_transaction_type"
+	CodeComplianceRejected         ErrorCode = "compliance_rejected"
+	CodeTransferBlocked            ErrorCode = "transfer_blocked"
+	CodeTransferUnderReview        ErrorCode = "transfer_under_review"
+
+	// async transfer
+	CodeTransferRequestNotFound ErrorCode = "transfer_request_not_found"
+	CodeTransferInterrupted     ErrorCode = "transfer_interrupted"
+
+	// fraud review
+	CodeReviewNotFound   ErrorCode = "review_not_found"
+	CodeReviewNotPending ErrorCode = "review_not_pending"
+
+	// reconciliation
+	CodeUnsupportedFormat     ErrorCode = "unsupported_statement_format"
+	CodeInvalidStatement      ErrorCode = "invalid_statement"
+	CodeStatementLineNotFound ErrorCode = "statement_line_not_found"
+	CodeTransactionNotFound   ErrorCode = "transaction_not_found"
+	CodeLineAlreadyReconciled ErrorCode = "line_already_reconciled"
+	CodeTransactionMismatch   ErrorCode = "transaction_mismatch"
+	CodeTransactionClaimed    ErrorCode = "transaction_claimed"
+
+	// stream
+	CodeInvalidEventID ErrorCode = "invalid_event_id"
+
+	// payee alias
+	CodeAliasNotFound    ErrorCode = "alias_not_found"
+	CodeAliasTaken       ErrorCode = "alias_taken"
+	CodeAliasCodeInvalid ErrorCode = "alias_code_invalid"
+)
+
+// Error is an error with a code, its message is safe to show to clients. Errors without a code
+// are internal and never shown
+type Error struct {
+	Code ErrorCode
+	Msg  string
+}
+
+func NewError(code ErrorCode, msg string) *Error {
+	return &Error{Code: code, Msg: msg}
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+//go:generate mockgen -destination ./mock/fee.go -source=./fee.go -package=mock
+
+type IFeeService interface {
+	// Quote calculates the fee the user pays on a transfer or withdraw of amount, a zero Total means no fee
+	Quote(ctx context.Context, userID uint, transactionType mysqlModel.TransactionType, amount decimal.Decimal) (fee *mysqlModel.FeeBreakdown, err error)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+//go:generate mockgen -destination ./mock/fraud.go -source=./fraud.go -package=mock
+
+type IFraudHandler interface {
+	GetReviews() gin.HandlerFunc
+	ApproveReview() gin.HandlerFunc
+	RejectReview() gin.HandlerFunc
+}
+
+type IFraudService interface {
+	ScreenTransfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (review *mysqlModel.FraudReview, err error)
+	GetReviews(ctx context.Context, status mysqlModel.FraudReviewStatus) (reviews []*mysqlModel.FraudReview, err error)
+	// ClaimReview moves a pending review to approved before its transfer executes, ReleaseReview moves it
+	// back to pending when the transfer fails
+	ClaimReview(ctx context.Context, reviewID, adminID uint) (review *mysqlModel.FraudReview, err error)
+	ReleaseReview(ctx context.Context, reviewID uint) (err error)
+	RejectReview(ctx context.Context, reviewID, adminID uint) (err error)
+}
+
+type IFraudQueryRepo interface {
+	CountTransfers(ctx context.Context, fromUserID, toUserID uint, since time.Time) (count int64, err error)
+	GetAverageTransferAmount(ctx context.Context, userID uint, since time.Time) (average decimal.Decimal, count int64, err error)
+	GetAPIKeyCreatedAt(ctx context.Context, key string) (createdAt time.Time, err error)
+	GetReviews(ctx context.Context, status mysqlModel.FraudReviewStatus) (reviews []*mysqlModel.FraudReview, err error)
+	GetReview(ctx context.Context, reviewID uint) (review *mysqlModel.FraudReview, err error)
+}
+
+type IFraudCommandRepo interface {
+	CreateReview(ctx context.Context, review *mysqlModel.FraudReview) (err error)
+	UpdateReviewStatus(ctx context.Context, reviewID uint, from, to mysqlModel.FraudReviewStatus, adminID *uint) (err error)
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -destination ./mock/health.go -source=./health.go -package=mock
+
+const (
+	HealthStatusOK           = "ok"
+	HealthStatusFail         = "fail"
+	HealthStatusShuttingDown = "shutting_down"
+)
+
+// HealthReport is the result of the readiness checks, Status is ok only when every check is ok
+type HealthReport struct {
+	Status    string                       `json:"status"`
+	CheckedAt time.Time                    `json:"checkedAt"`
+	Checks    map[string]*DependencyHealth `json:"checks,omitempty"`
+}
+
+// DependencyHealth is the result of the check of one dependency, Error never holds internal details
+type DependencyHealth struct {
+	Status     string   `json:"status"`
+	LatencyMs  int64    `json:"latencyMs"`
+	LagSeconds *float64 `json:"lagSeconds,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+type IHealthHandler interface {
+	Liveness() gin.HandlerFunc
+	Readiness() gin.HandlerFunc
+}
+
+type IHealthService interface {
+	// Readiness checks the dependencies, results are reused for health.cacheTTL seconds
+	Readiness(ctx context.Context) (report *HealthReport)
+	// ShutDown makes readiness fail from now on, so no new traffic is routed to a server shutting down
+	ShutDown()
+}
+
+type IHealthQueryRepo interface {
+	Ping(ctx context.Context) (err error)
+	// ReplicaLag returns how far the replica is behind its source, ErrReplicationStopped when it does not replicate
+	ReplicaLag(ctx context.Context) (lag time.Duration, err error)
+}
+
+type IRedisHealthQueryRepo interface {
+	Ping(ctx context.Context) (err error)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+//go:generate mockgen -destination ./mock/interest.go -source=./interest.go -package=mock
+
+type IInterestService interface {
+	// AccrueDaily records the interest of date for every user on a rate plan, days already accrued are skipped
+	AccrueDaily(ctx context.Context, date time.Time) (accrued int, err error)
+	// Capitalize posts the accruals of the month containing month to the balances, months already posted are skipped
+	Capitalize(ctx context.Context, month time.Time) (capitalized int, err error)
+}
+
+type IInterestQueryRepo interface {
+	GetInterestUsers(ctx context.Context, afterID uint, limit int) (users []*mysqlModel.User, err error)
+	GetAccrualUserIDs(ctx context.Context, from, to time.Time) (userIDs []uint, err error)
+	// SumUncapitalizedAccruals sums the accruals of the user dated in [from, to) which are not capitalized yet,
+	// a zero from has no lower bound
+	SumUncapitalizedAccruals(ctx context.Context, userID uint, from, to time.Time) (sum decimal.Decimal, err error)
+	GetLastCarry(ctx context.Context, userID uint) (carry decimal.Decimal, err error)
+}
+
+type IInterestCommandRepo interface {
+	// CreateAccrual returns false when the user already has an accrual for the date
+	CreateAccrual(ctx context.Context, accrual *mysqlModel.InterestAccrual) (created bool, err error)
+	// Capitalize records capitalization, links the accruals in [from, to) to it and credits Posted to the user
+	Capitalize(ctx context.Context, capitalization *mysqlModel.InterestCapitalization, from, to time.Time) (err error)
+}
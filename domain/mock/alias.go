@@ -0,0 +1,402 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./alias.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	mysql "banking/model/mysql"
+	context "context"
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIAliasHandler is a mock of IAliasHandler interface.
+type MockIAliasHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAliasHandlerMockRecorder
+}
+
+// MockIAliasHandlerMockRecorder is the mock recorder for MockIAliasHandler.
+type MockIAliasHandlerMockRecorder struct {
+	mock *MockIAliasHandler
+}
+
+// NewMockIAliasHandler creates a new mock instance.
+func NewMockIAliasHandler(ctrl *gomock.Controller) *MockIAliasHandler {
+	mock := &MockIAliasHandler{ctrl: ctrl}
+	mock.recorder = &MockIAliasHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAliasHandler) EXPECT() *MockIAliasHandlerMockRecorder {
+	return m.recorder
+}
+
+// CreateAlias mocks base method.
+func (m *MockIAliasHandler) CreateAlias() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlias")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// CreateAlias indicates an expected call of CreateAlias.
+func (mr *MockIAliasHandlerMockRecorder) CreateAlias() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlias", reflect.TypeOf((*MockIAliasHandler)(nil).CreateAlias))
+}
+
+// DeleteAlias mocks base method.
+func (m *MockIAliasHandler) DeleteAlias() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlias")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// DeleteAlias indicates an expected call of DeleteAlias.
+func (mr *MockIAliasHandlerMockRecorder) DeleteAlias() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlias", reflect.TypeOf((*MockIAliasHandler)(nil).DeleteAlias))
+}
+
+// GetAliases mocks base method.
+func (m *MockIAliasHandler) GetAliases() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAliases")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// GetAliases indicates an expected call of GetAliases.
+func (mr *MockIAliasHandlerMockRecorder) GetAliases() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAliases", reflect.TypeOf((*MockIAliasHandler)(nil).GetAliases))
+}
+
+// ResolveAlias mocks base method.
+func (m *MockIAliasHandler) ResolveAlias() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAlias")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// ResolveAlias indicates an expected call of ResolveAlias.
+func (mr *MockIAliasHandlerMockRecorder) ResolveAlias() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAlias", reflect.TypeOf((*MockIAliasHandler)(nil).ResolveAlias))
+}
+
+// VerifyAlias mocks base method.
+func (m *MockIAliasHandler) VerifyAlias() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAlias")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// VerifyAlias indicates an expected call of VerifyAlias.
+func (mr *MockIAliasHandlerMockRecorder) VerifyAlias() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAlias", reflect.TypeOf((*MockIAliasHandler)(nil).VerifyAlias))
+}
+
+// MockIAliasService is a mock of IAliasService interface.
+type MockIAliasService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAliasServiceMockRecorder
+}
+
+// MockIAliasServiceMockRecorder is the mock recorder for MockIAliasService.
+type MockIAliasServiceMockRecorder struct {
+	mock *MockIAliasService
+}
+
+// NewMockIAliasService creates a new mock instance.
+func NewMockIAliasService(ctrl *gomock.Controller) *MockIAliasService {
+	mock := &MockIAliasService{ctrl: ctrl}
+	mock.recorder = &MockIAliasServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAliasService) EXPECT() *MockIAliasServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateAlias mocks base method.
+func (m *MockIAliasService) CreateAlias(ctx context.Context, userID uint, aliasType mysql.AliasType, value string) (*mysql.PayeeAlias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlias", ctx, userID, aliasType, value)
+	ret0, _ := ret[0].(*mysql.PayeeAlias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAlias indicates an expected call of CreateAlias.
+func (mr *MockIAliasServiceMockRecorder) CreateAlias(ctx, userID, aliasType, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlias", reflect.TypeOf((*MockIAliasService)(nil).CreateAlias), ctx, userID, aliasType, value)
+}
+
+// DeleteAlias mocks base method.
+func (m *MockIAliasService) DeleteAlias(ctx context.Context, userID, aliasID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlias", ctx, userID, aliasID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlias indicates an expected call of DeleteAlias.
+func (mr *MockIAliasServiceMockRecorder) DeleteAlias(ctx, userID, aliasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlias", reflect.TypeOf((*MockIAliasService)(nil).DeleteAlias), ctx, userID, aliasID)
+}
+
+// GetAliases mocks base method.
+func (m *MockIAliasService) GetAliases(ctx context.Context, userID uint) ([]*mysql.PayeeAlias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAliases", ctx, userID)
+	ret0, _ := ret[0].([]*mysql.PayeeAlias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAliases indicates an expected call of GetAliases.
+func (mr *MockIAliasServiceMockRecorder) GetAliases(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAliases", reflect.TypeOf((*MockIAliasService)(nil).GetAliases), ctx, userID)
+}
+
+// Resolve mocks base method.
+func (m *MockIAliasService) Resolve(ctx context.Context, alias string) (*mysql.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, alias)
+	ret0, _ := ret[0].(*mysql.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockIAliasServiceMockRecorder) Resolve(ctx, alias interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockIAliasService)(nil).Resolve), ctx, alias)
+}
+
+// VerifyAlias mocks base method.
+func (m *MockIAliasService) VerifyAlias(ctx context.Context, userID, aliasID uint, code string) (*mysql.PayeeAlias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAlias", ctx, userID, aliasID, code)
+	ret0, _ := ret[0].(*mysql.PayeeAlias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAlias indicates an expected call of VerifyAlias.
+func (mr *MockIAliasServiceMockRecorder) VerifyAlias(ctx, userID, aliasID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAlias", reflect.TypeOf((*MockIAliasService)(nil).VerifyAlias), ctx, userID, aliasID, code)
+}
+
+// MockICodeSender is a mock of ICodeSender interface.
+type MockICodeSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockICodeSenderMockRecorder
+}
+
+// MockICodeSenderMockRecorder is the mock recorder for MockICodeSender.
+type MockICodeSenderMockRecorder struct {
+	mock *MockICodeSender
+}
+
+// NewMockICodeSender creates a new mock instance.
+func NewMockICodeSender(ctrl *gomock.Controller) *MockICodeSender {
+	mock := &MockICodeSender{ctrl: ctrl}
+	mock.recorder = &MockICodeSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICodeSender) EXPECT() *MockICodeSenderMockRecorder {
+	return m.recorder
+}
+
+// SendCode mocks base method.
+func (m *MockICodeSender) SendCode(ctx context.Context, alias *mysql.PayeeAlias, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendCode", ctx, alias, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendCode indicates an expected call of SendCode.
+func (mr *MockICodeSenderMockRecorder) SendCode(ctx, alias, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCode", reflect.TypeOf((*MockICodeSender)(nil).SendCode), ctx, alias, code)
+}
+
+// MockIAliasQueryRepo is a mock of IAliasQueryRepo interface.
+type MockIAliasQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAliasQueryRepoMockRecorder
+}
+
+// MockIAliasQueryRepoMockRecorder is the mock recorder for MockIAliasQueryRepo.
+type MockIAliasQueryRepoMockRecorder struct {
+	mock *MockIAliasQueryRepo
+}
+
+// NewMockIAliasQueryRepo creates a new mock instance.
+func NewMockIAliasQueryRepo(ctrl *gomock.Controller) *MockIAliasQueryRepo {
+	mock := &MockIAliasQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIAliasQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAliasQueryRepo) EXPECT() *MockIAliasQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// GetAlias mocks base method.
+func (m *MockIAliasQueryRepo) GetAlias(ctx context.Context, aliasID uint) (*mysql.PayeeAlias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlias", ctx, aliasID)
+	ret0, _ := ret[0].(*mysql.PayeeAlias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlias indicates an expected call of GetAlias.
+func (mr *MockIAliasQueryRepoMockRecorder) GetAlias(ctx, aliasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlias", reflect.TypeOf((*MockIAliasQueryRepo)(nil).GetAlias), ctx, aliasID)
+}
+
+// GetAliases mocks base method.
+func (m *MockIAliasQueryRepo) GetAliases(ctx context.Context, userID uint) ([]*mysql.PayeeAlias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAliases", ctx, userID)
+	ret0, _ := ret[0].([]*mysql.PayeeAlias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAliases indicates an expected call of GetAliases.
+func (mr *MockIAliasQueryRepoMockRecorder) GetAliases(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAliases", reflect.TypeOf((*MockIAliasQueryRepo)(nil).GetAliases), ctx, userID)
+}
+
+// GetVerifiedAlias mocks base method.
+func (m *MockIAliasQueryRepo) GetVerifiedAlias(ctx context.Context, value string) (*mysql.PayeeAlias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVerifiedAlias", ctx, value)
+	ret0, _ := ret[0].(*mysql.PayeeAlias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVerifiedAlias indicates an expected call of GetVerifiedAlias.
+func (mr *MockIAliasQueryRepoMockRecorder) GetVerifiedAlias(ctx, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVerifiedAlias", reflect.TypeOf((*MockIAliasQueryRepo)(nil).GetVerifiedAlias), ctx, value)
+}
+
+// MockIAliasCommandRepo is a mock of IAliasCommandRepo interface.
+type MockIAliasCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAliasCommandRepoMockRecorder
+}
+
+// MockIAliasCommandRepoMockRecorder is the mock recorder for MockIAliasCommandRepo.
+type MockIAliasCommandRepoMockRecorder struct {
+	mock *MockIAliasCommandRepo
+}
+
+// NewMockIAliasCommandRepo creates a new mock instance.
+func NewMockIAliasCommandRepo(ctrl *gomock.Controller) *MockIAliasCommandRepo {
+	mock := &MockIAliasCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockIAliasCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAliasCommandRepo) EXPECT() *MockIAliasCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// CountAttempt mocks base method.
+func (m *MockIAliasCommandRepo) CountAttempt(ctx context.Context, aliasID uint, maxAttempts int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAttempt", ctx, aliasID, maxAttempts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CountAttempt indicates an expected call of CountAttempt.
+func (mr *MockIAliasCommandRepoMockRecorder) CountAttempt(ctx, aliasID, maxAttempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAttempt", reflect.TypeOf((*MockIAliasCommandRepo)(nil).CountAttempt), ctx, aliasID, maxAttempts)
+}
+
+// CreateAlias mocks base method.
+func (m *MockIAliasCommandRepo) CreateAlias(ctx context.Context, alias *mysql.PayeeAlias) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlias", ctx, alias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAlias indicates an expected call of CreateAlias.
+func (mr *MockIAliasCommandRepoMockRecorder) CreateAlias(ctx, alias interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlias", reflect.TypeOf((*MockIAliasCommandRepo)(nil).CreateAlias), ctx, alias)
+}
+
+// DeleteAlias mocks base method.
+func (m *MockIAliasCommandRepo) DeleteAlias(ctx context.Context, userID, aliasID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlias", ctx, userID, aliasID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlias indicates an expected call of DeleteAlias.
+func (mr *MockIAliasCommandRepoMockRecorder) DeleteAlias(ctx, userID, aliasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlias", reflect.TypeOf((*MockIAliasCommandRepo)(nil).DeleteAlias), ctx, userID, aliasID)
+}
+
+// RenewCode mocks base method.
+func (m *MockIAliasCommandRepo) RenewCode(ctx context.Context, alias *mysql.PayeeAlias) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewCode", ctx, alias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenewCode indicates an expected call of RenewCode.
+func (mr *MockIAliasCommandRepoMockRecorder) RenewCode(ctx, alias interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewCode", reflect.TypeOf((*MockIAliasCommandRepo)(nil).RenewCode), ctx, alias)
+}
+
+// VerifyAlias mocks base method.
+func (m *MockIAliasCommandRepo) VerifyAlias(ctx context.Context, alias *mysql.PayeeAlias) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAlias", ctx, alias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyAlias indicates an expected call of VerifyAlias.
+func (mr *MockIAliasCommandRepoMockRecorder) VerifyAlias(ctx, alias interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAlias", reflect.TypeOf((*MockIAliasCommandRepo)(nil).VerifyAlias), ctx, alias)
+}
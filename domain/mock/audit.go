@@ -0,0 +1,236 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./audit.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	mysql "banking/model/mysql"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIAuditHandler is a mock of IAuditHandler interface.
+type MockIAuditHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditHandlerMockRecorder
+}
+
+// MockIAuditHandlerMockRecorder is the mock recorder for MockIAuditHandler.
+type MockIAuditHandlerMockRecorder struct {
+	mock *MockIAuditHandler
+}
+
+// NewMockIAuditHandler creates a new mock instance.
+func NewMockIAuditHandler(ctrl *gomock.Controller) *MockIAuditHandler {
+	mock := &MockIAuditHandler{ctrl: ctrl}
+	mock.recorder = &MockIAuditHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditHandler) EXPECT() *MockIAuditHandlerMockRecorder {
+	return m.recorder
+}
+
+// GetAuditLogs mocks base method.
+func (m *MockIAuditHandler) GetAuditLogs() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogs")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// GetAuditLogs indicates an expected call of GetAuditLogs.
+func (mr *MockIAuditHandlerMockRecorder) GetAuditLogs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogs", reflect.TypeOf((*MockIAuditHandler)(nil).GetAuditLogs))
+}
+
+// VerifyAuditChain mocks base method.
+func (m *MockIAuditHandler) VerifyAuditChain() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAuditChain")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// VerifyAuditChain indicates an expected call of VerifyAuditChain.
+func (mr *MockIAuditHandlerMockRecorder) VerifyAuditChain() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAuditChain", reflect.TypeOf((*MockIAuditHandler)(nil).VerifyAuditChain))
+}
+
+// MockIAuditService is a mock of IAuditService interface.
+type MockIAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditServiceMockRecorder
+}
+
+// MockIAuditServiceMockRecorder is the mock recorder for MockIAuditService.
+type MockIAuditServiceMockRecorder struct {
+	mock *MockIAuditService
+}
+
+// NewMockIAuditService creates a new mock instance.
+func NewMockIAuditService(ctrl *gomock.Controller) *MockIAuditService {
+	mock := &MockIAuditService{ctrl: ctrl}
+	mock.recorder = &MockIAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditService) EXPECT() *MockIAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAuditLogs mocks base method.
+func (m *MockIAuditService) GetAuditLogs(ctx context.Context, actorID uint, action string, from, to time.Time, afterID uint, limit int) ([]*mysql.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogs", ctx, actorID, action, from, to, afterID, limit)
+	ret0, _ := ret[0].([]*mysql.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogs indicates an expected call of GetAuditLogs.
+func (mr *MockIAuditServiceMockRecorder) GetAuditLogs(ctx, actorID, action, from, to, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogs", reflect.TypeOf((*MockIAuditService)(nil).GetAuditLogs), ctx, actorID, action, from, to, afterID, limit)
+}
+
+// Prepare mocks base method.
+func (m *MockIAuditService) Prepare(ctx context.Context, action, target string, before interface{}) context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prepare", ctx, action, target, before)
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Prepare indicates an expected call of Prepare.
+func (mr *MockIAuditServiceMockRecorder) Prepare(ctx, action, target, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prepare", reflect.TypeOf((*MockIAuditService)(nil).Prepare), ctx, action, target, before)
+}
+
+// Record mocks base method.
+func (m *MockIAuditService) Record(ctx context.Context, action, target string, before, after interface{}, actionErr error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Record", ctx, action, target, before, after, actionErr)
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockIAuditServiceMockRecorder) Record(ctx, action, target, before, after, actionErr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockIAuditService)(nil).Record), ctx, action, target, before, after, actionErr)
+}
+
+// VerifyAuditChain mocks base method.
+func (m *MockIAuditService) VerifyAuditChain(ctx context.Context) (int, uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAuditChain", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(uint)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// VerifyAuditChain indicates an expected call of VerifyAuditChain.
+func (mr *MockIAuditServiceMockRecorder) VerifyAuditChain(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAuditChain", reflect.TypeOf((*MockIAuditService)(nil).VerifyAuditChain), ctx)
+}
+
+// MockIAuditQueryRepo is a mock of IAuditQueryRepo interface.
+type MockIAuditQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditQueryRepoMockRecorder
+}
+
+// MockIAuditQueryRepoMockRecorder is the mock recorder for MockIAuditQueryRepo.
+type MockIAuditQueryRepoMockRecorder struct {
+	mock *MockIAuditQueryRepo
+}
+
+// NewMockIAuditQueryRepo creates a new mock instance.
+func NewMockIAuditQueryRepo(ctrl *gomock.Controller) *MockIAuditQueryRepo {
+	mock := &MockIAuditQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIAuditQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditQueryRepo) EXPECT() *MockIAuditQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// GetAuditChainHead mocks base method.
+func (m *MockIAuditQueryRepo) GetAuditChainHead(ctx context.Context) (*mysql.AuditChainHead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditChainHead", ctx)
+	ret0, _ := ret[0].(*mysql.AuditChainHead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditChainHead indicates an expected call of GetAuditChainHead.
+func (mr *MockIAuditQueryRepoMockRecorder) GetAuditChainHead(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditChainHead", reflect.TypeOf((*MockIAuditQueryRepo)(nil).GetAuditChainHead), ctx)
+}
+
+// GetAuditLogs mocks base method.
+func (m *MockIAuditQueryRepo) GetAuditLogs(ctx context.Context, actorID uint, action string, from, to time.Time, afterID uint, limit int) ([]*mysql.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogs", ctx, actorID, action, from, to, afterID, limit)
+	ret0, _ := ret[0].([]*mysql.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogs indicates an expected call of GetAuditLogs.
+func (mr *MockIAuditQueryRepoMockRecorder) GetAuditLogs(ctx, actorID, action, from, to, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogs", reflect.TypeOf((*MockIAuditQueryRepo)(nil).GetAuditLogs), ctx, actorID, action, from, to, afterID, limit)
+}
+
+// MockIAuditCommandRepo is a mock of IAuditCommandRepo interface.
+type MockIAuditCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditCommandRepoMockRecorder
+}
+
+// MockIAuditCommandRepoMockRecorder is the mock recorder for MockIAuditCommandRepo.
+type MockIAuditCommandRepoMockRecorder struct {
+	mock *MockIAuditCommandRepo
+}
+
+// NewMockIAuditCommandRepo creates a new mock instance.
+func NewMockIAuditCommandRepo(ctrl *gomock.Controller) *MockIAuditCommandRepo {
+	mock := &MockIAuditCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockIAuditCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditCommandRepo) EXPECT() *MockIAuditCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// AppendAuditLog mocks base method.
+func (m *MockIAuditCommandRepo) AppendAuditLog(ctx context.Context, log *mysql.AuditLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendAuditLog", ctx, log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendAuditLog indicates an expected call of AppendAuditLog.
+func (mr *MockIAuditCommandRepoMockRecorder) AppendAuditLog(ctx, log interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendAuditLog", reflect.TypeOf((*MockIAuditCommandRepo)(nil).AppendAuditLog), ctx, log)
+}
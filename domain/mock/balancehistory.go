@@ -0,0 +1,188 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./balancehistory.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	mysql "banking/model/mysql"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockIBalanceHistoryHandler is a mock of IBalanceHistoryHandler interface.
+type MockIBalanceHistoryHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBalanceHistoryHandlerMockRecorder
+}
+
+// MockIBalanceHistoryHandlerMockRecorder is the mock recorder for MockIBalanceHistoryHandler.
+type MockIBalanceHistoryHandlerMockRecorder struct {
+	mock *MockIBalanceHistoryHandler
+}
+
+// NewMockIBalanceHistoryHandler creates a new mock instance.
+func NewMockIBalanceHistoryHandler(ctrl *gomock.Controller) *MockIBalanceHistoryHandler {
+	mock := &MockIBalanceHistoryHandler{ctrl: ctrl}
+	mock.recorder = &MockIBalanceHistoryHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBalanceHistoryHandler) EXPECT() *MockIBalanceHistoryHandlerMockRecorder {
+	return m.recorder
+}
+
+// GetBalanceAt mocks base method.
+func (m *MockIBalanceHistoryHandler) GetBalanceAt() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAt")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// GetBalanceAt indicates an expected call of GetBalanceAt.
+func (mr *MockIBalanceHistoryHandlerMockRecorder) GetBalanceAt() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAt", reflect.TypeOf((*MockIBalanceHistoryHandler)(nil).GetBalanceAt))
+}
+
+// GetDailyBalances mocks base method.
+func (m *MockIBalanceHistoryHandler) GetDailyBalances() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyBalances")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// GetDailyBalances indicates an expected call of GetDailyBalances.
+func (mr *MockIBalanceHistoryHandlerMockRecorder) GetDailyBalances() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyBalances", reflect.TypeOf((*MockIBalanceHistoryHandler)(nil).GetDailyBalances))
+}
+
+// MockIBalanceHistoryService is a mock of IBalanceHistoryService interface.
+type MockIBalanceHistoryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBalanceHistoryServiceMockRecorder
+}
+
+// MockIBalanceHistoryServiceMockRecorder is the mock recorder for MockIBalanceHistoryService.
+type MockIBalanceHistoryServiceMockRecorder struct {
+	mock *MockIBalanceHistoryService
+}
+
+// NewMockIBalanceHistoryService creates a new mock instance.
+func NewMockIBalanceHistoryService(ctrl *gomock.Controller) *MockIBalanceHistoryService {
+	mock := &MockIBalanceHistoryService{ctrl: ctrl}
+	mock.recorder = &MockIBalanceHistoryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBalanceHistoryService) EXPECT() *MockIBalanceHistoryServiceMockRecorder {
+	return m.recorder
+}
+
+// GetBalanceAt mocks base method.
+func (m *MockIBalanceHistoryService) GetBalanceAt(ctx context.Context, userID uint, at time.Time) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAt", ctx, userID, at)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAt indicates an expected call of GetBalanceAt.
+func (mr *MockIBalanceHistoryServiceMockRecorder) GetBalanceAt(ctx, userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAt", reflect.TypeOf((*MockIBalanceHistoryService)(nil).GetBalanceAt), ctx, userID, at)
+}
+
+// GetDailyBalances mocks base method.
+func (m *MockIBalanceHistoryService) GetDailyBalances(ctx context.Context, userID uint, from, to time.Time) ([]*mysql.DailyBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyBalances", ctx, userID, from, to)
+	ret0, _ := ret[0].([]*mysql.DailyBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailyBalances indicates an expected call of GetDailyBalances.
+func (mr *MockIBalanceHistoryServiceMockRecorder) GetDailyBalances(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyBalances", reflect.TypeOf((*MockIBalanceHistoryService)(nil).GetDailyBalances), ctx, userID, from, to)
+}
+
+// MockIBalanceHistoryQueryRepo is a mock of IBalanceHistoryQueryRepo interface.
+type MockIBalanceHistoryQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBalanceHistoryQueryRepoMockRecorder
+}
+
+// MockIBalanceHistoryQueryRepoMockRecorder is the mock recorder for MockIBalanceHistoryQueryRepo.
+type MockIBalanceHistoryQueryRepoMockRecorder struct {
+	mock *MockIBalanceHistoryQueryRepo
+}
+
+// NewMockIBalanceHistoryQueryRepo creates a new mock instance.
+func NewMockIBalanceHistoryQueryRepo(ctrl *gomock.Controller) *MockIBalanceHistoryQueryRepo {
+	mock := &MockIBalanceHistoryQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIBalanceHistoryQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBalanceHistoryQueryRepo) EXPECT() *MockIBalanceHistoryQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// GetLastTransaction mocks base method.
+func (m *MockIBalanceHistoryQueryRepo) GetLastTransaction(ctx context.Context, userID uint, before time.Time) (*mysql.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastTransaction", ctx, userID, before)
+	ret0, _ := ret[0].(*mysql.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastTransaction indicates an expected call of GetLastTransaction.
+func (mr *MockIBalanceHistoryQueryRepoMockRecorder) GetLastTransaction(ctx, userID, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastTransaction", reflect.TypeOf((*MockIBalanceHistoryQueryRepo)(nil).GetLastTransaction), ctx, userID, before)
+}
+
+// GetNextTransaction mocks base method.
+func (m *MockIBalanceHistoryQueryRepo) GetNextTransaction(ctx context.Context, userID uint, from time.Time) (*mysql.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextTransaction", ctx, userID, from)
+	ret0, _ := ret[0].(*mysql.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextTransaction indicates an expected call of GetNextTransaction.
+func (mr *MockIBalanceHistoryQueryRepoMockRecorder) GetNextTransaction(ctx, userID, from interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextTransaction", reflect.TypeOf((*MockIBalanceHistoryQueryRepo)(nil).GetNextTransaction), ctx, userID, from)
+}
+
+// GetTransactionsBetween mocks base method.
+func (m *MockIBalanceHistoryQueryRepo) GetTransactionsBetween(ctx context.Context, userID uint, from, to time.Time) ([]*mysql.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsBetween", ctx, userID, from, to)
+	ret0, _ := ret[0].([]*mysql.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsBetween indicates an expected call of GetTransactionsBetween.
+func (mr *MockIBalanceHistoryQueryRepoMockRecorder) GetTransactionsBetween(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsBetween", reflect.TypeOf((*MockIBalanceHistoryQueryRepo)(nil).GetTransactionsBetween), ctx, userID, from, to)
+}
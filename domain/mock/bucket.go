@@ -0,0 +1,196 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./bucket.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	mysql "banking/model/mysql"
+	context "context"
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockIBucketHandler is a mock of IBucketHandler interface.
+type MockIBucketHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBucketHandlerMockRecorder
+}
+
+// MockIBucketHandlerMockRecorder is the mock recorder for MockIBucketHandler.
+type MockIBucketHandlerMockRecorder struct {
+	mock *MockIBucketHandler
+}
+
+// NewMockIBucketHandler creates a new mock instance.
+func NewMockIBucketHandler(ctrl *gomock.Controller) *MockIBucketHandler {
+	mock := &MockIBucketHandler{ctrl: ctrl}
+	mock.recorder = &MockIBucketHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBucketHandler) EXPECT() *MockIBucketHandlerMockRecorder {
+	return m.recorder
+}
+
+// SetBalanceBuckets mocks base method.
+func (m *MockIBucketHandler) SetBalanceBuckets() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBalanceBuckets")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// SetBalanceBuckets indicates an expected call of SetBalanceBuckets.
+func (mr *MockIBucketHandlerMockRecorder) SetBalanceBuckets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBalanceBuckets", reflect.TypeOf((*MockIBucketHandler)(nil).SetBalanceBuckets))
+}
+
+// MockIBucketService is a mock of IBucketService interface.
+type MockIBucketService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBucketServiceMockRecorder
+}
+
+// MockIBucketServiceMockRecorder is the mock recorder for MockIBucketService.
+type MockIBucketServiceMockRecorder struct {
+	mock *MockIBucketService
+}
+
+// NewMockIBucketService creates a new mock instance.
+func NewMockIBucketService(ctrl *gomock.Controller) *MockIBucketService {
+	mock := &MockIBucketService{ctrl: ctrl}
+	mock.recorder = &MockIBucketServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBucketService) EXPECT() *MockIBucketServiceMockRecorder {
+	return m.recorder
+}
+
+// Consolidate mocks base method.
+func (m *MockIBucketService) Consolidate(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consolidate", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consolidate indicates an expected call of Consolidate.
+func (mr *MockIBucketServiceMockRecorder) Consolidate(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consolidate", reflect.TypeOf((*MockIBucketService)(nil).Consolidate), ctx)
+}
+
+// SetBalanceBuckets mocks base method.
+func (m *MockIBucketService) SetBalanceBuckets(ctx context.Context, userID uint, buckets int) (*mysql.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBalanceBuckets", ctx, userID, buckets)
+	ret0, _ := ret[0].(*mysql.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBalanceBuckets indicates an expected call of SetBalanceBuckets.
+func (mr *MockIBucketServiceMockRecorder) SetBalanceBuckets(ctx, userID, buckets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBalanceBuckets", reflect.TypeOf((*MockIBucketService)(nil).SetBalanceBuckets), ctx, userID, buckets)
+}
+
+// MockIBucketQueryRepo is a mock of IBucketQueryRepo interface.
+type MockIBucketQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBucketQueryRepoMockRecorder
+}
+
+// MockIBucketQueryRepoMockRecorder is the mock recorder for MockIBucketQueryRepo.
+type MockIBucketQueryRepoMockRecorder struct {
+	mock *MockIBucketQueryRepo
+}
+
+// NewMockIBucketQueryRepo creates a new mock instance.
+func NewMockIBucketQueryRepo(ctrl *gomock.Controller) *MockIBucketQueryRepo {
+	mock := &MockIBucketQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIBucketQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBucketQueryRepo) EXPECT() *MockIBucketQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// GetHotAccountIDs mocks base method.
+func (m *MockIBucketQueryRepo) GetHotAccountIDs(ctx context.Context) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHotAccountIDs", ctx)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHotAccountIDs indicates an expected call of GetHotAccountIDs.
+func (mr *MockIBucketQueryRepoMockRecorder) GetHotAccountIDs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHotAccountIDs", reflect.TypeOf((*MockIBucketQueryRepo)(nil).GetHotAccountIDs), ctx)
+}
+
+// MockIBucketCommandRepo is a mock of IBucketCommandRepo interface.
+type MockIBucketCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBucketCommandRepoMockRecorder
+}
+
+// MockIBucketCommandRepoMockRecorder is the mock recorder for MockIBucketCommandRepo.
+type MockIBucketCommandRepoMockRecorder struct {
+	mock *MockIBucketCommandRepo
+}
+
+// NewMockIBucketCommandRepo creates a new mock instance.
+func NewMockIBucketCommandRepo(ctrl *gomock.Controller) *MockIBucketCommandRepo {
+	mock := &MockIBucketCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockIBucketCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBucketCommandRepo) EXPECT() *MockIBucketCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// Consolidate mocks base method.
+func (m *MockIBucketCommandRepo) Consolidate(ctx context.Context, userID uint) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consolidate", ctx, userID)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consolidate indicates an expected call of Consolidate.
+func (mr *MockIBucketCommandRepoMockRecorder) Consolidate(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consolidate", reflect.TypeOf((*MockIBucketCommandRepo)(nil).Consolidate), ctx, userID)
+}
+
+// SetBalanceBuckets mocks base method.
+func (m *MockIBucketCommandRepo) SetBalanceBuckets(ctx context.Context, userID uint, buckets int) (*mysql.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBalanceBuckets", ctx, userID, buckets)
+	ret0, _ := ret[0].(*mysql.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBalanceBuckets indicates an expected call of SetBalanceBuckets.
+func (mr *MockIBucketCommandRepoMockRecorder) SetBalanceBuckets(ctx, userID, buckets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBalanceBuckets", reflect.TypeOf((*MockIBucketCommandRepo)(nil).SetBalanceBuckets), ctx, userID, buckets)
+}
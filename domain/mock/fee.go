@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./fee.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	mysql "banking/model/mysql"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockIFeeService is a mock of IFeeService interface.
+type MockIFeeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFeeServiceMockRecorder
+}
+
+// MockIFeeServiceMockRecorder is the mock recorder for MockIFeeService.
+type MockIFeeServiceMockRecorder struct {
+	mock *MockIFeeService
+}
+
+// NewMockIFeeService creates a new mock instance.
+func NewMockIFeeService(ctrl *gomock.Controller) *MockIFeeService {
+	mock := &MockIFeeService{ctrl: ctrl}
+	mock.recorder = &MockIFeeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIFeeService) EXPECT() *MockIFeeServiceMockRecorder {
+	return m.recorder
+}
+
+// Quote mocks base method.
+func (m *MockIFeeService) Quote(ctx context.Context, userID uint, transactionType mysql.TransactionType, amount decimal.Decimal) (*mysql.FeeBreakdown, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", ctx, userID, transactionType, amount)
+	ret0, _ := ret[0].(*mysql.FeeBreakdown)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockIFeeServiceMockRecorder) Quote(ctx, userID, transactionType, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockIFeeService)(nil).Quote), ctx, userID, transactionType, amount)
+}
@@ -0,0 +1,327 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./fraud.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	mysql "banking/model/mysql"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockIFraudHandler is a mock of IFraudHandler interface.
+type MockIFraudHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFraudHandlerMockRecorder
+}
+
+// MockIFraudHandlerMockRecorder is the mock recorder for MockIFraudHandler.
+type MockIFraudHandlerMockRecorder struct {
+	mock *MockIFraudHandler
+}
+
+// NewMockIFraudHandler creates a new mock instance.
+func NewMockIFraudHandler(ctrl *gomock.Controller) *MockIFraudHandler {
+	mock := &MockIFraudHandler{ctrl: ctrl}
+	mock.recorder = &MockIFraudHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIFraudHandler) EXPECT() *MockIFraudHandlerMockRecorder {
+	return m.recorder
+}
+
+// ApproveReview mocks base method.
+func (m *MockIFraudHandler) ApproveReview() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveReview")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// ApproveReview indicates an expected call of ApproveReview.
+func (mr *MockIFraudHandlerMockRecorder) ApproveReview() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveReview", reflect.TypeOf((*MockIFraudHandler)(nil).ApproveReview))
+}
+
+// GetReviews mocks base method.
+func (m *MockIFraudHandler) GetReviews() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockIFraudHandlerMockRecorder) GetReviews() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockIFraudHandler)(nil).GetReviews))
+}
+
+// RejectReview mocks base method.
+func (m *MockIFraudHandler) RejectReview() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectReview")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// RejectReview indicates an expected call of RejectReview.
+func (mr *MockIFraudHandlerMockRecorder) RejectReview() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectReview", reflect.TypeOf((*MockIFraudHandler)(nil).RejectReview))
+}
+
+// MockIFraudService is a mock of IFraudService interface.
+type MockIFraudService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFraudServiceMockRecorder
+}
+
+// MockIFraudServiceMockRecorder is the mock recorder for MockIFraudService.
+type MockIFraudServiceMockRecorder struct {
+	mock *MockIFraudService
+}
+
+// NewMockIFraudService creates a new mock instance.
+func NewMockIFraudService(ctrl *gomock.Controller) *MockIFraudService {
+	mock := &MockIFraudService{ctrl: ctrl}
+	mock.recorder = &MockIFraudServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIFraudService) EXPECT() *MockIFraudServiceMockRecorder {
+	return m.recorder
+}
+
+// ClaimReview mocks base method.
+func (m *MockIFraudService) ClaimReview(ctx context.Context, reviewID, adminID uint) (*mysql.FraudReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimReview", ctx, reviewID, adminID)
+	ret0, _ := ret[0].(*mysql.FraudReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimReview indicates an expected call of ClaimReview.
+func (mr *MockIFraudServiceMockRecorder) ClaimReview(ctx, reviewID, adminID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimReview", reflect.TypeOf((*MockIFraudService)(nil).ClaimReview), ctx, reviewID, adminID)
+}
+
+// GetReviews mocks base method.
+func (m *MockIFraudService) GetReviews(ctx context.Context, status mysql.FraudReviewStatus) ([]*mysql.FraudReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", ctx, status)
+	ret0, _ := ret[0].([]*mysql.FraudReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockIFraudServiceMockRecorder) GetReviews(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockIFraudService)(nil).GetReviews), ctx, status)
+}
+
+// RejectReview mocks base method.
+func (m *MockIFraudService) RejectReview(ctx context.Context, reviewID, adminID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectReview", ctx, reviewID, adminID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectReview indicates an expected call of RejectReview.
+func (mr *MockIFraudServiceMockRecorder) RejectReview(ctx, reviewID, adminID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectReview", reflect.TypeOf((*MockIFraudService)(nil).RejectReview), ctx, reviewID, adminID)
+}
+
+// ReleaseReview mocks base method.
+func (m *MockIFraudService) ReleaseReview(ctx context.Context, reviewID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReview", ctx, reviewID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseReview indicates an expected call of ReleaseReview.
+func (mr *MockIFraudServiceMockRecorder) ReleaseReview(ctx, reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReview", reflect.TypeOf((*MockIFraudService)(nil).ReleaseReview), ctx, reviewID)
+}
+
+// ScreenTransfer mocks base method.
+func (m *MockIFraudService) ScreenTransfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, reference *mysql.TransactionReference) (*mysql.FraudReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScreenTransfer", ctx, fromUserID, toUserID, amount, reference)
+	ret0, _ := ret[0].(*mysql.FraudReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScreenTransfer indicates an expected call of ScreenTransfer.
+func (mr *MockIFraudServiceMockRecorder) ScreenTransfer(ctx, fromUserID, toUserID, amount, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScreenTransfer", reflect.TypeOf((*MockIFraudService)(nil).ScreenTransfer), ctx, fromUserID, toUserID, amount, reference)
+}
+
+// MockIFraudQueryRepo is a mock of IFraudQueryRepo interface.
+type MockIFraudQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFraudQueryRepoMockRecorder
+}
+
+// MockIFraudQueryRepoMockRecorder is the mock recorder for MockIFraudQueryRepo.
+type MockIFraudQueryRepoMockRecorder struct {
+	mock *MockIFraudQueryRepo
+}
+
+// NewMockIFraudQueryRepo creates a new mock instance.
+func NewMockIFraudQueryRepo(ctrl *gomock.Controller) *MockIFraudQueryRepo {
+	mock := &MockIFraudQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIFraudQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIFraudQueryRepo) EXPECT() *MockIFraudQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// CountTransfers mocks base method.
+func (m *MockIFraudQueryRepo) CountTransfers(ctx context.Context, fromUserID, toUserID uint, since time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTransfers", ctx, fromUserID, toUserID, since)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTransfers indicates an expected call of CountTransfers.
+func (mr *MockIFraudQueryRepoMockRecorder) CountTransfers(ctx, fromUserID, toUserID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTransfers", reflect.TypeOf((*MockIFraudQueryRepo)(nil).CountTransfers), ctx, fromUserID, toUserID, since)
+}
+
+// GetAPIKeyCreatedAt mocks base method.
+func (m *MockIFraudQueryRepo) GetAPIKeyCreatedAt(ctx context.Context, key string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyCreatedAt", ctx, key)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyCreatedAt indicates an expected call of GetAPIKeyCreatedAt.
+func (mr *MockIFraudQueryRepoMockRecorder) GetAPIKeyCreatedAt(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyCreatedAt", reflect.TypeOf((*MockIFraudQueryRepo)(nil).GetAPIKeyCreatedAt), ctx, key)
+}
+
+// GetAverageTransferAmount mocks base method.
+func (m *MockIFraudQueryRepo) GetAverageTransferAmount(ctx context.Context, userID uint, since time.Time) (decimal.Decimal, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAverageTransferAmount", ctx, userID, since)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAverageTransferAmount indicates an expected call of GetAverageTransferAmount.
+func (mr *MockIFraudQueryRepoMockRecorder) GetAverageTransferAmount(ctx, userID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAverageTransferAmount", reflect.TypeOf((*MockIFraudQueryRepo)(nil).GetAverageTransferAmount), ctx, userID, since)
+}
+
+// GetReview mocks base method.
+func (m *MockIFraudQueryRepo) GetReview(ctx context.Context, reviewID uint) (*mysql.FraudReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReview", ctx, reviewID)
+	ret0, _ := ret[0].(*mysql.FraudReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReview indicates an expected call of GetReview.
+func (mr *MockIFraudQueryRepoMockRecorder) GetReview(ctx, reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReview", reflect.TypeOf((*MockIFraudQueryRepo)(nil).GetReview), ctx, reviewID)
+}
+
+// GetReviews mocks base method.
+func (m *MockIFraudQueryRepo) GetReviews(ctx context.Context, status mysql.FraudReviewStatus) ([]*mysql.FraudReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", ctx, status)
+	ret0, _ := ret[0].([]*mysql.FraudReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockIFraudQueryRepoMockRecorder) GetReviews(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockIFraudQueryRepo)(nil).GetReviews), ctx, status)
+}
+
+// MockIFraudCommandRepo is a mock of IFraudCommandRepo interface.
+type MockIFraudCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFraudCommandRepoMockRecorder
+}
+
+// MockIFraudCommandRepoMockRecorder is the mock recorder for MockIFraudCommandRepo.
+type MockIFraudCommandRepoMockRecorder struct {
+	mock *MockIFraudCommandRepo
+}
+
+// NewMockIFraudCommandRepo creates a new mock instance.
+func NewMockIFraudCommandRepo(ctrl *gomock.Controller) *MockIFraudCommandRepo {
+	mock := &MockIFraudCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockIFraudCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIFraudCommandRepo) EXPECT() *MockIFraudCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateReview mocks base method.
+func (m *MockIFraudCommandRepo) CreateReview(ctx context.Context, review *mysql.FraudReview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReview", ctx, review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReview indicates an expected call of CreateReview.
+func (mr *MockIFraudCommandRepoMockRecorder) CreateReview(ctx, review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReview", reflect.TypeOf((*MockIFraudCommandRepo)(nil).CreateReview), ctx, review)
+}
+
+// UpdateReviewStatus mocks base method.
+func (m *MockIFraudCommandRepo) UpdateReviewStatus(ctx context.Context, reviewID uint, from, to mysql.FraudReviewStatus, adminID *uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReviewStatus", ctx, reviewID, from, to, adminID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReviewStatus indicates an expected call of UpdateReviewStatus.
+func (mr *MockIFraudCommandRepoMockRecorder) UpdateReviewStatus(ctx, reviewID, from, to, adminID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReviewStatus", reflect.TypeOf((*MockIFraudCommandRepo)(nil).UpdateReviewStatus), ctx, reviewID, from, to, adminID)
+}
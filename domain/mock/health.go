@@ -0,0 +1,204 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./health.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "banking/domain"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIHealthHandler is a mock of IHealthHandler interface.
+type MockIHealthHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockIHealthHandlerMockRecorder
+}
+
+// MockIHealthHandlerMockRecorder is the mock recorder for MockIHealthHandler.
+type MockIHealthHandlerMockRecorder struct {
+	mock *MockIHealthHandler
+}
+
+// NewMockIHealthHandler creates a new mock instance.
+func NewMockIHealthHandler(ctrl *gomock.Controller) *MockIHealthHandler {
+	mock := &MockIHealthHandler{ctrl: ctrl}
+	mock.recorder = &MockIHealthHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIHealthHandler) EXPECT() *MockIHealthHandlerMockRecorder {
+	return m.recorder
+}
+
+// Liveness mocks base method.
+func (m *MockIHealthHandler) Liveness() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Liveness")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// Liveness indicates an expected call of Liveness.
+func (mr *MockIHealthHandlerMockRecorder) Liveness() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Liveness", reflect.TypeOf((*MockIHealthHandler)(nil).Liveness))
+}
+
+// Readiness mocks base method.
+func (m *MockIHealthHandler) Readiness() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Readiness")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// Readiness indicates an expected call of Readiness.
+func (mr *MockIHealthHandlerMockRecorder) Readiness() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Readiness", reflect.TypeOf((*MockIHealthHandler)(nil).Readiness))
+}
+
+// MockIHealthService is a mock of IHealthService interface.
+type MockIHealthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIHealthServiceMockRecorder
+}
+
+// MockIHealthServiceMockRecorder is the mock recorder for MockIHealthService.
+type MockIHealthServiceMockRecorder struct {
+	mock *MockIHealthService
+}
+
+// NewMockIHealthService creates a new mock instance.
+func NewMockIHealthService(ctrl *gomock.Controller) *MockIHealthService {
+	mock := &MockIHealthService{ctrl: ctrl}
+	mock.recorder = &MockIHealthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIHealthService) EXPECT() *MockIHealthServiceMockRecorder {
+	return m.recorder
+}
+
+// Readiness mocks base method.
+func (m *MockIHealthService) Readiness(ctx context.Context) *domain.HealthReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Readiness", ctx)
+	ret0, _ := ret[0].(*domain.HealthReport)
+	return ret0
+}
+
+// Readiness indicates an expected call of Readiness.
+func (mr *MockIHealthServiceMockRecorder) Readiness(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Readiness", reflect.TypeOf((*MockIHealthService)(nil).Readiness), ctx)
+}
+
+// ShutDown mocks base method.
+func (m *MockIHealthService) ShutDown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ShutDown")
+}
+
+// ShutDown indicates an expected call of ShutDown.
+func (mr *MockIHealthServiceMockRecorder) ShutDown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShutDown", reflect.TypeOf((*MockIHealthService)(nil).ShutDown))
+}
+
+// MockIHealthQueryRepo is a mock of IHealthQueryRepo interface.
+type MockIHealthQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIHealthQueryRepoMockRecorder
+}
+
+// MockIHealthQueryRepoMockRecorder is the mock recorder for MockIHealthQueryRepo.
+type MockIHealthQueryRepoMockRecorder struct {
+	mock *MockIHealthQueryRepo
+}
+
+// NewMockIHealthQueryRepo creates a new mock instance.
+func NewMockIHealthQueryRepo(ctrl *gomock.Controller) *MockIHealthQueryRepo {
+	mock := &MockIHealthQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIHealthQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIHealthQueryRepo) EXPECT() *MockIHealthQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// Ping mocks base method.
+func (m *MockIHealthQueryRepo) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockIHealthQueryRepoMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockIHealthQueryRepo)(nil).Ping), ctx)
+}
+
+// ReplicaLag mocks base method.
+func (m *MockIHealthQueryRepo) ReplicaLag(ctx context.Context) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplicaLag", ctx)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplicaLag indicates an expected call of ReplicaLag.
+func (mr *MockIHealthQueryRepoMockRecorder) ReplicaLag(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplicaLag", reflect.TypeOf((*MockIHealthQueryRepo)(nil).ReplicaLag), ctx)
+}
+
+// MockIRedisHealthQueryRepo is a mock of IRedisHealthQueryRepo interface.
+type MockIRedisHealthQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRedisHealthQueryRepoMockRecorder
+}
+
+// MockIRedisHealthQueryRepoMockRecorder is the mock recorder for MockIRedisHealthQueryRepo.
+type MockIRedisHealthQueryRepoMockRecorder struct {
+	mock *MockIRedisHealthQueryRepo
+}
+
+// NewMockIRedisHealthQueryRepo creates a new mock instance.
+func NewMockIRedisHealthQueryRepo(ctrl *gomock.Controller) *MockIRedisHealthQueryRepo {
+	mock := &MockIRedisHealthQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIRedisHealthQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRedisHealthQueryRepo) EXPECT() *MockIRedisHealthQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// Ping mocks base method.
+func (m *MockIRedisHealthQueryRepo) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockIRedisHealthQueryRepoMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockIRedisHealthQueryRepo)(nil).Ping), ctx)
+}
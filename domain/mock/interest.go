@@ -0,0 +1,203 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./interest.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	mysql "banking/model/mysql"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockIInterestService is a mock of IInterestService interface.
+type MockIInterestService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIInterestServiceMockRecorder
+}
+
+// MockIInterestServiceMockRecorder is the mock recorder for MockIInterestService.
+type MockIInterestServiceMockRecorder struct {
+	mock *MockIInterestService
+}
+
+// NewMockIInterestService creates a new mock instance.
+func NewMockIInterestService(ctrl *gomock.Controller) *MockIInterestService {
+	mock := &MockIInterestService{ctrl: ctrl}
+	mock.recorder = &MockIInterestServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIInterestService) EXPECT() *MockIInterestServiceMockRecorder {
+	return m.recorder
+}
+
+// AccrueDaily mocks base method.
+func (m *MockIInterestService) AccrueDaily(ctx context.Context, date time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueDaily", ctx, date)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrueDaily indicates an expected call of AccrueDaily.
+func (mr *MockIInterestServiceMockRecorder) AccrueDaily(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueDaily", reflect.TypeOf((*MockIInterestService)(nil).AccrueDaily), ctx, date)
+}
+
+// Capitalize mocks base method.
+func (m *MockIInterestService) Capitalize(ctx context.Context, month time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capitalize", ctx, month)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capitalize indicates an expected call of Capitalize.
+func (mr *MockIInterestServiceMockRecorder) Capitalize(ctx, month interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capitalize", reflect.TypeOf((*MockIInterestService)(nil).Capitalize), ctx, month)
+}
+
+// MockIInterestQueryRepo is a mock of IInterestQueryRepo interface.
+type MockIInterestQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIInterestQueryRepoMockRecorder
+}
+
+// MockIInterestQueryRepoMockRecorder is the mock recorder for MockIInterestQueryRepo.
+type MockIInterestQueryRepoMockRecorder struct {
+	mock *MockIInterestQueryRepo
+}
+
+// NewMockIInterestQueryRepo creates a new mock instance.
+func NewMockIInterestQueryRepo(ctrl *gomock.Controller) *MockIInterestQueryRepo {
+	mock := &MockIInterestQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIInterestQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIInterestQueryRepo) EXPECT() *MockIInterestQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// GetAccrualUserIDs mocks base method.
+func (m *MockIInterestQueryRepo) GetAccrualUserIDs(ctx context.Context, from, to time.Time) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccrualUserIDs", ctx, from, to)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccrualUserIDs indicates an expected call of GetAccrualUserIDs.
+func (mr *MockIInterestQueryRepoMockRecorder) GetAccrualUserIDs(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccrualUserIDs", reflect.TypeOf((*MockIInterestQueryRepo)(nil).GetAccrualUserIDs), ctx, from, to)
+}
+
+// GetInterestUsers mocks base method.
+func (m *MockIInterestQueryRepo) GetInterestUsers(ctx context.Context, afterID uint, limit int) ([]*mysql.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInterestUsers", ctx, afterID, limit)
+	ret0, _ := ret[0].([]*mysql.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInterestUsers indicates an expected call of GetInterestUsers.
+func (mr *MockIInterestQueryRepoMockRecorder) GetInterestUsers(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterestUsers", reflect.TypeOf((*MockIInterestQueryRepo)(nil).GetInterestUsers), ctx, afterID, limit)
+}
+
+// GetLastCarry mocks base method.
+func (m *MockIInterestQueryRepo) GetLastCarry(ctx context.Context, userID uint) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastCarry", ctx, userID)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastCarry indicates an expected call of GetLastCarry.
+func (mr *MockIInterestQueryRepoMockRecorder) GetLastCarry(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastCarry", reflect.TypeOf((*MockIInterestQueryRepo)(nil).GetLastCarry), ctx, userID)
+}
+
+// SumUncapitalizedAccruals mocks base method.
+func (m *MockIInterestQueryRepo) SumUncapitalizedAccruals(ctx context.Context, userID uint, from, to time.Time) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumUncapitalizedAccruals", ctx, userID, from, to)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumUncapitalizedAccruals indicates an expected call of SumUncapitalizedAccruals.
+func (mr *MockIInterestQueryRepoMockRecorder) SumUncapitalizedAccruals(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumUncapitalizedAccruals", reflect.TypeOf((*MockIInterestQueryRepo)(nil).SumUncapitalizedAccruals), ctx, userID, from, to)
+}
+
+// MockIInterestCommandRepo is a mock of IInterestCommandRepo interface.
+type MockIInterestCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIInterestCommandRepoMockRecorder
+}
+
+// MockIInterestCommandRepoMockRecorder is the mock recorder for MockIInterestCommandRepo.
+type MockIInterestCommandRepoMockRecorder struct {
+	mock *MockIInterestCommandRepo
+}
+
+// NewMockIInterestCommandRepo creates a new mock instance.
+func NewMockIInterestCommandRepo(ctrl *gomock.Controller) *MockIInterestCommandRepo {
+	mock := &MockIInterestCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockIInterestCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIInterestCommandRepo) EXPECT() *MockIInterestCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// Capitalize mocks base method.
+func (m *MockIInterestCommandRepo) Capitalize(ctx context.Context, capitalization *mysql.InterestCapitalization, from, to time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capitalize", ctx, capitalization, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Capitalize indicates an expected call of Capitalize.
+func (mr *MockIInterestCommandRepoMockRecorder) Capitalize(ctx, capitalization, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capitalize", reflect.TypeOf((*MockIInterestCommandRepo)(nil).Capitalize), ctx, capitalization, from, to)
+}
+
+// CreateAccrual mocks base method.
+func (m *MockIInterestCommandRepo) CreateAccrual(ctx context.Context, accrual *mysql.InterestAccrual) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccrual", ctx, accrual)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccrual indicates an expected call of CreateAccrual.
+func (mr *MockIInterestCommandRepoMockRecorder) CreateAccrual(ctx, accrual interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccrual", reflect.TypeOf((*MockIInterestCommandRepo)(nil).CreateAccrual), ctx, accrual)
+}
@@ -0,0 +1,232 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./projection.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	mysql "banking/model/mysql"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockIProjectionService is a mock of IProjectionService interface.
+type MockIProjectionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIProjectionServiceMockRecorder
+}
+
+// MockIProjectionServiceMockRecorder is the mock recorder for MockIProjectionService.
+type MockIProjectionServiceMockRecorder struct {
+	mock *MockIProjectionService
+}
+
+// NewMockIProjectionService creates a new mock instance.
+func NewMockIProjectionService(ctrl *gomock.Controller) *MockIProjectionService {
+	mock := &MockIProjectionService{ctrl: ctrl}
+	mock.recorder = &MockIProjectionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIProjectionService) EXPECT() *MockIProjectionServiceMockRecorder {
+	return m.recorder
+}
+
+// Project mocks base method.
+func (m *MockIProjectionService) Project(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Project", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Project indicates an expected call of Project.
+func (mr *MockIProjectionServiceMockRecorder) Project(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Project", reflect.TypeOf((*MockIProjectionService)(nil).Project), ctx)
+}
+
+// Rebuild mocks base method.
+func (m *MockIProjectionService) Rebuild(ctx context.Context, fromSnapshot bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebuild", ctx, fromSnapshot)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rebuild indicates an expected call of Rebuild.
+func (mr *MockIProjectionServiceMockRecorder) Rebuild(ctx, fromSnapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebuild", reflect.TypeOf((*MockIProjectionService)(nil).Rebuild), ctx, fromSnapshot)
+}
+
+// Run mocks base method.
+func (m *MockIProjectionService) Run(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockIProjectionServiceMockRecorder) Run(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockIProjectionService)(nil).Run), ctx)
+}
+
+// MockIProjectionQueryRepo is a mock of IProjectionQueryRepo interface.
+type MockIProjectionQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIProjectionQueryRepoMockRecorder
+}
+
+// MockIProjectionQueryRepoMockRecorder is the mock recorder for MockIProjectionQueryRepo.
+type MockIProjectionQueryRepoMockRecorder struct {
+	mock *MockIProjectionQueryRepo
+}
+
+// NewMockIProjectionQueryRepo creates a new mock instance.
+func NewMockIProjectionQueryRepo(ctrl *gomock.Controller) *MockIProjectionQueryRepo {
+	mock := &MockIProjectionQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIProjectionQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIProjectionQueryRepo) EXPECT() *MockIProjectionQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// GetBalances mocks base method.
+func (m *MockIProjectionQueryRepo) GetBalances(ctx context.Context, userIDs []uint) (map[uint]decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalances", ctx, userIDs)
+	ret0, _ := ret[0].(map[uint]decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalances indicates an expected call of GetBalances.
+func (mr *MockIProjectionQueryRepoMockRecorder) GetBalances(ctx, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalances", reflect.TypeOf((*MockIProjectionQueryRepo)(nil).GetBalances), ctx, userIDs)
+}
+
+// GetCheckpoint mocks base method.
+func (m *MockIProjectionQueryRepo) GetCheckpoint(ctx context.Context) (*mysql.ProjectionCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckpoint", ctx)
+	ret0, _ := ret[0].(*mysql.ProjectionCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckpoint indicates an expected call of GetCheckpoint.
+func (mr *MockIProjectionQueryRepoMockRecorder) GetCheckpoint(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckpoint", reflect.TypeOf((*MockIProjectionQueryRepo)(nil).GetCheckpoint), ctx)
+}
+
+// GetHistory mocks base method.
+func (m *MockIProjectionQueryRepo) GetHistory(ctx context.Context, fromUserID uint) ([]*mysql.TransactionHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, fromUserID)
+	ret0, _ := ret[0].([]*mysql.TransactionHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockIProjectionQueryRepoMockRecorder) GetHistory(ctx, fromUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockIProjectionQueryRepo)(nil).GetHistory), ctx, fromUserID)
+}
+
+// MockIProjectionCommandRepo is a mock of IProjectionCommandRepo interface.
+type MockIProjectionCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIProjectionCommandRepoMockRecorder
+}
+
+// MockIProjectionCommandRepoMockRecorder is the mock recorder for MockIProjectionCommandRepo.
+type MockIProjectionCommandRepoMockRecorder struct {
+	mock *MockIProjectionCommandRepo
+}
+
+// NewMockIProjectionCommandRepo creates a new mock instance.
+func NewMockIProjectionCommandRepo(ctrl *gomock.Controller) *MockIProjectionCommandRepo {
+	mock := &MockIProjectionCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockIProjectionCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIProjectionCommandRepo) EXPECT() *MockIProjectionCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// ExpireGaps mocks base method.
+func (m *MockIProjectionCommandRepo) ExpireGaps(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireGaps", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireGaps indicates an expected call of ExpireGaps.
+func (mr *MockIProjectionCommandRepoMockRecorder) ExpireGaps(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireGaps", reflect.TypeOf((*MockIProjectionCommandRepo)(nil).ExpireGaps), ctx, before)
+}
+
+// Project mocks base method.
+func (m *MockIProjectionCommandRepo) Project(ctx context.Context, limit int, gapTimeout time.Duration) (*mysql.LedgerEvent, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Project", ctx, limit, gapTimeout)
+	ret0, _ := ret[0].(*mysql.LedgerEvent)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Project indicates an expected call of Project.
+func (mr *MockIProjectionCommandRepoMockRecorder) Project(ctx, limit, gapTimeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Project", reflect.TypeOf((*MockIProjectionCommandRepo)(nil).Project), ctx, limit, gapTimeout)
+}
+
+// Rebuild mocks base method.
+func (m *MockIProjectionCommandRepo) Rebuild(ctx context.Context, fromSnapshot bool, limit int, gapTimeout time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebuild", ctx, fromSnapshot, limit, gapTimeout)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rebuild indicates an expected call of Rebuild.
+func (mr *MockIProjectionCommandRepoMockRecorder) Rebuild(ctx, fromSnapshot, limit, gapTimeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebuild", reflect.TypeOf((*MockIProjectionCommandRepo)(nil).Rebuild), ctx, fromSnapshot, limit, gapTimeout)
+}
+
+// Snapshot mocks base method.
+func (m *MockIProjectionCommandRepo) Snapshot(ctx context.Context) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockIProjectionCommandRepoMockRecorder) Snapshot(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockIProjectionCommandRepo)(nil).Snapshot), ctx)
+}
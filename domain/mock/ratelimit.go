@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./ratelimit.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRedisRateLimitCommandRepo is a mock of IRedisRateLimitCommandRepo interface.
+type MockIRedisRateLimitCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRedisRateLimitCommandRepoMockRecorder
+}
+
+// MockIRedisRateLimitCommandRepoMockRecorder is the mock recorder for MockIRedisRateLimitCommandRepo.
+type MockIRedisRateLimitCommandRepoMockRecorder struct {
+	mock *MockIRedisRateLimitCommandRepo
+}
+
+// NewMockIRedisRateLimitCommandRepo creates a new mock instance.
+func NewMockIRedisRateLimitCommandRepo(ctrl *gomock.Controller) *MockIRedisRateLimitCommandRepo {
+	mock := &MockIRedisRateLimitCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockIRedisRateLimitCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRedisRateLimitCommandRepo) EXPECT() *MockIRedisRateLimitCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// TakeRateLimit mocks base method.
+func (m *MockIRedisRateLimitCommandRepo) TakeRateLimit(ctx context.Context, key string, limit int64, duration time.Duration) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeRateLimit", ctx, key, limit, duration)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TakeRateLimit indicates an expected call of TakeRateLimit.
+func (mr *MockIRedisRateLimitCommandRepoMockRecorder) TakeRateLimit(ctx, key, limit, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRateLimit", reflect.TypeOf((*MockIRedisRateLimitCommandRepo)(nil).TakeRateLimit), ctx, key, limit, duration)
+}
@@ -0,0 +1,298 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./reconciliation.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	mysql "banking/model/mysql"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockIReconciliationHandler is a mock of IReconciliationHandler interface.
+type MockIReconciliationHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReconciliationHandlerMockRecorder
+}
+
+// MockIReconciliationHandlerMockRecorder is the mock recorder for MockIReconciliationHandler.
+type MockIReconciliationHandlerMockRecorder struct {
+	mock *MockIReconciliationHandler
+}
+
+// NewMockIReconciliationHandler creates a new mock instance.
+func NewMockIReconciliationHandler(ctrl *gomock.Controller) *MockIReconciliationHandler {
+	mock := &MockIReconciliationHandler{ctrl: ctrl}
+	mock.recorder = &MockIReconciliationHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReconciliationHandler) EXPECT() *MockIReconciliationHandlerMockRecorder {
+	return m.recorder
+}
+
+// GetStatementLines mocks base method.
+func (m *MockIReconciliationHandler) GetStatementLines() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatementLines")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// GetStatementLines indicates an expected call of GetStatementLines.
+func (mr *MockIReconciliationHandlerMockRecorder) GetStatementLines() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatementLines", reflect.TypeOf((*MockIReconciliationHandler)(nil).GetStatementLines))
+}
+
+// ImportStatement mocks base method.
+func (m *MockIReconciliationHandler) ImportStatement() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportStatement")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// ImportStatement indicates an expected call of ImportStatement.
+func (mr *MockIReconciliationHandlerMockRecorder) ImportStatement() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportStatement", reflect.TypeOf((*MockIReconciliationHandler)(nil).ImportStatement))
+}
+
+// ResolveStatementLine mocks base method.
+func (m *MockIReconciliationHandler) ResolveStatementLine() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveStatementLine")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// ResolveStatementLine indicates an expected call of ResolveStatementLine.
+func (mr *MockIReconciliationHandlerMockRecorder) ResolveStatementLine() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveStatementLine", reflect.TypeOf((*MockIReconciliationHandler)(nil).ResolveStatementLine))
+}
+
+// MockIReconciliationService is a mock of IReconciliationService interface.
+type MockIReconciliationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReconciliationServiceMockRecorder
+}
+
+// MockIReconciliationServiceMockRecorder is the mock recorder for MockIReconciliationService.
+type MockIReconciliationServiceMockRecorder struct {
+	mock *MockIReconciliationService
+}
+
+// NewMockIReconciliationService creates a new mock instance.
+func NewMockIReconciliationService(ctrl *gomock.Controller) *MockIReconciliationService {
+	mock := &MockIReconciliationService{ctrl: ctrl}
+	mock.recorder = &MockIReconciliationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReconciliationService) EXPECT() *MockIReconciliationServiceMockRecorder {
+	return m.recorder
+}
+
+// GetStatementLines mocks base method.
+func (m *MockIReconciliationService) GetStatementLines(ctx context.Context, statementID uint, status mysql.ReconciliationStatus) ([]*mysql.BankStatementLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatementLines", ctx, statementID, status)
+	ret0, _ := ret[0].([]*mysql.BankStatementLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatementLines indicates an expected call of GetStatementLines.
+func (mr *MockIReconciliationServiceMockRecorder) GetStatementLines(ctx, statementID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatementLines", reflect.TypeOf((*MockIReconciliationService)(nil).GetStatementLines), ctx, statementID, status)
+}
+
+// ImportStatement mocks base method.
+func (m *MockIReconciliationService) ImportStatement(ctx context.Context, format mysql.StatementFormat, fileName string, data []byte, adminID uint) (*mysql.BankStatement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportStatement", ctx, format, fileName, data, adminID)
+	ret0, _ := ret[0].(*mysql.BankStatement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportStatement indicates an expected call of ImportStatement.
+func (mr *MockIReconciliationServiceMockRecorder) ImportStatement(ctx, format, fileName, data, adminID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportStatement", reflect.TypeOf((*MockIReconciliationService)(nil).ImportStatement), ctx, format, fileName, data, adminID)
+}
+
+// ResolveStatementLine mocks base method.
+func (m *MockIReconciliationService) ResolveStatementLine(ctx context.Context, lineID, transactionID, adminID uint) (*mysql.BankStatementLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveStatementLine", ctx, lineID, transactionID, adminID)
+	ret0, _ := ret[0].(*mysql.BankStatementLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveStatementLine indicates an expected call of ResolveStatementLine.
+func (mr *MockIReconciliationServiceMockRecorder) ResolveStatementLine(ctx, lineID, transactionID, adminID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveStatementLine", reflect.TypeOf((*MockIReconciliationService)(nil).ResolveStatementLine), ctx, lineID, transactionID, adminID)
+}
+
+// MockIReconciliationQueryRepo is a mock of IReconciliationQueryRepo interface.
+type MockIReconciliationQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReconciliationQueryRepoMockRecorder
+}
+
+// MockIReconciliationQueryRepoMockRecorder is the mock recorder for MockIReconciliationQueryRepo.
+type MockIReconciliationQueryRepoMockRecorder struct {
+	mock *MockIReconciliationQueryRepo
+}
+
+// NewMockIReconciliationQueryRepo creates a new mock instance.
+func NewMockIReconciliationQueryRepo(ctrl *gomock.Controller) *MockIReconciliationQueryRepo {
+	mock := &MockIReconciliationQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIReconciliationQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReconciliationQueryRepo) EXPECT() *MockIReconciliationQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// GetCandidateTransactions mocks base method.
+func (m *MockIReconciliationQueryRepo) GetCandidateTransactions(ctx context.Context, transactionType mysql.TransactionType, minAmount, maxAmount decimal.Decimal, from, to time.Time) ([]*mysql.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCandidateTransactions", ctx, transactionType, minAmount, maxAmount, from, to)
+	ret0, _ := ret[0].([]*mysql.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCandidateTransactions indicates an expected call of GetCandidateTransactions.
+func (mr *MockIReconciliationQueryRepoMockRecorder) GetCandidateTransactions(ctx, transactionType, minAmount, maxAmount, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCandidateTransactions", reflect.TypeOf((*MockIReconciliationQueryRepo)(nil).GetCandidateTransactions), ctx, transactionType, minAmount, maxAmount, from, to)
+}
+
+// GetStatementLine mocks base method.
+func (m *MockIReconciliationQueryRepo) GetStatementLine(ctx context.Context, lineID uint) (*mysql.BankStatementLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatementLine", ctx, lineID)
+	ret0, _ := ret[0].(*mysql.BankStatementLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatementLine indicates an expected call of GetStatementLine.
+func (mr *MockIReconciliationQueryRepoMockRecorder) GetStatementLine(ctx, lineID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatementLine", reflect.TypeOf((*MockIReconciliationQueryRepo)(nil).GetStatementLine), ctx, lineID)
+}
+
+// GetStatementLines mocks base method.
+func (m *MockIReconciliationQueryRepo) GetStatementLines(ctx context.Context, statementID uint, status mysql.ReconciliationStatus) ([]*mysql.BankStatementLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatementLines", ctx, statementID, status)
+	ret0, _ := ret[0].([]*mysql.BankStatementLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatementLines indicates an expected call of GetStatementLines.
+func (mr *MockIReconciliationQueryRepoMockRecorder) GetStatementLines(ctx, statementID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatementLines", reflect.TypeOf((*MockIReconciliationQueryRepo)(nil).GetStatementLines), ctx, statementID, status)
+}
+
+// GetTransaction mocks base method.
+func (m *MockIReconciliationQueryRepo) GetTransaction(ctx context.Context, transactionID uint) (*mysql.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransaction", ctx, transactionID)
+	ret0, _ := ret[0].(*mysql.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransaction indicates an expected call of GetTransaction.
+func (mr *MockIReconciliationQueryRepoMockRecorder) GetTransaction(ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockIReconciliationQueryRepo)(nil).GetTransaction), ctx, transactionID)
+}
+
+// IsTransactionClaimed mocks base method.
+func (m *MockIReconciliationQueryRepo) IsTransactionClaimed(ctx context.Context, transactionID uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTransactionClaimed", ctx, transactionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTransactionClaimed indicates an expected call of IsTransactionClaimed.
+func (mr *MockIReconciliationQueryRepoMockRecorder) IsTransactionClaimed(ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTransactionClaimed", reflect.TypeOf((*MockIReconciliationQueryRepo)(nil).IsTransactionClaimed), ctx, transactionID)
+}
+
+// MockIReconciliationCommandRepo is a mock of IReconciliationCommandRepo interface.
+type MockIReconciliationCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReconciliationCommandRepoMockRecorder
+}
+
+// MockIReconciliationCommandRepoMockRecorder is the mock recorder for MockIReconciliationCommandRepo.
+type MockIReconciliationCommandRepoMockRecorder struct {
+	mock *MockIReconciliationCommandRepo
+}
+
+// NewMockIReconciliationCommandRepo creates a new mock instance.
+func NewMockIReconciliationCommandRepo(ctrl *gomock.Controller) *MockIReconciliationCommandRepo {
+	mock := &MockIReconciliationCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockIReconciliationCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReconciliationCommandRepo) EXPECT() *MockIReconciliationCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateStatement mocks base method.
+func (m *MockIReconciliationCommandRepo) CreateStatement(ctx context.Context, statement *mysql.BankStatement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStatement", ctx, statement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateStatement indicates an expected call of CreateStatement.
+func (mr *MockIReconciliationCommandRepoMockRecorder) CreateStatement(ctx, statement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatement", reflect.TypeOf((*MockIReconciliationCommandRepo)(nil).CreateStatement), ctx, statement)
+}
+
+// UpdateStatementLine mocks base method.
+func (m *MockIReconciliationCommandRepo) UpdateStatementLine(ctx context.Context, line *mysql.BankStatementLine) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatementLine", ctx, line)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatementLine indicates an expected call of UpdateStatementLine.
+func (mr *MockIReconciliationCommandRepoMockRecorder) UpdateStatementLine(ctx, line interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatementLine", reflect.TypeOf((*MockIReconciliationCommandRepo)(nil).UpdateStatementLine), ctx, line)
+}
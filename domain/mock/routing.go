@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./routing.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockIReadRouter is a mock of IReadRouter interface.
+type MockIReadRouter struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReadRouterMockRecorder
+}
+
+// MockIReadRouterMockRecorder is the mock recorder for MockIReadRouter.
+type MockIReadRouterMockRecorder struct {
+	mock *MockIReadRouter
+}
+
+// NewMockIReadRouter creates a new mock instance.
+func NewMockIReadRouter(ctrl *gomock.Controller) *MockIReadRouter {
+	mock := &MockIReadRouter{ctrl: ctrl}
+	mock.recorder = &MockIReadRouterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReadRouter) EXPECT() *MockIReadRouterMockRecorder {
+	return m.recorder
+}
+
+// Pin mocks base method.
+func (m *MockIReadRouter) Pin(ctx context.Context, userIDs ...uint) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range userIDs {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Pin", varargs...)
+}
+
+// Pin indicates an expected call of Pin.
+func (mr *MockIReadRouterMockRecorder) Pin(ctx interface{}, userIDs ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, userIDs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pin", reflect.TypeOf((*MockIReadRouter)(nil).Pin), varargs...)
+}
+
+// Reader mocks base method.
+func (m *MockIReadRouter) Reader(ctx context.Context, userID uint) *gorm.DB {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reader", ctx, userID)
+	ret0, _ := ret[0].(*gorm.DB)
+	return ret0
+}
+
+// Reader indicates an expected call of Reader.
+func (mr *MockIReadRouterMockRecorder) Reader(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reader", reflect.TypeOf((*MockIReadRouter)(nil).Reader), ctx, userID)
+}
+
+// MockIRedisRoutingQueryRepo is a mock of IRedisRoutingQueryRepo interface.
+type MockIRedisRoutingQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRedisRoutingQueryRepoMockRecorder
+}
+
+// MockIRedisRoutingQueryRepoMockRecorder is the mock recorder for MockIRedisRoutingQueryRepo.
+type MockIRedisRoutingQueryRepoMockRecorder struct {
+	mock *MockIRedisRoutingQueryRepo
+}
+
+// NewMockIRedisRoutingQueryRepo creates a new mock instance.
+func NewMockIRedisRoutingQueryRepo(ctrl *gomock.Controller) *MockIRedisRoutingQueryRepo {
+	mock := &MockIRedisRoutingQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIRedisRoutingQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRedisRoutingQueryRepo) EXPECT() *MockIRedisRoutingQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// IsPinned mocks base method.
+func (m *MockIRedisRoutingQueryRepo) IsPinned(ctx context.Context, userID uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPinned", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsPinned indicates an expected call of IsPinned.
+func (mr *MockIRedisRoutingQueryRepoMockRecorder) IsPinned(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPinned", reflect.TypeOf((*MockIRedisRoutingQueryRepo)(nil).IsPinned), ctx, userID)
+}
+
+// MockIRedisRoutingCommandRepo is a mock of IRedisRoutingCommandRepo interface.
+type MockIRedisRoutingCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRedisRoutingCommandRepoMockRecorder
+}
+
+// MockIRedisRoutingCommandRepoMockRecorder is the mock recorder for MockIRedisRoutingCommandRepo.
+type MockIRedisRoutingCommandRepoMockRecorder struct {
+	mock *MockIRedisRoutingCommandRepo
+}
+
+// NewMockIRedisRoutingCommandRepo creates a new mock instance.
+func NewMockIRedisRoutingCommandRepo(ctrl *gomock.Controller) *MockIRedisRoutingCommandRepo {
+	mock := &MockIRedisRoutingCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockIRedisRoutingCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRedisRoutingCommandRepo) EXPECT() *MockIRedisRoutingCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// SetPin mocks base method.
+func (m *MockIRedisRoutingCommandRepo) SetPin(ctx context.Context, userID uint, window time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPin", ctx, userID, window)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPin indicates an expected call of SetPin.
+func (mr *MockIRedisRoutingCommandRepoMockRecorder) SetPin(ctx, userID, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPin", reflect.TypeOf((*MockIRedisRoutingCommandRepo)(nil).SetPin), ctx, userID, window)
+}
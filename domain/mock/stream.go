@@ -0,0 +1,206 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./stream.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	mysql "banking/model/mysql"
+	redis "banking/model/redis"
+	context "context"
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIStreamHandler is a mock of IStreamHandler interface.
+type MockIStreamHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockIStreamHandlerMockRecorder
+}
+
+// MockIStreamHandlerMockRecorder is the mock recorder for MockIStreamHandler.
+type MockIStreamHandlerMockRecorder struct {
+	mock *MockIStreamHandler
+}
+
+// NewMockIStreamHandler creates a new mock instance.
+func NewMockIStreamHandler(ctrl *gomock.Controller) *MockIStreamHandler {
+	mock := &MockIStreamHandler{ctrl: ctrl}
+	mock.recorder = &MockIStreamHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIStreamHandler) EXPECT() *MockIStreamHandlerMockRecorder {
+	return m.recorder
+}
+
+// SSE mocks base method.
+func (m *MockIStreamHandler) SSE() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSE")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// SSE indicates an expected call of SSE.
+func (mr *MockIStreamHandlerMockRecorder) SSE() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSE", reflect.TypeOf((*MockIStreamHandler)(nil).SSE))
+}
+
+// WebSocket mocks base method.
+func (m *MockIStreamHandler) WebSocket() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebSocket")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// WebSocket indicates an expected call of WebSocket.
+func (mr *MockIStreamHandlerMockRecorder) WebSocket() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebSocket", reflect.TypeOf((*MockIStreamHandler)(nil).WebSocket))
+}
+
+// MockIStreamService is a mock of IStreamService interface.
+type MockIStreamService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIStreamServiceMockRecorder
+}
+
+// MockIStreamServiceMockRecorder is the mock recorder for MockIStreamService.
+type MockIStreamServiceMockRecorder struct {
+	mock *MockIStreamService
+}
+
+// NewMockIStreamService creates a new mock instance.
+func NewMockIStreamService(ctrl *gomock.Controller) *MockIStreamService {
+	mock := &MockIStreamService{ctrl: ctrl}
+	mock.recorder = &MockIStreamServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIStreamService) EXPECT() *MockIStreamServiceMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockIStreamService) Publish(ctx context.Context, transaction *mysql.Transaction) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", ctx, transaction)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockIStreamServiceMockRecorder) Publish(ctx, transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockIStreamService)(nil).Publish), ctx, transaction)
+}
+
+// Subscribe mocks base method.
+func (m *MockIStreamService) Subscribe(ctx context.Context, userID uint, lastEventID string) (<-chan *redis.BalanceEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, userID, lastEventID)
+	ret0, _ := ret[0].(<-chan *redis.BalanceEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockIStreamServiceMockRecorder) Subscribe(ctx, userID, lastEventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockIStreamService)(nil).Subscribe), ctx, userID, lastEventID)
+}
+
+// MockIRedisStreamQueryRepo is a mock of IRedisStreamQueryRepo interface.
+type MockIRedisStreamQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRedisStreamQueryRepoMockRecorder
+}
+
+// MockIRedisStreamQueryRepoMockRecorder is the mock recorder for MockIRedisStreamQueryRepo.
+type MockIRedisStreamQueryRepoMockRecorder struct {
+	mock *MockIRedisStreamQueryRepo
+}
+
+// NewMockIRedisStreamQueryRepo creates a new mock instance.
+func NewMockIRedisStreamQueryRepo(ctrl *gomock.Controller) *MockIRedisStreamQueryRepo {
+	mock := &MockIRedisStreamQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockIRedisStreamQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRedisStreamQueryRepo) EXPECT() *MockIRedisStreamQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// GetEventsAfter mocks base method.
+func (m *MockIRedisStreamQueryRepo) GetEventsAfter(ctx context.Context, userID uint, lastEventID string) ([]*redis.BalanceEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventsAfter", ctx, userID, lastEventID)
+	ret0, _ := ret[0].([]*redis.BalanceEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventsAfter indicates an expected call of GetEventsAfter.
+func (mr *MockIRedisStreamQueryRepoMockRecorder) GetEventsAfter(ctx, userID, lastEventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsAfter", reflect.TypeOf((*MockIRedisStreamQueryRepo)(nil).GetEventsAfter), ctx, userID, lastEventID)
+}
+
+// SubscribeEvents mocks base method.
+func (m *MockIRedisStreamQueryRepo) SubscribeEvents(ctx context.Context) (<-chan *redis.BalanceEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeEvents", ctx)
+	ret0, _ := ret[0].(<-chan *redis.BalanceEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeEvents indicates an expected call of SubscribeEvents.
+func (mr *MockIRedisStreamQueryRepoMockRecorder) SubscribeEvents(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeEvents", reflect.TypeOf((*MockIRedisStreamQueryRepo)(nil).SubscribeEvents), ctx)
+}
+
+// MockIRedisStreamCommandRepo is a mock of IRedisStreamCommandRepo interface.
+type MockIRedisStreamCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRedisStreamCommandRepoMockRecorder
+}
+
+// MockIRedisStreamCommandRepoMockRecorder is the mock recorder for MockIRedisStreamCommandRepo.
+type MockIRedisStreamCommandRepoMockRecorder struct {
+	mock *MockIRedisStreamCommandRepo
+}
+
+// NewMockIRedisStreamCommandRepo creates a new mock instance.
+func NewMockIRedisStreamCommandRepo(ctrl *gomock.Controller) *MockIRedisStreamCommandRepo {
+	mock := &MockIRedisStreamCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockIRedisStreamCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRedisStreamCommandRepo) EXPECT() *MockIRedisStreamCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// AppendEvent mocks base method.
+func (m *MockIRedisStreamCommandRepo) AppendEvent(ctx context.Context, event *redis.BalanceEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendEvent indicates an expected call of AppendEvent.
+func (mr *MockIRedisStreamCommandRepoMockRecorder) AppendEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendEvent", reflect.TypeOf((*MockIRedisStreamCommandRepo)(nil).AppendEvent), ctx, event)
+}
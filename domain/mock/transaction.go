@@ -65,6 +65,34 @@ func (mr *MockITransactionHandlerMockRecorder) GetTransactions() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockITransactionHandler)(nil).GetTransactions))
 }
 
+// GetTransferRequest mocks base method.
+func (m *MockITransactionHandler) GetTransferRequest() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferRequest")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// GetTransferRequest indicates an expected call of GetTransferRequest.
+func (mr *MockITransactionHandlerMockRecorder) GetTransferRequest() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferRequest", reflect.TypeOf((*MockITransactionHandler)(nil).GetTransferRequest))
+}
+
+// QuoteFee mocks base method.
+func (m *MockITransactionHandler) QuoteFee() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteFee")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// QuoteFee indicates an expected call of QuoteFee.
+func (mr *MockITransactionHandlerMockRecorder) QuoteFee() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteFee", reflect.TypeOf((*MockITransactionHandler)(nil).QuoteFee))
+}
+
 // Transfer mocks base method.
 func (m *MockITransactionHandler) Transfer() gin.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -116,19 +144,34 @@ func (m *MockITransactionService) EXPECT() *MockITransactionServiceMockRecorder
 	return m.recorder
 }
 
+// ApproveReview mocks base method.
+func (m *MockITransactionService) ApproveReview(ctx context.Context, reviewID, adminID uint) (*mysql.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveReview", ctx, reviewID, adminID)
+	ret0, _ := ret[0].(*mysql.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveReview indicates an expected call of ApproveReview.
+func (mr *MockITransactionServiceMockRecorder) ApproveReview(ctx, reviewID, adminID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveReview", reflect.TypeOf((*MockITransactionService)(nil).ApproveReview), ctx, reviewID, adminID)
+}
+
 // Deposit mocks base method.
-func (m *MockITransactionService) Deposit(ctx context.Context, userID uint, amount decimal.Decimal) (*mysql.Transaction, error) {
+func (m *MockITransactionService) Deposit(ctx context.Context, userID uint, amount decimal.Decimal, reference *mysql.TransactionReference) (*mysql.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Deposit", ctx, userID, amount)
+	ret := m.ctrl.Call(m, "Deposit", ctx, userID, amount, reference)
 	ret0, _ := ret[0].(*mysql.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Deposit indicates an expected call of Deposit.
-func (mr *MockITransactionServiceMockRecorder) Deposit(ctx, userID, amount interface{}) *gomock.Call {
+func (mr *MockITransactionServiceMockRecorder) Deposit(ctx, userID, amount, reference interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockITransactionService)(nil).Deposit), ctx, userID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockITransactionService)(nil).Deposit), ctx, userID, amount, reference)
 }
 
 // GetTransactions mocks base method.
@@ -146,34 +189,64 @@ func (mr *MockITransactionServiceMockRecorder) GetTransactions(ctx, userID inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockITransactionService)(nil).GetTransactions), ctx, userID)
 }
 
+// GetTransactionsByReference mocks base method.
+func (m *MockITransactionService) GetTransactionsByReference(ctx context.Context, userID uint, reference string) ([]*mysql.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByReference", ctx, userID, reference)
+	ret0, _ := ret[0].([]*mysql.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByReference indicates an expected call of GetTransactionsByReference.
+func (mr *MockITransactionServiceMockRecorder) GetTransactionsByReference(ctx, userID, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByReference", reflect.TypeOf((*MockITransactionService)(nil).GetTransactionsByReference), ctx, userID, reference)
+}
+
+// QuoteFee mocks base method.
+func (m *MockITransactionService) QuoteFee(ctx context.Context, userID uint, transactionType mysql.TransactionType, amount decimal.Decimal) (*mysql.FeeBreakdown, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteFee", ctx, userID, transactionType, amount)
+	ret0, _ := ret[0].(*mysql.FeeBreakdown)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteFee indicates an expected call of QuoteFee.
+func (mr *MockITransactionServiceMockRecorder) QuoteFee(ctx, userID, transactionType, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteFee", reflect.TypeOf((*MockITransactionService)(nil).QuoteFee), ctx, userID, transactionType, amount)
+}
+
 // Transfer mocks base method.
-func (m *MockITransactionService) Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal) (*mysql.Transaction, error) {
+func (m *MockITransactionService) Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, reference *mysql.TransactionReference) (*mysql.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Transfer", ctx, fromUserID, toUserID, amount)
+	ret := m.ctrl.Call(m, "Transfer", ctx, fromUserID, toUserID, amount, reference)
 	ret0, _ := ret[0].(*mysql.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Transfer indicates an expected call of Transfer.
-func (mr *MockITransactionServiceMockRecorder) Transfer(ctx, fromUserID, toUserID, amount interface{}) *gomock.Call {
+func (mr *MockITransactionServiceMockRecorder) Transfer(ctx, fromUserID, toUserID, amount, reference interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockITransactionService)(nil).Transfer), ctx, fromUserID, toUserID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockITransactionService)(nil).Transfer), ctx, fromUserID, toUserID, amount, reference)
 }
 
 // Withdraw mocks base method.
-func (m *MockITransactionService) Withdraw(ctx context.Context, userID uint, amount decimal.Decimal) (*mysql.Transaction, error) {
+func (m *MockITransactionService) Withdraw(ctx context.Context, userID uint, amount decimal.Decimal, reference *mysql.TransactionReference) (*mysql.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Withdraw", ctx, userID, amount)
+	ret := m.ctrl.Call(m, "Withdraw", ctx, userID, amount, reference)
 	ret0, _ := ret[0].(*mysql.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Withdraw indicates an expected call of Withdraw.
-func (mr *MockITransactionServiceMockRecorder) Withdraw(ctx, userID, amount interface{}) *gomock.Call {
+func (mr *MockITransactionServiceMockRecorder) Withdraw(ctx, userID, amount, reference interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockITransactionService)(nil).Withdraw), ctx, userID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockITransactionService)(nil).Withdraw), ctx, userID, amount, reference)
 }
 
 // MockITransactionQueryRepo is a mock of ITransactionQueryRepo interface.
@@ -214,6 +287,21 @@ func (mr *MockITransactionQueryRepoMockRecorder) GetTransactions(ctx, userID int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockITransactionQueryRepo)(nil).GetTransactions), ctx, userID)
 }
 
+// GetTransactionsByReference mocks base method.
+func (m *MockITransactionQueryRepo) GetTransactionsByReference(ctx context.Context, userID uint, reference string) ([]*mysql.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByReference", ctx, userID, reference)
+	ret0, _ := ret[0].([]*mysql.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByReference indicates an expected call of GetTransactionsByReference.
+func (mr *MockITransactionQueryRepoMockRecorder) GetTransactionsByReference(ctx, userID, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByReference", reflect.TypeOf((*MockITransactionQueryRepo)(nil).GetTransactionsByReference), ctx, userID, reference)
+}
+
 // MockITransactionCommandRepo is a mock of ITransactionCommandRepo interface.
 type MockITransactionCommandRepo struct {
 	ctrl     *gomock.Controller
@@ -238,46 +326,46 @@ func (m *MockITransactionCommandRepo) EXPECT() *MockITransactionCommandRepoMockR
 }
 
 // Deposit mocks base method.
-func (m *MockITransactionCommandRepo) Deposit(ctx context.Context, userID uint, amount decimal.Decimal) (*mysql.Transaction, error) {
+func (m *MockITransactionCommandRepo) Deposit(ctx context.Context, userID uint, amount decimal.Decimal, reference *mysql.TransactionReference) (*mysql.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Deposit", ctx, userID, amount)
+	ret := m.ctrl.Call(m, "Deposit", ctx, userID, amount, reference)
 	ret0, _ := ret[0].(*mysql.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Deposit indicates an expected call of Deposit.
-func (mr *MockITransactionCommandRepoMockRecorder) Deposit(ctx, userID, amount interface{}) *gomock.Call {
+func (mr *MockITransactionCommandRepoMockRecorder) Deposit(ctx, userID, amount, reference interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockITransactionCommandRepo)(nil).Deposit), ctx, userID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockITransactionCommandRepo)(nil).Deposit), ctx, userID, amount, reference)
 }
 
 // Transfer mocks base method.
-func (m *MockITransactionCommandRepo) Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal) (*mysql.Transaction, error) {
+func (m *MockITransactionCommandRepo) Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, fee *mysql.FeeBreakdown, reference *mysql.TransactionReference) (*mysql.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Transfer", ctx, fromUserID, toUserID, amount)
+	ret := m.ctrl.Call(m, "Transfer", ctx, fromUserID, toUserID, amount, fee, reference)
 	ret0, _ := ret[0].(*mysql.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Transfer indicates an expected call of Transfer.
-func (mr *MockITransactionCommandRepoMockRecorder) Transfer(ctx, fromUserID, toUserID, amount interface{}) *gomock.Call {
+func (mr *MockITransactionCommandRepoMockRecorder) Transfer(ctx, fromUserID, toUserID, amount, fee, reference interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockITransactionCommandRepo)(nil).Transfer), ctx, fromUserID, toUserID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockITransactionCommandRepo)(nil).Transfer), ctx, fromUserID, toUserID, amount, fee, reference)
 }
 
 // Withdraw mocks base method.
-func (m *MockITransactionCommandRepo) Withdraw(ctx context.Context, userID uint, amount decimal.Decimal) (*mysql.Transaction, error) {
+func (m *MockITransactionCommandRepo) Withdraw(ctx context.Context, userID uint, amount decimal.Decimal, fee *mysql.FeeBreakdown, reference *mysql.TransactionReference) (*mysql.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Withdraw", ctx, userID, amount)
+	ret := m.ctrl.Call(m, "Withdraw", ctx, userID, amount, fee, reference)
 	ret0, _ := ret[0].(*mysql.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Withdraw indicates an expected call of Withdraw.
-func (mr *MockITransactionCommandRepoMockRecorder) Withdraw(ctx, userID, amount interface{}) *gomock.Call {
+func (mr *MockITransactionCommandRepoMockRecorder) Withdraw(ctx, userID, amount, fee, reference interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockITransactionCommandRepo)(nil).Withdraw), ctx, userID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockITransactionCommandRepo)(nil).Withdraw), ctx, userID, amount, fee, reference)
 }
@@ -0,0 +1,216 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./transferqueue.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "banking/domain"
+	mysql "banking/model/mysql"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockITransferQueueService is a mock of ITransferQueueService interface.
+type MockITransferQueueService struct {
+	ctrl     *gomock.Controller
+	recorder *MockITransferQueueServiceMockRecorder
+}
+
+// MockITransferQueueServiceMockRecorder is the mock recorder for MockITransferQueueService.
+type MockITransferQueueServiceMockRecorder struct {
+	mock *MockITransferQueueService
+}
+
+// NewMockITransferQueueService creates a new mock instance.
+func NewMockITransferQueueService(ctrl *gomock.Controller) *MockITransferQueueService {
+	mock := &MockITransferQueueService{ctrl: ctrl}
+	mock.recorder = &MockITransferQueueServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITransferQueueService) EXPECT() *MockITransferQueueServiceMockRecorder {
+	return m.recorder
+}
+
+// Enqueue mocks base method.
+func (m *MockITransferQueueService) Enqueue(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, reference *mysql.TransactionReference, callbackURL string) (*mysql.TransferRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, fromUserID, toUserID, amount, reference, callbackURL)
+	ret0, _ := ret[0].(*mysql.TransferRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockITransferQueueServiceMockRecorder) Enqueue(ctx, fromUserID, toUserID, amount, reference, callbackURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockITransferQueueService)(nil).Enqueue), ctx, fromUserID, toUserID, amount, reference, callbackURL)
+}
+
+// GetTransferRequest mocks base method.
+func (m *MockITransferQueueService) GetTransferRequest(ctx context.Context, userID, requestID uint) (*mysql.TransferRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferRequest", ctx, userID, requestID)
+	ret0, _ := ret[0].(*mysql.TransferRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferRequest indicates an expected call of GetTransferRequest.
+func (mr *MockITransferQueueServiceMockRecorder) GetTransferRequest(ctx, userID, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferRequest", reflect.TypeOf((*MockITransferQueueService)(nil).GetTransferRequest), ctx, userID, requestID)
+}
+
+// ProcessNext mocks base method.
+func (m *MockITransferQueueService) ProcessNext(ctx context.Context) (*mysql.TransferRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessNext", ctx)
+	ret0, _ := ret[0].(*mysql.TransferRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessNext indicates an expected call of ProcessNext.
+func (mr *MockITransferQueueServiceMockRecorder) ProcessNext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessNext", reflect.TypeOf((*MockITransferQueueService)(nil).ProcessNext), ctx)
+}
+
+// Run mocks base method.
+func (m *MockITransferQueueService) Run(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockITransferQueueServiceMockRecorder) Run(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockITransferQueueService)(nil).Run), ctx)
+}
+
+// MockITransferQueueQueryRepo is a mock of ITransferQueueQueryRepo interface.
+type MockITransferQueueQueryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockITransferQueueQueryRepoMockRecorder
+}
+
+// MockITransferQueueQueryRepoMockRecorder is the mock recorder for MockITransferQueueQueryRepo.
+type MockITransferQueueQueryRepoMockRecorder struct {
+	mock *MockITransferQueueQueryRepo
+}
+
+// NewMockITransferQueueQueryRepo creates a new mock instance.
+func NewMockITransferQueueQueryRepo(ctrl *gomock.Controller) *MockITransferQueueQueryRepo {
+	mock := &MockITransferQueueQueryRepo{ctrl: ctrl}
+	mock.recorder = &MockITransferQueueQueryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITransferQueueQueryRepo) EXPECT() *MockITransferQueueQueryRepoMockRecorder {
+	return m.recorder
+}
+
+// GetTransferRequest mocks base method.
+func (m *MockITransferQueueQueryRepo) GetTransferRequest(ctx context.Context, requestID uint) (*mysql.TransferRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferRequest", ctx, requestID)
+	ret0, _ := ret[0].(*mysql.TransferRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferRequest indicates an expected call of GetTransferRequest.
+func (mr *MockITransferQueueQueryRepoMockRecorder) GetTransferRequest(ctx, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferRequest", reflect.TypeOf((*MockITransferQueueQueryRepo)(nil).GetTransferRequest), ctx, requestID)
+}
+
+// MockITransferQueueCommandRepo is a mock of ITransferQueueCommandRepo interface.
+type MockITransferQueueCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockITransferQueueCommandRepoMockRecorder
+}
+
+// MockITransferQueueCommandRepoMockRecorder is the mock recorder for MockITransferQueueCommandRepo.
+type MockITransferQueueCommandRepoMockRecorder struct {
+	mock *MockITransferQueueCommandRepo
+}
+
+// NewMockITransferQueueCommandRepo creates a new mock instance.
+func NewMockITransferQueueCommandRepo(ctrl *gomock.Controller) *MockITransferQueueCommandRepo {
+	mock := &MockITransferQueueCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockITransferQueueCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITransferQueueCommandRepo) EXPECT() *MockITransferQueueCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockITransferQueueCommandRepo) Claim(ctx context.Context) (*mysql.TransferRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx)
+	ret0, _ := ret[0].(*mysql.TransferRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockITransferQueueCommandRepoMockRecorder) Claim(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockITransferQueueCommandRepo)(nil).Claim), ctx)
+}
+
+// Enqueue mocks base method.
+func (m *MockITransferQueueCommandRepo) Enqueue(ctx context.Context, request *mysql.TransferRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockITransferQueueCommandRepoMockRecorder) Enqueue(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockITransferQueueCommandRepo)(nil).Enqueue), ctx, request)
+}
+
+// FailStale mocks base method.
+func (m *MockITransferQueueCommandRepo) FailStale(ctx context.Context, claimedBefore time.Time, code domain.ErrorCode, msg string) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStale", ctx, claimedBefore, code, msg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FailStale indicates an expected call of FailStale.
+func (mr *MockITransferQueueCommandRepoMockRecorder) FailStale(ctx, claimedBefore, code, msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStale", reflect.TypeOf((*MockITransferQueueCommandRepo)(nil).FailStale), ctx, claimedBefore, code, msg)
+}
+
+// Finish mocks base method.
+func (m *MockITransferQueueCommandRepo) Finish(ctx context.Context, request *mysql.TransferRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockITransferQueueCommandRepoMockRecorder) Finish(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockITransferQueueCommandRepo)(nil).Finish), ctx, request)
+}
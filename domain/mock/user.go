@@ -225,6 +225,21 @@ func (mr *MockIUserQueryRepoMockRecorder) GetUserByEmail(ctx, email interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockIUserQueryRepo)(nil).GetUserByEmail), ctx, email)
 }
 
+// GetUserNames mocks base method.
+func (m *MockIUserQueryRepo) GetUserNames(ctx context.Context, userIDs []uint) (map[uint]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNames", ctx, userIDs)
+	ret0, _ := ret[0].(map[uint]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNames indicates an expected call of GetUserNames.
+func (mr *MockIUserQueryRepoMockRecorder) GetUserNames(ctx, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNames", reflect.TypeOf((*MockIUserQueryRepo)(nil).GetUserNames), ctx, userIDs)
+}
+
 // GetUsers mocks base method.
 func (m *MockIUserQueryRepo) GetUsers(ctx context.Context, userID uint) ([]*mysql.User, error) {
 	m.ctrl.T.Helper()
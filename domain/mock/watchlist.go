@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./watchlist.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	mysql "banking/model/mysql"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIWatchlistService is a mock of IWatchlistService interface.
+type MockIWatchlistService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWatchlistServiceMockRecorder
+}
+
+// MockIWatchlistServiceMockRecorder is the mock recorder for MockIWatchlistService.
+type MockIWatchlistServiceMockRecorder struct {
+	mock *MockIWatchlistService
+}
+
+// NewMockIWatchlistService creates a new mock instance.
+func NewMockIWatchlistService(ctrl *gomock.Controller) *MockIWatchlistService {
+	mock := &MockIWatchlistService{ctrl: ctrl}
+	mock.recorder = &MockIWatchlistServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWatchlistService) EXPECT() *MockIWatchlistServiceMockRecorder {
+	return m.recorder
+}
+
+// ScreenName mocks base method.
+func (m *MockIWatchlistService) ScreenName(ctx context.Context, name string, userID *uint, screeningContext mysql.ScreeningContext) (*mysql.ScreeningResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScreenName", ctx, name, userID, screeningContext)
+	ret0, _ := ret[0].(*mysql.ScreeningResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScreenName indicates an expected call of ScreenName.
+func (mr *MockIWatchlistServiceMockRecorder) ScreenName(ctx, name, userID, screeningContext interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScreenName", reflect.TypeOf((*MockIWatchlistService)(nil).ScreenName), ctx, name, userID, screeningContext)
+}
+
+// ScreenUser mocks base method.
+func (m *MockIWatchlistService) ScreenUser(ctx context.Context, userID uint, screeningContext mysql.ScreeningContext) (*mysql.ScreeningResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScreenUser", ctx, userID, screeningContext)
+	ret0, _ := ret[0].(*mysql.ScreeningResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScreenUser indicates an expected call of ScreenUser.
+func (mr *MockIWatchlistServiceMockRecorder) ScreenUser(ctx, userID, screeningContext interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScreenUser", reflect.TypeOf((*MockIWatchlistService)(nil).ScreenUser), ctx, userID, screeningContext)
+}
+
+// MockIWatchlistCommandRepo is a mock of IWatchlistCommandRepo interface.
+type MockIWatchlistCommandRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWatchlistCommandRepoMockRecorder
+}
+
+// MockIWatchlistCommandRepoMockRecorder is the mock recorder for MockIWatchlistCommandRepo.
+type MockIWatchlistCommandRepoMockRecorder struct {
+	mock *MockIWatchlistCommandRepo
+}
+
+// NewMockIWatchlistCommandRepo creates a new mock instance.
+func NewMockIWatchlistCommandRepo(ctrl *gomock.Controller) *MockIWatchlistCommandRepo {
+	mock := &MockIWatchlistCommandRepo{ctrl: ctrl}
+	mock.recorder = &MockIWatchlistCommandRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWatchlistCommandRepo) EXPECT() *MockIWatchlistCommandRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateScreeningResult mocks base method.
+func (m *MockIWatchlistCommandRepo) CreateScreeningResult(ctx context.Context, result *mysql.ScreeningResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScreeningResult", ctx, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateScreeningResult indicates an expected call of CreateScreeningResult.
+func (mr *MockIWatchlistCommandRepoMockRecorder) CreateScreeningResult(ctx, result interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScreeningResult", reflect.TypeOf((*MockIWatchlistCommandRepo)(nil).CreateScreeningResult), ctx, result)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+//go:generate mockgen -destination ./mock/projection.go -source=./projection.go -package=mock
+
+type IProjectionService interface {
+	// Project applies the events appended since the checkpoint to the read models and snapshots the
+	// balances every projection.snapshotEvery events
+	Project(ctx context.Context) (applied int, err error)
+	// Rebuild replays the event store into new read models which replace the read models, from the
+	// latest snapshot if fromSnapshot
+	Rebuild(ctx context.Context, fromSnapshot bool) (applied int, err error)
+	// Run projects every projection.interval until ctx is done
+	Run(ctx context.Context)
+}
+
+type IProjectionQueryRepo interface {
+	GetCheckpoint(ctx context.Context) (checkpoint *mysqlModel.ProjectionCheckpoint, err error)
+	// GetBalances returns the projected balances of the users, users without events are missing
+	GetBalances(ctx context.Context, userIDs []uint) (balances map[uint]decimal.Decimal, err error)
+	// GetHistory returns the history rows of both users of the transactions paid by fromUserID, ordered
+	// by event and user
+	GetHistory(ctx context.Context, fromUserID uint) (history []*mysqlModel.TransactionHistory, err error)
+}
+
+type IProjectionCommandRepo interface {
+	// Project applies at most limit events after the checkpoint and moves the checkpoint to last, last is
+	// nil when no event was applied. It stops before a gap of event ids which is younger than gapTimeout,
+	// the missing events may belong to transactions which are not committed yet. The ids of older gaps
+	// are skipped and re-checked, their events are applied late once committed
+	Project(ctx context.Context, limit int, gapTimeout time.Duration) (last *mysqlModel.LedgerEvent, applied int, err error)
+	// ExpireGaps stops re-checking the event ids skipped before before, no transaction is open that
+	// long and their events were rolled back
+	ExpireGaps(ctx context.Context, before time.Time) (expired int, err error)
+	// Snapshot copies the projected balances at the checkpoint, the previous snapshot is kept
+	Snapshot(ctx context.Context) (position uint, err error)
+	// Rebuild replays the events into shadow read models, empty or reset to the latest snapshot, in
+	// batches of limit and copies them over the read models at the end. Readers and the projector use
+	// the old read models until then, one rebuild runs at a time
+	Rebuild(ctx context.Context, fromSnapshot bool, limit int, gapTimeout time.Duration) (applied int, err error)
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination ./mock/ratelimit.go -source=./ratelimit.go -package=mock
+
+type IRedisRateLimitCommandRepo interface {
+	// TakeRateLimit takes one request from the fixed window budget of key, remaining is negative when
+	// the budget is exhausted and reset is the milliseconds left in the window
+	TakeRateLimit(ctx context.Context, key string, limit int64, duration time.Duration) (remaining, reset int64, err error)
+}
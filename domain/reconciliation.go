@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+//go:generate mockgen -destination ./mock/reconciliation.go -source=./reconciliation.go -package=mock
+
+type IReconciliationHandler interface {
+	ImportStatement() gin.HandlerFunc
+	GetStatementLines() gin.HandlerFunc
+	ResolveStatementLine() gin.HandlerFunc
+}
+
+type IReconciliationService interface {
+	ImportStatement(ctx context.Context, format mysqlModel.StatementFormat, fileName string, data []byte, adminID uint) (statement *mysqlModel.BankStatement, err error)
+	GetStatementLines(ctx context.Context, statementID uint, status mysqlModel.ReconciliationStatus) (lines []*mysqlModel.BankStatementLine, err error)
+	ResolveStatementLine(ctx context.Context, lineID, transactionID, adminID uint) (line *mysqlModel.BankStatementLine, err error)
+}
+
+type IReconciliationQueryRepo interface {
+	GetStatementLines(ctx context.Context, statementID uint, status mysqlModel.ReconciliationStatus) (lines []*mysqlModel.BankStatementLine, err error)
+	GetStatementLine(ctx context.Context, lineID uint) (line *mysqlModel.BankStatementLine, err error)
+	GetTransaction(ctx context.Context, transactionID uint) (transaction *mysqlModel.Transaction, err error)
+	GetCandidateTransactions(ctx context.Context, transactionType mysqlModel.TransactionType, minAmount, maxAmount decimal.Decimal, from, to time.Time) (transactions []*mysqlModel.Transaction, err error)
+	IsTransactionClaimed(ctx context.Context, transactionID uint) (claimed bool, err error)
+}
+
+type IReconciliationCommandRepo interface {
+	CreateStatement(ctx context.Context, statement *mysqlModel.BankStatement) (err error)
+	UpdateStatementLine(ctx context.Context, line *mysqlModel.BankStatementLine) (err error)
+}
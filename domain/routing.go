@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -destination ./mock/routing.go -source=./routing.go -package=mock
+
+type IReadRouter interface {
+	// Reader returns the slave, or the master when ctx demands strong consistency, the user wrote within
+	// routing.pinWindow or the slave lags more than routing.maxReplicaLag. A zero userID is never pinned
+	Reader(ctx context.Context, userID uint) *gorm.DB
+	// Pin routes the reads of the users to the master for routing.pinWindow, call it after their write is committed
+	Pin(ctx context.Context, userIDs ...uint)
+}
+
+type IRedisRoutingQueryRepo interface {
+	IsPinned(ctx context.Context, userID uint) (pinned bool, err error)
+}
+
+type IRedisRoutingCommandRepo interface {
+	SetPin(ctx context.Context, userID uint, window time.Duration) (err error)
+}
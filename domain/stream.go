@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+
+	mysqlModel "banking/model/mysql"
+	redisModel "banking/model/redis"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -destination ./mock/stream.go -source=./stream.go -package=mock
+
+type IStreamHandler interface {
+	SSE() gin.HandlerFunc
+	WebSocket() gin.HandlerFunc
+}
+
+type IStreamService interface {
+	Publish(ctx context.Context, transaction *mysqlModel.Transaction)
+	Subscribe(ctx context.Context, userID uint, lastEventID string) (events <-chan *redisModel.BalanceEvent, err error)
+}
+
+type IRedisStreamQueryRepo interface {
+	GetEventsAfter(ctx context.Context, userID uint, lastEventID string) (events []*redisModel.BalanceEvent, err error)
+	SubscribeEvents(ctx context.Context) (events <-chan *redisModel.BalanceEvent, err error)
+}
+
+type IRedisStreamCommandRepo interface {
+	AppendEvent(ctx context.Context, event *redisModel.BalanceEvent) (err error)
+}
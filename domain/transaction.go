@@ -14,24 +14,36 @@ import (
 
 type ITransactionHandler interface {
 	Transfer() gin.HandlerFunc
+	GetTransferRequest() gin.HandlerFunc
 	Deposit() gin.HandlerFunc
 	Withdraw() gin.HandlerFunc
 	GetTransactions() gin.HandlerFunc
+	QuoteFee() gin.HandlerFunc
 }
 
+// ITransactionService takes an optional reference on transfers, deposits and withdrawals, reference may be nil
 type ITransactionService interface {
-	Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error)
-	Deposit(ctx context.Context, userID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error)
-	Withdraw(ctx context.Context, userID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error)
+	Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error)
+	Deposit(ctx context.Context, userID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error)
+	Withdraw(ctx context.Context, userID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error)
+	// ApproveReview executes the transfer held by a fraud review, the review goes back to pending when the transfer fails
+	ApproveReview(ctx context.Context, reviewID, adminID uint) (transaction *mysqlModel.Transaction, err error)
+	// GetTransactions and GetTransactionsByReference return the transactions with the names of their users
 	GetTransactions(ctx context.Context, userID uint) (transactions []*mysqlModel.Transaction, err error)
+	GetTransactionsByReference(ctx context.Context, userID uint, reference string) (transactions []*mysqlModel.Transaction, err error)
+	QuoteFee(ctx context.Context, userID uint, transactionType mysqlModel.TransactionType, amount decimal.Decimal) (fee *mysqlModel.FeeBreakdown, err error)
 }
 
 type ITransactionQueryRepo interface {
 	GetTransactions(ctx context.Context, userID uint) (transactions []*mysqlModel.Transaction, err error)
+	// GetTransactionsByReference returns the transactions of GetTransactions whose external reference or memo is reference
+	GetTransactionsByReference(ctx context.Context, userID uint, reference string) (transactions []*mysqlModel.Transaction, err error)
 }
 
 type ITransactionCommandRepo interface {
-	Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error)
-	Deposit(ctx context.Context, userID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error)
-	Withdraw(ctx context.Context, userID uint, amount decimal.Decimal) (transaction *mysqlModel.Transaction, err error)
+	// Transfer and Withdraw debit amount plus fee.Total and credit the fee to the house account, fee
+	// and reference may be nil
+	Transfer(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, fee *mysqlModel.FeeBreakdown, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error)
+	Deposit(ctx context.Context, userID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error)
+	Withdraw(ctx context.Context, userID uint, amount decimal.Decimal, fee *mysqlModel.FeeBreakdown, reference *mysqlModel.TransactionReference) (transaction *mysqlModel.Transaction, err error)
 }
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	mysqlModel "banking/model/mysql"
+
+	"github.com/shopspring/decimal"
+)
+
+//go:generate mockgen -destination ./mock/transferqueue.go -source=./transferqueue.go -package=mock
+
+type ITransferQueueService interface {
+	Enqueue(ctx context.Context, fromUserID, toUserID uint, amount decimal.Decimal, reference *mysqlModel.TransactionReference, callbackURL string) (request *mysqlModel.TransferRequest, err error)
+	GetTransferRequest(ctx context.Context, userID, requestID uint) (request *mysqlModel.TransferRequest, err error)
+	// ProcessNext executes the oldest queued transfer, request is nil when the queue is empty
+	ProcessNext(ctx context.Context) (request *mysqlModel.TransferRequest, err error)
+	// Run processes the queue with the configured workers until ctx is done
+	Run(ctx context.Context)
+}
+
+type ITransferQueueQueryRepo interface {
+	GetTransferRequest(ctx context.Context, requestID uint) (request *mysqlModel.TransferRequest, err error)
+}
+
+type ITransferQueueCommandRepo interface {
+	Enqueue(ctx context.Context, request *mysqlModel.TransferRequest) (err error)
+	// Claim moves the oldest queued request to processing, one request is claimed by one caller only.
+	// request is nil when nothing is queued
+	Claim(ctx context.Context) (request *mysqlModel.TransferRequest, err error)
+	// Finish records the outcome of a claimed request, a request which is no longer processing is not changed
+	Finish(ctx context.Context, request *mysqlModel.TransferRequest) (err error)
+	// FailStale settles the requests claimed before claimedBefore, their worker is gone. A request whose
+	// transfer committed, found by its request id, is completed with the transaction, the others fail
+	FailStale(ctx context.Context, claimedBefore time.Time, code ErrorCode, msg string) (completed, failed int64, err error)
+}
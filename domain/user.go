@@ -29,6 +29,8 @@ type IUserService interface {
 type IUserQueryRepo interface {
 	GetUsers(ctx context.Context, userID uint) (users []*mysqlModel.User, err error)
 	GetUserByEmail(ctx context.Context, email string) (user *mysqlModel.User, err error)
+	// GetUserNames returns the names of the users of userIDs by id, missing users are left out
+	GetUserNames(ctx context.Context, userIDs []uint) (names map[uint]string, err error)
 }
 
 type IUserCommandRepo interface {
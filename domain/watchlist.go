@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"context"
+
+	mysqlModel "banking/model/mysql"
+)
+
+//go:generate mockgen -destination ./mock/watchlist.go -source=./watchlist.go -package=mock
+
+type IWatchlistService interface {
+	ScreenName(ctx context.Context, name string, userID *uint, screeningContext mysqlModel.ScreeningContext) (result *mysqlModel.ScreeningResult, err error)
+	ScreenUser(ctx context.Context, userID uint, screeningContext mysqlModel.ScreeningContext) (result *mysqlModel.ScreeningResult, err error)
+}
+
+type IWatchlistCommandRepo interface {
+	CreateScreeningResult(ctx context.Context, result *mysqlModel.ScreeningResult) (err error)
+}
@@ -0,0 +1,17 @@
+package global
+
+import (
+	"context"
+
+	"banking/utils"
+
+	"go.uber.org/zap"
+)
+
+// LoggerFromContext returns Logger with the request id of ctx, so entries of one request can be found together
+func LoggerFromContext(ctx context.Context) *zap.SugaredLogger {
+	if requestID := utils.RequestIDFromContext(ctx); requestID != "" {
+		return Logger.With("requestId", requestID)
+	}
+	return Logger
+}
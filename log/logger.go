@@ -9,17 +9,17 @@ import (
 	"path/filepath"
 	"time"
 
+	"banking/tracing"
+
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
 	"github.com/spf13/viper"
-	"go.elastic.co/apm/module/apmzap/v2"
-	"go.elastic.co/apm/v2"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-func InitLogger(tracer *apm.Tracer) (*zap.SugaredLogger, error) {
+func InitLogger(tracer tracing.Tracer) (*zap.SugaredLogger, error) {
 	logMode := zapcore.InfoLevel
 
 	// local file log
@@ -30,9 +30,8 @@ func InitLogger(tracer *apm.Tracer) (*zap.SugaredLogger, error) {
 	consoleConfig.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	consoleCore := zapcore.NewCore(zapcore.NewConsoleEncoder(consoleConfig.EncoderConfig), zapcore.AddSync(os.Stdout), logMode)
 
-	// apm
-	apmzapCore := &apmzap.Core{Tracer: tracer}
-	apmzapWrapCore := apmzapCore.WrapCore(fileCore)
+	// tracing backend, Elastic APM receives the error entries
+	tracingCore := tracer.LogCore()
 
 	// ElasticSearch log
 	esSyncer, err := getElasticSearchSyncer()
@@ -42,7 +41,7 @@ func InitLogger(tracer *apm.Tracer) (*zap.SugaredLogger, error) {
 	esCore := zapcore.NewCore(getEncoder(), esSyncer, logMode)
 
 	// combine three cores
-	core := zapcore.NewTee(fileCore, consoleCore, apmzapWrapCore, esCore)
+	core := zapcore.NewTee(fileCore, consoleCore, tracingCore, esCore)
 
 	return zap.New(core).Sugar(), nil
 }
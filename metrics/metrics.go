@@ -0,0 +1,178 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"banking/domain"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/shopspring/decimal"
+)
+
+const namespace = "banking"
+
+const (
+	OutcomeSuccess  = "success"
+	OutcomeHeld     = "held"     // transfer held for fraud review
+	OutcomeRejected = "rejected" // refused with an error code, such as insufficient balance
+	OutcomeError    = "error"    // internal error
+)
+
+const (
+	// AuthMethodPassword is the login with email and password, the other methods are utils.AuthMethodJWT
+	// and utils.AuthMethodAPIKey
+	AuthMethodPassword = "password"
+)
+
+// Queues of ObserveQueueLag, the redis stream of balance events, the queue of async transfers and the
+// ledger events waiting for the projector
+const (
+	QueueBalanceStream = "balance_stream"
+	QueueTransfer      = "transfer_queue"
+	QueueProjection    = "projection"
+)
+
+// keyPrefixLength is the length of the API key prefix labelling rate limit rejections, enough to tell
+// keys apart without exposing them in the metrics
+const keyPrefixLength = 8
+
+var (
+	TransactionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transactions_total",
+		Help:      "Transfers, deposits and withdraws by type and outcome.",
+	}, []string{"type", "outcome"})
+
+	TransactionAmountTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transaction_amount_total",
+		Help:      "Sum of the requested amounts of transfers, deposits and withdraws by type and outcome.",
+	}, []string{"type", "outcome"})
+
+	InsufficientBalanceTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "insufficient_balance_total",
+		Help:      "Transfers and withdraws rejected for insufficient balance by type.",
+	}, []string{"type"})
+
+	RateLimitRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected by the rate limit by API key prefix.",
+	}, []string{"key"})
+
+	AuthFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_failures_total",
+		Help:      "Failed authentications by method.",
+	}, []string{"method"})
+
+	LockWaitSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "lock_wait_seconds",
+		Help:      "Time to acquire the user row locks of a balance update by operation.",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation"})
+
+	DBTransactionSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_transaction_seconds",
+		Help:      "Duration of the database transactions of balance updates by operation and result.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation", "result"})
+
+	DBRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_retries_total",
+		Help:      "Database transactions of balance updates run again after a deadlock or lock timeout by operation.",
+	}, []string{"operation"})
+
+	APIKeyCacheTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "apikey_cache_total",
+		Help:      "Redis lookups of API key secrets by result, hit or miss.",
+	}, []string{"result"})
+
+	QueueLagSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "queue_lag_seconds",
+		Help:      "Time from enqueueing a message to delivering it by queue.",
+		Buckets:   []float64{.001, .005, .01, .05, .1, .5, 1, 5, 10, 30},
+	}, []string{"queue"})
+)
+
+// Outcome classifies the error of a request, errors with a code were refused on purpose
+func Outcome(err error) string {
+	var domainErr *domain.Error
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.As(err, &domainErr) && domainErr.Code == domain.CodeTransferUnderReview:
+		return OutcomeHeld
+	case errors.As(err, &domainErr):
+		return OutcomeRejected
+	default:
+		return OutcomeError
+	}
+}
+
+// ObserveTransaction counts a transfer, deposit or withdraw request and its amount
+func ObserveTransaction(transactionType mysqlModel.TransactionType, amount decimal.Decimal, err error) {
+	outcome := Outcome(err)
+	TransactionsTotal.WithLabelValues(string(transactionType), outcome).Inc()
+	TransactionAmountTotal.WithLabelValues(string(transactionType), outcome).Add(amount.Abs().InexactFloat64())
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) && domainErr.Code == domain.CodeInsufficientBalance {
+		InsufficientBalanceTotal.WithLabelValues(string(transactionType)).Inc()
+	}
+}
+
+// ObserveRateLimitRejection counts a request rejected by the rate limit of key
+func ObserveRateLimitRejection(key string) {
+	if len(key) > keyPrefixLength {
+		key = key[:keyPrefixLength]
+	}
+	RateLimitRejectionsTotal.WithLabelValues(key).Inc()
+}
+
+// ObserveAuthFailure counts a failed authentication by method
+func ObserveAuthFailure(method string) {
+	AuthFailuresTotal.WithLabelValues(method).Inc()
+}
+
+// ObserveLockWait records the time since start spent acquiring row locks
+func ObserveLockWait(operation string, start time.Time) {
+	LockWaitSeconds.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDBTransaction records the duration of a database transaction begun at start, err rolled it back
+func ObserveDBTransaction(operation string, start time.Time, err error) {
+	result := "commit"
+	if err != nil {
+		result = "rollback"
+	}
+	DBTransactionSeconds.WithLabelValues(operation, result).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDBRetry counts a database transaction run again after a lock conflict
+func ObserveDBRetry(operation string) {
+	DBRetriesTotal.WithLabelValues(operation).Inc()
+}
+
+// ObserveAPIKeyCache counts an API key secret lookup in redis
+func ObserveAPIKeyCache(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	APIKeyCacheTotal.WithLabelValues(result).Inc()
+}
+
+// ObserveQueueLag records the time since a message was enqueued at enqueuedAt
+func ObserveQueueLag(queue string, enqueuedAt time.Time) {
+	QueueLagSeconds.WithLabelValues(queue).Observe(time.Since(enqueuedAt).Seconds())
+}
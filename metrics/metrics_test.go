@@ -0,0 +1,56 @@
+package metrics_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"banking/domain"
+	"banking/metrics"
+	mysqlModel "banking/model/mysql"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Outcome(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "success", err: nil, want: metrics.OutcomeSuccess},
+		{name: "held", err: fmt.Errorf("screening: %w", domain.NewError(domain.CodeTransferUnderReview, "held")), want: metrics.OutcomeHeld},
+		{name: "rejected", err: domain.NewError(domain.CodeInsufficientBalance, "insufficient balance"), want: metrics.OutcomeRejected},
+		{name: "error", err: errors.New("connection refused"), want: metrics.OutcomeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, metrics.Outcome(tt.err))
+		})
+	}
+}
+
+func Test_ObserveTransaction(t *testing.T) {
+	insufficient := domain.NewError(domain.CodeInsufficientBalance, "insufficient balance")
+
+	count := testutil.ToFloat64(metrics.TransactionsTotal.WithLabelValues(string(mysqlModel.Withdraw), metrics.OutcomeRejected))
+	amount := testutil.ToFloat64(metrics.TransactionAmountTotal.WithLabelValues(string(mysqlModel.Withdraw), metrics.OutcomeRejected))
+	rejections := testutil.ToFloat64(metrics.InsufficientBalanceTotal.WithLabelValues(string(mysqlModel.Withdraw)))
+
+	metrics.ObserveTransaction(mysqlModel.Withdraw, decimal.NewFromFloat(12.5), insufficient)
+
+	assert.Equal(t, count+1, testutil.ToFloat64(metrics.TransactionsTotal.WithLabelValues(string(mysqlModel.Withdraw), metrics.OutcomeRejected)))
+	assert.Equal(t, amount+12.5, testutil.ToFloat64(metrics.TransactionAmountTotal.WithLabelValues(string(mysqlModel.Withdraw), metrics.OutcomeRejected)))
+	assert.Equal(t, rejections+1, testutil.ToFloat64(metrics.InsufficientBalanceTotal.WithLabelValues(string(mysqlModel.Withdraw))))
+}
+
+func Test_ObserveRateLimitRejection(t *testing.T) {
+	// only the key prefix is exposed
+	metrics.ObserveRateLimitRejection("abcdefgh-secret-part")
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.RateLimitRejectionsTotal.WithLabelValues("abcdefgh")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.RateLimitRejectionsTotal.WithLabelValues("abcdefgh-secret-part")))
+}
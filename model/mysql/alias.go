@@ -0,0 +1,42 @@
+package mysql
+
+import (
+	"time"
+)
+
+type AliasType string
+
+const (
+	AliasEmail  AliasType = "email"
+	AliasPhone  AliasType = "phone"  // E.164, e.g. +33612345678
+	AliasHandle AliasType = "handle" // chosen by the user, e.g. alice.m
+)
+
+// PayeeAlias is an email, phone number or handle a user is paid by instead of its id. Value is
+// normalized, see the alias service. An alias resolves to its user once verified, VerifiedValue is
+// then set to Value and unique, so unverified claims of other users do not block it
+type PayeeAlias struct {
+	ID                   uint       `gorm:"primarykey" json:"id"`
+	CreatedAt            time.Time  `gorm:"precision:3" json:"createdAt"`
+	UpdatedAt            time.Time  `gorm:"precision:3" json:"updatedAt"`
+	UserID               uint       `gorm:"not null;uniqueIndex:idx_payee_alias_user_value" json:"userId"`
+	Type                 AliasType  `gorm:"type:varchar(10);not null" json:"type"`
+	Value                string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_payee_alias_user_value" json:"value"`
+	VerifiedValue        *string    `gorm:"type:varchar(255);uniqueIndex" json:"-"`
+	VerifiedAt           *time.Time `gorm:"precision:3" json:"verifiedAt"`
+	CodeHash             string     `gorm:"type:varchar(255)" json:"-"` // bcrypt of the verification code
+	CodeExpiresAt        *time.Time `gorm:"precision:3" json:"-"`
+	VerificationAttempts int        `gorm:"not null;default:0" json:"-"`
+}
+
+// Verified aliases resolve to their user
+func (a *PayeeAlias) Verified() bool {
+	return a.VerifiedAt != nil
+}
+
+// Payee is the user of an alias as shown to a payer before a transfer, the name is masked
+type Payee struct {
+	UserID     uint
+	AliasType  AliasType
+	MaskedName string
+}
@@ -0,0 +1,91 @@
+package mysql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditGenesisHash is the PrevHash of the first audit entry
+const AuditGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+type AuditOutcome string
+
+const (
+	AuditSuccess AuditOutcome = "success"
+	AuditFailure AuditOutcome = "failure"
+)
+
+// audited actions
+const (
+	AuditUserLogin            = "user.login"
+	AuditAPIKeyCreate         = "apikey.create"
+	AuditAPIKeyDelete         = "apikey.delete"
+	AuditTransfer             = "transaction.transfer"
+	AuditDeposit              = "transaction.deposit"
+	AuditWithdraw             = "transaction.withdraw"
+	AuditStatementImport      = "reconciliation.import"
+	AuditStatementLineResolve = "reconciliation.resolve"
+	AuditFraudReviewApprove   = "fraud.approve"
+	AuditFraudReviewReject    = "fraud.reject"
+	AuditConfigChange         = "config.change"
+	AuditBalanceBuckets       = "user.balanceBuckets"
+	AuditAliasCreate          = "alias.create"
+	AuditAliasVerify          = "alias.verify"
+	AuditAliasDelete          = "alias.delete"
+)
+
+// AuditLog is append-only, each entry stores the hash of its predecessor so any
+// modified, inserted or deleted row breaks the chain from that point on
+type AuditLog struct {
+	ID         uint         `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time    `gorm:"precision:3;index;not null" json:"createdAt"`
+	ActorID    *uint        `gorm:"index" json:"actorId"`
+	ActorEmail string       `gorm:"type:varchar(100)" json:"actorEmail"`
+	AuthMethod string       `gorm:"type:varchar(20)" json:"authMethod"`
+	IP         string       `gorm:"type:varchar(45)" json:"ip"`
+	Action     string       `gorm:"type:varchar(64);index;not null" json:"action"`
+	Target     string       `gorm:"type:varchar(255)" json:"target"`
+	Before     string       `gorm:"type:text" json:"before"`
+	After      string       `gorm:"type:text" json:"after"`
+	RequestID  string       `gorm:"type:varchar(64);index" json:"requestId"`
+	Outcome    AuditOutcome `gorm:"type:varchar(20);not null" json:"outcome"`
+	PrevHash   string       `gorm:"size:64;not null" json:"prevHash"`
+	Hash       string       `gorm:"size:64;unique;not null" json:"hash"`
+}
+
+// AuditChainHead holds the hash of the latest audit entry, its single row is locked
+// while appending so concurrent writers extend the chain one after another
+type AuditChainHead struct {
+	ID     uint   `gorm:"primarykey"`
+	LastID uint   `gorm:"not null"`
+	Hash   string `gorm:"size:64;not null"`
+}
+
+// ComputeHash hashes PrevHash together with every recorded field
+func (l *AuditLog) ComputeHash() string {
+	actorID := ""
+	if l.ActorID != nil {
+		actorID = strconv.FormatUint(uint64(*l.ActorID), 10)
+	}
+
+	payload := strings.Join([]string{
+		l.PrevHash,
+		l.CreatedAt.UTC().Format(time.RFC3339Nano),
+		actorID,
+		l.ActorEmail,
+		l.AuthMethod,
+		l.IP,
+		l.Action,
+		l.Target,
+		l.Before,
+		l.After,
+		l.RequestID,
+		string(l.Outcome),
+	}, "\x1f")
+
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
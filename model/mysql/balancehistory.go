@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// DailyBalance is the balance of a user at the end of Date, it is not stored but computed from the
+// balances recorded on the transactions
+type DailyBalance struct {
+	Date    string          `json:"date"` // yyyy-mm-dd in the time zone of the request
+	Balance decimal.Decimal `json:"balance"`
+}
+
+// BalanceOf returns the balance of userID recorded on the transaction, after it was applied
+func (t *Transaction) BalanceOf(userID uint) decimal.Decimal {
+	if t.FromUserID == userID {
+		return t.FromUserBalance
+	}
+	return t.ToUserBalance
+}
+
+// BalanceBefore returns the balance of userID before the transaction was applied, the balance must
+// be exact
+func (t *Transaction) BalanceBefore(userID uint) decimal.Decimal {
+	return t.BalanceOf(userID).Sub(t.PostingOf(userID))
+}
+
+// BalanceExact tells whether the balance of userID recorded on the transaction is exact. A credit of
+// a hot account through a bucket misses the uncommitted credits to its other buckets
+func (t *Transaction) BalanceExact(userID uint) bool {
+	return !t.ToUserBalancePartial || t.ToUserID != userID
+}
+
+// BalanceAfter returns the balance of userID after the transaction from its balance before it, the
+// recorded balance when it is exact
+func (t *Transaction) BalanceAfter(userID uint, before decimal.Decimal) decimal.Decimal {
+	if t.BalanceExact(userID) {
+		return t.BalanceOf(userID)
+	}
+	return before.Add(t.PostingOf(userID))
+}
+
+// PostingOf returns the change of the balance of userID by the transaction
+func (t *Transaction) PostingOf(userID uint) decimal.Decimal {
+	return NewLedgerEvent(t).Postings()[userID]
+}
+
+// RecordedBefore orders transactions by creation, the id breaks ties of the same millisecond
+func (t *Transaction) RecordedBefore(other *Transaction) bool {
+	if !t.CreatedAt.Equal(other.CreatedAt) {
+		return t.CreatedAt.Before(other.CreatedAt)
+	}
+	return t.ID < other.ID
+}
@@ -0,0 +1,17 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceBucket is one share of the balance of a hot account. Credits go to the buckets round-robin,
+// so they do not all wait for the lock of the user row, and the balance of the user is User.Balance
+// plus the balances of its buckets
+type BalanceBucket struct {
+	UserID    uint            `gorm:"primaryKey;autoIncrement:false" json:"userId"`
+	Bucket    int             `gorm:"primaryKey;autoIncrement:false" json:"bucket"`
+	Balance   decimal.Decimal `gorm:"type:decimal(10,2);not null;default:'0'" json:"balance"`
+	UpdatedAt time.Time       `gorm:"precision:3" json:"updatedAt"`
+}
@@ -0,0 +1,93 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type LedgerEventType string
+
+const (
+	EventAccountOpened     LedgerEventType = "account_opened" // balance of a user created with one, or before the event store
+	EventMoneyDeposited    LedgerEventType = "money_deposited"
+	EventMoneyWithdrawn    LedgerEventType = "money_withdrawn"
+	EventTransferCompleted LedgerEventType = "transfer_completed"
+	EventFeeCharged        LedgerEventType = "fee_charged"
+	EventInterestCredited  LedgerEventType = "interest_credited"
+)
+
+// eventTypes are the events recorded for each transaction type
+var eventTypes = map[TransactionType]LedgerEventType{
+	Deposit:   EventMoneyDeposited,
+	Withdraw:  EventMoneyWithdrawn,
+	Transfer:  EventTransferCompleted,
+	FeeCharge: EventFeeCharged,
+	Interest:  EventInterestCredited,
+}
+
+// LedgerEvent is an append-only fact of the event store, it is never updated or deleted. The balance
+// and transaction history projections are built by replaying the events in ID order. FromUserID and
+// ToUserID are those of the transaction, both are the user for deposits, withdrawals and interest
+type LedgerEvent struct {
+	ID                uint            `gorm:"primarykey" json:"id"`
+	CreatedAt         time.Time       `gorm:"precision:3" json:"createdAt"`
+	Type              LedgerEventType `gorm:"type:varchar(30);not null" json:"type"`
+	TransactionID     *uint           `gorm:"index" json:"transactionId"`
+	FromUserID        uint            `gorm:"not null" json:"fromUserId"`
+	ToUserID          uint            `gorm:"not null" json:"toUserId"`
+	Amount            decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Fee               decimal.Decimal `gorm:"type:decimal(10,2);not null;default:'0'" json:"fee"` // debited with the amount, credited by the fee_charged event
+	Details           string          `gorm:"type:text" json:"details"`
+	ExternalReference string          `gorm:"type:varchar(64)" json:"externalReference"`
+	RequestID         string          `gorm:"type:varchar(64)" json:"requestId"`
+}
+
+// NewLedgerEvent is the event of a transaction which was just created
+func NewLedgerEvent(transaction *Transaction) *LedgerEvent {
+	return &LedgerEvent{
+		Type:              eventTypes[transaction.TransactionType],
+		TransactionID:     &transaction.ID,
+		FromUserID:        transaction.FromUserID,
+		ToUserID:          transaction.ToUserID,
+		Amount:            transaction.Amount,
+		Fee:               transaction.Fee,
+		Details:           transaction.Details,
+		ExternalReference: transaction.ExternalReference,
+		RequestID:         transaction.RequestID,
+	}
+}
+
+// NewAccountOpened is the event of a user created with a balance
+func NewAccountOpened(user *User) *LedgerEvent {
+	return &LedgerEvent{
+		Type:       EventAccountOpened,
+		FromUserID: user.ID,
+		ToUserID:   user.ID,
+		Amount:     user.Balance,
+	}
+}
+
+// TransactionType is the transaction type recorded with the event, empty for EventAccountOpened
+func (t LedgerEventType) TransactionType() TransactionType {
+	for transactionType, eventType := range eventTypes {
+		if eventType == t {
+			return transactionType
+		}
+	}
+	return ""
+}
+
+// Postings are the balance changes of the event by user. A transfer or withdrawal debits its fee from
+// the payer too, so the fee_charged event which follows it only credits the house account
+func (e *LedgerEvent) Postings() map[uint]decimal.Decimal {
+	switch e.Type {
+	case EventAccountOpened, EventMoneyDeposited, EventInterestCredited, EventFeeCharged:
+		return map[uint]decimal.Decimal{e.ToUserID: e.Amount}
+	case EventMoneyWithdrawn:
+		return map[uint]decimal.Decimal{e.FromUserID: e.Amount.Add(e.Fee).Neg()}
+	case EventTransferCompleted:
+		return map[uint]decimal.Decimal{e.FromUserID: e.Amount.Add(e.Fee).Neg(), e.ToUserID: e.Amount}
+	}
+	return nil
+}
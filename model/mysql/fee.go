@@ -0,0 +1,17 @@
+package mysql
+
+import "github.com/shopspring/decimal"
+
+// FeeBreakdown itemizes the fee of a transfer or withdraw, it is not stored, the charged
+// total is kept in Transaction.Fee
+type FeeBreakdown struct {
+	TransactionType TransactionType `json:"transactionType"`
+	Tier            string          `json:"tier"`
+	Amount          decimal.Decimal `json:"amount"`
+	Flat            decimal.Decimal `json:"flat"`
+	Percentage      decimal.Decimal `json:"percentage"`
+	Tiered          decimal.Decimal `json:"tiered"`
+	Adjustment      decimal.Decimal `json:"adjustment"` // positive when raised to the minimum, negative when capped at the maximum
+	Total           decimal.Decimal `json:"total"`
+	HouseAccountID  uint            `json:"-"`
+}
@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type FraudDecision string
+
+const (
+	Allow  FraudDecision = "allow"
+	Review FraudDecision = "review"
+	Block  FraudDecision = "block"
+)
+
+type FraudReviewStatus string
+
+const (
+	ReviewPending  FraudReviewStatus = "pending"
+	ReviewApproved FraudReviewStatus = "approved"
+	ReviewRejected FraudReviewStatus = "rejected"
+	ReviewBlocked  FraudReviewStatus = "blocked"
+)
+
+// FraudReview records a transfer which was held for review or blocked by fraud screening
+type FraudReview struct {
+	gorm.Model
+	FromUserID        uint              `gorm:"index;not null" json:"fromUserId"`
+	ToUserID          uint              `gorm:"not null" json:"toUserId"`
+	Amount            decimal.Decimal   `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Memo              string            `gorm:"type:varchar(140)" json:"memo"`
+	ExternalReference string            `gorm:"type:varchar(64)" json:"externalReference"`
+	APIKey            string            `gorm:"type:varchar(255)" json:"-"`
+	Score             int               `gorm:"type:int;not null" json:"score"`
+	Decision          FraudDecision     `gorm:"type:varchar(20);not null" json:"decision"`
+	Reasons           string            `gorm:"type:varchar(255)" json:"reasons"` // comma separated rule names
+	Status            FraudReviewStatus `gorm:"type:varchar(20);index;not null" json:"status"`
+	ReviewedBy        *uint             `json:"reviewedBy"`
+	TransactionID     *uint             `json:"transactionId"`
+}
+
+// Reference is the reference the held transfer is executed with once approved
+func (r *FraudReview) Reference() *TransactionReference {
+	return &TransactionReference{Memo: r.Memo, ExternalReference: r.ExternalReference}
+}
@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// InterestAccrual is the interest earned by a user on one day, amounts keep ten decimal places
+// and are only rounded when a month is capitalized
+type InterestAccrual struct {
+	gorm.Model
+	UserID           uint            `gorm:"not null;uniqueIndex:idx_interest_accrual_user_date" json:"userId"`
+	Date             time.Time       `gorm:"type:date;not null;uniqueIndex:idx_interest_accrual_user_date" json:"date"`
+	Plan             string          `gorm:"type:varchar(20);not null" json:"plan"`
+	Rate             decimal.Decimal `gorm:"type:decimal(8,4);not null" json:"rate"` // annual percent
+	Principal        decimal.Decimal `gorm:"type:decimal(20,10);not null" json:"principal"`
+	Amount           decimal.Decimal `gorm:"type:decimal(20,10);not null" json:"amount"`
+	CapitalizationID *uint           `gorm:"index" json:"capitalizationId"`
+}
+
+// InterestCapitalization posts the accruals of one month to the balance, Carry is the part
+// below a cent left over by banker's rounding and is added to the next month
+type InterestCapitalization struct {
+	gorm.Model
+	UserID        uint            `gorm:"not null;uniqueIndex:idx_interest_capitalization_user_period" json:"userId"`
+	Period        string          `gorm:"type:varchar(7);not null;uniqueIndex:idx_interest_capitalization_user_period" json:"period"` // yyyy-mm
+	Accrued       decimal.Decimal `gorm:"type:decimal(20,10);not null" json:"accrued"`
+	PreviousCarry decimal.Decimal `gorm:"type:decimal(20,10);not null" json:"previousCarry"`
+	Posted        decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"posted"`
+	Carry         decimal.Decimal `gorm:"type:decimal(20,10);not null" json:"carry"`
+	TransactionID *uint           `json:"transactionId"`
+}
@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceProjection is the balance of a user projected from the event store up to EventID
+type BalanceProjection struct {
+	UserID    uint            `gorm:"primaryKey;autoIncrement:false" json:"userId"`
+	Balance   decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"balance"`
+	EventID   uint            `gorm:"not null" json:"eventId"`
+	UpdatedAt time.Time       `gorm:"precision:3" json:"updatedAt"`
+}
+
+// TransactionHistory is one event as seen by one of its users, with the balance of the user after it
+type TransactionHistory struct {
+	ID                uint            `gorm:"primarykey" json:"id"`
+	EventID           uint            `gorm:"index;not null" json:"eventId"`
+	UserID            uint            `gorm:"index;not null" json:"userId"`
+	Type              LedgerEventType `gorm:"type:varchar(30);not null" json:"type"`
+	TransactionID     *uint           `json:"transactionId"`
+	FromUserID        uint            `gorm:"index;not null" json:"fromUserId"` // transactions are listed by payer
+	ToUserID          uint            `gorm:"not null" json:"toUserId"`
+	Amount            decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Fee               decimal.Decimal `gorm:"type:decimal(10,2);not null;default:'0'" json:"fee"`
+	Balance           decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"balance"`
+	Details           string          `gorm:"type:text" json:"details"`
+	ExternalReference string          `gorm:"type:varchar(64)" json:"externalReference"`
+	RequestID         string          `gorm:"type:varchar(64)" json:"requestId"`
+	CreatedAt         time.Time       `gorm:"precision:3" json:"createdAt"` // of the event
+}
+
+// ProjectionCheckpoint is the last event applied to the read models, SnapshotPosition the last
+// event of the latest balance snapshot
+type ProjectionCheckpoint struct {
+	Name             string    `gorm:"type:varchar(64);primaryKey" json:"name"`
+	Position         uint      `gorm:"not null" json:"position"`
+	SnapshotPosition uint      `gorm:"not null;default:0" json:"snapshotPosition"`
+	UpdatedAt        time.Time `gorm:"precision:3" json:"updatedAt"`
+}
+
+// ProjectionGap is an event id the projection of Checkpoint passed while it was missing, after
+// projection.gapTimeout. The event is applied late if its transaction commits after all
+type ProjectionGap struct {
+	Checkpoint string    `gorm:"type:varchar(64);primaryKey" json:"checkpoint"`
+	EventID    uint      `gorm:"primaryKey;autoIncrement:false" json:"eventId"`
+	CreatedAt  time.Time `gorm:"precision:3" json:"createdAt"`
+}
+
+// BalanceSnapshot is the projected balance of a user after the event Position, a rebuild may start
+// from the latest snapshot instead of the first event
+type BalanceSnapshot struct {
+	Position  uint            `gorm:"primaryKey;autoIncrement:false" json:"position"`
+	UserID    uint            `gorm:"primaryKey;autoIncrement:false" json:"userId"`
+	Balance   decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"balance"`
+	CreatedAt time.Time       `gorm:"precision:3" json:"createdAt"`
+}
@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type StatementFormat string
+
+const (
+	MT940   StatementFormat = "mt940"
+	CAMT053 StatementFormat = "camt053"
+)
+
+type StatementDirection string
+
+const (
+	Credit StatementDirection = "credit"
+	Debit  StatementDirection = "debit"
+)
+
+type ReconciliationStatus string
+
+const (
+	Matched   ReconciliationStatus = "matched"
+	Unmatched ReconciliationStatus = "unmatched"
+	Ambiguous ReconciliationStatus = "ambiguous"
+	Resolved  ReconciliationStatus = "resolved"
+	Ignored   ReconciliationStatus = "ignored"
+)
+
+type BankStatement struct {
+	gorm.Model
+	Format       StatementFormat     `gorm:"type:varchar(20);not null" json:"format"`
+	StatementRef string              `gorm:"type:varchar(64);index;not null" json:"statementRef"`
+	Account      string              `gorm:"type:varchar(64);not null" json:"account"`
+	FileName     string              `gorm:"type:varchar(255)" json:"fileName"`
+	ImportedBy   uint                `gorm:"not null" json:"importedBy"`
+	Lines        []BankStatementLine `gorm:"foreignKey:BankStatementID" json:"lines,omitempty"`
+}
+
+type BankStatementLine struct {
+	gorm.Model
+	BankStatementID uint                 `gorm:"index;not null" json:"bankStatementId"`
+	ValueDate       time.Time            `gorm:"type:date;not null" json:"valueDate"`
+	Direction       StatementDirection   `gorm:"type:varchar(20);not null" json:"direction"`
+	Amount          decimal.Decimal      `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Currency        string               `gorm:"type:varchar(3)" json:"currency"`
+	Reference       string               `gorm:"type:varchar(64);index" json:"reference"`
+	BankReference   string               `gorm:"type:varchar(64)" json:"bankReference"`
+	Description     string               `gorm:"type:text" json:"description"`
+	Status          ReconciliationStatus `gorm:"type:varchar(20);index;not null" json:"status"`
+	TransactionID   *uint                `gorm:"uniqueIndex" json:"transactionId"`      // a transaction reconciles one line
+	CandidateIDs    string               `gorm:"type:varchar(255)" json:"candidateIds"` // comma separated transaction ids when ambiguous
+	ResolvedBy      *uint                `json:"resolvedBy"`
+}
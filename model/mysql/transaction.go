@@ -11,17 +11,54 @@ const (
 	Deposit  TransactionType = "deposit"
 	Withdraw TransactionType = "withdraw"
 	Transfer TransactionType = "transfer"
+	// FeeCharge moves a fee from the payer to the house account
+	FeeCharge TransactionType = "fee"
+	// Interest credits capitalized interest to the user
+	Interest TransactionType = "interest"
 )
 
 type Transaction struct {
 	gorm.Model
-	FromUser        User            `gorm:"foreignKey:FromUserID" json:"-"`
-	FromUserID      uint            `gorm:"type:int;unsigned;index;not null" json:"fromUserId"`
-	FromUserBalance decimal.Decimal `gorm:"type:decimal(10,2);unsigned;not null" json:"fromUserBalance"`
-	ToUser          User            `gorm:"foreignKey:ToUserID" json:"-"`
-	ToUserID        uint            `gorm:"type:int;unsigned;index;not null" json:"toUserId"`
-	ToUserBalance   decimal.Decimal `gorm:"type:decimal(10,2);unsigned;not null" json:"toUserBalance"`
-	Amount          decimal.Decimal `gorm:"type:decimal(10,2);unsigned;not null" json:"amount"`
-	TransactionType TransactionType `gorm:"type:enum('deposit','withdraw','transfer');not null" json:"transactionType"`
-	Details         string          `gorm:"type:text" json:"details"`
+	FromUser             User            `gorm:"foreignKey:FromUserID" json:"-"`
+	FromUserID           uint            `gorm:"index;not null" json:"fromUserId"`
+	FromUserBalance      decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"fromUserBalance"`
+	ToUser               User            `gorm:"foreignKey:ToUserID" json:"-"`
+	ToUserID             uint            `gorm:"index;not null" json:"toUserId"`
+	ToUserBalance        decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"toUserBalance"`
+	ToUserBalancePartial bool            `gorm:"not null;default:false" json:"toUserBalancePartial"` // credited through a bucket, uncommitted credits to the other buckets are missing
+	Amount               decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Fee                  decimal.Decimal `gorm:"type:decimal(10,2);not null;default:'0'" json:"fee"`
+	TransactionType      TransactionType `gorm:"type:varchar(20);not null" json:"transactionType"`
+	Details              string          `gorm:"type:text" json:"details"`                        // memo of the payer, or the description of fees and interest
+	ExternalReference    string          `gorm:"type:varchar(64);index" json:"externalReference"` // reference of the payer in its own systems
+	RequestID            string          `gorm:"type:varchar(64);index" json:"requestId"`         // X-Request-ID of the request which created it
+	FeeBreakdown         *FeeBreakdown   `gorm:"-" json:"-"`
+	FromUserName         string          `gorm:"-" json:"-"` // resolved for the transaction history
+	ToUserName           string          `gorm:"-" json:"-"`
+}
+
+const (
+	MaxMemoLength              = 140
+	MaxExternalReferenceLength = 64
+)
+
+// TransactionReference is what the payer wrote on a transfer, deposit or withdrawal. The memo is
+// stored as the details of the transaction
+type TransactionReference struct {
+	Memo              string `json:"memo"`
+	ExternalReference string `json:"externalReference"`
+}
+
+// ApplyTo writes the reference on transaction, a nil reference leaves it empty
+func (r *TransactionReference) ApplyTo(transaction *Transaction) {
+	if r == nil {
+		return
+	}
+	transaction.Details = r.Memo
+	transaction.ExternalReference = r.ExternalReference
+}
+
+// MatchesReference is true when reference is the external reference or the memo of the transaction
+func (t *Transaction) MatchesReference(reference string) bool {
+	return reference != "" && (t.ExternalReference == reference || t.Details == reference)
 }
@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type TransferRequestStatus string
+
+const (
+	TransferQueued     TransferRequestStatus = "queued"
+	TransferProcessing TransferRequestStatus = "processing"
+	TransferCompleted  TransferRequestStatus = "completed"
+	TransferHeld       TransferRequestStatus = "held" // held for fraud review, see ReviewID
+	TransferFailed     TransferRequestStatus = "failed"
+)
+
+// TransferRequest is a transfer accepted in async mode, a worker executes it and records the outcome.
+// APIKey, ClientIP and RequestID are those of the request which enqueued it, the transfer is screened
+// and audited as that request
+type TransferRequest struct {
+	gorm.Model
+	FromUserID        uint                  `gorm:"index;not null" json:"fromUserId"`
+	ToUserID          uint                  `gorm:"not null" json:"toUserId"`
+	Amount            decimal.Decimal       `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Memo              string                `gorm:"type:varchar(140)" json:"memo"`
+	ExternalReference string                `gorm:"type:varchar(64)" json:"externalReference"`
+	Status            TransferRequestStatus `gorm:"type:varchar(20);index;not null" json:"status"`
+	APIKey            string                `gorm:"type:varchar(255)" json:"-"`
+	ClientIP          string                `gorm:"type:varchar(64)" json:"-"`
+	RequestID         string                `gorm:"type:varchar(64)" json:"requestId"`
+	CallbackURL       string                `gorm:"type:varchar(2048)" json:"-"`
+	ClaimedAt         *time.Time            `gorm:"precision:3" json:"-"` // when a worker took it, stale claims are failed
+	TransactionID     *uint                 `json:"transactionId"`
+	ReviewID          *uint                 `json:"reviewId"`
+	ErrorCode         string                `gorm:"type:varchar(64)" json:"errorCode"`
+	ErrorMessage      string                `gorm:"type:varchar(255)" json:"error"`
+}
+
+// Reference is the reference the transfer is executed with
+func (r *TransferRequest) Reference() *TransactionReference {
+	return &TransactionReference{Memo: r.Memo, ExternalReference: r.ExternalReference}
+}
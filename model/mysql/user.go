@@ -7,9 +7,13 @@ import (
 
 type User struct {
 	gorm.Model
-	Name     string          `gorm:"type:varchar(20);not null" json:"name"`
-	Email    string          `gorm:"type:varchar(100);unique;index;not null" json:"email"`
-	Password string          `gorm:"type:varchar(255);not null" json:"password"`
-	Balance  decimal.Decimal `gorm:"type:decimal(10,2);unsigned;not null;default:'0'" json:"balance"`
-	IsAdmin  bool            `gorm:"type:tinyint(1);default:false" json:"isAdmin"`
+	Name           string          `gorm:"type:varchar(20);not null" json:"name"`
+	Email          string          `gorm:"type:varchar(100);unique;index;not null" json:"email"`
+	Password       string          `gorm:"type:varchar(255);not null" json:"password"`
+	Balance        decimal.Decimal `gorm:"type:decimal(10,2);not null;default:'0'" json:"balance"`
+	IsAdmin        bool            `gorm:"default:false" json:"isAdmin"`
+	IsAuditor      bool            `gorm:"default:false" json:"isAuditor"`
+	Tier           string          `gorm:"type:varchar(20);not null;default:'standard'" json:"tier"` // fee schedule tier
+	InterestPlan   string          `gorm:"type:varchar(20)" json:"interestPlan"`                     // empty for accounts without interest
+	BalanceBuckets int             `gorm:"not null;default:0" json:"balanceBuckets"`                 // hot accounts spread credits over this many buckets, 0 for none
 }
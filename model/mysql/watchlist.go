@@ -0,0 +1,31 @@
+package mysql
+
+import "gorm.io/gorm"
+
+type ScreeningContext string
+
+const (
+	ScreeningRegistration ScreeningContext = "registration"
+	ScreeningTransfer     ScreeningContext = "transfer"
+)
+
+type ScreeningDecision string
+
+const (
+	ScreeningClear ScreeningDecision = "clear"
+	ScreeningFlag  ScreeningDecision = "flag"
+	ScreeningBlock ScreeningDecision = "block"
+)
+
+// ScreeningResult records every watchlist screening for audit
+type ScreeningResult struct {
+	gorm.Model
+	UserID      *uint             `gorm:"index" json:"userId"`
+	Name        string            `gorm:"type:varchar(100);not null" json:"name"`
+	Context     ScreeningContext  `gorm:"type:varchar(20);not null" json:"context"`
+	Decision    ScreeningDecision `gorm:"type:varchar(20);index;not null" json:"decision"`
+	Score       float64           `gorm:"type:decimal(5,4);not null" json:"score"`
+	EntryID     string            `gorm:"type:varchar(64)" json:"entryId"`
+	EntryName   string            `gorm:"type:varchar(255)" json:"entryName"`
+	EntrySource string            `gorm:"type:varchar(64)" json:"entrySource"`
+}
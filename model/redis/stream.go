@@ -0,0 +1,30 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const TransactionEvent = "transaction"
+
+// BalanceEvent is pushed to a user when a transaction changed the user balance
+type BalanceEvent struct {
+	ID          string             `json:"id"` // redis stream entry id, clients resume after it
+	UserID      uint               `json:"userId"`
+	Type        string             `json:"type"`
+	Balance     decimal.Decimal    `json:"balance"` // balance of the user after the transaction
+	Transaction *StreamTransaction `json:"transaction"`
+}
+
+// StreamTransaction is the transaction of an event, without the balance of the counterparty
+type StreamTransaction struct {
+	ID              uint            `json:"id"`
+	FromUserID      uint            `json:"fromUserId"`
+	ToUserID        uint            `json:"toUserId"`
+	Amount          decimal.Decimal `json:"amount"`
+	Fee             decimal.Decimal `json:"fee"`
+	TransactionType string          `json:"transactionType"`
+	Details         string          `json:"details"`
+	CreatedAt       time.Time       `json:"createdAt"`
+}
@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"go.elastic.co/apm/module/apmgin/v2"
+	apmmysql "go.elastic.co/apm/module/apmgormv2/v2/driver/mysql"
+	apmpostgres "go.elastic.co/apm/module/apmgormv2/v2/driver/postgres"
+	apmsqlite "go.elastic.co/apm/module/apmgormv2/v2/driver/sqlite"
+	"go.elastic.co/apm/module/apmzap/v2"
+	"go.elastic.co/apm/v2"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// apmTracer reports to Elastic APM, the agent is configured by the ELASTIC_APM_* environment
+type apmTracer struct {
+	tracer *apm.Tracer
+}
+
+func NewAPMTracer(serviceName string) (Tracer, error) {
+	tracer, err := apm.NewTracer(serviceName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &apmTracer{tracer: tracer}, nil
+}
+
+func (t *apmTracer) StartSpan(ctx context.Context, name, spanType string) (Span, context.Context) {
+	return apm.StartSpan(ctx, name, spanType)
+}
+
+func (t *apmTracer) CaptureError(ctx context.Context, err error) {
+	apm.CaptureError(ctx, err).Send()
+}
+
+func (t *apmTracer) SetLabel(ctx context.Context, key, value string) {
+	if tx := apm.TransactionFromContext(ctx); tx != nil {
+		tx.Context.SetLabel(key, value)
+	}
+}
+
+func (t *apmTracer) GinMiddleware(engine *gin.Engine) gin.HandlerFunc {
+	return apmgin.Middleware(engine, apmgin.WithTracer(t.tracer))
+}
+
+func (t *apmTracer) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{grpc.ChainUnaryInterceptor(t.unaryServerInterceptor)}
+}
+
+// unaryServerInterceptor starts an APM transaction for every call, so handler and repo spans are
+// reported the same way as requests served by apmgin
+func (t *apmTracer) unaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	tx := t.tracer.StartTransaction(info.FullMethod, "request")
+	defer tx.End()
+	ctx = apm.ContextWithTransaction(ctx, tx)
+
+	resp, err := handler(ctx, req)
+	tx.Result = status.Code(err).String()
+	if err != nil {
+		tx.Outcome = "failure"
+	} else {
+		tx.Outcome = "success"
+	}
+
+	return resp, err
+}
+
+// MySQLDialector wraps the driver with apmsql, queries are reported as spans of the request
+func (t *apmTracer) MySQLDialector(dsn string) gorm.Dialector {
+	return apmmysql.Open(dsn)
+}
+
+func (t *apmTracer) PostgresDialector(dsn string) gorm.Dialector {
+	return apmpostgres.Open(dsn)
+}
+
+func (t *apmTracer) SQLiteDialector(dsn string) gorm.Dialector {
+	return apmsqlite.Open(dsn)
+}
+
+func (t *apmTracer) InstrumentGORM(*gorm.DB) error {
+	return nil
+}
+
+func (t *apmTracer) InstrumentRedis(client *redis.Client) {
+	client.AddHook(apmRedisHook{})
+}
+
+func (t *apmTracer) LogCore() zapcore.Core {
+	return &apmzap.Core{Tracer: t.tracer}
+}
+
+func (t *apmTracer) Shutdown(ctx context.Context) error {
+	t.tracer.Flush(ctx.Done())
+	t.tracer.Close()
+	return nil
+}
+
+// apmRedisHook reports every redis command as a span of the request
+type apmRedisHook struct{}
+
+func (apmRedisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	_, ctx = apm.StartSpan(ctx, cmd.FullName(), "db.redis")
+	return ctx, nil
+}
+
+func (apmRedisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	if span := apm.SpanFromContext(ctx); span != nil {
+		span.End()
+	}
+	return nil
+}
+
+func (apmRedisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	_, ctx = apm.StartSpan(ctx, "pipeline", "db.redis")
+	return ctx, nil
+}
+
+func (apmRedisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	if span := apm.SpanFromContext(ctx); span != nil {
+		span.End()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type noopSpan struct{}
+
+func (noopSpan) End() {}
+
+// noopTracer is the default until Init runs, so tests and commands without tracing need no backend
+type noopTracer struct{}
+
+func NewNoopTracer() Tracer {
+	return noopTracer{}
+}
+
+func (noopTracer) StartSpan(ctx context.Context, _, _ string) (Span, context.Context) {
+	return noopSpan{}, ctx
+}
+
+func (noopTracer) CaptureError(context.Context, error) {}
+
+func (noopTracer) SetLabel(context.Context, string, string) {}
+
+func (noopTracer) GinMiddleware(*gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+	}
+}
+
+func (noopTracer) ServerOptions() []grpc.ServerOption {
+	return nil
+}
+
+func (noopTracer) MySQLDialector(dsn string) gorm.Dialector {
+	return mysql.Open(dsn)
+}
+
+func (noopTracer) PostgresDialector(dsn string) gorm.Dialector {
+	return postgres.Open(dsn)
+}
+
+func (noopTracer) SQLiteDialector(dsn string) gorm.Dialector {
+	return sqlite.Open(dsn)
+}
+
+func (noopTracer) InstrumentGORM(*gorm.DB) error {
+	return nil
+}
+
+func (noopTracer) InstrumentRedis(*redis.Client) {}
+
+func (noopTracer) LogCore() zapcore.Core {
+	return zapcore.NewNopCore()
+}
+
+func (noopTracer) Shutdown(context.Context) error {
+	return nil
+}
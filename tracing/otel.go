@@ -0,0 +1,210 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/extra/redisotel/v8"
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	otelgorm "gorm.io/plugin/opentelemetry/tracing"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+const instrumentationName = "banking"
+
+// otelTracer exports traces and metrics with OpenTelemetry, to an OTLP collector or to stdout for
+// local use. It is installed as the global provider, so the Gin, gRPC, GORM and go-redis
+// instrumentations report to it too
+type otelTracer struct {
+	serviceName    string
+	tracer         trace.Tracer
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+}
+
+func NewOTelTracer(ctx context.Context) (Tracer, error) {
+	serviceName := viper.GetString("tracing.otel.serviceName")
+	if serviceName == "" {
+		return nil, errors.New("tracing.otel.serviceName is required")
+	}
+
+	sampleRatio := 1.0
+	if viper.IsSet("tracing.otel.sampleRatio") {
+		sampleRatio = viper.GetFloat64("tracing.otel.sampleRatio")
+	}
+	if sampleRatio < 0 || sampleRatio > 1 {
+		return nil, fmt.Errorf("tracing.otel.sampleRatio %v should be between 0 and 1", sampleRatio)
+	}
+
+	metricInterval := 60 * time.Second
+	if viper.IsSet("tracing.otel.metricInterval") {
+		metricInterval = time.Duration(viper.GetInt("tracing.otel.metricInterval")) * time.Second
+	}
+	if metricInterval <= 0 {
+		return nil, errors.New("tracing.otel.metricInterval should be positive")
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	spanExporter, metricExporter, err := newOTelExporters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(metricInterval))),
+		sdkmetric.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return &otelTracer{
+		serviceName:    serviceName,
+		tracer:         tracerProvider.Tracer(instrumentationName),
+		tracerProvider: tracerProvider,
+		meterProvider:  meterProvider,
+	}, nil
+}
+
+// newOTelExporters creates the span and metric exporters of tracing.otel.exporter
+func newOTelExporters(ctx context.Context) (sdktrace.SpanExporter, sdkmetric.Exporter, error) {
+	switch exporter := viper.GetString("tracing.otel.exporter"); exporter {
+	case ExporterOTLP, "":
+		endpoint := viper.GetString("tracing.otel.endpoint")
+		traceOptions := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+		metricOptions := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(endpoint)}
+		if viper.GetBool("tracing.otel.insecure") {
+			traceOptions = append(traceOptions, otlptracegrpc.WithInsecure())
+			metricOptions = append(metricOptions, otlpmetricgrpc.WithInsecure())
+		}
+
+		spanExporter, err := otlptracegrpc.New(ctx, traceOptions...)
+		if err != nil {
+			return nil, nil, err
+		}
+		metricExporter, err := otlpmetricgrpc.New(ctx, metricOptions...)
+		if err != nil {
+			return nil, nil, err
+		}
+		return spanExporter, metricExporter, nil
+	case ExporterStdout:
+		spanExporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, nil, err
+		}
+		metricExporter, err := stdoutmetric.New(stdoutmetric.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, nil, err
+		}
+		return spanExporter, metricExporter, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %s", exporter)
+	}
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) End() {
+	s.span.End()
+}
+
+func (t *otelTracer) StartSpan(ctx context.Context, name, spanType string) (Span, context.Context) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(attribute.String("span.type", spanType)))
+	return otelSpan{span: span}, ctx
+}
+
+func (t *otelTracer) CaptureError(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func (t *otelTracer) SetLabel(ctx context.Context, key, value string) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String(key, value))
+}
+
+func (t *otelTracer) GinMiddleware(*gin.Engine) gin.HandlerFunc {
+	return otelgin.Middleware(t.serviceName, otelgin.WithTracerProvider(t.tracerProvider))
+}
+
+func (t *otelTracer) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{grpc.StatsHandler(otelgrpc.NewServerHandler(
+		otelgrpc.WithTracerProvider(t.tracerProvider),
+		otelgrpc.WithMeterProvider(t.meterProvider),
+	))}
+}
+
+func (t *otelTracer) MySQLDialector(dsn string) gorm.Dialector {
+	return mysql.Open(dsn)
+}
+
+func (t *otelTracer) PostgresDialector(dsn string) gorm.Dialector {
+	return postgres.Open(dsn)
+}
+
+func (t *otelTracer) SQLiteDialector(dsn string) gorm.Dialector {
+	return sqlite.Open(dsn)
+}
+
+// InstrumentGORM reports queries as spans and the connection pool as metrics
+func (t *otelTracer) InstrumentGORM(db *gorm.DB) error {
+	return db.Use(otelgorm.NewPlugin(otelgorm.WithTracerProvider(t.tracerProvider)))
+}
+
+func (t *otelTracer) InstrumentRedis(client *redis.Client) {
+	client.AddHook(redisotel.NewTracingHook(redisotel.WithTracerProvider(t.tracerProvider)))
+}
+
+func (t *otelTracer) LogCore() zapcore.Core {
+	return zapcore.NewNopCore()
+}
+
+func (t *otelTracer) Shutdown(ctx context.Context) error {
+	return errors.Join(t.tracerProvider.Shutdown(ctx), t.meterProvider.Shutdown(ctx))
+}
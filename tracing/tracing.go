@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+)
+
+const (
+	ProviderAPM  = "apm"
+	ProviderOTel = "otel"
+	ProviderNone = "none"
+)
+
+// Span is a timed operation of a request, such as a handler, service or repo call
+type Span interface {
+	End()
+}
+
+// Tracer reports spans and errors to a tracing backend and instruments the frameworks of the
+// apiserver, the backend is selected by tracing.provider
+type Tracer interface {
+	// StartSpan starts a child span of the span in ctx, spanType is handler, service or repo
+	StartSpan(ctx context.Context, name, spanType string) (Span, context.Context)
+	// CaptureError reports err on the request in ctx
+	CaptureError(ctx context.Context, err error)
+	// SetLabel adds a searchable key value pair to the request in ctx
+	SetLabel(ctx context.Context, key, value string)
+
+	// GinMiddleware starts a trace for every request served by engine
+	GinMiddleware(engine *gin.Engine) gin.HandlerFunc
+	// ServerOptions start a trace for every gRPC call
+	ServerOptions() []grpc.ServerOption
+	// MySQLDialector opens MySQL, tracing the queries when the backend instruments the driver
+	MySQLDialector(dsn string) gorm.Dialector
+	// PostgresDialector opens PostgreSQL, tracing the queries when the backend instruments the driver
+	PostgresDialector(dsn string) gorm.Dialector
+	// SQLiteDialector opens SQLite, tracing the queries when the backend instruments the driver
+	SQLiteDialector(dsn string) gorm.Dialector
+	// InstrumentGORM traces the queries of db when the backend instruments GORM
+	InstrumentGORM(db *gorm.DB) error
+	// InstrumentRedis traces the commands of client
+	InstrumentRedis(client *redis.Client)
+	// LogCore forwards log entries to the backend, it may drop all of them
+	LogCore() zapcore.Core
+
+	// Shutdown flushes buffered spans
+	Shutdown(ctx context.Context) error
+}
+
+var defaultTracer Tracer = NewNoopTracer()
+
+// Init creates the tracer of tracing.provider and makes it the default
+func Init(ctx context.Context) (Tracer, error) {
+	var (
+		tracer Tracer
+		err    error
+	)
+	switch provider := viper.GetString("tracing.provider"); provider {
+	case ProviderAPM, "":
+		tracer, err = NewAPMTracer(viper.GetString("apm.serviceName"))
+	case ProviderOTel:
+		tracer, err = NewOTelTracer(ctx)
+	case ProviderNone:
+		tracer = NewNoopTracer()
+	default:
+		return nil, fmt.Errorf("unknown tracing provider %s", provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	SetDefault(tracer)
+	return tracer, nil
+}
+
+// SetDefault replaces the tracer used by StartSpan, CaptureError and SetLabel, it is not safe to
+// call while requests are served
+func SetDefault(tracer Tracer) {
+	defaultTracer = tracer
+}
+
+// Default returns the tracer used by StartSpan, CaptureError and SetLabel
+func Default() Tracer {
+	return defaultTracer
+}
+
+// StartSpan starts a span with the default tracer
+func StartSpan(ctx context.Context, name, spanType string) (Span, context.Context) {
+	return defaultTracer.StartSpan(ctx, name, spanType)
+}
+
+// CaptureError reports err with the default tracer
+func CaptureError(ctx context.Context, err error) {
+	defaultTracer.CaptureError(ctx, err)
+}
+
+// SetLabel labels the request in ctx with the default tracer
+func SetLabel(ctx context.Context, key, value string) {
+	defaultTracer.SetLabel(ctx, key, value)
+}
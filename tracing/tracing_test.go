@@ -0,0 +1,56 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"banking/tracing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_Init(t *testing.T) {
+	t.Cleanup(func() {
+		tracing.SetDefault(tracing.NewNoopTracer())
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		viper.Set("tracing.provider", "zipkin")
+
+		_, err := tracing.Init(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		viper.Set("tracing.provider", tracing.ProviderNone)
+
+		tracer, err := tracing.Init(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, tracer, tracing.Default())
+
+		span, ctx := tracing.StartSpan(context.Background(), "test", "service")
+		span.End()
+		assert.False(t, trace.SpanFromContext(ctx).SpanContext().IsValid())
+	})
+
+	t.Run("otel with stdout exporter", func(t *testing.T) {
+		viper.Set("tracing.provider", tracing.ProviderOTel)
+		viper.Set("tracing.otel.serviceName", "banking-test")
+		viper.Set("tracing.otel.exporter", tracing.ExporterStdout)
+
+		tracer, err := tracing.Init(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, tracer, tracing.Default())
+
+		span, ctx := tracing.StartSpan(context.Background(), "test", "service")
+		tracing.SetLabel(ctx, "request_id", "request-1")
+		tracing.CaptureError(ctx, errors.New("failed"))
+		span.End()
+		assert.True(t, trace.SpanFromContext(ctx).SpanContext().IsValid())
+
+		assert.NoError(t, tracer.Shutdown(context.Background()))
+	})
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"context"
+
+	mysqlModel "banking/model/mysql"
+)
+
+type contextKey string
+
+const (
+	apiKeyContextKey    contextKey = "apiKey"
+	actorContextKey     contextKey = "actor"
+	clientIPContextKey  contextKey = "clientIP"
+	requestIDContextKey contextKey = "requestID"
+	strongContextKey    contextKey = "strongConsistency"
+	reviewContextKey    contextKey = "approvedReview"
+	auditContextKey     contextKey = "auditEntry"
+)
+
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "apikey"
+)
+
+// Actor is the authenticated user performing the request
+type Actor struct {
+	UserID     uint
+	Email      string
+	AuthMethod string
+	IsAdmin    bool
+}
+
+// ContextWithAPIKey stores the API key used to authenticate the request
+func ContextWithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey, key)
+}
+
+// APIKeyFromContext returns the API key of the request, empty when the request was not authenticated by API key
+func APIKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(apiKeyContextKey).(string)
+	return key
+}
+
+// ContextWithActor stores the authenticated user of the request
+func ContextWithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey, actor)
+}
+
+// ActorFromContext returns the authenticated user, nil for anonymous requests
+func ActorFromContext(ctx context.Context) *Actor {
+	actor, _ := ctx.Value(actorContextKey).(*Actor)
+	return actor
+}
+
+// ContextWithClientIP stores the IP address of the client
+func ContextWithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPContextKey, ip)
+}
+
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPContextKey).(string)
+	return ip
+}
+
+// ContextWithRequestID stores the id correlating the request across services
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+// ConsistencyHeader set to ConsistencyStrong demands strong consistency for the reads of a request,
+// gRPC uses it lower case as metadata key
+const (
+	ConsistencyHeader = "X-Consistency"
+	ConsistencyStrong = "strong"
+)
+
+// ContextWithStrongConsistency makes the reads of the request go to the master, so they see every committed write
+func ContextWithStrongConsistency(ctx context.Context) context.Context {
+	return context.WithValue(ctx, strongContextKey, true)
+}
+
+func StrongConsistencyFromContext(ctx context.Context) bool {
+	strong, _ := ctx.Value(strongContextKey).(bool)
+	return strong
+}
+
+// ContextWithApprovedReview marks a transfer as the execution of an approved fraud review, it skips fraud
+// screening and links the review to the transaction, never set it from request input
+func ContextWithApprovedReview(ctx context.Context, reviewID uint) context.Context {
+	return context.WithValue(ctx, reviewContextKey, reviewID)
+}
+
+// ApprovedReviewFromContext returns the id of the approved fraud review the transfer executes, 0 for other transfers
+func ApprovedReviewFromContext(ctx context.Context) uint {
+	reviewID, _ := ctx.Value(reviewContextKey).(uint)
+	return reviewID
+}
+
+// ContextWithAuditEntry carries the audit entry of an action, the repo of the action appends it in the
+// database transaction of the action
+func ContextWithAuditEntry(ctx context.Context, entry *mysqlModel.AuditLog) context.Context {
+	return context.WithValue(ctx, auditContextKey, entry)
+}
+
+// AuditEntryFromContext returns the audit entry of the action, nil when the action is audited after it ends
+func AuditEntryFromContext(ctx context.Context) *mysqlModel.AuditLog {
+	entry, _ := ctx.Value(auditContextKey).(*mysqlModel.AuditLog)
+	return entry
+}
@@ -10,18 +10,20 @@ import (
 
 // JWTClaims defines the custom claims for the JWT token
 type JWTClaims struct {
-	UserID  uint   `json:"userId"`
-	IsAdmin bool   `json:"isAdmin"`
-	Email   string `json:"email"`
+	UserID    uint   `json:"userId"`
+	IsAdmin   bool   `json:"isAdmin"`
+	IsAuditor bool   `json:"isAuditor"`
+	Email     string `json:"email"`
 	jwt.StandardClaims
 }
 
 // GenerateJWT generates a JWT token for the user
-func GenerateJWT(userID uint, email string, isAdmin bool) (string, error) {
+func GenerateJWT(userID uint, email string, isAdmin, isAuditor bool) (string, error) {
 	claims := JWTClaims{
-		UserID:  userID,
-		IsAdmin: isAdmin,
-		Email:   email,
+		UserID:    userID,
+		IsAdmin:   isAdmin,
+		IsAuditor: isAuditor,
+		Email:     email,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(time.Duration(viper.GetInt("jwt.expirationTime")) * time.Hour).Unix(),
 			Issuer:    viper.GetString("jwt.issuer"),
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var rateLimitScript = redis.NewScript(`
+	local limit = tonumber(ARGV[1])
+	local ttl = ARGV[2]
+	local current = redis.call("GET", KEYS[1])
+	if not current then
+		redis.call("SET", KEYS[1], limit - 1, "PX", ttl)
+		return {limit - 1, ttl}
+	end
+	if tonumber(current) <= 0 then
+		return {-1, redis.call("PTTL", KEYS[1])}
+	end
+	redis.call("DECR", KEYS[1])
+	return {tonumber(current) - 1, redis.call("PTTL", KEYS[1])}
+`)
+
+// RateLimit takes one request from the fixed window budget of the API key, remaining is negative
+// when the budget is exhausted and reset is the milliseconds left in the window.
+// REST and gRPC requests share the same budget
+func RateLimit(ctx context.Context, redisClient *redis.Client, key string, limit int64, duration time.Duration) (remaining, reset int64, err error) {
+	result, err := rateLimitScript.Run(ctx, redisClient, []string{fmt.Sprintf("rate_limit:%s", key)}, limit, duration.Milliseconds()).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	data := result.([]interface{})
+	if remaining, err = strconv.ParseInt(fmt.Sprintf("%v", data[0]), 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if reset, err = strconv.ParseInt(fmt.Sprintf("%v", data[1]), 10, 64); err != nil {
+		return 0, 0, err
+	}
+
+	return remaining, reset, nil
+}
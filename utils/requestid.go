@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+)
+
+// RequestIDHeader carries the request id in both directions, gRPC uses it lower case as metadata key
+const RequestIDHeader = "X-Request-ID"
+
+// client ids are logged and stored, so only short ids of safe characters are accepted
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// NewRequestID returns a random 128 bit request id
+func NewRequestID() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(bytes)
+}
+
+// RequestIDOrNew returns the request id sent by the client, or a new one when it is missing or invalid
+func RequestIDOrNew(requestID string) string {
+	if requestIDPattern.MatchString(requestID) {
+		return requestID
+	}
+	return NewRequestID()
+}